@@ -22,10 +22,30 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 			SecurityGroupName: srcNet.LoadBalancer.SecurityGroupName,
 			Listener:          infrastructurev1beta2.OscLoadBalancerListener(srcNet.LoadBalancer.Listener),
 			HealthCheck:       infrastructurev1beta2.OscLoadBalancerHealthCheck(srcNet.LoadBalancer.HealthCheck),
+			AdditionalListeners: lo.Map(srcNet.LoadBalancer.AdditionalListeners, func(src OscLoadBalancerListener, _ int) infrastructurev1beta2.OscLoadBalancerListener {
+				return infrastructurev1beta2.OscLoadBalancerListener(src)
+			}),
+			PublicIp:     srcNet.LoadBalancer.PublicIp,
+			PublicIpPool: srcNet.LoadBalancer.PublicIpPool,
+			AccessLog:    infrastructurev1beta2.OscLoadBalancerAccessLog(srcNet.LoadBalancer.AccessLog),
 		},
+		AdditionalLoadBalancers: lo.Map(srcNet.AdditionalLoadBalancers, func(src OscAdditionalLoadBalancer, _ int) infrastructurev1beta2.OscAdditionalLoadBalancer {
+			return infrastructurev1beta2.OscAdditionalLoadBalancer{
+				LoadBalancerName: src.LoadBalancerName,
+				LoadBalancerType: src.LoadBalancerType,
+				BackendRole:      infrastructurev1beta2.OscRole(src.BackendRole),
+				Listeners: lo.Map(src.Listeners, func(src OscLoadBalancerListener, _ int) infrastructurev1beta2.OscLoadBalancerListener {
+					return infrastructurev1beta2.OscLoadBalancerListener(src)
+				}),
+				HealthCheck:               infrastructurev1beta2.OscLoadBalancerHealthCheck(src.HealthCheck),
+				UseAsControlPlaneEndpoint: src.UseAsControlPlaneEndpoint,
+			}
+		}),
 		Net: infrastructurev1beta2.OscNet{
-			Name:    srcNet.Net.Name,
-			IpRange: srcNet.Net.IpRange,
+			Name:        srcNet.Net.Name,
+			IpRange:     srcNet.Net.IpRange,
+			NetPool:     srcNet.Net.NetPool,
+			DhcpOptions: infrastructurev1beta2.OscDhcpOptions(srcNet.Net.DhcpOptions),
 		},
 		NetPeering: infrastructurev1beta2.OscNetPeering{
 			Enable:                srcNet.NetPeering.Enable,
@@ -34,6 +54,26 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 			ManagementNetID:       srcNet.NetPeering.ManagementNetID,
 			ManagementSubnetID:    srcNet.NetPeering.ManagementSubnetID,
 		},
+		Peerings: lo.Map(srcNet.Peerings, func(src OscPeering, _ int) infrastructurev1beta2.OscPeering {
+			return infrastructurev1beta2.OscPeering{
+				Name:          src.Name,
+				Credentials:   infrastructurev1beta2.OscCredentials(src.Credentials),
+				AccountID:     src.AccountID,
+				NetID:         src.NetID,
+				PeerSubnetIDs: src.PeerSubnetIDs,
+				Roles: lo.Map(src.Roles, func(src OscRole, _ int) infrastructurev1beta2.OscRole {
+					return infrastructurev1beta2.OscRole(src)
+				}),
+				AcceptPolicy: infrastructurev1beta2.OscPeeringAcceptPolicy(src.AcceptPolicy),
+			}
+		}),
+		Vpn: infrastructurev1beta2.OscVpn{
+			Enable:           srcNet.Vpn.Enable,
+			VirtualGatewayID: srcNet.Vpn.VirtualGatewayID,
+			Connections: lo.Map(srcNet.Vpn.Connections, func(src OscVpnConnection, _ int) infrastructurev1beta2.OscVpnConnection {
+				return infrastructurev1beta2.OscVpnConnection(src)
+			}),
+		},
 		NetAccessPoints: lo.Map(srcNet.NetAccessPoints, func(src OscNetAccessPointService, _ int) infrastructurev1beta2.OscNetAccessPointService {
 			return infrastructurev1beta2.OscNetAccessPointService(src)
 		}),
@@ -46,8 +86,21 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 				}),
 				IpSubnetRange: src.IpSubnetRange,
 				SubregionName: src.SubregionName,
+				Pool:          src.Pool,
 			}
 		}),
+		SubnetAllocation: infrastructurev1beta2.OscSubnetAllocation(srcNet.SubnetAllocation),
+		SubnetLayout: infrastructurev1beta2.OscSubnetLayout{
+			PublicPrefixLength:       srcNet.SubnetLayout.PublicPrefixLength,
+			ControlPlanePrefixLength: srcNet.SubnetLayout.ControlPlanePrefixLength,
+			WorkerPrefixLength:       srcNet.SubnetLayout.WorkerPrefixLength,
+			SeparateNatSubnets:       srcNet.SubnetLayout.SeparateNatSubnets,
+			NatPrefixLength:          srcNet.SubnetLayout.NatPrefixLength,
+			Pools: lo.Map(srcNet.SubnetLayout.Pools, func(src OscSubnetPool, _ int) infrastructurev1beta2.OscSubnetPool {
+				return infrastructurev1beta2.OscSubnetPool(src)
+			}),
+			ReservedIpRange: srcNet.SubnetLayout.ReservedIpRange,
+		},
 		InternetService: infrastructurev1beta2.OscInternetService{
 			Name: srcNet.InternetService.Name,
 		},
@@ -55,15 +108,18 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 			Name:          srcNet.NatService.Name,
 			SubnetName:    srcNet.NatService.SubnetName,
 			SubregionName: srcNet.NatService.SubregionName,
+			PublicIpId:    srcNet.NatService.PublicIpId,
 		},
 		NatServices: lo.Map(srcNet.NatServices, func(src OscNatService, _ int) infrastructurev1beta2.OscNatService {
 			return infrastructurev1beta2.OscNatService{
 				Name:          src.Name,
 				SubnetName:    src.SubnetName,
 				SubregionName: src.SubregionName,
+				PublicIpId:    src.PublicIpId,
 			}
 		}),
 		NatPublicIpPool: srcNet.NatPublicIpPool,
+		NatFailover:     infrastructurev1beta2.OscNatFailover(srcNet.NatFailover),
 		RouteTables: lo.Map(srcNet.RouteTables, func(src OscRouteTable, _ int) infrastructurev1beta2.OscRouteTable {
 			return infrastructurev1beta2.OscRouteTable{
 				Name:          src.Name,
@@ -73,6 +129,7 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 				Routes: lo.Map(src.Routes, func(src OscRoute, _ int) infrastructurev1beta2.OscRoute {
 					return infrastructurev1beta2.OscRoute(src)
 				}),
+				Authoritative: src.Authoritative,
 			}
 		}),
 		SecurityGroups: lo.Map(srcNet.SecurityGroups, func(src OscSecurityGroup, _ int) infrastructurev1beta2.OscSecurityGroup {
@@ -80,7 +137,7 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 				Name:        src.Name,
 				Description: src.Description,
 				SecurityGroupRules: lo.Map(src.SecurityGroupRules, func(src OscSecurityGroupRule, _ int) infrastructurev1beta2.OscSecurityGroupRule {
-					return infrastructurev1beta2.OscSecurityGroupRule(src)
+					return convertSecurityGroupRuleTo(src)
 				}),
 			}
 		}),
@@ -90,10 +147,12 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 					return infrastructurev1beta2.OscRole(src)
 				}),
 				Rules: lo.Map(src.Rules, func(src OscSecurityGroupRule, _ int) infrastructurev1beta2.OscSecurityGroupRule {
-					return infrastructurev1beta2.OscSecurityGroupRule(src)
+					return convertSecurityGroupRuleTo(src)
 				}),
 			}
 		}),
+		Cni:           infrastructurev1beta2.OscCni(srcNet.Cni),
+		CniProfileRef: srcNet.CniProfileRef,
 		Bastion: infrastructurev1beta2.OscBastion{
 			Name:           srcNet.Bastion.Name,
 			ImageId:        srcNet.Bastion.ImageId,
@@ -113,6 +172,20 @@ func (src *OscClusterSpec) ConvertTo(dst *infrastructurev1beta2.OscClusterSpec)
 		Subregions:        srcNet.Subregions,
 		AllowFromIPRanges: srcNet.AllowFromIPRanges, // The list of IP ranges (in CIDR notation) the nodes can talk to ("0.0.0.0/0" if not set).
 		AllowToIPRanges:   srcNet.AllowToIPRanges,
+		EgressRules: lo.Map(srcNet.EgressRules, func(src OscEgressRule, _ int) infrastructurev1beta2.OscEgressRule {
+			return infrastructurev1beta2.OscEgressRule{
+				Roles: lo.Map(src.Roles, func(src OscRole, _ int) infrastructurev1beta2.OscRole {
+					return infrastructurev1beta2.OscRole(src)
+				}),
+				IpProtocol:    src.IpProtocol,
+				FromPortRange: src.FromPortRange,
+				ToPortRange:   src.ToPortRange,
+				IpRanges:      src.IpRanges,
+				Destinations: lo.Map(src.Destinations, func(src OscEgressDestination, _ int) infrastructurev1beta2.OscEgressDestination {
+					return infrastructurev1beta2.OscEgressDestination(src)
+				}),
+			}
+		}),
 		ReconciliationRules: lo.Map(srcNet.ReconciliationRules, func(src OscReconciliationRule, _ int) infrastructurev1beta2.OscReconciliationRule {
 			return infrastructurev1beta2.OscReconciliationRule{
 				AppliesTo: lo.Map(src.AppliesTo, func(src Reconciler, _ int) infrastructurev1beta2.Reconciler {
@@ -142,10 +215,30 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 			SecurityGroupName: srcNet.LoadBalancer.SecurityGroupName,
 			Listener:          OscLoadBalancerListener(srcNet.LoadBalancer.Listener),
 			HealthCheck:       OscLoadBalancerHealthCheck(srcNet.LoadBalancer.HealthCheck),
+			AdditionalListeners: lo.Map(srcNet.LoadBalancer.AdditionalListeners, func(src infrastructurev1beta2.OscLoadBalancerListener, _ int) OscLoadBalancerListener {
+				return OscLoadBalancerListener(src)
+			}),
+			PublicIp:     srcNet.LoadBalancer.PublicIp,
+			PublicIpPool: srcNet.LoadBalancer.PublicIpPool,
+			AccessLog:    OscLoadBalancerAccessLog(srcNet.LoadBalancer.AccessLog),
 		},
+		AdditionalLoadBalancers: lo.Map(srcNet.AdditionalLoadBalancers, func(src infrastructurev1beta2.OscAdditionalLoadBalancer, _ int) OscAdditionalLoadBalancer {
+			return OscAdditionalLoadBalancer{
+				LoadBalancerName: src.LoadBalancerName,
+				LoadBalancerType: src.LoadBalancerType,
+				BackendRole:      OscRole(src.BackendRole),
+				Listeners: lo.Map(src.Listeners, func(src infrastructurev1beta2.OscLoadBalancerListener, _ int) OscLoadBalancerListener {
+					return OscLoadBalancerListener(src)
+				}),
+				HealthCheck:               OscLoadBalancerHealthCheck(src.HealthCheck),
+				UseAsControlPlaneEndpoint: src.UseAsControlPlaneEndpoint,
+			}
+		}),
 		Net: OscNet{
-			Name:    srcNet.Net.Name,
-			IpRange: srcNet.Net.IpRange,
+			Name:        srcNet.Net.Name,
+			IpRange:     srcNet.Net.IpRange,
+			NetPool:     srcNet.Net.NetPool,
+			DhcpOptions: OscDhcpOptions(srcNet.Net.DhcpOptions),
 		},
 		NetPeering: OscNetPeering{
 			Enable:                srcNet.NetPeering.Enable,
@@ -154,6 +247,26 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 			ManagementNetID:       srcNet.NetPeering.ManagementNetID,
 			ManagementSubnetID:    srcNet.NetPeering.ManagementSubnetID,
 		},
+		Peerings: lo.Map(srcNet.Peerings, func(src infrastructurev1beta2.OscPeering, _ int) OscPeering {
+			return OscPeering{
+				Name:          src.Name,
+				Credentials:   OscCredentials(src.Credentials),
+				AccountID:     src.AccountID,
+				NetID:         src.NetID,
+				PeerSubnetIDs: src.PeerSubnetIDs,
+				Roles: lo.Map(src.Roles, func(src infrastructurev1beta2.OscRole, _ int) OscRole {
+					return OscRole(src)
+				}),
+				AcceptPolicy: OscPeeringAcceptPolicy(src.AcceptPolicy),
+			}
+		}),
+		Vpn: OscVpn{
+			Enable:           srcNet.Vpn.Enable,
+			VirtualGatewayID: srcNet.Vpn.VirtualGatewayID,
+			Connections: lo.Map(srcNet.Vpn.Connections, func(src infrastructurev1beta2.OscVpnConnection, _ int) OscVpnConnection {
+				return OscVpnConnection(src)
+			}),
+		},
 		NetAccessPoints: lo.Map(srcNet.NetAccessPoints, func(src infrastructurev1beta2.OscNetAccessPointService, _ int) OscNetAccessPointService {
 			return OscNetAccessPointService(src)
 		}),
@@ -166,8 +279,21 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 				}),
 				IpSubnetRange: src.IpSubnetRange,
 				SubregionName: src.SubregionName,
+				Pool:          src.Pool,
 			}
 		}),
+		SubnetAllocation: OscSubnetAllocation(srcNet.SubnetAllocation),
+		SubnetLayout: OscSubnetLayout{
+			PublicPrefixLength:       srcNet.SubnetLayout.PublicPrefixLength,
+			ControlPlanePrefixLength: srcNet.SubnetLayout.ControlPlanePrefixLength,
+			WorkerPrefixLength:       srcNet.SubnetLayout.WorkerPrefixLength,
+			SeparateNatSubnets:       srcNet.SubnetLayout.SeparateNatSubnets,
+			NatPrefixLength:          srcNet.SubnetLayout.NatPrefixLength,
+			Pools: lo.Map(srcNet.SubnetLayout.Pools, func(src infrastructurev1beta2.OscSubnetPool, _ int) OscSubnetPool {
+				return OscSubnetPool(src)
+			}),
+			ReservedIpRange: srcNet.SubnetLayout.ReservedIpRange,
+		},
 		InternetService: OscInternetService{
 			Name: srcNet.InternetService.Name,
 		},
@@ -175,15 +301,18 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 			Name:          srcNet.NatService.Name,
 			SubnetName:    srcNet.NatService.SubnetName,
 			SubregionName: srcNet.NatService.SubregionName,
+			PublicIpId:    srcNet.NatService.PublicIpId,
 		},
 		NatServices: lo.Map(srcNet.NatServices, func(src infrastructurev1beta2.OscNatService, _ int) OscNatService {
 			return OscNatService{
 				Name:          src.Name,
 				SubnetName:    src.SubnetName,
 				SubregionName: src.SubregionName,
+				PublicIpId:    src.PublicIpId,
 			}
 		}),
 		NatPublicIpPool: srcNet.NatPublicIpPool,
+		NatFailover:     OscNatFailover(srcNet.NatFailover),
 		RouteTables: lo.Map(srcNet.RouteTables, func(src infrastructurev1beta2.OscRouteTable, _ int) OscRouteTable {
 			return OscRouteTable{
 				Name:          src.Name,
@@ -193,6 +322,7 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 				Routes: lo.Map(src.Routes, func(src infrastructurev1beta2.OscRoute, _ int) OscRoute {
 					return OscRoute(src)
 				}),
+				Authoritative: src.Authoritative,
 			}
 		}),
 		SecurityGroups: lo.Map(srcNet.SecurityGroups, func(src infrastructurev1beta2.OscSecurityGroup, _ int) OscSecurityGroup {
@@ -200,7 +330,7 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 				Name:        src.Name,
 				Description: src.Description,
 				SecurityGroupRules: lo.Map(src.SecurityGroupRules, func(src infrastructurev1beta2.OscSecurityGroupRule, _ int) OscSecurityGroupRule {
-					return OscSecurityGroupRule(src)
+					return convertSecurityGroupRuleFrom(src)
 				}),
 			}
 		}),
@@ -210,10 +340,12 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 					return OscRole(src)
 				}),
 				Rules: lo.Map(src.Rules, func(src infrastructurev1beta2.OscSecurityGroupRule, _ int) OscSecurityGroupRule {
-					return OscSecurityGroupRule(src)
+					return convertSecurityGroupRuleFrom(src)
 				}),
 			}
 		}),
+		Cni:           OscCni(srcNet.Cni),
+		CniProfileRef: srcNet.CniProfileRef,
 		Bastion: OscBastion{
 			Name:           srcNet.Bastion.Name,
 			ImageId:        srcNet.Bastion.ImageId,
@@ -233,6 +365,20 @@ func (dst *OscClusterSpec) ConvertFrom(src *infrastructurev1beta2.OscClusterSpec
 		Subregions:        srcNet.Subregions,
 		AllowFromIPRanges: srcNet.AllowFromIPRanges, // The list of IP ranges (in CIDR notation) the nodes can talk to ("0.0.0.0/0" if not set).
 		AllowToIPRanges:   srcNet.AllowToIPRanges,
+		EgressRules: lo.Map(srcNet.EgressRules, func(src infrastructurev1beta2.OscEgressRule, _ int) OscEgressRule {
+			return OscEgressRule{
+				Roles: lo.Map(src.Roles, func(src infrastructurev1beta2.OscRole, _ int) OscRole {
+					return OscRole(src)
+				}),
+				IpProtocol:    src.IpProtocol,
+				FromPortRange: src.FromPortRange,
+				ToPortRange:   src.ToPortRange,
+				IpRanges:      src.IpRanges,
+				Destinations: lo.Map(src.Destinations, func(src infrastructurev1beta2.OscEgressDestination, _ int) OscEgressDestination {
+					return OscEgressDestination(src)
+				}),
+			}
+		}),
 		ReconciliationRules: lo.Map(srcNet.ReconciliationRules, func(src infrastructurev1beta2.OscReconciliationRule, _ int) OscReconciliationRule {
 			return OscReconciliationRule{
 				AppliesTo: lo.Map(src.AppliesTo, func(src infrastructurev1beta2.Reconciler, _ int) Reconciler {
@@ -279,3 +425,39 @@ func (dst *OscCluster) ConvertFrom(srcRaw conversion.Hub) error {
 }
 
 var _ conversion.Convertible = (*OscCluster)(nil)
+
+func convertSecurityGroupRuleTo(src OscSecurityGroupRule) infrastructurev1beta2.OscSecurityGroupRule {
+	return infrastructurev1beta2.OscSecurityGroupRule{
+		Name:               src.Name,
+		Flow:               src.Flow,
+		IpProtocol:         src.IpProtocol,
+		IpRange:            src.IpRange,
+		IpRanges:           src.IpRanges,
+		SecurityGroupNames: src.SecurityGroupNames,
+		SecurityGroupRoles: lo.Map(src.SecurityGroupRoles, func(src OscRole, _ int) infrastructurev1beta2.OscRole {
+			return infrastructurev1beta2.OscRole(src)
+		}),
+		SecurityGroupIds: src.SecurityGroupIds,
+		FromPortRange:    src.FromPortRange,
+		ToPortRange:      src.ToPortRange,
+		ResourceId:       src.ResourceId,
+	}
+}
+
+func convertSecurityGroupRuleFrom(src infrastructurev1beta2.OscSecurityGroupRule) OscSecurityGroupRule {
+	return OscSecurityGroupRule{
+		Name:               src.Name,
+		Flow:               src.Flow,
+		IpProtocol:         src.IpProtocol,
+		IpRange:            src.IpRange,
+		IpRanges:           src.IpRanges,
+		SecurityGroupNames: src.SecurityGroupNames,
+		SecurityGroupRoles: lo.Map(src.SecurityGroupRoles, func(src infrastructurev1beta2.OscRole, _ int) OscRole {
+			return OscRole(src)
+		}),
+		SecurityGroupIds: src.SecurityGroupIds,
+		FromPortRange:    src.FromPortRange,
+		ToPortRange:      src.ToPortRange,
+		ResourceId:       src.ResourceId,
+	}
+}
@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package v1beta1
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"net/netip"
 	"regexp"
 	"slices"
 
+	"github.com/outscale/cluster-api-provider-outscale/util/cidr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -34,24 +37,37 @@ func ValidateOscClusterSpec(spec OscClusterSpec) field.ErrorList {
 	lbDisabled := slices.Contains(spec.Network.Disable, DisableLB)
 
 	allErrs = append(allErrs, ValidateLoadbalancer(spec.Network.LoadBalancer, lbDisabled)...)
+	allErrs = append(allErrs, ValidateAdditionalLoadBalancers(spec.Network.AdditionalLoadBalancers, spec.Network.LoadBalancer)...)
 	allErrs = append(allErrs, ValidateNet(spec.Network.Net, spec.Network.UseExisting)...)
+	allErrs = append(allErrs, ValidateDhcpOptions(spec.Network.Net.DhcpOptions, spec.Network.UseExisting)...)
 	allErrs = append(allErrs, ValidateSubnets(spec.Network.Subnets, spec.Network.Net, spec.Network.UseExisting)...)
+	allErrs = append(allErrs, ValidateSubnetLayout(spec.Network)...)
+	allErrs = append(allErrs, ValidatePeerings(spec.Network.Peerings, spec.Network.UseExisting)...)
+	allErrs = append(allErrs, ValidateVpn(spec.Network.Vpn, spec.Network.UseExisting)...)
+	allErrs = append(allErrs, ValidateRouteTables(spec.Network.RouteTables)...)
 	allErrs = append(allErrs, ValidateNatServices(spec.Network.NatServices, spec.Network.Subnets, spec.Network.Net, spec.Network.UseExisting)...)
 	allErrs = append(allErrs, ValidateSecurityGroups(spec.Network.SecurityGroups, spec.Network.Net, spec.Network.UseExisting)...)
+	allErrs = append(allErrs, ValidateCni(spec.Network)...)
 	allErrs = append(allErrs, ValidateAllowFromIPs(spec.Network.AllowFromIPRanges)...)
+	allErrs = append(allErrs, ValidateEgressRules(spec.Network.EgressRules, spec.Network.SecurityGroups)...)
 	return allErrs
 }
 
 func ValidateNet(spec OscNet, reuse OscReuse) field.ErrorList {
 	switch {
-	case spec == OscNet{}:
+	case spec.Name == "" && spec.ClusterName == "" && !spec.HasIpRange():
 		return nil
 	case reuse.Net:
 		return MergeValidation(
+			ValidateEmpty(field.NewPath("network", "net", "netPool"), spec.NetPool, "must not be set when reusing a network"),
 			ValidateRequired(field.NewPath("network", "net", "resourceId"), spec.ResourceId, "must be set when reusing a network"),
 			ValidateRequired(field.NewPath("network", "net", "ipRange"), spec.IpRange, "must be set when reusing a network"),
 			ValidateCidr(field.NewPath("network", "net", "ipRange"), spec.IpRange),
 		)
+	case spec.NetPool != "":
+		return MergeValidation(
+			Optional(ValidateCidr(field.NewPath("network", "net", "ipRange"), spec.IpRange)),
+		)
 	default:
 		return MergeValidation(
 			ValidateRequired(field.NewPath("network", "net", "ipRange"), spec.IpRange, "must be set when not reusing a network"),
@@ -60,8 +76,31 @@ func ValidateNet(spec OscNet, reuse OscReuse) field.ErrorList {
 	}
 }
 
+// ValidateDhcpOptions checks that DHCP servers are IP addresses, and that DHCP options are not set on an existing net.
+func ValidateDhcpOptions(spec OscDhcpOptions, reuse OscReuse) field.ErrorList {
+	p := field.NewPath("network", "net", "dhcpOptions")
+	if reuse.Net && !spec.IsZero() {
+		return field.ErrorList{field.Forbidden(p, "must not be set when reusing a network")}
+	}
+	var erl field.ErrorList
+	for _, ip := range spec.DomainNameServers {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			erl = append(erl, field.Invalid(p.Child("domainNameServers"), ip, "invalid IP address"))
+		}
+	}
+	for _, ip := range spec.NtpServers {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			erl = append(erl, field.Invalid(p.Child("ntpServers"), ip, "invalid IP address"))
+		}
+	}
+	return erl
+}
+
 func ValidateSubnets(specs []OscSubnet, net OscNet, reuse OscReuse) field.ErrorList {
 	var erl field.ErrorList
+	if net.NetPool != "" && net.IpRange == "" {
+		erl = AppendValidation(erl, ValidateEmptySlice(field.NewPath("network", "subnets"), specs, "subnets must not be set when the net range is allocated from a net pool"))
+	}
 	for _, spec := range specs {
 		if reuse.Net {
 			erl = AppendValidation(erl,
@@ -78,6 +117,183 @@ func ValidateSubnets(specs []OscSubnet, net OscNet, reuse OscReuse) field.ErrorL
 	return erl
 }
 
+const (
+	minSubnetPrefixLength = 16
+	maxSubnetPrefixLength = 28
+)
+
+// ValidateSubnetLayout checks that the subnets of the layout fit in the net.
+func ValidateSubnetLayout(spec OscNetwork) field.ErrorList {
+	layout := spec.SubnetLayout
+	if layout.IsZero() {
+		return nil
+	}
+	p := field.NewPath("network", "subnetLayout")
+	erl := AppendValidation(nil,
+		ValidateEmptySlice(field.NewPath("network", "subnets"), spec.Subnets, "subnets must not be set with a subnet layout"),
+		Optional(ValidateRange(p.Child("publicPrefixLength"), layout.PublicPrefixLength, minSubnetPrefixLength, maxSubnetPrefixLength)),
+		Optional(ValidateRange(p.Child("controlPlanePrefixLength"), layout.ControlPlanePrefixLength, minSubnetPrefixLength, maxSubnetPrefixLength)),
+		Optional(ValidateRange(p.Child("workerPrefixLength"), layout.WorkerPrefixLength, minSubnetPrefixLength, maxSubnetPrefixLength)),
+		Optional(ValidateRange(p.Child("natPrefixLength"), layout.NatPrefixLength, minSubnetPrefixLength, maxSubnetPrefixLength)),
+	)
+	pools := map[string]bool{}
+	for _, pool := range layout.Pools {
+		erl = AppendValidation(erl,
+			ValidateRequired(p.Child("pools", "name"), pool.Name, "a pool name is required"),
+			Optional(ValidateRange(p.Child("pools", "prefixLength"), pool.PrefixLength, minSubnetPrefixLength, maxSubnetPrefixLength)),
+		)
+		if pools[pool.Name] {
+			erl = append(erl, field.Duplicate(p.Child("pools", "name"), pool.Name))
+		}
+		pools[pool.Name] = true
+	}
+	net := spec.Net
+	if net.IsZero() {
+		net = DefaultNet
+	}
+	parent, err := netip.ParsePrefix(net.IpRange)
+	if err != nil {
+		return erl
+	}
+	var reserved []netip.Prefix
+	if layout.ReservedIpRange != "" {
+		r, err := netip.ParsePrefix(layout.ReservedIpRange)
+		switch {
+		case err != nil:
+			erl = append(erl, field.Invalid(p.Child("reservedIpRange"), layout.ReservedIpRange, "invalid CIDR address"))
+		case r.Bits() < parent.Bits() || !parent.Contains(r.Addr()):
+			erl = append(erl, field.Invalid(p.Child("reservedIpRange"), layout.ReservedIpRange, "reserved range must be contained in net"))
+		default:
+			reserved = append(reserved, r)
+		}
+	}
+	if len(erl) > 0 {
+		return erl
+	}
+	if _, err := cidr.AllocateAll(parent, reserved, layoutPrefixLengths(spec)); err != nil {
+		erl = append(erl, field.Invalid(field.NewPath("network", "net", "ipRange"), net.IpRange, "the subnets of the layout do not fit in the net"))
+	}
+	return erl
+}
+
+// layoutPrefixLengths returns the prefix lengths of the subnets of the layout, in allocation order.
+func layoutPrefixLengths(spec OscNetwork) []int {
+	layout := spec.SubnetLayout
+	def := cmp.Or(spec.SubnetAllocation.PrefixLength, 24)
+	worker := cmp.Or(layout.WorkerPrefixLength, def)
+	perSubregion := []int32{cmp.Or(layout.PublicPrefixLength, def)}
+	if layout.SeparateNatSubnets {
+		perSubregion = append(perSubregion, cmp.Or(layout.NatPrefixLength, layout.PublicPrefixLength, def))
+	}
+	perSubregion = append(perSubregion, worker, cmp.Or(layout.ControlPlanePrefixLength, def))
+	subregions := max(len(spec.Subregions), 1)
+	bits := make([]int, 0, (len(perSubregion)+len(layout.Pools))*subregions)
+	for range subregions {
+		for _, b := range perSubregion {
+			bits = append(bits, int(b))
+		}
+	}
+	for _, pool := range layout.Pools {
+		for range subregions {
+			bits = append(bits, int(cmp.Or(pool.PrefixLength, worker)))
+		}
+	}
+	return bits
+}
+
+// ValidatePeerings checks that peerings are uniquely named and target a net.
+func ValidatePeerings(specs []OscPeering, reuse OscReuse) field.ErrorList {
+	p := field.NewPath("network", "peerings")
+	if reuse.Net {
+		return AppendValidation(nil, ValidateEmptySlice(p, specs, "peerings must not be set when reusing a network"))
+	}
+	var erl field.ErrorList
+	names := map[string]bool{}
+	for _, spec := range specs {
+		erl = AppendValidation(erl,
+			ValidateRequired(p.Child("name"), spec.Name, "a peering name is required"),
+			ValidateRequired(p.Child("accountId"), spec.AccountID, "the peer account is required"),
+			ValidateRequired(p.Child("netId"), spec.NetID, "the peer net is required"),
+		)
+		switch {
+		case spec.Name == "default":
+			erl = append(erl, field.Invalid(p.Child("name"), spec.Name, "default is reserved for the management netPeering"))
+		case names[spec.Name]:
+			erl = append(erl, field.Duplicate(p.Child("name"), spec.Name))
+		}
+		names[spec.Name] = true
+	}
+	return erl
+}
+
+// ValidateVpn checks that VPN connections are uniquely named, and have a valid client gateway IP and static routes.
+func ValidateVpn(spec OscVpn, reuse OscReuse) field.ErrorList {
+	p := field.NewPath("network", "vpn")
+	switch {
+	case !spec.Enable:
+		return MergeValidation(
+			ValidateEmpty(p.Child("virtualGatewayId"), spec.VirtualGatewayID, "must not be set when the vpn is not enabled"),
+			ValidateEmptySlice(p.Child("connections"), spec.Connections, "must not be set when the vpn is not enabled"),
+		)
+	case reuse.Net:
+		return MergeValidation(field.Forbidden(p.Child("enable"), "vpn must not be enabled when reusing a network"))
+	}
+	var erl field.ErrorList
+	names := map[string]bool{}
+	for _, conn := range spec.Connections {
+		erl = AppendValidation(erl,
+			ValidateRequired(p.Child("connections", "name"), conn.Name, "a vpn connection name is required"),
+			ValidateRequiredSlice(p.Child("connections", "staticRoutes"), conn.StaticRoutes, "at least one static route is required"),
+		)
+		if names[conn.Name] {
+			erl = append(erl, field.Duplicate(p.Child("connections", "name"), conn.Name))
+		}
+		names[conn.Name] = true
+		if _, err := netip.ParseAddr(conn.ClientGatewayIP); err != nil {
+			erl = append(erl, field.Invalid(p.Child("connections", "clientGatewayIp"), conn.ClientGatewayIP, "invalid IP address"))
+		}
+		for _, ipRange := range conn.StaticRoutes {
+			erl = AppendValidation(erl, ValidateCidr(p.Child("connections", "staticRoutes"), ipRange))
+		}
+	}
+	return erl
+}
+
+// ValidateCni checks that a ConfigMap is set for custom CNIs only.
+func ValidateCni(spec OscNetwork) field.ErrorList {
+	p := field.NewPath("network", "cniProfileRef")
+	if spec.Cni != CniCustom {
+		if spec.CniProfileRef != nil {
+			return MergeValidation(field.Forbidden(p, "must only be set when cni is custom"))
+		}
+		return nil
+	}
+	if spec.CniProfileRef == nil {
+		return MergeValidation(field.Required(p, "must be set when cni is custom"))
+	}
+	return MergeValidation(ValidateRequired(p.Child("name"), spec.CniProfileRef.Name, "a ConfigMap name is required"))
+}
+
+// ValidateRouteTables checks that routes have a valid target.
+func ValidateRouteTables(specs []OscRouteTable) field.ErrorList {
+	p := field.NewPath("network", "routeTables", "routes")
+	var erl field.ErrorList
+	for _, spec := range specs {
+		for _, route := range spec.Routes {
+			switch route.TargetType {
+			case "gateway", "nat", "nat-service":
+				erl = AppendValidation(erl, ValidateEmpty(p.Child("targetId"), route.TargetId, "must not be set for gateway and nat targets"))
+			case "vm", "nic", "netPeering", "virtualGateway":
+				erl = AppendValidation(erl, ValidateRequired(p.Child("targetId"), route.TargetId, "a target ID is required"))
+			default:
+				erl = append(erl, field.NotSupported(p.Child("targetType"), route.TargetType, []string{"gateway", "nat", "nat-service", "vm", "nic", "netPeering", "virtualGateway"}))
+			}
+			erl = AppendValidation(erl, ValidateCidr(p.Child("destination"), route.Destination))
+		}
+	}
+	return erl
+}
+
 func ValidateNatServices(specs []OscNatService, subnets []OscSubnet, net OscNet, reuse OscReuse) field.ErrorList {
 	var erl field.ErrorList
 	if reuse.Net {
@@ -111,6 +327,13 @@ func ValidateSecurityGroups(specs []OscSecurityGroup, net OscNet, reuse OscReuse
 				),
 			)
 			erl = AppendValidation(erl, ValidateSecurityGroupRules(spec.SecurityGroupRules)...)
+			for _, rule := range spec.SecurityGroupRules {
+				for _, name := range rule.SecurityGroupNames {
+					if !slices.ContainsFunc(specs, func(sg OscSecurityGroup) bool { return sg.Name == name }) {
+						erl = append(erl, field.NotFound(field.NewPath("network", "securityGroups", "securityGroupRules", "securityGroupNames"), name))
+					}
+				}
+			}
 		}
 	}
 	return erl
@@ -123,8 +346,11 @@ func ValidateSecurityGroupRules(specs []OscSecurityGroupRule) field.ErrorList {
 			ValidateFlow(field.NewPath("network", "securityGroups", "securityGroupRules", "flow"), spec.Flow),
 			ValidateIpProtocol(field.NewPath("network", "securityGroups", "securityGroupRules", "ipProtocol"), spec.IpProtocol),
 			Or(
-				ValidateRequired(field.NewPath("network", "securityGroups", "securityGroupRules", "ipRange"), spec.IpRange, "ipRange or ipRanges must be set"),
-				ValidateRequiredSlice(field.NewPath("network", "securityGroups", "securityGroupRules", "ipRanges"), spec.IpRanges, "ipRange or ipRanges must be set"),
+				ValidateRequired(field.NewPath("network", "securityGroups", "securityGroupRules", "ipRange"), spec.IpRange, "ipRange, ipRanges or security groups must be set"),
+				ValidateRequiredSlice(field.NewPath("network", "securityGroups", "securityGroupRules", "ipRanges"), spec.IpRanges, "ipRange, ipRanges or security groups must be set"),
+				ValidateRequiredSlice(field.NewPath("network", "securityGroups", "securityGroupRules", "securityGroupNames"), spec.SecurityGroupNames, "ipRange, ipRanges or security groups must be set"),
+				ValidateRequiredSlice(field.NewPath("network", "securityGroups", "securityGroupRules", "securityGroupRoles"), spec.SecurityGroupRoles, "ipRange, ipRanges or security groups must be set"),
+				ValidateRequiredSlice(field.NewPath("network", "securityGroups", "securityGroupRules", "securityGroupIds"), spec.SecurityGroupIds, "ipRange, ipRanges or security groups must be set"),
 			),
 			ValidateRange(field.NewPath("network", "securityGroups", "securityGroupRules", "fromPortRange"), spec.FromPortRange, minPort, maxPort),
 			ValidateRange(field.NewPath("network", "securityGroups", "securityGroupRules", "toPortRange"), spec.ToPortRange, minPort, maxPort),
@@ -147,6 +373,50 @@ func ValidateSecurityGroupRules(specs []OscSecurityGroupRule) field.ErrorList {
 	return erl
 }
 
+// ValidateEgressRules checks egress roles, protocols and destinations.
+// With manual security groups, egress rules are only added to security groups dedicated to a single role, which must exist for all node roles.
+func ValidateEgressRules(specs []OscEgressRule, sgs []OscSecurityGroup) field.ErrorList {
+	p := field.NewPath("network", "egressRules")
+	var erl field.ErrorList
+	if len(specs) > 0 {
+		dedicated := map[OscRole]bool{}
+		for _, sg := range sgs {
+			if len(sg.Roles) == 1 {
+				dedicated[sg.Roles[0]] = true
+			}
+		}
+		for _, role := range []OscRole{RoleControlPlane, RoleWorker, RoleBastion} {
+			used := slices.ContainsFunc(sgs, func(sg OscSecurityGroup) bool { return slices.Contains(sg.Roles, role) })
+			if used && !dedicated[role] {
+				erl = append(erl, field.Required(field.NewPath("network", "securityGroups", "roles"),
+					fmt.Sprintf("a security group dedicated to the %s role is required with egress rules", role)))
+			}
+		}
+	}
+	for _, spec := range specs {
+		erl = AppendValidation(erl, ValidateRequiredSlice(p.Child("roles"), spec.Roles, "at least one role is required"))
+		for _, role := range spec.Roles {
+			switch role {
+			case RoleControlPlane, RoleWorker, RoleBastion:
+			default:
+				erl = append(erl, field.NotSupported(p.Child("roles"), role, []OscRole{RoleControlPlane, RoleWorker, RoleBastion}))
+			}
+		}
+		if spec.IpProtocol != "" && spec.IpProtocol != "-1" {
+			erl = AppendValidation(erl,
+				ValidateIpProtocol(p.Child("ipProtocol"), spec.IpProtocol),
+				ValidateRange(p.Child("fromPortRange"), spec.FromPortRange, minPort, maxPort),
+				ValidateRange(p.Child("toPortRange"), spec.ToPortRange, minPort, maxPort),
+				ValidatePortRange(p.Child("toPortRange"), spec.FromPortRange, spec.ToPortRange, "toPortRange must be >= fromPortRange"),
+			)
+		}
+		for _, ipRange := range spec.IpRanges {
+			erl = AppendValidation(erl, ValidateCidr(p.Child("ipRanges"), ipRange))
+		}
+	}
+	return erl
+}
+
 func ValidateLoadbalancer(spec OscLoadBalancer, lbDisabled bool) field.ErrorList {
 	var erl field.ErrorList
 	if lbDisabled {
@@ -155,22 +425,122 @@ func ValidateLoadbalancer(spec OscLoadBalancer, lbDisabled bool) field.ErrorList
 	erl = AppendValidation(erl,
 		ValidateLoadBalancerName(field.NewPath("network", "loadBalancer", "loadbalancername"), spec.LoadBalancerName),
 		Optional(ValidateLoadBalancerType(field.NewPath("network", "loadBalancer", "loadbalancertype"), spec.LoadBalancerType)),
-
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "listener", "loadbalancerport"), spec.Listener.LoadBalancerPort, minPort, maxPort)),
-		Optional(ValidateProtocol(field.NewPath("network", "loadBalancer", "listener", "loadbalancerprotocol"), spec.Listener.LoadBalancerProtocol)),
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "listener", "backendport"), spec.Listener.BackendPort, minPort, maxPort)),
-		Optional(ValidateProtocol(field.NewPath("network", "loadBalancer", "listener", "backendprotocol"), spec.Listener.BackendProtocol)),
-
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "healthCheck", "checkinterval"), spec.HealthCheck.CheckInterval, minInterval, maxInterval)),
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "healthCheck", "port"), spec.HealthCheck.Port, minPort, maxPort)),
-		Optional(ValidateProtocol(field.NewPath("network", "loadBalancer", "healthCheck", "protocol"), spec.HealthCheck.Protocol)),
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "healthCheck", "timeout"), spec.HealthCheck.Timeout, minTimeout, maxTimeout)),
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "healthCheck", "healthythreshold"), spec.HealthCheck.HealthyThreshold, minThreshold, maxThreshold)),
-		Optional(ValidateRange(field.NewPath("network", "loadBalancer", "healthCheck", "unhealthythreshold"), spec.HealthCheck.UnhealthyThreshold, minThreshold, maxThreshold)),
 	)
+	erl = AppendValidation(erl, ValidateListener(field.NewPath("network", "loadBalancer", "listener"), spec.Listener)...)
+	erl = AppendValidation(erl, ValidateHealthCheck(field.NewPath("network", "loadBalancer", "healthCheck"), spec.HealthCheck)...)
+	erl = AppendValidation(erl, ValidateAccessLog(field.NewPath("network", "loadBalancer", "accessLog"), spec.AccessLog)...)
+	switch {
+	case spec.PublicIp && spec.LoadBalancerType == "internal":
+		erl = append(erl, field.Invalid(field.NewPath("network", "loadBalancer", "publicIp"), spec.PublicIp, "public IPs are only available on internet-facing loadBalancers"))
+	case !spec.PublicIp && spec.PublicIpPool != "":
+		erl = append(erl, field.Invalid(field.NewPath("network", "loadBalancer", "publicIpPool"), spec.PublicIpPool, "publicIp must be set"))
+	}
+	ports := map[int32]bool{cmp.Or(spec.Listener.LoadBalancerPort, APIPort): true}
+	p := field.NewPath("network", "loadBalancer", "additionalListeners")
+	for _, listener := range spec.AdditionalListeners {
+		erl = AppendValidation(erl, ValidateListener(p, listener)...)
+		switch {
+		case listener.LoadBalancerPort == 0:
+			erl = append(erl, field.Required(p.Child("loadbalancerport"), "the loadbalancer port is required"))
+		case ports[listener.LoadBalancerPort]:
+			erl = append(erl, field.Duplicate(p.Child("loadbalancerport"), listener.LoadBalancerPort))
+		}
+		ports[listener.LoadBalancerPort] = true
+	}
 	return erl
 }
 
+// ValidateAdditionalLoadBalancers checks that additional loadBalancers are uniquely named, have backends and listeners,
+// and that at most one is used as control plane endpoint.
+func ValidateAdditionalLoadBalancers(specs []OscAdditionalLoadBalancer, lb OscLoadBalancer) field.ErrorList {
+	var erl field.ErrorList
+	p := field.NewPath("network", "additionalLoadBalancers")
+	names := map[string]bool{lb.LoadBalancerName: true}
+	hasEndpoint := false
+	for _, spec := range specs {
+		erl = AppendValidation(erl,
+			ValidateLoadBalancerName(p.Child("loadbalancername"), spec.LoadBalancerName),
+			Optional(ValidateLoadBalancerType(p.Child("loadbalancertype"), spec.LoadBalancerType)),
+		)
+		if names[spec.LoadBalancerName] {
+			erl = append(erl, field.Duplicate(p.Child("loadbalancername"), spec.LoadBalancerName))
+		}
+		names[spec.LoadBalancerName] = true
+		switch spec.BackendRole {
+		case RoleControlPlane, RoleWorker:
+		default:
+			erl = append(erl, field.NotSupported(p.Child("backendRole"), spec.BackendRole, []OscRole{RoleControlPlane, RoleWorker}))
+		}
+		erl = AppendValidation(erl, ValidateRequiredSlice(p.Child("listeners"), spec.Listeners, "at least one listener is required"))
+		ports := map[int32]bool{}
+		for _, listener := range spec.Listeners {
+			erl = AppendValidation(erl, ValidateListener(p.Child("listeners"), listener)...)
+			switch {
+			case listener.LoadBalancerPort == 0:
+				erl = append(erl, field.Required(p.Child("listeners", "loadbalancerport"), "the loadbalancer port is required"))
+			case ports[listener.LoadBalancerPort]:
+				erl = append(erl, field.Duplicate(p.Child("listeners", "loadbalancerport"), listener.LoadBalancerPort))
+			}
+			ports[listener.LoadBalancerPort] = true
+		}
+		erl = AppendValidation(erl, ValidateHealthCheck(p.Child("healthCheck"), spec.HealthCheck)...)
+		if spec.UseAsControlPlaneEndpoint {
+			switch {
+			case spec.BackendRole != RoleControlPlane:
+				erl = append(erl, field.Invalid(p.Child("useAsControlPlaneEndpoint"), spec.UseAsControlPlaneEndpoint, "the backendRole must be controlplane"))
+			case hasEndpoint:
+				erl = append(erl, field.Forbidden(p.Child("useAsControlPlaneEndpoint"), "only one loadBalancer may be used as control plane endpoint"))
+			}
+			hasEndpoint = true
+		}
+	}
+	return erl
+}
+
+// ValidateListener checks the ports and protocols of a listener.
+func ValidateListener(p *field.Path, spec OscLoadBalancerListener) []*field.Error {
+	if spec.ServerCertificateSecretName != "" {
+		return []*field.Error{
+			Optional(ValidateRange(p.Child("loadbalancerport"), spec.LoadBalancerPort, minPort, maxPort)),
+			ValidateSecureProtocol(p.Child("loadbalancerprotocol"), spec.LoadBalancerProtocol),
+			Optional(ValidateRange(p.Child("backendport"), spec.BackendPort, minPort, maxPort)),
+			ValidateSecureBackendProtocol(p.Child("backendprotocol"), spec.LoadBalancerProtocol, spec.BackendProtocol),
+		}
+	}
+	return []*field.Error{
+		Optional(ValidateRange(p.Child("loadbalancerport"), spec.LoadBalancerPort, minPort, maxPort)),
+		Optional(ValidateProtocol(p.Child("loadbalancerprotocol"), spec.LoadBalancerProtocol)),
+		Optional(ValidateRange(p.Child("backendport"), spec.BackendPort, minPort, maxPort)),
+		Optional(ValidateProtocol(p.Child("backendprotocol"), spec.BackendProtocol)),
+	}
+}
+
+// ValidateAccessLog checks the access log configuration of a loadBalancer.
+func ValidateAccessLog(p *field.Path, spec OscLoadBalancerAccessLog) []*field.Error {
+	var errs []*field.Error
+	if spec.Bucket == "" && (spec.Prefix != "" || spec.Interval != 0) {
+		errs = append(errs, field.Required(p.Child("bucket"), "the bucket is required"))
+	}
+	switch spec.Interval {
+	case 0, 5, 60:
+	default:
+		errs = append(errs, field.Invalid(p.Child("interval"), spec.Interval, "must be 5 or 60"))
+	}
+	return errs
+}
+
+// ValidateHealthCheck checks the values of a healthCheck.
+func ValidateHealthCheck(p *field.Path, spec OscLoadBalancerHealthCheck) []*field.Error {
+	return []*field.Error{
+		Optional(ValidateRange(p.Child("checkinterval"), spec.CheckInterval, minInterval, maxInterval)),
+		Optional(ValidateRange(p.Child("port"), spec.Port, minPort, maxPort)),
+		Optional(ValidateProtocol(p.Child("protocol"), spec.Protocol)),
+		Optional(ValidateRange(p.Child("timeout"), spec.Timeout, minTimeout, maxTimeout)),
+		Optional(ValidateRange(p.Child("healthythreshold"), spec.HealthyThreshold, minThreshold, maxThreshold)),
+		Optional(ValidateRange(p.Child("unhealthythreshold"), spec.UnhealthyThreshold, minThreshold, maxThreshold)),
+	}
+}
+
 func ValidateAllowFromIPs(ips []string) field.ErrorList {
 	var erl field.ErrorList
 	for _, ip := range ips {
@@ -283,7 +653,7 @@ func ValidatePortRange(p *field.Path, from, to int32, msg string) *field.Error {
 
 // ValidateEmptyLoadBalancer checks that the loadBalancerName is a valid name of load balancer
 func ValidateEmptyLoadBalancer(p *field.Path, spec OscLoadBalancer) *field.Error {
-	if spec != (OscLoadBalancer{}) {
+	if !equality.Semantic.DeepEqual(spec, OscLoadBalancer{}) {
 		return field.Forbidden(p, "loadBalancer must be empty when disabled")
 	}
 	return nil
@@ -322,8 +692,32 @@ func ValidateProtocol(p *field.Path, protocol string) *field.Error {
 	case "HTTP", "TCP":
 		return nil
 	case "SSL", "HTTPS":
-		return field.Invalid(p, protocol, "SSL certificate is required")
+		return field.Invalid(p, protocol, "serverCertificateSecretName is required")
 	default:
 		return field.Invalid(p, protocol, "only HTTP and TCP are supported")
 	}
 }
+
+// ValidateSecureProtocol checks the protocol of a listener having a server certificate.
+func ValidateSecureProtocol(p *field.Path, protocol string) *field.Error {
+	switch protocol {
+	case "HTTPS", "SSL":
+		return nil
+	default:
+		return field.NotSupported(p, protocol, []string{"HTTPS", "SSL"})
+	}
+}
+
+// ValidateSecureBackendProtocol checks that the backend protocol of a listener having a server certificate matches its protocol.
+func ValidateSecureBackendProtocol(p *field.Path, protocol, backendProtocol string) *field.Error {
+	switch {
+	case backendProtocol == "":
+		return nil
+	case protocol == "HTTPS" && (backendProtocol == "HTTP" || backendProtocol == "HTTPS"):
+		return nil
+	case protocol == "SSL" && (backendProtocol == "TCP" || backendProtocol == "SSL"):
+		return nil
+	default:
+		return field.Invalid(p, backendProtocol, "backend protocol does not match the "+protocol+" protocol")
+	}
+}
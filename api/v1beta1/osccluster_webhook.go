@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"slices"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -75,6 +76,58 @@ func (OscClusterWebhook) ValidateUpdate(_ context.Context, obj runtime.Object, o
 					r.Spec.Network.LoadBalancer.LoadBalancerType, "field is immutable"),
 			)
 		}
+		if r.Spec.Network.LoadBalancer.SubnetName != old.Spec.Network.LoadBalancer.SubnetName {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("network", "loadBalancer", "subnetname"),
+					r.Spec.Network.LoadBalancer.SubnetName, "field is immutable"),
+			)
+		}
+		if r.Spec.Network.LoadBalancer.PublicIp != old.Spec.Network.LoadBalancer.PublicIp ||
+			r.Spec.Network.LoadBalancer.PublicIpPool != old.Spec.Network.LoadBalancer.PublicIpPool {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("network", "loadBalancer", "publicIp"),
+					r.Spec.Network.LoadBalancer.PublicIp, "field is immutable"),
+			)
+		}
+		// The loadBalancer port is the port of the control plane endpoint.
+		lb, oldLb := r.Spec.Network.LoadBalancer, old.Spec.Network.LoadBalancer
+		lb.SetDefaultValue()
+		oldLb.SetDefaultValue()
+		if lb.Listener.LoadBalancerPort != oldLb.Listener.LoadBalancerPort {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("network", "loadBalancer", "listener", "loadbalancerport"),
+					r.Spec.Network.LoadBalancer.Listener.LoadBalancerPort, "field is immutable"),
+			)
+		}
+	}
+	for _, alb := range r.Spec.Network.AdditionalLoadBalancers {
+		for _, oldAlb := range old.Spec.Network.AdditionalLoadBalancers {
+			if alb.LoadBalancerName == oldAlb.LoadBalancerName && alb.LoadBalancerType != oldAlb.LoadBalancerType {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("network", "additionalLoadBalancers", "loadbalancertype"),
+						alb.LoadBalancerType, "field is immutable"),
+				)
+			}
+		}
+	}
+	// The control plane endpoint cannot be moved to another loadBalancer.
+	if r.Spec.Network.GetEndpointLoadBalancerName() != old.Spec.Network.GetEndpointLoadBalancerName() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("network", "additionalLoadBalancers", "useAsControlPlaneEndpoint"),
+				r.Spec.Network.GetEndpointLoadBalancerName(), "field is immutable"),
+		)
+	} else if port, oldPort := getEndpointLoadBalancerPort(&r.Spec.Network), getEndpointLoadBalancerPort(&old.Spec.Network); port != oldPort {
+		// The first listener of the endpoint loadBalancer is the port of the control plane endpoint.
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("network", "additionalLoadBalancers", "listeners", "loadbalancerport"),
+				port, "field is immutable on the loadBalancer used as control plane endpoint"),
+		)
+	}
+	if !subnetLayoutExtends(r.Spec.Network.SubnetLayout, old.Spec.Network.SubnetLayout) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("network", "subnetLayout"),
+				r.Spec.Network.SubnetLayout, "field is immutable, only pools may be added"),
+		)
 	}
 	if len(allErrs) == 0 {
 		return nil, nil
@@ -82,6 +135,26 @@ func (OscClusterWebhook) ValidateUpdate(_ context.Context, obj runtime.Object, o
 	return nil, apierrors.NewInvalid(GroupVersion.WithKind("OscCluster").GroupKind(), r.Name, allErrs)
 }
 
+// getEndpointLoadBalancerPort returns the port of the first listener of the additional loadBalancer used as control plane endpoint, or 0 if none.
+func getEndpointLoadBalancerPort(network *OscNetwork) int32 {
+	for _, alb := range network.AdditionalLoadBalancers {
+		if alb.UseAsControlPlaneEndpoint && len(alb.Listeners) > 0 {
+			return alb.Listeners[0].LoadBalancerPort
+		}
+	}
+	return 0
+}
+
+// subnetLayoutExtends returns true if layout is old, with optional additional pools.
+// Other changes would move the ranges of existing subnets.
+func subnetLayoutExtends(layout, old OscSubnetLayout) bool {
+	if len(layout.Pools) < len(old.Pools) || !slices.Equal(layout.Pools[:len(old.Pools)], old.Pools) {
+		return false
+	}
+	layout.Pools, old.Pools = nil, nil
+	return equality.Semantic.DeepEqual(layout, old)
+}
+
 // ValidateDelete implements webhook.CustomValidator.
 func (OscClusterWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
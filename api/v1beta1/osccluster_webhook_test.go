@@ -12,6 +12,7 @@ import (
 
 	infrastructurev1beta1 "github.com/outscale/cluster-api-provider-outscale/api/v1beta1"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -128,6 +129,593 @@ func TestOscCluster_ValidateCreate(t *testing.T) {
 			},
 			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.subnets.ipSubnetRange: Invalid value: \"10.0.1.0/24\": subnet overlaps 10.0.1.0/24"),
 		},
+		{
+			name: "subnet layout fitting in net",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						IpRange: "10.0.0.0/22",
+					},
+					Subregions: []string{"eu-west-2a", "eu-west-2b"},
+					SubnetLayout: infrastructurev1beta1.OscSubnetLayout{
+						PublicPrefixLength:       27,
+						ControlPlanePrefixLength: 27,
+						WorkerPrefixLength:       25,
+						SeparateNatSubnets:       true,
+						NatPrefixLength:          28,
+						Pools:                    []infrastructurev1beta1.OscSubnetPool{{Name: "gpu", PrefixLength: 26}},
+						ReservedIpRange:          "10.0.3.0/24",
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "subnet layout not fitting in net",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						IpRange: "10.0.0.0/22",
+					},
+					Subregions: []string{"eu-west-2a", "eu-west-2b"},
+					SubnetLayout: infrastructurev1beta1.OscSubnetLayout{
+						WorkerPrefixLength: 24,
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.net.ipRange: Invalid value: \"10.0.0.0/22\": the subnets of the layout do not fit in the net"),
+		},
+		{
+			name: "reserved range not within net",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						IpRange: "10.0.0.0/16",
+					},
+					SubnetLayout: infrastructurev1beta1.OscSubnetLayout{
+						ReservedIpRange: "10.1.0.0/24",
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.subnetLayout.reservedIpRange: Invalid value: \"10.1.0.0/24\": reserved range must be contained in net"),
+		},
+		{
+			name: "subnet layout with subnets",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						IpRange: "10.0.0.0/16",
+					},
+					Subnets: []infrastructurev1beta1.OscSubnet{{IpSubnetRange: "10.0.1.0/24"}},
+					SubnetLayout: infrastructurev1beta1.OscSubnetLayout{
+						Pools: []infrastructurev1beta1.OscSubnetPool{{Name: "gpu", PrefixLength: 30}},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.subnets: Forbidden: subnets must not be set with a subnet layout, network.subnetLayout.pools.prefixLength: Invalid value: 30: must be between 16 and 28]"),
+		},
+		{
+			name: "net range from a net pool",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						NetPool: "pool",
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "net pool with subnets",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						NetPool: "pool",
+					},
+					Subnets: []infrastructurev1beta1.OscSubnet{{IpSubnetRange: "10.0.1.0/24"}},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.subnets: Forbidden: subnets must not be set when the net range is allocated from a net pool"),
+		},
+		{
+			name: "net pool with an existing net",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						NetPool:    "pool",
+						ResourceId: "vpc-foo",
+						IpRange:    "10.0.0.0/16",
+					},
+					UseExisting: infrastructurev1beta1.OscReuse{Net: true},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.net.netPool: Forbidden: must not be set when reusing a network"),
+		},
+		{
+			name: "peerings",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Peerings: []infrastructurev1beta1.OscPeering{
+						{Name: "shared", AccountID: "123456789012", NetID: "vpc-shared"},
+						{Name: "logging", AccountID: "123456789012", NetID: "vpc-logging", AcceptPolicy: infrastructurev1beta1.PeeringAcceptManual},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid peerings",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Peerings: []infrastructurev1beta1.OscPeering{
+						{Name: "default", AccountID: "123456789012", NetID: "vpc-shared"},
+						{Name: "shared", AccountID: "123456789012"},
+						{Name: "shared", AccountID: "123456789012", NetID: "vpc-logging"},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.peerings.name: Invalid value: \"default\": default is reserved for the management netPeering, network.peerings.netId: Required value: the peer net is required, network.peerings.name: Duplicate value: \"shared\"]"),
+		},
+		{
+			name: "dhcp options",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						DhcpOptions: infrastructurev1beta1.OscDhcpOptions{
+							DomainName:        "corp.example.com",
+							DomainNameServers: []string{"10.1.0.53", "10.2.0.53"},
+							NtpServers:        []string{"10.1.0.123"},
+						},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid dhcp options",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						DhcpOptions: infrastructurev1beta1.OscDhcpOptions{
+							DomainNameServers: []string{"10.1.0"},
+							NtpServers:        []string{"ntp.example.com"},
+						},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.net.dhcpOptions.domainNameServers: Invalid value: \"10.1.0\": invalid IP address, network.net.dhcpOptions.ntpServers: Invalid value: \"ntp.example.com\": invalid IP address]"),
+		},
+		{
+			name: "dhcp options with an existing net",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Net: infrastructurev1beta1.OscNet{
+						ResourceId: "vpc-foo",
+						IpRange:    "10.0.0.0/16",
+						DhcpOptions: infrastructurev1beta1.OscDhcpOptions{
+							DomainName: "corp.example.com",
+						},
+					},
+					UseExisting: infrastructurev1beta1.OscReuse{Net: true},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.net.dhcpOptions: Forbidden: must not be set when reusing a network"),
+		},
+		{
+			name: "cni profile",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Cni: infrastructurev1beta1.CniCilium,
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "custom cni profile",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Cni:           infrastructurev1beta1.CniCustom,
+					CniProfileRef: &corev1.LocalObjectReference{Name: "cni-rules"},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "custom cni profile without ConfigMap",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Cni: infrastructurev1beta1.CniCustom,
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.cniProfileRef: Required value: must be set when cni is custom"),
+		},
+		{
+			name: "ConfigMap without custom cni",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Cni:           infrastructurev1beta1.CniCalico,
+					CniProfileRef: &corev1.LocalObjectReference{Name: "cni-rules"},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.cniProfileRef: Forbidden: must only be set when cni is custom"),
+		},
+		{
+			name: "egress rules",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					EgressRules: []infrastructurev1beta1.OscEgressRule{
+						{Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleWorker}, IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 443, IpRanges: []string{"198.51.100.0/24"}},
+						{Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleControlPlane}, Destinations: []infrastructurev1beta1.OscEgressDestination{infrastructurev1beta1.EgressDestinationAPI}},
+						{Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleBastion}},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "egress rules with a multi-role security group",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					EgressRules: []infrastructurev1beta1.OscEgressRule{
+						{Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleWorker}, IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 443, IpRanges: []string{"198.51.100.0/24"}},
+					},
+					SecurityGroups: []infrastructurev1beta1.OscSecurityGroup{
+						{Name: "node", Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleControlPlane, infrastructurev1beta1.RoleWorker}},
+						{Name: "worker", Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleWorker}},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.securityGroups.roles: Required value: a security group dedicated to the controlplane role is required with egress rules"),
+		},
+		{
+			name: "invalid egress rules",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					EgressRules: []infrastructurev1beta1.OscEgressRule{
+						{Roles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleLoadBalancer}, IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 80, IpRanges: []string{"198.51.100.0"}},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.egressRules.roles: Unsupported value: \"loadbalancer\": supported values: \"controlplane\", \"worker\", \"bastion\", network.egressRules.toPortRange: Invalid value: 80: toPortRange must be >= fromPortRange, network.egressRules.ipRanges: Invalid value: \"198.51.100.0\": invalid CIDR address]"),
+		},
+		{
+			name: "vpn",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Vpn: infrastructurev1beta1.OscVpn{
+						Enable: true,
+						Connections: []infrastructurev1beta1.OscVpnConnection{
+							{Name: "paris", ClientGatewayIP: "198.51.100.10", StaticRoutes: []string{"192.168.0.0/16"}},
+							{Name: "lyon", ClientGatewayIP: "198.51.100.20", BgpAsn: 65010, StaticRoutes: []string{"172.16.0.0/16", "172.17.0.0/16"}},
+						},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid vpn",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Vpn: infrastructurev1beta1.OscVpn{
+						Enable: true,
+						Connections: []infrastructurev1beta1.OscVpnConnection{
+							{Name: "paris", ClientGatewayIP: "198.51.100", StaticRoutes: []string{"192.168.0.0"}},
+							{Name: "paris", ClientGatewayIP: "198.51.100.20"},
+						},
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.vpn.connections.clientGatewayIp: Invalid value: \"198.51.100\": invalid IP address, network.vpn.connections.staticRoutes: Invalid value: \"192.168.0.0\": invalid CIDR address, network.vpn.connections.staticRoutes: Required value: at least one static route is required, network.vpn.connections.name: Duplicate value: \"paris\"]"),
+		},
+		{
+			name: "vpn connections without vpn",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					Vpn: infrastructurev1beta1.OscVpn{
+						VirtualGatewayID: "vgw-foo",
+					},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.vpn.virtualGatewayId: Forbidden: must not be set when the vpn is not enabled"),
+		},
+		{
+			name: "route targets",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					RouteTables: []infrastructurev1beta1.OscRouteTable{{
+						Role:          infrastructurev1beta1.RoleWorker,
+						SubregionName: "eu-west-2a",
+						Routes: []infrastructurev1beta1.OscRoute{
+							{TargetType: "vm", TargetId: "i-firewall", Destination: "0.0.0.0/0"},
+							{TargetType: "nic", TargetId: "eni-firewall", Destination: "10.1.0.0/16"},
+							{TargetType: "netPeering", TargetId: "pcx-foo", Destination: "10.2.0.0/16"},
+							{TargetType: "virtualGateway", TargetId: "vgw-foo", Destination: "192.168.0.0/16"},
+						},
+						Authoritative: true,
+					}},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid route targets",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					RouteTables: []infrastructurev1beta1.OscRouteTable{{
+						Role: infrastructurev1beta1.RoleWorker,
+						Routes: []infrastructurev1beta1.OscRoute{
+							{TargetType: "vm", Destination: "0.0.0.0/0"},
+							{TargetType: "gateway", TargetId: "igw-foo", Destination: "0.0.0.0/0"},
+							{TargetType: "firewall", Destination: "10.1.0.0"},
+						},
+					}},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.routeTables.routes.targetId: Required value: a target ID is required, network.routeTables.routes.targetId: Forbidden: must not be set for gateway and nat targets, network.routeTables.routes.targetType: Unsupported value: \"firewall\": supported values: \"gateway\", \"nat\", \"nat-service\", \"vm\", \"nic\", \"netPeering\", \"virtualGateway\", network.routeTables.routes.destination: Invalid value: \"10.1.0.0\": invalid CIDR address]"),
+		},
+		{
+			name: "security group rules referencing security groups",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					SecurityGroups: []infrastructurev1beta1.OscSecurityGroup{{
+						Name: "kw",
+						SecurityGroupRules: []infrastructurev1beta1.OscSecurityGroupRule{
+							{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupNames: []string{"kcp"}},
+							{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 9100, ToPortRange: 9100, SecurityGroupIds: []string{"sg-monitoring"}},
+						},
+					}, {
+						Name: "kcp",
+						SecurityGroupRules: []infrastructurev1beta1.OscSecurityGroupRule{
+							{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, SecurityGroupRoles: []infrastructurev1beta1.OscRole{infrastructurev1beta1.RoleWorker}},
+						},
+					}},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid security group references",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					SecurityGroups: []infrastructurev1beta1.OscSecurityGroup{{
+						Name: "kw",
+						SecurityGroupRules: []infrastructurev1beta1.OscSecurityGroupRule{
+							{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupNames: []string{"kcp"}},
+							{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 9100, ToPortRange: 9100},
+						},
+					}},
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.securityGroups.securityGroupRules.ipRange: Required value: ipRange, ipRanges or security groups must be set, network.securityGroups.securityGroupRules.securityGroupNames: Not found: \"kcp\"]"),
+		},
+		{
+			name: "additional listeners and loadBalancers",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						AdditionalListeners: []infrastructurev1beta1.OscLoadBalancerListener{
+							{LoadBalancerPort: 8132},
+						},
+					},
+					AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{{
+						LoadBalancerName: "foo-ingress",
+						BackendRole:      infrastructurev1beta1.RoleWorker,
+						Listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+							{LoadBalancerPort: 80, BackendPort: 30080},
+							{LoadBalancerPort: 443, BackendPort: 30443},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "internal API loadBalancer and public loadBalancer as control plane endpoint",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						LoadBalancerType: "internal",
+					},
+					AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{{
+						LoadBalancerName:          "foo-public",
+						BackendRole:               infrastructurev1beta1.RoleControlPlane,
+						Listeners:                 []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 6443}},
+						UseAsControlPlaneEndpoint: true,
+					}},
+				},
+			},
+		},
+		{
+			name: "invalid control plane endpoint loadBalancers",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+					},
+					AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{
+						{
+							LoadBalancerName:          "foo-public",
+							BackendRole:               infrastructurev1beta1.RoleControlPlane,
+							Listeners:                 []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 6443}},
+							UseAsControlPlaneEndpoint: true,
+						},
+						{
+							LoadBalancerName:          "foo-public2",
+							BackendRole:               infrastructurev1beta1.RoleWorker,
+							Listeners:                 []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 6443}},
+							UseAsControlPlaneEndpoint: true,
+						},
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.additionalLoadBalancers.useAsControlPlaneEndpoint: Invalid value: true: the backendRole must be controlplane"),
+		},
+		{
+			name: "public IP from a pool",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						PublicIp:         true,
+						PublicIpPool:     "pool-foo",
+					},
+				},
+			},
+		},
+		{
+			name: "public IP on an internal loadBalancer",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						LoadBalancerType: "internal",
+						PublicIp:         true,
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.loadBalancer.publicIp: Invalid value: true: public IPs are only available on internet-facing loadBalancers"),
+		},
+		{
+			name: "public IP pool without public IP",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						PublicIpPool:     "pool-foo",
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: network.loadBalancer.publicIpPool: Invalid value: \"pool-foo\": publicIp must be set"),
+		},
+		{
+			name: "access logs and HTTPS listener",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						AccessLog:        infrastructurev1beta1.OscLoadBalancerAccessLog{Bucket: "logs", Prefix: "foo", Interval: 5},
+						AdditionalListeners: []infrastructurev1beta1.OscLoadBalancerListener{
+							{LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS", BackendPort: 6443, ServerCertificateSecretName: "foo-tls"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid access logs and HTTPS listeners",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						AccessLog:        infrastructurev1beta1.OscLoadBalancerAccessLog{Prefix: "foo", Interval: 10},
+						AdditionalListeners: []infrastructurev1beta1.OscLoadBalancerListener{
+							{LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS", BackendPort: 6443},
+							{LoadBalancerPort: 8443, LoadBalancerProtocol: "TCP", BackendPort: 6443, ServerCertificateSecretName: "foo-tls"},
+							{LoadBalancerPort: 9443, LoadBalancerProtocol: "SSL", BackendProtocol: "HTTP", BackendPort: 6443, ServerCertificateSecretName: "foo-tls"},
+						},
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.loadBalancer.accessLog.bucket: Required value: the bucket is required, network.loadBalancer.accessLog.interval: Invalid value: 10: must be 5 or 60, network.loadBalancer.additionalListeners.loadbalancerprotocol: Invalid value: \"HTTPS\": serverCertificateSecretName is required, network.loadBalancer.additionalListeners.loadbalancerprotocol: Unsupported value: \"TCP\": supported values: \"HTTPS\", \"SSL\", network.loadBalancer.additionalListeners.backendprotocol: Invalid value: \"HTTP\": backend protocol does not match the SSL protocol]"),
+		},
+		{
+			name: "invalid additional listeners and loadBalancers",
+			clusterSpec: infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{
+					LoadBalancer: infrastructurev1beta1.OscLoadBalancer{
+						LoadBalancerName: "foo",
+						AdditionalListeners: []infrastructurev1beta1.OscLoadBalancerListener{
+							{LoadBalancerPort: 6443},
+							{BackendPort: 8132},
+						},
+					},
+					AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{
+						{
+							LoadBalancerName: "foo",
+							BackendRole:      infrastructurev1beta1.RoleBastion,
+							Listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+								{LoadBalancerPort: 80},
+							},
+						},
+						{
+							LoadBalancerName: "foo-ingress",
+							BackendRole:      infrastructurev1beta1.RoleWorker,
+						},
+					},
+				},
+			},
+			expValidateCreateErr: errors.New("OscCluster.infrastructure.cluster.x-k8s.io \"webhook-test\" is invalid: [network.loadBalancer.additionalListeners.loadbalancerport: Duplicate value: 6443, network.loadBalancer.additionalListeners.loadbalancerport: Required value: the loadbalancer port is required, network.additionalLoadBalancers.loadbalancername: Duplicate value: \"foo\", network.additionalLoadBalancers.backendRole: Unsupported value: \"bastion\": supported values: \"controlplane\", \"worker\", network.additionalLoadBalancers.listeners: Required value: at least one listener is required]"),
+		},
 	}
 	h := infrastructurev1beta1.OscClusterWebhook{}
 	for _, ctc := range clusterTestCases {
@@ -143,6 +731,246 @@ func TestOscCluster_ValidateCreate(t *testing.T) {
 	}
 }
 
+func TestOscCluster_ValidateUpdate(t *testing.T) {
+	layout := infrastructurev1beta1.OscSubnetLayout{
+		WorkerPrefixLength: 22,
+		Pools:              []infrastructurev1beta1.OscSubnetPool{{Name: "gpu"}},
+	}
+	clusterTestCases := []struct {
+		name                 string
+		layout               infrastructurev1beta1.OscSubnetLayout
+		expValidateUpdateErr bool
+	}{
+		{
+			name:   "unchanged subnet layout",
+			layout: layout,
+		},
+		{
+			name: "pool added to subnet layout",
+			layout: infrastructurev1beta1.OscSubnetLayout{
+				WorkerPrefixLength: 22,
+				Pools:              []infrastructurev1beta1.OscSubnetPool{{Name: "gpu"}, {Name: "batch"}},
+			},
+		},
+		{
+			name: "pool removed from subnet layout",
+			layout: infrastructurev1beta1.OscSubnetLayout{
+				WorkerPrefixLength: 22,
+			},
+			expValidateUpdateErr: true,
+		},
+		{
+			name: "prefix length changed in subnet layout",
+			layout: infrastructurev1beta1.OscSubnetLayout{
+				WorkerPrefixLength: 23,
+				Pools:              []infrastructurev1beta1.OscSubnetPool{{Name: "gpu"}},
+			},
+			expValidateUpdateErr: true,
+		},
+	}
+	h := infrastructurev1beta1.OscClusterWebhook{}
+	for _, ctc := range clusterTestCases {
+		t.Run(ctc.name, func(t *testing.T) {
+			old := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{SubnetLayout: layout},
+			}, "webhook-test", "default")
+			oscInfraCluster := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{SubnetLayout: ctc.layout},
+			}, "webhook-test", "default")
+			_, err := h.ValidateUpdate(context.TODO(), oscInfraCluster, old)
+			if ctc.expValidateUpdateErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOscCluster_ValidateUpdateAdditionalLoadBalancer(t *testing.T) {
+	alb := infrastructurev1beta1.OscAdditionalLoadBalancer{
+		LoadBalancerName: "foo-ingress",
+		BackendRole:      infrastructurev1beta1.RoleWorker,
+		Listeners:        []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 80, BackendPort: 30080}},
+	}
+	clusterTestCases := []struct {
+		name                 string
+		alb                  infrastructurev1beta1.OscAdditionalLoadBalancer
+		expValidateUpdateErr bool
+	}{
+		{
+			name: "listeners changed",
+			alb: infrastructurev1beta1.OscAdditionalLoadBalancer{
+				LoadBalancerName: "foo-ingress",
+				BackendRole:      infrastructurev1beta1.RoleWorker,
+				Listeners:        []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 443, BackendPort: 30443}},
+			},
+		},
+		{
+			name: "control plane endpoint changed",
+			alb: infrastructurev1beta1.OscAdditionalLoadBalancer{
+				LoadBalancerName:          "foo-ingress",
+				BackendRole:               infrastructurev1beta1.RoleControlPlane,
+				Listeners:                 []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 80, BackendPort: 30080}},
+				UseAsControlPlaneEndpoint: true,
+			},
+			expValidateUpdateErr: true,
+		},
+		{
+			name: "type changed",
+			alb: infrastructurev1beta1.OscAdditionalLoadBalancer{
+				LoadBalancerName: "foo-ingress",
+				LoadBalancerType: "internal",
+				BackendRole:      infrastructurev1beta1.RoleWorker,
+				Listeners:        []infrastructurev1beta1.OscLoadBalancerListener{{LoadBalancerPort: 80, BackendPort: 30080}},
+			},
+			expValidateUpdateErr: true,
+		},
+	}
+	h := infrastructurev1beta1.OscClusterWebhook{}
+	for _, ctc := range clusterTestCases {
+		t.Run(ctc.name, func(t *testing.T) {
+			old := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{alb}},
+			}, "webhook-test", "default")
+			oscInfraCluster := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{ctc.alb}},
+			}, "webhook-test", "default")
+			_, err := h.ValidateUpdate(context.TODO(), oscInfraCluster, old)
+			if ctc.expValidateUpdateErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOscCluster_ValidateUpdateEndpointLoadBalancer(t *testing.T) {
+	alb := infrastructurev1beta1.OscAdditionalLoadBalancer{
+		LoadBalancerName: "foo-public",
+		BackendRole:      infrastructurev1beta1.RoleControlPlane,
+		Listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+			{LoadBalancerPort: 6443},
+			{LoadBalancerPort: 8132},
+		},
+		UseAsControlPlaneEndpoint: true,
+	}
+	clusterTestCases := []struct {
+		name                 string
+		listeners            []infrastructurev1beta1.OscLoadBalancerListener
+		expValidateUpdateErr bool
+	}{
+		{
+			name: "additional listener changed",
+			listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+				{LoadBalancerPort: 6443},
+				{LoadBalancerPort: 8133},
+			},
+		},
+		{
+			name: "endpoint port changed",
+			listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+				{LoadBalancerPort: 443},
+				{LoadBalancerPort: 8132},
+			},
+			expValidateUpdateErr: true,
+		},
+		{
+			name: "listeners reordered",
+			listeners: []infrastructurev1beta1.OscLoadBalancerListener{
+				{LoadBalancerPort: 8132},
+				{LoadBalancerPort: 6443},
+			},
+			expValidateUpdateErr: true,
+		},
+	}
+	h := infrastructurev1beta1.OscClusterWebhook{}
+	for _, ctc := range clusterTestCases {
+		t.Run(ctc.name, func(t *testing.T) {
+			old := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{alb}},
+			}, "webhook-test", "default")
+			updated := alb
+			updated.Listeners = ctc.listeners
+			oscInfraCluster := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{AdditionalLoadBalancers: []infrastructurev1beta1.OscAdditionalLoadBalancer{updated}},
+			}, "webhook-test", "default")
+			_, err := h.ValidateUpdate(context.TODO(), oscInfraCluster, old)
+			if ctc.expValidateUpdateErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOscCluster_ValidateUpdateLoadBalancer(t *testing.T) {
+	lb := infrastructurev1beta1.OscLoadBalancer{
+		LoadBalancerName: "test-webhook",
+		Listener:         infrastructurev1beta1.OscLoadBalancerListener{BackendPort: 6443},
+	}
+	clusterTestCases := []struct {
+		name                 string
+		lb                   infrastructurev1beta1.OscLoadBalancer
+		expValidateUpdateErr bool
+	}{
+		{
+			name: "healthcheck and backend changed",
+			lb: infrastructurev1beta1.OscLoadBalancer{
+				LoadBalancerName:  "test-webhook",
+				SecurityGroupName: "lb-sg",
+				Listener:          infrastructurev1beta1.OscLoadBalancerListener{BackendPort: 8443, LoadBalancerPort: 6443},
+				HealthCheck:       infrastructurev1beta1.OscLoadBalancerHealthCheck{CheckInterval: 30, Port: 8443},
+			},
+		},
+		{
+			name: "loadbalancer port changed",
+			lb: infrastructurev1beta1.OscLoadBalancer{
+				LoadBalancerName: "test-webhook",
+				Listener:         infrastructurev1beta1.OscLoadBalancerListener{BackendPort: 6443, LoadBalancerPort: 443},
+			},
+			expValidateUpdateErr: true,
+		},
+		{
+			name: "subnet changed",
+			lb: infrastructurev1beta1.OscLoadBalancer{
+				LoadBalancerName: "test-webhook",
+				SubnetName:       "other-subnet",
+				Listener:         infrastructurev1beta1.OscLoadBalancerListener{BackendPort: 6443},
+			},
+			expValidateUpdateErr: true,
+		},
+		{
+			name: "public IP added",
+			lb: infrastructurev1beta1.OscLoadBalancer{
+				LoadBalancerName: "test-webhook",
+				Listener:         infrastructurev1beta1.OscLoadBalancerListener{BackendPort: 6443},
+				PublicIp:         true,
+			},
+			expValidateUpdateErr: true,
+		},
+	}
+	h := infrastructurev1beta1.OscClusterWebhook{}
+	for _, ctc := range clusterTestCases {
+		t.Run(ctc.name, func(t *testing.T) {
+			old := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{LoadBalancer: lb},
+			}, "webhook-test", "default")
+			oscInfraCluster := createOscInfraCluster(infrastructurev1beta1.OscClusterSpec{
+				Network: infrastructurev1beta1.OscNetwork{LoadBalancer: ctc.lb},
+			}, "webhook-test", "default")
+			_, err := h.ValidateUpdate(context.TODO(), oscInfraCluster, old)
+			if ctc.expValidateUpdateErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 // createOscInfraCluster create oscInfraCluster
 func createOscInfraCluster(infraClusterSpec infrastructurev1beta1.OscClusterSpec, name string, namespace string) *infrastructurev1beta1.OscCluster {
 	oscInfraCluster := &infrastructurev1beta1.OscCluster{
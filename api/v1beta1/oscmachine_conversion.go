@@ -12,16 +12,19 @@ func (src *OscMachineSpec) ConvertTo(dst *infrastructurev1beta2.OscMachineSpec)
 		ProviderID: src.ProviderID,
 		Node: infrastructurev1beta2.OscNode{
 			Vm: infrastructurev1beta2.OscVm{
-				Name:           srcNode.Vm.Name,
-				ImageId:        srcNode.Vm.ImageId,
-				KeypairName:    srcNode.Vm.KeypairName,
-				VmType:         srcNode.Vm.VmType,
-				SubnetName:     srcNode.Vm.SubnetName,
-				PublicIp:       srcNode.Vm.PublicIp,
-				RootDisk:       infrastructurev1beta2.OscRootDisk(srcNode.Vm.RootDisk),
-				SubregionName:  srcNode.Vm.SubregionName,
-				SubregionMode:  infrastructurev1beta2.SubregionMode(srcNode.Vm.SubregionMode),
-				SubregionNames: srcNode.Vm.SubregionNames,
+				Name:             srcNode.Vm.Name,
+				ImageId:          srcNode.Vm.ImageId,
+				KeypairName:      srcNode.Vm.KeypairName,
+				VmType:           srcNode.Vm.VmType,
+				SubnetName:       srcNode.Vm.SubnetName,
+				SubnetPool:       srcNode.Vm.SubnetPool,
+				PublicIp:         srcNode.Vm.PublicIp,
+				RootDisk:         infrastructurev1beta2.OscRootDisk(srcNode.Vm.RootDisk),
+				SubregionName:    srcNode.Vm.SubregionName,
+				SubregionMode:    infrastructurev1beta2.SubregionMode(srcNode.Vm.SubregionMode),
+				SubregionNames:   srcNode.Vm.SubregionNames,
+				AdoptVmId:        srcNode.Vm.AdoptVmId,
+				PrivateIpPoolRef: srcNode.Vm.PrivateIpPoolRef.DeepCopy(),
 				SecurityGroupNames: lo.Map(srcNode.Vm.SecurityGroupNames, func(src OscSecurityGroupElement, _ int) infrastructurev1beta2.OscSecurityGroupElement {
 					return infrastructurev1beta2.OscSecurityGroupElement(src)
 				}),
@@ -67,16 +70,19 @@ func (dst *OscMachineSpec) ConvertFrom(src *infrastructurev1beta2.OscMachineSpec
 		ProviderID: src.ProviderID,
 		Node: OscNode{
 			Vm: OscVm{
-				Name:           srcNode.Vm.Name,
-				ImageId:        srcNode.Vm.ImageId,
-				KeypairName:    srcNode.Vm.KeypairName,
-				VmType:         srcNode.Vm.VmType,
-				SubnetName:     srcNode.Vm.SubnetName,
-				PublicIp:       srcNode.Vm.PublicIp,
-				RootDisk:       OscRootDisk(srcNode.Vm.RootDisk),
-				SubregionName:  srcNode.Vm.SubregionName,
-				SubregionMode:  SubregionMode(srcNode.Vm.SubregionMode),
-				SubregionNames: srcNode.Vm.SubregionNames,
+				Name:             srcNode.Vm.Name,
+				ImageId:          srcNode.Vm.ImageId,
+				KeypairName:      srcNode.Vm.KeypairName,
+				VmType:           srcNode.Vm.VmType,
+				SubnetName:       srcNode.Vm.SubnetName,
+				SubnetPool:       srcNode.Vm.SubnetPool,
+				PublicIp:         srcNode.Vm.PublicIp,
+				RootDisk:         OscRootDisk(srcNode.Vm.RootDisk),
+				SubregionName:    srcNode.Vm.SubregionName,
+				SubregionMode:    SubregionMode(srcNode.Vm.SubregionMode),
+				SubregionNames:   srcNode.Vm.SubregionNames,
+				AdoptVmId:        srcNode.Vm.AdoptVmId,
+				PrivateIpPoolRef: srcNode.Vm.PrivateIpPoolRef.DeepCopy(),
 				SecurityGroupNames: lo.Map(srcNode.Vm.SecurityGroupNames, func(src infrastructurev1beta2.OscSecurityGroupElement, _ int) OscSecurityGroupElement {
 					return OscSecurityGroupElement(src)
 				}),
@@ -40,9 +40,25 @@ func ValidateOscMachineSpec(spec OscMachineSpec) field.ErrorList {
 	allErrs = AppendValidation(allErrs, ValidateIops(field.NewPath("node", "vm", "rootDisk", "rootDiskIops"), spec.Node.Vm.RootDisk.RootDiskIops, spec.Node.Vm.RootDisk.RootDiskSize))
 	allErrs = AppendValidation(allErrs, ValidateSize(field.NewPath("node", "vm", "rootDisk", "rootDiskSize"), spec.Node.Vm.RootDisk.RootDiskSize))
 	allErrs = AppendValidation(allErrs, ValidateVolumeType(field.NewPath("node", "vm", "rootDisk", "rootDiskType"), spec.Node.Vm.RootDisk.RootDiskType))
+	allErrs = AppendValidation(allErrs, ValidatePrivateIpPoolRef(field.NewPath("node", "vm"), spec.Node.Vm)...)
 	return allErrs
 }
 
+func ValidatePrivateIpPoolRef(path *field.Path, spec OscVm) field.ErrorList {
+	ref := spec.PrivateIpPoolRef
+	if ref == nil {
+		return nil
+	}
+	var apiGroup string
+	if ref.APIGroup != nil {
+		apiGroup = *ref.APIGroup
+	}
+	return MergeValidation(
+		ValidateEmptySlice(path.Child("privateIps"), spec.PrivateIps, "privateIps must not be set with privateIpPoolRef"),
+		ValidateRequired(path.Child("privateIpPoolRef", "apiGroup"), apiGroup, "the apiGroup of the IPAM pool is required"),
+	)
+}
+
 func ValidateVolume(path *field.Path, spec OscVolume) field.ErrorList {
 	var allErrs field.ErrorList
 	return AppendValidation(allErrs,
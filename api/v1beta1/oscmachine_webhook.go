@@ -98,6 +98,13 @@ func (OscMachineWebhook) ValidateUpdate(_ context.Context, obj runtime.Object, o
 				r.Spec.Node.Vm.SubnetName, "field is immutable"),
 		)
 	}
+	if r.Spec.Node.Vm.AdoptVmId != old.Spec.Node.Vm.AdoptVmId {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("node", "vm", "adoptVmId"),
+				r.Spec.Node.Vm.AdoptVmId, "field is immutable"),
+		)
+	}
+
 	if r.Spec.Node.Vm.RootDisk.RootDiskSize != old.Spec.Node.Vm.RootDisk.RootDiskSize {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("node", "vm", "rootDisk", "rootDiskSize"),
@@ -11,6 +11,7 @@ import (
 
 	infrastructurev1beta1 "github.com/outscale/cluster-api-provider-outscale/api/v1beta1"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -215,6 +216,39 @@ func TestOscMachine_ValidateCreate(t *testing.T) {
 			},
 			errorCount: 0,
 		},
+		{
+			name: "create with a private IP pool",
+			machineSpec: infrastructurev1beta1.OscMachineSpec{
+				Node: infrastructurev1beta1.OscNode{
+					Vm: infrastructurev1beta1.OscVm{
+						KeypairName: "test-webhook",
+						VmType:      "tinav4.c2r4p2",
+						PrivateIpPoolRef: &corev1.TypedLocalObjectReference{
+							APIGroup: new("ipam.cluster.x-k8s.io"),
+							Kind:     "InClusterIPPool",
+							Name:     "controlplane",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "create with a private IP pool and private IPs",
+			machineSpec: infrastructurev1beta1.OscMachineSpec{
+				Node: infrastructurev1beta1.OscNode{
+					Vm: infrastructurev1beta1.OscVm{
+						KeypairName: "test-webhook",
+						VmType:      "tinav4.c2r4p2",
+						PrivateIps:  []infrastructurev1beta1.OscPrivateIpElement{{PrivateIp: "10.0.4.10"}},
+						PrivateIpPoolRef: &corev1.TypedLocalObjectReference{
+							Kind: "InClusterIPPool",
+							Name: "controlplane",
+						},
+					},
+				},
+			},
+			errorCount: 2,
+		},
 	}
 	h := infrastructurev1beta1.OscMachineWebhook{}
 	for _, mtc := range machineTestCases {
@@ -313,6 +347,24 @@ func TestOscMachine_ValidateUpdate(t *testing.T) {
 			},
 			errorCount: 1,
 		},
+		{
+			name: "update adoptVmId",
+			oldMachineSpec: infrastructurev1beta1.OscMachineSpec{
+				Node: infrastructurev1beta1.OscNode{
+					Vm: infrastructurev1beta1.OscVm{
+						AdoptVmId: "i-foo",
+					},
+				},
+			},
+			machineSpec: infrastructurev1beta1.OscMachineSpec{
+				Node: infrastructurev1beta1.OscNode{
+					Vm: infrastructurev1beta1.OscVm{
+						AdoptVmId: "i-bar",
+					},
+				},
+			},
+			errorCount: 1,
+		},
 	}
 	h := infrastructurev1beta1.OscMachineWebhook{}
 	for _, mtc := range machineTestCases {
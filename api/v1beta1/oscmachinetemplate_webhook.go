@@ -50,7 +50,12 @@ func (OscMachineTemplateWebhook) ValidateCreate(_ context.Context, obj runtime.O
 	if !ok {
 		return nil, fmt.Errorf("expected an OscMachineTemplate object but got %T", r)
 	}
-	if allErrs := ValidateOscMachineSpec(r.Spec.Template.Spec); len(allErrs) > 0 {
+	allErrs := ValidateOscMachineSpec(r.Spec.Template.Spec)
+	// All machines of a template would adopt the same vm.
+	if r.Spec.Template.Spec.Node.Vm.AdoptVmId != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("template", "spec", "node", "vm", "adoptVmId"), "a vm cannot be adopted by machines of a template"))
+	}
+	if len(allErrs) > 0 {
 		return nil, apierrors.NewInvalid(GroupVersion.WithKind("OscMachineTemplate").GroupKind(), r.Name, allErrs)
 	}
 	return nil, nil
@@ -259,6 +259,19 @@ func TestOscMachineTemplate_ValidateCreate(t *testing.T) {
 			},
 			errorCount: 0,
 		},
+		{
+			name: "create with an adopted vm",
+			machineSpec: infrastructurev1beta1.OscMachineSpec{
+				Node: infrastructurev1beta1.OscNode{
+					Vm: infrastructurev1beta1.OscVm{
+						KeypairName: "test-webhook",
+						VmType:      "tinav4.c2r4p2",
+						AdoptVmId:   "i-foo",
+					},
+				},
+			},
+			errorCount: 1,
+		},
 	}
 	h := infrastructurev1beta1.OscMachineTemplateWebhook{}
 	for _, mtc := range machineTestCases {
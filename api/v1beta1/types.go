@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type OscRole string
@@ -59,12 +60,21 @@ type OscNetwork struct {
 	// The Load Balancer configuration
 	// +optional
 	LoadBalancer OscLoadBalancer `json:"loadBalancer,omitempty"`
+	// Additional load balancers, having the VMs of a role as backends (e.g. for ingress on platforms without CCM).
+	// +optional
+	AdditionalLoadBalancers []OscAdditionalLoadBalancer `json:"additionalLoadBalancers,omitempty"`
 	// The Net configuration
 	// +optional
 	Net OscNet `json:"net,omitempty"`
 	// The NetPeering configuration, required if the load balancer is internal, and management and workload clusters are on separate VPCs.
 	// +optional
 	NetPeering OscNetPeering `json:"netPeering,omitempty"`
+	// NetPeerings to other nets, in the same or in other accounts.
+	// +optional
+	Peerings []OscPeering `json:"peerings,omitempty"`
+	// The VPN configuration, linking a virtual gateway to the net, with VPN connections to on-premises networks.
+	// +optional
+	Vpn OscVpn `json:"vpn,omitempty"`
 	// The NetAccessPoints configuration, required if internet is disabled.
 	// +optional
 	NetAccessPoints []OscNetAccessPointService `json:"netAccessPoints,omitempty"`
@@ -74,6 +84,13 @@ type OscNetwork struct {
 	// The Subnets configuration
 	// +optional
 	Subnets []OscSubnet `json:"subnets,omitempty"`
+	// The automatic allocation of subnet IP ranges, used when no subnets are set.
+	// +optional
+	SubnetAllocation OscSubnetAllocation `json:"subnetAllocation,omitempty"`
+	// The layout of the subnets created when no subnets are set.
+	// By default, a public, a worker and a controlplane /24 subnet are created in each subregion.
+	// +optional
+	SubnetLayout OscSubnetLayout `json:"subnetLayout,omitempty"`
 	// The Internet Service configuration
 	// +optional
 	InternetService OscInternetService `json:"internetService,omitempty"`
@@ -86,6 +103,9 @@ type OscNetwork struct {
 	// The IP Pool storing the Nat Services public IPs
 	// +optional
 	NatPublicIpPool string `json:"natPublicIpPool,omitempty"`
+	// The health monitor of the Nat Services, moving routes from a failed Nat Service to a healthy one.
+	// +optional
+	NatFailover OscNatFailover `json:"natFailover,omitempty,omitzero"`
 	// The Route Table configuration
 	// +optional
 	RouteTables []OscRouteTable `json:"routeTables,omitempty"`
@@ -95,6 +115,12 @@ type OscNetwork struct {
 	// Additional rules to add to the automatic security groups
 	// +optional
 	AdditionalSecurityRules []OscAdditionalSecurityRules `json:"additionalSecurityRules,omitempty"`
+	// The CNI of the cluster, selecting the CNI rules of the automatic node securityGroup (calico, cilium, flannel or custom, default: the rules of all supported CNIs).
+	// +optional
+	Cni OscCni `json:"cni,omitempty"`
+	// The ConfigMap defining the CNI rules of the automatic node securityGroup in its rules key, required if cni is custom.
+	// +optional
+	CniProfileRef *corev1.LocalObjectReference `json:"cniProfileRef,omitempty"`
 	// The Public Ip configuration (unused)
 	// +optional
 	PublicIps []*OscPublicIp `json:"publicIps,omitempty"`
@@ -119,16 +145,67 @@ type OscNetwork struct {
 	// The list of IP ranges (in CIDR notation) the nodes can talk to ("0.0.0.0/0" if not set).
 	// + optional
 	AllowToIPRanges []string `json:"allowToIPRanges,omitempty"`
+	// Per-role egress rules. If set, the outbound traffic of controlplane, worker and bastion nodes is restricted to the egress rules of their roles (roles without egress rules use allowToIPRanges).
+	// + optional
+	EgressRules []OscEgressRule `json:"egressRules,omitempty"`
 	// Reconciliation rules (default: {securityGroup, random, 10%}, {*, onChange}). Only the first matching rule applies.
 	// + optional
 	ReconciliationRules []OscReconciliationRule `json:"reconciliationRules,omitempty"`
 }
 
+type OscSubnetAllocation struct {
+	// If set, free IP ranges of the net are claimed for the subnets of the cluster, allowing multiple clusters to share a net.
+	// Subnets are created in the claimed ranges, and deleted with the cluster.
+	Enable bool `json:"enable,omitempty"`
+	// The prefix length of the allocated subnets (default: 24)
+	// +optional
+	PrefixLength int32 `json:"prefixLength,omitempty"`
+}
+
+type OscSubnetLayout struct {
+	// The prefix length of public subnets (default: 24)
+	// +optional
+	PublicPrefixLength int32 `json:"publicPrefixLength,omitempty"`
+	// The prefix length of controlplane subnets (default: 24)
+	// +optional
+	ControlPlanePrefixLength int32 `json:"controlPlanePrefixLength,omitempty"`
+	// The prefix length of worker subnets (default: 24)
+	// +optional
+	WorkerPrefixLength int32 `json:"workerPrefixLength,omitempty"`
+	// If set, NAT services are created in dedicated public subnets, separate from the load balancer subnets.
+	// +optional
+	SeparateNatSubnets bool `json:"separateNatSubnets,omitempty"`
+	// The prefix length of NAT subnets, if separate (default: the prefix length of public subnets)
+	// +optional
+	NatPrefixLength int32 `json:"natPrefixLength,omitempty"`
+	// Dedicated worker subnets, created in each subregion, for machines having the same subnetPool.
+	// +optional
+	Pools []OscSubnetPool `json:"pools,omitempty"`
+	// An IP range of the net where no subnet is created, reserved for future use.
+	// +optional
+	ReservedIpRange string `json:"reservedIpRange,omitempty"`
+}
+
+func (o *OscSubnetLayout) IsZero() bool {
+	return o.PublicPrefixLength == 0 && o.ControlPlanePrefixLength == 0 && o.WorkerPrefixLength == 0 &&
+		!o.SeparateNatSubnets && o.NatPrefixLength == 0 && len(o.Pools) == 0 && o.ReservedIpRange == ""
+}
+
+type OscSubnetPool struct {
+	// The name of the pool
+	Name string `json:"name"`
+	// The prefix length of the subnets of the pool (default: the prefix length of worker subnets)
+	// +optional
+	PrefixLength int32 `json:"prefixLength,omitempty"`
+}
+
 type OscReuse struct {
 	// If set, net, subnets, internet service, nat services and route tables are externally managed
 	Net bool `json:"net,omitempty"`
 	// If set, security groups are externally managed.
 	SecurityGroups bool `json:"securityGroups,omitempty"`
+	// If set, the load balancer named loadBalancer.loadbalancername is externally managed, only control plane VMs are registered as backends.
+	LoadBalancer bool `json:"loadBalancer,omitempty"`
 }
 
 // +kubebuilder:validation:Enum:=internet;loadbalancer
@@ -139,6 +216,44 @@ const (
 	DisableLB       OscDisable = "loadbalancer"
 )
 
+// +kubebuilder:validation:Enum:=api;oos
+type OscEgressDestination string
+
+const (
+	EgressDestinationAPI OscEgressDestination = "api"
+	EgressDestinationOOS OscEgressDestination = "oos"
+)
+
+type OscEgressRule struct {
+	// The roles the rule applies to (controlplane, worker or bastion).
+	Roles []OscRole `json:"roles"`
+	// The ip protocol name (tcp, udp, icmp or -1, default: -1)
+	// +optional
+	IpProtocol string `json:"ipProtocol,omitempty"`
+	// The beginning of the port range
+	// +optional
+	FromPortRange int32 `json:"fromPortRange,omitempty"`
+	// The end of the port range
+	// +optional
+	ToPortRange int32 `json:"toPortRange,omitempty"`
+	// The list of destination ip ranges
+	// +optional
+	IpRanges []string `json:"ipRanges,omitempty"`
+	// Named destinations, resolved to the IPs of the Outscale endpoints of the region (api or oos).
+	// +optional
+	Destinations []OscEgressDestination `json:"destinations,omitempty"`
+}
+
+// +kubebuilder:validation:Enum:=calico;cilium;flannel;custom
+type OscCni string
+
+const (
+	CniCalico  OscCni = "calico"
+	CniCilium  OscCni = "cilium"
+	CniFlannel OscCni = "flannel"
+	CniCustom  OscCni = "custom"
+)
+
 type OscLoadBalancer struct {
 	// The Load Balancer unique name
 	// +optional
@@ -158,23 +273,73 @@ type OscLoadBalancer struct {
 	// The healthCheck configuration of the Load Balancer
 	// +optional
 	HealthCheck OscLoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+	// Additional listeners of the Load Balancer (e.g. konnectivity), forwarding to the control plane VMs
+	// +optional
+	AdditionalListeners []OscLoadBalancerListener `json:"additionalListeners,omitempty"`
+	// If set, a public IP owned by the account is associated with the internet-facing Load Balancer (the IP is deleted with the cluster unless picked from a pool).
+	// +optional
+	PublicIp bool `json:"publicIp,omitempty"`
+	// The name of the pool from which the public IP will be picked.
+	// +optional
+	PublicIpPool string `json:"publicIpPool,omitempty"`
+	// The access log configuration of the Load Balancer
+	// +optional
+	AccessLog OscLoadBalancerAccessLog `json:"accessLog,omitempty,omitzero"`
 	// unused
 	ClusterName string `json:"clusterName,omitempty"`
 }
 
+type OscAdditionalLoadBalancer struct {
+	// The Load Balancer unique name
+	// +kubebuilder:validation:Required
+	LoadBalancerName string `json:"loadbalancername"`
+	// The Load Balancer type (internet-facing or internal, default: internet-facing)
+	// +optional
+	LoadBalancerType string `json:"loadbalancertype,omitempty"`
+	// The role of the backend VMs (controlplane or worker)
+	// +kubebuilder:validation:Required
+	BackendRole OscRole `json:"backendRole"`
+	// The Listeners of the Load Balancer
+	// +kubebuilder:validation:MinItems=1
+	Listeners []OscLoadBalancerListener `json:"listeners"`
+	// The healthCheck configuration of the Load Balancer (default: TCP check on the backend port of the first listener)
+	// +optional
+	HealthCheck OscLoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+	// If set, the DNS name and the first listener port of the Load Balancer are used as the control plane endpoint, instead of the API Load Balancer.
+	// The backendRole must be controlplane.
+	// +optional
+	UseAsControlPlaneEndpoint bool `json:"useAsControlPlaneEndpoint,omitempty"`
+}
+
 type OscLoadBalancerListener struct {
 	// The port on which the backend VMs will listen
 	// +optional
 	BackendPort int32 `json:"backendport,omitempty"`
-	// The protocol ('HTTP'|'TCP') to route the traffic to the backend vm
+	// The protocol ('HTTP'|'TCP', or 'HTTPS'|'SSL' with a server certificate) to route the traffic to the backend vm
 	// +optional
 	BackendProtocol string `json:"backendprotocol,omitempty"`
 	// The port on which the loadbalancer will listen
 	// +optional
 	LoadBalancerPort int32 `json:"loadbalancerport,omitempty"`
-	// the routing protocol ('HTTP'|'TCP')
+	// the routing protocol ('HTTP'|'TCP', or 'HTTPS'|'SSL' with a server certificate)
 	// +optional
 	LoadBalancerProtocol string `json:"loadbalancerprotocol,omitempty"`
+	// The name of a kubernetes.io/tls Secret, in the namespace of the cluster, uploaded as the server certificate of an HTTPS or SSL listener.
+	// The certificate is uploaded again when the Secret is updated.
+	// +optional
+	ServerCertificateSecretName string `json:"serverCertificateSecretName,omitempty"`
+}
+
+type OscLoadBalancerAccessLog struct {
+	// The name of the OOS bucket where access logs are published (access logs are disabled if not set)
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+	// The path of the folder of access logs in the bucket (default: root of the bucket)
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// The publication interval of access logs, in minutes (5 or 60, default: 60)
+	// +optional
+	Interval int32 `json:"interval,omitempty"`
 }
 
 type OscLoadBalancerHealthCheck struct {
@@ -211,10 +376,37 @@ type OscNet struct {
 	// The Id of the Net to reuse (if useExisting.net is set)
 	// +optional
 	ResourceId string `json:"resourceId,omitempty"`
+	// The name of the OscNetPool from which the ip range of the Net is allocated, if ipRange is not set
+	// +optional
+	NetPool string `json:"netPool,omitempty"`
+	// The DHCP options of the Net. If set, a DHCP options set is created and linked to the Net, and the default options are restored before deletion.
+	// +optional
+	DhcpOptions OscDhcpOptions `json:"dhcpOptions,omitempty"`
 }
 
 func (o *OscNet) IsZero() bool {
-	return o.IpRange == "" && o.ResourceId == ""
+	return !o.HasIpRange() && o.DhcpOptions.IsZero()
+}
+
+// HasIpRange returns true if the ip range of the Net is set, reused or allocated from a pool.
+func (o *OscNet) HasIpRange() bool {
+	return o.IpRange != "" || o.ResourceId != "" || o.NetPool != ""
+}
+
+type OscDhcpOptions struct {
+	// The domain name, used as search domain by the VMs of the Net.
+	// +optional
+	DomainName string `json:"domainName,omitempty"`
+	// The IPs of the domain name servers (default: the DNS provided by Outscale).
+	// +optional
+	DomainNameServers []string `json:"domainNameServers,omitempty"`
+	// The IPs of the NTP servers.
+	// +optional
+	NtpServers []string `json:"ntpServers,omitempty"`
+}
+
+func (o *OscDhcpOptions) IsZero() bool {
+	return o.DomainName == "" && len(o.DomainNameServers) == 0 && len(o.NtpServers) == 0
 }
 
 var DefaultNet = OscNet{
@@ -239,6 +431,66 @@ type OscNetPeering struct {
 	ManagementSubnetID string `json:"managementSubnetId,omitempty"`
 }
 
+// +kubebuilder:validation:Enum:=auto;manual
+type OscPeeringAcceptPolicy string
+
+const (
+	PeeringAcceptAuto   OscPeeringAcceptPolicy = "auto"
+	PeeringAcceptManual OscPeeringAcceptPolicy = "manual"
+)
+
+type OscPeering struct {
+	// The name of the peering, used to track the peering and to name its condition.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// The credentials of the peer account, used to accept the peering and to route the peer net. The cluster credentials are used by default.
+	// +optional
+	Credentials OscCredentials `json:"credentials,omitempty,omitzero"`
+	// The peer account ID.
+	// +kubebuilder:validation:Required
+	AccountID string `json:"accountId"`
+	// The peer net ID.
+	// +kubebuilder:validation:Required
+	NetID string `json:"netId"`
+	// By default, all subnets of the peer net are routed to the peering. If set, only the specified subnets will be routed.
+	// +optional
+	PeerSubnetIDs []string `json:"peerSubnetIds,omitempty"`
+	// By default, all subnets of the cluster are routed to the peering. If set, only the subnets having one of the roles will be routed.
+	// +optional
+	Roles []OscRole `json:"roles,omitempty"`
+	// The accept policy: auto (default, the peering is accepted and the peer net is routed using the peer credentials) or manual (the peering is accepted and the peer net is routed by the owner of the peer account).
+	// +optional
+	AcceptPolicy OscPeeringAcceptPolicy `json:"acceptPolicy,omitempty"`
+}
+
+type OscVpn struct {
+	// If set, a virtual gateway is linked to the net of the cluster.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+	// The ID of an existing virtual gateway to link to the net (optional, a virtual gateway is created and deleted with the cluster if not set).
+	// +optional
+	VirtualGatewayID string `json:"virtualGatewayId,omitempty"`
+	// The VPN connections to on-premises client gateways.
+	// +optional
+	Connections []OscVpnConnection `json:"connections,omitempty"`
+}
+
+type OscVpnConnection struct {
+	// The name of the VPN connection, used to track the client gateway and the VPN connection.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// The public IP of the client gateway.
+	// +kubebuilder:validation:Required
+	ClientGatewayIP string `json:"clientGatewayIp"`
+	// The BGP ASN of the client gateway (default: 65000).
+	// +optional
+	BgpAsn int32 `json:"bgpAsn,omitempty"`
+	// The on-premises IP ranges (in CIDR notation) routed through the VPN connection.
+	// They are routed from all route tables of the cluster, and allowed in the automatic security groups.
+	// +kubebuilder:validation:MinItems=1
+	StaticRoutes []string `json:"staticRoutes"`
+}
+
 // +kubebuilder:validation:Enum:=api;directlink;eim;kms;lbu;oos
 type OscNetAccessPointService string
 
@@ -279,6 +531,9 @@ type OscSubnet struct {
 	// The id of the Subnet to reuse (if useExisting.net is set)
 	// +optional
 	ResourceId string `json:"resourceId,omitempty"`
+	// The pool for which the subnet is dedicated. Dedicated subnets are only used by machines having the same subnetPool.
+	// +optional
+	Pool string `json:"pool,omitempty"`
 }
 
 type OscNatService struct {
@@ -300,6 +555,19 @@ type OscNatService struct {
 	// The resource id (unused)
 	// +optional
 	ResourceId string `json:"resourceId,omitempty"`
+	// The id of an existing public IP to use (the public IP is not deleted with the cluster)
+	// +optional
+	PublicIpId string `json:"publicIpId,omitempty"`
+}
+
+type OscNatFailover struct {
+	// If set, Nat Services are checked periodically. Routes to a failed or deleted Nat Service are moved to a healthy Nat Service
+	// of another subregion, and moved back once the Nat Service has recovered.
+	Enable bool `json:"enable,omitempty"`
+	// The time in seconds between two health checks (default: 60)
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	CheckInterval int32 `json:"checkInterval,omitempty"`
 }
 
 type OscRouteTable struct {
@@ -318,6 +586,10 @@ type OscRouteTable struct {
 	// The Route configuration
 	// +optional
 	Routes []OscRoute `json:"routes,omitempty"`
+	// If set, routes not in spec are deleted.
+	// Local routes, net access point routes, and routes to the peerings and to the virtual gateway of the cluster are kept.
+	// +optional
+	Authoritative bool `json:"authoritative,omitempty"`
 	// The resource id (unused)
 	// +optional
 	ResourceId string `json:"resourceId,omitempty"`
@@ -387,9 +659,13 @@ type OscRoute struct {
 	// The tag name associate with the target resource type
 	// +optional
 	TargetName string `json:"targetName,omitempty"`
-	// The target resource type which can be Internet Service (gateway) or Nat Service (nat-service)
+	// The target resource type which can be Internet Service (gateway), Nat Service (nat or nat-service),
+	// VM (vm), NIC (nic), Net Peering (netPeering) or Virtual Gateway (virtualGateway)
 	// +optional
 	TargetType string `json:"targetType,omitempty"`
+	// The ID of the target resource, required for vm, nic, netPeering and virtualGateway targets
+	// +optional
+	TargetId string `json:"targetId,omitempty"`
 	// the destination match Ip range with CIDR notation
 	// +optional
 	Destination string `json:"destination,omitempty"`
@@ -423,6 +699,15 @@ type OscSecurityGroupRule struct {
 	// The list of ip ranges of the security group rule
 	// +optional
 	IpRanges []string `json:"ipRanges,omitempty"`
+	// The names of cluster security groups allowed as source (inbound rules) or destination (outbound rules)
+	// +optional
+	SecurityGroupNames []string `json:"securityGroupNames,omitempty"`
+	// The roles of cluster security groups allowed as source (inbound rules) or destination (outbound rules)
+	// +optional
+	SecurityGroupRoles []OscRole `json:"securityGroupRoles,omitempty"`
+	// The ids of external security groups allowed as source (inbound rules) or destination (outbound rules)
+	// +optional
+	SecurityGroupIds []string `json:"securityGroupIds,omitempty"`
 	// The beginning of the port range
 	// +optional
 	FromPortRange int32 `json:"fromPortRange,omitempty"`
@@ -502,9 +787,31 @@ type OscClusterResources struct {
 	NatService      map[string]string `json:"natService,omitempty"`
 	Bastion         map[string]string `json:"bastion,omitempty"`
 	PublicIPs       map[string]string `json:"publicIps,omitempty"`
+	VirtualGateway  map[string]string `json:"virtualGateway,omitempty"`
+	ClientGateway   map[string]string `json:"clientGateway,omitempty"`
+	VpnConnection   map[string]string `json:"vpnConnection,omitempty"`
+	DhcpOptions     map[string]string `json:"dhcpOptions,omitempty"`
+	// Security group rules created with a security group member (key: <security group id>/<flow>/<protocol>/<from port>/<to port>/<member id>, value: member id).
+	SecurityGroupMemberRules map[string]string `json:"securityGroupMemberRules,omitempty"`
+	// Routes moved to another Nat Service by the Nat Service health monitor (key: <route table id>/<destination>, value: client token of the failed Nat Service).
+	NatFailover map[string]string `json:"natFailover,omitempty"`
+	// IP ranges claimed for automatically allocated subnets (key: <subregion>/<roles>, value: IP range).
+	SubnetClaims map[string]string `json:"subnetClaims,omitempty"`
+	// Resources being created, which might not be tagged yet (key: <resource type>/<key>, value: comma-separated ids of the existing resources that might be mistaken with the one being created, or created:<id> once the resource is created but not tagged).
+	Creating map[string]string `json:"creating,omitempty"`
+	// Server certificates uploaded from Secrets (key: <loadbalancer name>/<loadbalancer port>, value: server certificate name).
+	ServerCertificates map[string]string `json:"serverCertificates,omitempty"`
+	// Role tags added by the cluster to reused subnets (key: subnet id, value: comma-separated tag keys).
+	SharedRoleTags map[string]string `json:"sharedRoleTags,omitempty"`
+	// Hash of the rules of the custom CNI profile applied to the security groups (key: default).
+	CniProfile map[string]string `json:"cniProfile,omitempty"`
+	// IP ranges resolved for the egress destinations applied to the security groups (key: destination, value: comma-separated IP ranges).
+	EgressDestinations map[string]string `json:"egressDestinations,omitempty"`
+	// Additional loadBalancers created by the cluster (key: loadbalancer name, value: DNS name).
+	AdditionalLoadBalancers map[string]string `json:"additionalLoadBalancers,omitempty"`
 }
 
-// +kubebuilder:validation:Enum:=bastion;net;netPeering;netPeering/routes;subnet;internetService;netAccessPoint;natService;routeTable;securityGroup;loadbalancer;vm;*
+// +kubebuilder:validation:Enum:=bastion;net;netPeering;netPeering/routes;peering;vpn;subnet;internetService;netAccessPoint;natService;routeTable;securityGroup;loadbalancer;vm;*
 type Reconciler string
 
 const (
@@ -512,6 +819,8 @@ const (
 	ReconcilerNet              Reconciler = "net"
 	ReconcilerNetPeering       Reconciler = "netPeering"
 	ReconcilerNetPeeringRoutes Reconciler = "netPeering/routes"
+	ReconcilerPeering          Reconciler = "peering"
+	ReconcilerVpn              Reconciler = "vpn"
 	ReconcilerSubnet           Reconciler = "subnet"
 	ReconcilerInternetService  Reconciler = "internetService"
 	ReconcilerNetAccessPoint   Reconciler = "netAccessPoint"
@@ -537,7 +846,7 @@ const (
 )
 
 type OscReconciliationRule struct {
-	// The list of items this rule applies to (bastion, net, netPeering, netPeering/routes, subnet, internetService, netAccessPoint, natService, routeTable, securityGroup, loadbalancer, vm or * for all)
+	// The list of items this rule applies to (bastion, net, netPeering, netPeering/routes, peering, vpn, subnet, internetService, netAccessPoint, natService, routeTable, securityGroup, loadbalancer, vm or * for all)
 	AppliesTo []Reconciler `json:"appliesTo,omitempty"`
 	// The mode of reconciliation: onChange (only when the spec change, default), always, random (onChange + randomPercent% chance)
 	Mode ReconciliationMode `json:"mode,omitempty"`
@@ -567,6 +876,8 @@ type OscMachineResources struct {
 	Image     map[string]string `json:"image,omitempty"`
 	Volumes   map[string]string `json:"volumes,omitempty"`
 	PublicIPs map[string]string `json:"publicIps,omitempty"`
+	// Resources being created, which might not be tagged yet (key: <resource type>/<key>, value: comma-separated ids of the existing resources that might be mistaken with the one being created, or created:<id> once the resource is created but not tagged).
+	Creating map[string]string `json:"creating,omitempty"`
 }
 
 type OscImage struct {
@@ -644,7 +955,10 @@ type OscVm struct {
 	DeviceName string `json:"deviceName,omitempty"`
 	// The subnet of the node (deprecated, use controlplane and/or worker roles on subnets)
 	// +optional
-	SubnetName string      `json:"subnetName,omitempty"`
+	SubnetName string `json:"subnetName,omitempty"`
+	// The pool of dedicated subnets where the node is created.
+	// +optional
+	SubnetPool string      `json:"subnetPool,omitempty"`
 	RootDisk   OscRootDisk `json:"rootDisk,omitempty"`
 	// unused
 	LoadBalancerName string `json:"loadBalancerName,omitempty"`
@@ -669,10 +983,16 @@ type OscVm struct {
 	// +optional
 	SubregionNames []string              `json:"subregionNames,omitempty"`
 	PrivateIps     []OscPrivateIpElement `json:"privateIps,omitempty"`
+	// The IPAM pool (ipam.cluster.x-k8s.io) from which the private IP of the VM is claimed.
+	// +optional
+	PrivateIpPoolRef *corev1.TypedLocalObjectReference `json:"privateIpPoolRef,omitempty"`
 	// The list of security groups to use (deprecated, use controlplane and/or worker roles on security groups)
 	SecurityGroupNames []OscSecurityGroupElement `json:"securityGroupNames,omitempty"`
 	// The resource id of the vm (not set anymore)
 	ResourceId string `json:"resourceId,omitempty"`
+	// The id of an existing VM to adopt instead of creating a new one. The VM needs to be in the cluster Net.
+	// +optional
+	AdoptVmId string `json:"adoptVmId,omitempty"`
 	// The node role (controlplane or worker, worker by default).
 	// +optional
 	Role OscRole `json:"role,omitempty"`
@@ -821,6 +1141,16 @@ func (bastion *OscBastion) SetDefaultValue() {
 	}
 }
 
+// defaultBackendProtocol returns the default backend protocol of a listener: TCP, or the loadbalancer protocol for HTTPS and SSL listeners.
+func defaultBackendProtocol(loadBalancerProtocol string) string {
+	switch loadBalancerProtocol {
+	case "HTTPS", "SSL":
+		return loadBalancerProtocol
+	default:
+		return DefaultLoadBalancerProtocol
+	}
+}
+
 // SetDefaultValue set the LoadBalancer Service default values
 func (lb *OscLoadBalancer) SetDefaultValue() {
 	if lb.LoadBalancerType == "" {
@@ -830,7 +1160,7 @@ func (lb *OscLoadBalancer) SetDefaultValue() {
 		lb.Listener.BackendPort = APIPort
 	}
 	if lb.Listener.BackendProtocol == "" {
-		lb.Listener.BackendProtocol = DefaultLoadBalancerProtocol
+		lb.Listener.BackendProtocol = defaultBackendProtocol(lb.Listener.LoadBalancerProtocol)
 	}
 	if lb.Listener.LoadBalancerPort == 0 {
 		lb.Listener.LoadBalancerPort = APIPort
@@ -857,3 +1187,13 @@ func (lb *OscLoadBalancer) SetDefaultValue() {
 		lb.HealthCheck.Port = APIPort
 	}
 }
+
+// GetEndpointLoadBalancerName returns the name of the additional loadBalancer used as control plane endpoint, or "" if the API loadBalancer is used
+func (network *OscNetwork) GetEndpointLoadBalancerName() string {
+	for _, alb := range network.AdditionalLoadBalancers {
+		if alb.UseAsControlPlaneEndpoint {
+			return alb.LoadBalancerName
+		}
+	}
+	return ""
+}
@@ -28,6 +28,27 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscAdditionalLoadBalancer) DeepCopyInto(out *OscAdditionalLoadBalancer) {
+	*out = *in
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]OscLoadBalancerListener, len(*in))
+		copy(*out, *in)
+	}
+	out.HealthCheck = in.HealthCheck
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscAdditionalLoadBalancer.
+func (in *OscAdditionalLoadBalancer) DeepCopy() *OscAdditionalLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(OscAdditionalLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscAdditionalSecurityRules) DeepCopyInto(out *OscAdditionalSecurityRules) {
 	*out = *in
@@ -206,6 +227,97 @@ func (in *OscClusterResources) DeepCopyInto(out *OscClusterResources) {
 			(*out)[key] = val
 		}
 	}
+	if in.VirtualGateway != nil {
+		in, out := &in.VirtualGateway, &out.VirtualGateway
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClientGateway != nil {
+		in, out := &in.ClientGateway, &out.ClientGateway
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VpnConnection != nil {
+		in, out := &in.VpnConnection, &out.VpnConnection
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DhcpOptions != nil {
+		in, out := &in.DhcpOptions, &out.DhcpOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityGroupMemberRules != nil {
+		in, out := &in.SecurityGroupMemberRules, &out.SecurityGroupMemberRules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NatFailover != nil {
+		in, out := &in.NatFailover, &out.NatFailover
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SubnetClaims != nil {
+		in, out := &in.SubnetClaims, &out.SubnetClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Creating != nil {
+		in, out := &in.Creating, &out.Creating
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServerCertificates != nil {
+		in, out := &in.ServerCertificates, &out.ServerCertificates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SharedRoleTags != nil {
+		in, out := &in.SharedRoleTags, &out.SharedRoleTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CniProfile != nil {
+		in, out := &in.CniProfile, &out.CniProfile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EgressDestinations != nil {
+		in, out := &in.EgressDestinations, &out.EgressDestinations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscClusterResources.
@@ -415,6 +527,31 @@ func (in *OscCredentials) DeepCopy() *OscCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscDhcpOptions) DeepCopyInto(out *OscDhcpOptions) {
+	*out = *in
+	if in.DomainNameServers != nil {
+		in, out := &in.DomainNameServers, &out.DomainNameServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NtpServers != nil {
+		in, out := &in.NtpServers, &out.NtpServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscDhcpOptions.
+func (in *OscDhcpOptions) DeepCopy() *OscDhcpOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(OscDhcpOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscFGPU) DeepCopyInto(out *OscFGPU) {
 	*out = *in
@@ -445,6 +582,36 @@ func (in *OscImage) DeepCopy() *OscImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscEgressRule) DeepCopyInto(out *OscEgressRule) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.IpRanges != nil {
+		in, out := &in.IpRanges, &out.IpRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]OscEgressDestination, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscEgressRule.
+func (in *OscEgressRule) DeepCopy() *OscEgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OscEgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscInternetService) DeepCopyInto(out *OscInternetService) {
 	*out = *in
@@ -480,6 +647,12 @@ func (in *OscLoadBalancer) DeepCopyInto(out *OscLoadBalancer) {
 	*out = *in
 	out.Listener = in.Listener
 	out.HealthCheck = in.HealthCheck
+	if in.AdditionalListeners != nil {
+		in, out := &in.AdditionalListeners, &out.AdditionalListeners
+		*out = make([]OscLoadBalancerListener, len(*in))
+		copy(*out, *in)
+	}
+	out.AccessLog = in.AccessLog
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscLoadBalancer.
@@ -492,6 +665,21 @@ func (in *OscLoadBalancer) DeepCopy() *OscLoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscLoadBalancerAccessLog) DeepCopyInto(out *OscLoadBalancerAccessLog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscLoadBalancerAccessLog.
+func (in *OscLoadBalancerAccessLog) DeepCopy() *OscLoadBalancerAccessLog {
+	if in == nil {
+		return nil
+	}
+	out := new(OscLoadBalancerAccessLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscLoadBalancerHealthCheck) DeepCopyInto(out *OscLoadBalancerHealthCheck) {
 	*out = *in
@@ -619,6 +807,13 @@ func (in *OscMachineResources) DeepCopyInto(out *OscMachineResources) {
 			(*out)[key] = val
 		}
 	}
+	if in.Creating != nil {
+		in, out := &in.Creating, &out.Creating
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscMachineResources.
@@ -859,6 +1054,21 @@ func (in *OscMachineWebhook) DeepCopy() *OscMachineWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNatFailover) DeepCopyInto(out *OscNatFailover) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNatFailover.
+func (in *OscNatFailover) DeepCopy() *OscNatFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNatFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscNatService) DeepCopyInto(out *OscNatService) {
 	*out = *in
@@ -877,6 +1087,7 @@ func (in *OscNatService) DeepCopy() *OscNatService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscNet) DeepCopyInto(out *OscNet) {
 	*out = *in
+	in.DhcpOptions.DeepCopyInto(&out.DhcpOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNet.
@@ -914,9 +1125,24 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]OscDisable, len(*in))
 		copy(*out, *in)
 	}
-	out.LoadBalancer = in.LoadBalancer
-	out.Net = in.Net
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make([]OscAdditionalLoadBalancer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Net.DeepCopyInto(&out.Net)
 	out.NetPeering = in.NetPeering
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make([]OscPeering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Vpn.DeepCopyInto(&out.Vpn)
 	if in.NetAccessPoints != nil {
 		in, out := &in.NetAccessPoints, &out.NetAccessPoints
 		*out = make([]OscNetAccessPointService, len(*in))
@@ -934,6 +1160,8 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.SubnetAllocation = in.SubnetAllocation
+	in.SubnetLayout.DeepCopyInto(&out.SubnetLayout)
 	out.InternetService = in.InternetService
 	out.NatService = in.NatService
 	if in.NatServices != nil {
@@ -941,6 +1169,7 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]OscNatService, len(*in))
 		copy(*out, *in)
 	}
+	out.NatFailover = in.NatFailover
 	if in.RouteTables != nil {
 		in, out := &in.RouteTables, &out.RouteTables
 		*out = make([]OscRouteTable, len(*in))
@@ -962,6 +1191,11 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CniProfileRef != nil {
+		in, out := &in.CniProfileRef, &out.CniProfileRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.PublicIps != nil {
 		in, out := &in.PublicIps, &out.PublicIps
 		*out = make([]*OscPublicIp, len(*in))
@@ -990,6 +1224,13 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EgressRules != nil {
+		in, out := &in.EgressRules, &out.EgressRules
+		*out = make([]OscEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ReconciliationRules != nil {
 		in, out := &in.ReconciliationRules, &out.ReconciliationRules
 		*out = make([]OscReconciliationRule, len(*in))
@@ -1101,6 +1342,32 @@ func (in *OscNodeResource) DeepCopy() *OscNodeResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscPeering) DeepCopyInto(out *OscPeering) {
+	*out = *in
+	out.Credentials = in.Credentials
+	if in.PeerSubnetIDs != nil {
+		in, out := &in.PeerSubnetIDs, &out.PeerSubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscPeering.
+func (in *OscPeering) DeepCopy() *OscPeering {
+	if in == nil {
+		return nil
+	}
+	out := new(OscPeering)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscPlacement) DeepCopyInto(out *OscPlacement) {
 	*out = *in
@@ -1334,6 +1601,21 @@ func (in *OscSecurityGroupRule) DeepCopyInto(out *OscSecurityGroupRule) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SecurityGroupNames != nil {
+		in, out := &in.SecurityGroupNames, &out.SecurityGroupNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupRoles != nil {
+		in, out := &in.SecurityGroupRoles, &out.SecurityGroupRoles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupIds != nil {
+		in, out := &in.SecurityGroupIds, &out.SecurityGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSecurityGroupRule.
@@ -1366,6 +1648,56 @@ func (in *OscSubnet) DeepCopy() *OscSubnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetAllocation) DeepCopyInto(out *OscSubnetAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetAllocation.
+func (in *OscSubnetAllocation) DeepCopy() *OscSubnetAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetLayout) DeepCopyInto(out *OscSubnetLayout) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]OscSubnetPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetLayout.
+func (in *OscSubnetLayout) DeepCopy() *OscSubnetLayout {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetLayout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetPool) DeepCopyInto(out *OscSubnetPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetPool.
+func (in *OscSubnetPool) DeepCopy() *OscSubnetPool {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscVm) DeepCopyInto(out *OscVm) {
 	*out = *in
@@ -1385,6 +1717,11 @@ func (in *OscVm) DeepCopyInto(out *OscVm) {
 		*out = make([]OscPrivateIpElement, len(*in))
 		copy(*out, *in)
 	}
+	if in.PrivateIpPoolRef != nil {
+		in, out := &in.PrivateIpPoolRef, &out.PrivateIpPoolRef
+		*out = new(v1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecurityGroupNames != nil {
 		in, out := &in.SecurityGroupNames, &out.SecurityGroupNames
 		*out = make([]OscSecurityGroupElement, len(*in))
@@ -1424,3 +1761,45 @@ func (in *OscVolume) DeepCopy() *OscVolume {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscVpn) DeepCopyInto(out *OscVpn) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]OscVpnConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscVpn.
+func (in *OscVpn) DeepCopy() *OscVpn {
+	if in == nil {
+		return nil
+	}
+	out := new(OscVpn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscVpnConnection) DeepCopyInto(out *OscVpnConnection) {
+	*out = *in
+	if in.StaticRoutes != nil {
+		in, out := &in.StaticRoutes, &out.StaticRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscVpnConnection.
+func (in *OscVpnConnection) DeepCopy() *OscVpnConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(OscVpnConnection)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -10,6 +10,8 @@ import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 const (
 	NetCreatedReason              string                  = "NetCreated"
+	NetRangeAllocatedReason       string                  = "NetRangeAllocated"
+	DhcpOptionsCreatedReason      string                  = "DhcpOptionsCreated"
 	NetReadyCondition             clusterv1.ConditionType = "NetReady"
 	NetReconciliationFailedReason string                  = "NetReconciliationFailed"
 )
@@ -20,6 +22,23 @@ const (
 	NetPeeringReconciliationFailedReason string                  = "NetPeeringReconciliationFailed"
 )
 
+const (
+	PeeringCreatedReason              string = "PeeringCreated"
+	PeeringAcceptedReason             string = "PeeringAccepted"
+	PeeringReconciliationFailedReason string = "PeeringReconciliationFailed"
+)
+
+// PeeringReadyCondition returns the condition of a peering.
+func PeeringReadyCondition(name string) clusterv1.ConditionType {
+	return clusterv1.ConditionType("PeeringReady/" + name)
+}
+
+const (
+	VpnCreatedReason              string                  = "VpnCreated"
+	VpnReadyCondition             clusterv1.ConditionType = "VpnReady"
+	VpnReconciliationFailedReason string                  = "VpnReconciliationFailed"
+)
+
 const (
 	NetAccessPointCreatedReason               string                  = "NetAccessPointCreated"
 	NetAccessPointsReadyCondition             clusterv1.ConditionType = "NetAccessPointsReady"
@@ -44,6 +63,13 @@ const (
 	NatServicesReconciliationFailedReason string                  = "NatServicesReconciliationFailed"
 )
 
+const (
+	NatServicesHealthyCondition clusterv1.ConditionType = "NatServicesHealthy"
+	NatFailoverReason           string                  = "NatFailover"
+	NatRestoredReason           string                  = "NatRestored"
+	NatFailoverFailedReason     string                  = "NatFailoverFailed"
+)
+
 const (
 	RouteTableCreatedReason              string                  = "RouteTableCreated"
 	RouteTablesReadyCondition            clusterv1.ConditionType = "RouteTablesReady"
@@ -57,15 +83,17 @@ const (
 	VmStoppedReason                       string                  = "VmStopped"
 	VmNotReadyReason                      string                  = "VmNotReady"
 	VmCreatedReason                       string                  = "VmCreated"
+	VmAdoptedReason                       string                  = "VmAdopted"
 	VmProvisionFailedReason               string                  = "VmProvisionFailed"
 	WaitingForClusterInfrastructureReason string                  = "WaitingForClusterInfrastructure"
 	WaitingForBootstrapDataReason         string                  = "WaitingForBoostrapData"
 )
 
 const (
-	IPAllocated         string = "IPAllocated"
-	FGPUAllocatedReason string = "fGPUAllocated"
-	FGPUAttachedReason  string = "fGPUAttached"
+	IPAllocated            string = "IPAllocated"
+	IPAddressClaimedReason string = "IPAddressClaimed"
+	FGPUAllocatedReason    string = "fGPUAllocated"
+	FGPUAttachedReason     string = "fGPUAttached"
 )
 
 const (
@@ -80,6 +108,11 @@ const (
 	LoadBalancerFailedReason   string                  = "LoadBalancerFailed"
 )
 
+// AdditionalLoadBalancerReadyCondition returns the condition of an additional loadBalancer.
+func AdditionalLoadBalancerReadyCondition(name string) clusterv1.ConditionType {
+	return clusterv1.ConditionType("LoadBalancerReady/" + name)
+}
+
 const (
 	VolumeReadyCondition             clusterv1.ConditionType = "VolumeReady"
 	VolumeReconciliationFailedReason string                  = "VolumeFailed"
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultNetPoolPrefixLength is the default prefix length of the ip ranges allocated from an OscNetPool.
+const DefaultNetPoolPrefixLength = 16
+
+// OscNetPoolSpec defines the desired state of OscNetPool
+type OscNetPoolSpec struct {
+	// The ip range in CIDR notation from which the ip ranges of Nets are allocated
+	IpRange string `json:"ipRange"`
+	// The prefix length of the allocated ip ranges (default: 16)
+	// +optional
+	PrefixLength int32 `json:"prefixLength,omitempty"`
+	// Ip ranges in CIDR notation excluded from allocations
+	// +optional
+	ExcludedIpRanges []string `json:"excludedIpRanges,omitempty"`
+}
+
+// OscNetPoolAllocation is an ip range allocated to an OscCluster.
+type OscNetPoolAllocation struct {
+	// The namespace of the OscCluster
+	Namespace string `json:"namespace"`
+	// The name of the OscCluster
+	Name string `json:"name"`
+	// The UID of the OscCluster
+	UID string `json:"uid"`
+}
+
+// OscNetPoolStatus defines the observed state of OscNetPool
+type OscNetPoolStatus struct {
+	// The allocated ip ranges (key: ip range, value: the OscCluster using it)
+	// +optional
+	Allocations map[string]OscNetPoolAllocation `json:"allocations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=oscnetpools,scope=Cluster,categories=cluster-api
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="IpRange",type="string",JSONPath=".spec.ipRange"
+
+// OscNetPool is the Schema for the oscnetpools API
+type OscNetPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OscNetPoolSpec   `json:"spec,omitempty"`
+	Status OscNetPoolStatus `json:"status,omitempty"`
+}
+
+// GetPrefixLength returns the prefix length of allocated ip ranges.
+func (p *OscNetPool) GetPrefixLength() int {
+	if p.Spec.PrefixLength > 0 {
+		return int(p.Spec.PrefixLength)
+	}
+	return DefaultNetPoolPrefixLength
+}
+
+//+kubebuilder:object:root=true
+
+// OscNetPoolList contains a list of OscNetPool
+type OscNetPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OscNetPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OscNetPool{}, &OscNetPoolList{})
+}
@@ -28,6 +28,27 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscAdditionalLoadBalancer) DeepCopyInto(out *OscAdditionalLoadBalancer) {
+	*out = *in
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]OscLoadBalancerListener, len(*in))
+		copy(*out, *in)
+	}
+	out.HealthCheck = in.HealthCheck
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscAdditionalLoadBalancer.
+func (in *OscAdditionalLoadBalancer) DeepCopy() *OscAdditionalLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(OscAdditionalLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscAdditionalSecurityRules) DeepCopyInto(out *OscAdditionalSecurityRules) {
 	*out = *in
@@ -206,6 +227,97 @@ func (in *OscClusterResources) DeepCopyInto(out *OscClusterResources) {
 			(*out)[key] = val
 		}
 	}
+	if in.VirtualGateway != nil {
+		in, out := &in.VirtualGateway, &out.VirtualGateway
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClientGateway != nil {
+		in, out := &in.ClientGateway, &out.ClientGateway
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VpnConnection != nil {
+		in, out := &in.VpnConnection, &out.VpnConnection
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DhcpOptions != nil {
+		in, out := &in.DhcpOptions, &out.DhcpOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityGroupMemberRules != nil {
+		in, out := &in.SecurityGroupMemberRules, &out.SecurityGroupMemberRules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NatFailover != nil {
+		in, out := &in.NatFailover, &out.NatFailover
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SubnetClaims != nil {
+		in, out := &in.SubnetClaims, &out.SubnetClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Creating != nil {
+		in, out := &in.Creating, &out.Creating
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServerCertificates != nil {
+		in, out := &in.ServerCertificates, &out.ServerCertificates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SharedRoleTags != nil {
+		in, out := &in.SharedRoleTags, &out.SharedRoleTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CniProfile != nil {
+		in, out := &in.CniProfile, &out.CniProfile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EgressDestinations != nil {
+		in, out := &in.EgressDestinations, &out.EgressDestinations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscClusterResources.
@@ -414,6 +526,31 @@ func (in *OscCredentials) DeepCopy() *OscCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscDhcpOptions) DeepCopyInto(out *OscDhcpOptions) {
+	*out = *in
+	if in.DomainNameServers != nil {
+		in, out := &in.DomainNameServers, &out.DomainNameServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NtpServers != nil {
+		in, out := &in.NtpServers, &out.NtpServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscDhcpOptions.
+func (in *OscDhcpOptions) DeepCopy() *OscDhcpOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(OscDhcpOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscFGPU) DeepCopyInto(out *OscFGPU) {
 	*out = *in
@@ -444,6 +581,36 @@ func (in *OscImage) DeepCopy() *OscImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscEgressRule) DeepCopyInto(out *OscEgressRule) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.IpRanges != nil {
+		in, out := &in.IpRanges, &out.IpRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]OscEgressDestination, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscEgressRule.
+func (in *OscEgressRule) DeepCopy() *OscEgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OscEgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscInternetService) DeepCopyInto(out *OscInternetService) {
 	*out = *in
@@ -479,6 +646,12 @@ func (in *OscLoadBalancer) DeepCopyInto(out *OscLoadBalancer) {
 	*out = *in
 	out.Listener = in.Listener
 	out.HealthCheck = in.HealthCheck
+	if in.AdditionalListeners != nil {
+		in, out := &in.AdditionalListeners, &out.AdditionalListeners
+		*out = make([]OscLoadBalancerListener, len(*in))
+		copy(*out, *in)
+	}
+	out.AccessLog = in.AccessLog
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscLoadBalancer.
@@ -491,6 +664,21 @@ func (in *OscLoadBalancer) DeepCopy() *OscLoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscLoadBalancerAccessLog) DeepCopyInto(out *OscLoadBalancerAccessLog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscLoadBalancerAccessLog.
+func (in *OscLoadBalancerAccessLog) DeepCopy() *OscLoadBalancerAccessLog {
+	if in == nil {
+		return nil
+	}
+	out := new(OscLoadBalancerAccessLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscLoadBalancerHealthCheck) DeepCopyInto(out *OscLoadBalancerHealthCheck) {
 	*out = *in
@@ -618,6 +806,13 @@ func (in *OscMachineResources) DeepCopyInto(out *OscMachineResources) {
 			(*out)[key] = val
 		}
 	}
+	if in.Creating != nil {
+		in, out := &in.Creating, &out.Creating
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscMachineResources.
@@ -857,6 +1052,21 @@ func (in *OscMachineWebhook) DeepCopy() *OscMachineWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNatFailover) DeepCopyInto(out *OscNatFailover) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNatFailover.
+func (in *OscNatFailover) DeepCopy() *OscNatFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNatFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscNatService) DeepCopyInto(out *OscNatService) {
 	*out = *in
@@ -875,6 +1085,7 @@ func (in *OscNatService) DeepCopy() *OscNatService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscNet) DeepCopyInto(out *OscNet) {
 	*out = *in
+	in.DhcpOptions.DeepCopyInto(&out.DhcpOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNet.
@@ -903,6 +1114,122 @@ func (in *OscNetPeering) DeepCopy() *OscNetPeering {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNetPool) DeepCopyInto(out *OscNetPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNetPool.
+func (in *OscNetPool) DeepCopy() *OscNetPool {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNetPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OscNetPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNetPoolAllocation) DeepCopyInto(out *OscNetPoolAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNetPoolAllocation.
+func (in *OscNetPoolAllocation) DeepCopy() *OscNetPoolAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNetPoolAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNetPoolList) DeepCopyInto(out *OscNetPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OscNetPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNetPoolList.
+func (in *OscNetPoolList) DeepCopy() *OscNetPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNetPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OscNetPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNetPoolSpec) DeepCopyInto(out *OscNetPoolSpec) {
+	*out = *in
+	if in.ExcludedIpRanges != nil {
+		in, out := &in.ExcludedIpRanges, &out.ExcludedIpRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNetPoolSpec.
+func (in *OscNetPoolSpec) DeepCopy() *OscNetPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNetPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscNetPoolStatus) DeepCopyInto(out *OscNetPoolStatus) {
+	*out = *in
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make(map[string]OscNetPoolAllocation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscNetPoolStatus.
+func (in *OscNetPoolStatus) DeepCopy() *OscNetPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OscNetPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 	*out = *in
@@ -912,9 +1239,24 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]OscDisable, len(*in))
 		copy(*out, *in)
 	}
-	out.LoadBalancer = in.LoadBalancer
-	out.Net = in.Net
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make([]OscAdditionalLoadBalancer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Net.DeepCopyInto(&out.Net)
 	out.NetPeering = in.NetPeering
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make([]OscPeering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Vpn.DeepCopyInto(&out.Vpn)
 	if in.NetAccessPoints != nil {
 		in, out := &in.NetAccessPoints, &out.NetAccessPoints
 		*out = make([]OscNetAccessPointService, len(*in))
@@ -932,6 +1274,8 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.SubnetAllocation = in.SubnetAllocation
+	in.SubnetLayout.DeepCopyInto(&out.SubnetLayout)
 	out.InternetService = in.InternetService
 	out.NatService = in.NatService
 	if in.NatServices != nil {
@@ -939,6 +1283,7 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]OscNatService, len(*in))
 		copy(*out, *in)
 	}
+	out.NatFailover = in.NatFailover
 	if in.RouteTables != nil {
 		in, out := &in.RouteTables, &out.RouteTables
 		*out = make([]OscRouteTable, len(*in))
@@ -960,6 +1305,11 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CniProfileRef != nil {
+		in, out := &in.CniProfileRef, &out.CniProfileRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	in.Bastion.DeepCopyInto(&out.Bastion)
 	if in.Subregions != nil {
 		in, out := &in.Subregions, &out.Subregions
@@ -976,6 +1326,13 @@ func (in *OscNetwork) DeepCopyInto(out *OscNetwork) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EgressRules != nil {
+		in, out := &in.EgressRules, &out.EgressRules
+		*out = make([]OscEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ReconciliationRules != nil {
 		in, out := &in.ReconciliationRules, &out.ReconciliationRules
 		*out = make([]OscReconciliationRule, len(*in))
@@ -1023,6 +1380,32 @@ func (in *OscNode) DeepCopy() *OscNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscPeering) DeepCopyInto(out *OscPeering) {
+	*out = *in
+	out.Credentials = in.Credentials
+	if in.PeerSubnetIDs != nil {
+		in, out := &in.PeerSubnetIDs, &out.PeerSubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscPeering.
+func (in *OscPeering) DeepCopy() *OscPeering {
+	if in == nil {
+		return nil
+	}
+	out := new(OscPeering)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscPlacement) DeepCopyInto(out *OscPlacement) {
 	*out = *in
@@ -1219,6 +1602,21 @@ func (in *OscSecurityGroupRule) DeepCopyInto(out *OscSecurityGroupRule) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SecurityGroupNames != nil {
+		in, out := &in.SecurityGroupNames, &out.SecurityGroupNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupRoles != nil {
+		in, out := &in.SecurityGroupRoles, &out.SecurityGroupRoles
+		*out = make([]OscRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupIds != nil {
+		in, out := &in.SecurityGroupIds, &out.SecurityGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSecurityGroupRule.
@@ -1251,6 +1649,56 @@ func (in *OscSubnet) DeepCopy() *OscSubnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetAllocation) DeepCopyInto(out *OscSubnetAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetAllocation.
+func (in *OscSubnetAllocation) DeepCopy() *OscSubnetAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetLayout) DeepCopyInto(out *OscSubnetLayout) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]OscSubnetPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetLayout.
+func (in *OscSubnetLayout) DeepCopy() *OscSubnetLayout {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetLayout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscSubnetPool) DeepCopyInto(out *OscSubnetPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscSubnetPool.
+func (in *OscSubnetPool) DeepCopy() *OscSubnetPool {
+	if in == nil {
+		return nil
+	}
+	out := new(OscSubnetPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OscVm) DeepCopyInto(out *OscVm) {
 	*out = *in
@@ -1270,6 +1718,11 @@ func (in *OscVm) DeepCopyInto(out *OscVm) {
 		*out = make([]OscPrivateIpElement, len(*in))
 		copy(*out, *in)
 	}
+	if in.PrivateIpPoolRef != nil {
+		in, out := &in.PrivateIpPoolRef, &out.PrivateIpPoolRef
+		*out = new(v1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecurityGroupNames != nil {
 		in, out := &in.SecurityGroupNames, &out.SecurityGroupNames
 		*out = make([]OscSecurityGroupElement, len(*in))
@@ -1309,3 +1762,45 @@ func (in *OscVolume) DeepCopy() *OscVolume {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscVpn) DeepCopyInto(out *OscVpn) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]OscVpnConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscVpn.
+func (in *OscVpn) DeepCopy() *OscVpn {
+	if in == nil {
+		return nil
+	}
+	out := new(OscVpn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OscVpnConnection) DeepCopyInto(out *OscVpnConnection) {
+	*out = *in
+	if in.StaticRoutes != nil {
+		in, out := &in.StaticRoutes, &out.StaticRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OscVpnConnection.
+func (in *OscVpnConnection) DeepCopy() *OscVpnConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(OscVpnConnection)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -7,17 +7,25 @@ SPDX-License-Identifier: Apache-2.0
 package scope
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math/rand/v2"
 	"net"
+	"net/netip"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/cluster-api-provider-outscale/util/cidr"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -65,6 +73,26 @@ type ClusterScope struct {
 	Cluster     *clusterv1.Cluster
 	OscCluster  *infrastructurev1beta2.OscCluster
 	Tenant      tenant.Tenant
+
+	// cniProfileRules are the rules of a custom CNI, loaded by LoadCniProfile.
+	cniProfileRules []infrastructurev1beta2.OscSecurityGroupRule
+	// cniProfileHash is the hash of the rules of a custom CNI, loaded by LoadCniProfile.
+	cniProfileHash string
+	// egressDestinations are the IP ranges of egress destinations, loaded by LoadEgressDestinations.
+	egressDestinations map[infrastructurev1beta2.OscEgressDestination][]string
+
+	// mu protects the OscCluster status, updated by concurrent reconcilers.
+	mu sync.Mutex
+}
+
+// Lock locks the OscCluster status.
+func (s *ClusterScope) Lock() {
+	s.mu.Lock()
+}
+
+// Unlock unlocks the OscCluster status.
+func (s *ClusterScope) Unlock() {
+	s.mu.Unlock()
 }
 
 // Close closes the scope of the cluster configuration and status
@@ -112,8 +140,10 @@ func (s *ClusterScope) GetNetwork() *infrastructurev1beta2.OscNetwork {
 
 // GetNet return the net of the cluster
 func (s *ClusterScope) GetNet() infrastructurev1beta2.OscNet {
-	if s.OscCluster.Spec.Network.Net.IsZero() {
-		return infrastructurev1beta2.DefaultNet
+	if !s.OscCluster.Spec.Network.Net.HasIpRange() {
+		net := infrastructurev1beta2.DefaultNet
+		net.DhcpOptions = s.OscCluster.Spec.Network.Net.DhcpOptions
+		return net
 	}
 	return s.OscCluster.Spec.Network.Net
 }
@@ -142,32 +172,141 @@ func (s *ClusterScope) GetSubregions() []string {
 	return []string{s.GetNetwork().SubregionName}
 }
 
+// defaultSubnetRoles lists the roles of the subnets created in each subregion, when no subnets are set.
+var defaultSubnetRoles = [][]infrastructurev1beta2.OscRole{
+	{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
+	{infrastructurev1beta2.RoleWorker},
+	{infrastructurev1beta2.RoleControlPlane},
+}
+
+// separateNatSubnetRoles lists the roles of the subnets created in each subregion, when NAT subnets are separate.
+var separateNatSubnetRoles = [][]infrastructurev1beta2.OscRole{
+	{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion},
+	{infrastructurev1beta2.RoleNat},
+	{infrastructurev1beta2.RoleWorker},
+	{infrastructurev1beta2.RoleControlPlane},
+}
+
+// DefaultSubnetPrefixLength is the default prefix length of allocated subnets.
+const DefaultSubnetPrefixLength = 24
+
+// AllocatesSubnets returns true if the IP ranges of subnets are claimed in the net.
+func (s *ClusterScope) AllocatesSubnets() bool {
+	return s.GetNetwork().SubnetAllocation.Enable && len(s.OscCluster.Spec.Network.Subnets) == 0
+}
+
+// GetSubnetPrefixLength returns the prefix length of a subnet created by the cluster.
+func (s *ClusterScope) GetSubnetPrefixLength(subnet infrastructurev1beta2.OscSubnet) int {
+	layout := s.GetNetwork().SubnetLayout
+	var bits int32
+	switch {
+	case subnet.Pool != "":
+		for _, pool := range layout.Pools {
+			if pool.Name == subnet.Pool {
+				bits = pool.PrefixLength
+			}
+		}
+		bits = cmp.Or(bits, layout.WorkerPrefixLength)
+	case slices.Equal(subnet.Roles, []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleNat}):
+		bits = cmp.Or(layout.NatPrefixLength, layout.PublicPrefixLength)
+	case s.SubnetIsPublic(subnet):
+		bits = layout.PublicPrefixLength
+	case s.SubnetHasRole(subnet, infrastructurev1beta2.RoleControlPlane):
+		bits = layout.ControlPlanePrefixLength
+	default:
+		bits = layout.WorkerPrefixLength
+	}
+	return int(cmp.Or(bits, s.GetNetwork().SubnetAllocation.PrefixLength, DefaultSubnetPrefixLength))
+}
+
+// SubnetClaimKey returns the key of the claim of an allocated subnet.
+func SubnetClaimKey(subnet infrastructurev1beta2.OscSubnet) string {
+	roles := make([]string, 0, len(subnet.Roles))
+	for _, role := range subnet.Roles {
+		roles = append(roles, string(role))
+	}
+	key := subnet.SubregionName + "/" + strings.Join(roles, ",")
+	if subnet.Pool != "" {
+		key += "/" + subnet.Pool
+	}
+	return key
+}
+
+// getLayoutSubnets returns the subnets created when no subnets are set, without their IP ranges.
+// Pool subnets come last, for pools to be added without changing the ranges of other subnets.
+func (s *ClusterScope) getLayoutSubnets() []infrastructurev1beta2.OscSubnet {
+	layout := s.GetNetwork().SubnetLayout
+	roles := defaultSubnetRoles
+	if layout.SeparateNatSubnets {
+		roles = separateNatSubnetRoles
+	}
+	fds := s.GetSubregions()
+	subnets := make([]infrastructurev1beta2.OscSubnet, 0, (len(roles)+len(layout.Pools))*len(fds))
+	for _, fd := range fds {
+		for _, roles := range roles {
+			subnets = append(subnets, infrastructurev1beta2.OscSubnet{
+				Roles:         slices.Clone(roles),
+				SubregionName: fd,
+			})
+		}
+	}
+	for _, pool := range layout.Pools {
+		for _, fd := range fds {
+			subnets = append(subnets, infrastructurev1beta2.OscSubnet{
+				Name:          "Subnet for pool " + pool.Name + " of " + s.OscCluster.Name + "/" + fd,
+				Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				SubregionName: fd,
+				Pool:          pool.Name,
+			})
+		}
+	}
+	return subnets
+}
+
 // GetSubnets returns the subnets of the cluster.
 func (s *ClusterScope) GetSubnets() []infrastructurev1beta2.OscSubnet {
 	if len(s.OscCluster.Spec.Network.Subnets) > 0 {
 		return s.OscCluster.Spec.Network.Subnets
 	}
-	_, net, err := net.ParseCIDR(s.GetNet().IpRange)
-	if err != nil {
-		return nil
-	}
-	fds := s.GetSubregions()
-	subnets := make([]infrastructurev1beta2.OscSubnet, 0, 3*len(fds))
-	net.IP[2]++
-	net.Mask[1], net.Mask[2] = 255, 255
-	for _, fd := range fds {
-		for _, roles := range [][]infrastructurev1beta2.OscRole{
-			{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
-			{infrastructurev1beta2.RoleWorker},
-			{infrastructurev1beta2.RoleControlPlane},
-		} {
+	subnets := s.getLayoutSubnets()
+	switch {
+	case s.AllocatesSubnets():
+		s.Lock()
+		defer s.Unlock()
+		claims := s.GetResources().SubnetClaims
+		for i := range subnets {
+			subnets[i].IpSubnetRange = claims[SubnetClaimKey(subnets[i])]
+		}
+	case s.GetNetwork().SubnetLayout.IsZero():
+		_, net, err := net.ParseCIDR(s.GetNet().IpRange)
+		if err != nil {
+			return nil
+		}
+		net.IP[2]++
+		net.Mask[1], net.Mask[2] = 255, 255
+		for i := range subnets {
 			net.IP[2]++
-			subnet := infrastructurev1beta2.OscSubnet{
-				IpSubnetRange: net.String(),
-				Roles:         roles,
-				SubregionName: fd,
-			}
-			subnets = append(subnets, subnet)
+			subnets[i].IpSubnetRange = net.String()
+		}
+	default:
+		parent, err := netip.ParsePrefix(s.GetNet().IpRange)
+		if err != nil {
+			return nil
+		}
+		var reserved []netip.Prefix
+		if p, err := netip.ParsePrefix(s.GetNetwork().SubnetLayout.ReservedIpRange); err == nil {
+			reserved = append(reserved, p)
+		}
+		bits := make([]int, 0, len(subnets))
+		for _, subnet := range subnets {
+			bits = append(bits, s.GetSubnetPrefixLength(subnet))
+		}
+		ranges, err := cidr.AllocateAll(parent, reserved, bits)
+		if err != nil {
+			return nil
+		}
+		for i := range subnets {
+			subnets[i].IpSubnetRange = ranges[i].String()
 		}
 	}
 	return subnets
@@ -179,10 +318,17 @@ func (s *ClusterScope) GetSubnet(name string, role infrastructurev1beta2.OscRole
 	if subregion == "" {
 		subregion = s.GetDefaultSubregion()
 	}
-	for _, spec := range s.GetSubnets() {
+	subnets := s.GetSubnets()
+	if name != "" {
+		for _, spec := range subnets {
+			if spec.Name == name {
+				return spec, nil
+			}
+		}
+	}
+	for _, spec := range subnets {
 		switch {
-		case name != "" && spec.Name == name:
-			return spec, nil
+		case spec.Pool != "":
 		case !s.SubnetHasRole(spec, role):
 		case s.GetSubnetSubregion(spec) == subregion:
 			return spec, nil
@@ -191,6 +337,19 @@ func (s *ClusterScope) GetSubnet(name string, role infrastructurev1beta2.OscRole
 	return infrastructurev1beta2.OscSubnet{}, ErrNoSubnetFound
 }
 
+// GetPoolSubnet returns the subnet of a pool, in a subregion.
+func (s *ClusterScope) GetPoolSubnet(pool string, role infrastructurev1beta2.OscRole, subregion string) (infrastructurev1beta2.OscSubnet, error) {
+	if subregion == "" {
+		subregion = s.GetDefaultSubregion()
+	}
+	for _, spec := range s.GetSubnets() {
+		if spec.Pool == pool && s.SubnetHasRole(spec, role) && s.GetSubnetSubregion(spec) == subregion {
+			return spec, nil
+		}
+	}
+	return infrastructurev1beta2.OscSubnet{}, ErrNoSubnetFound
+}
+
 func (s *ClusterScope) SubnetHasRole(spec infrastructurev1beta2.OscSubnet, role infrastructurev1beta2.OscRole) bool {
 	if len(spec.Roles) > 0 {
 		return slices.Contains(spec.Roles, role)
@@ -316,6 +475,28 @@ func (s *ClusterScope) GetNatServiceName(nat infrastructurev1beta2.OscNatService
 	return name
 }
 
+// IsNatFailoverEnabled checks if the natService health monitor is enabled.
+func (s *ClusterScope) IsNatFailoverEnabled() bool {
+	return s.GetNetwork().NatFailover.Enable && !s.IsInternetDisabled() && !s.GetNetwork().UseExisting.Net
+}
+
+// GetNatFailoverCheckInterval returns the interval between two natService health checks.
+func (s *ClusterScope) GetNatFailoverCheckInterval() time.Duration {
+	interval := s.GetNetwork().NatFailover.CheckInterval
+	if interval == 0 {
+		interval = infrastructurev1beta2.DefaultNatFailoverCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// GetNatServiceSubregion returns the subregion of a natService.
+func (s *ClusterScope) GetNatServiceSubregion(nat infrastructurev1beta2.OscNatService) string {
+	if nat.SubregionName != "" {
+		return nat.SubregionName
+	}
+	return s.GetDefaultSubregion()
+}
+
 // GetNatServiceClientToken return the client token for a nat service
 func (s *ClusterScope) GetNatServiceClientToken(nat infrastructurev1beta2.OscNatService) string {
 	if nat.Name != "" {
@@ -363,6 +544,23 @@ func (s *ClusterScope) HasIPRestriction() bool {
 	return len(s.OscCluster.Spec.Network.AllowFromIPRanges) > 0
 }
 
+// GetVpnIPRanges returns the on-premises IP ranges routed through the VPN connections.
+func (s *ClusterScope) GetVpnIPRanges() []string {
+	vpn := s.GetNetwork().Vpn
+	if !vpn.Enable {
+		return nil
+	}
+	var ipRanges []string
+	for _, conn := range vpn.Connections {
+		for _, ipRange := range conn.StaticRoutes {
+			if !slices.Contains(ipRanges, ipRange) {
+				ipRanges = append(ipRanges, ipRange)
+			}
+		}
+	}
+	return ipRanges
+}
+
 func (s *ClusterScope) getAdditionalRules(roles ...infrastructurev1beta2.OscRole) []infrastructurev1beta2.OscSecurityGroupRule {
 	for _, ar := range s.GetNetwork().AdditionalSecurityRules {
 		if slices.Equal(roles, ar.Roles) {
@@ -372,6 +570,60 @@ func (s *ClusterScope) getAdditionalRules(roles ...infrastructurev1beta2.OscRole
 	return nil
 }
 
+// getBackendListeners returns the additional listeners forwarding to the VMs of a role, the API listener being excluded.
+func (s *ClusterScope) getBackendListeners(role infrastructurev1beta2.OscRole) []infrastructurev1beta2.OscLoadBalancerListener {
+	var listeners []infrastructurev1beta2.OscLoadBalancerListener
+	if role == infrastructurev1beta2.RoleControlPlane && !s.IsLBDisabled() {
+		listeners = append(listeners, s.GetLoadBalancer().AdditionalListeners...)
+	}
+	for _, alb := range s.GetNetwork().AdditionalLoadBalancers {
+		if alb.BackendRole == role {
+			lb := alb.GetLoadBalancer()
+			listeners = append(listeners, lb.GetListeners()...)
+		}
+	}
+	return listeners
+}
+
+// getListenerRules returns the loadBalancer rules needed by additional listeners and loadBalancers.
+func (s *ClusterScope) getListenerRules(allowedIn []string) []infrastructurev1beta2.OscSecurityGroupRule {
+	var rules []infrastructurev1beta2.OscSecurityGroupRule
+	for _, role := range []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane, infrastructurev1beta2.RoleWorker} {
+		for _, listener := range s.getBackendListeners(role) {
+			rules = appendRules(rules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Inbound", IpProtocol: "tcp", FromPortRange: listener.LoadBalancerPort, ToPortRange: listener.LoadBalancerPort, IpRanges: allowedIn,
+			})
+			rules = appendRules(rules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Outbound", IpProtocol: "tcp", FromPortRange: listener.BackendPort, ToPortRange: listener.BackendPort, IpRange: s.GetNet().IpRange,
+			})
+		}
+	}
+	return rules
+}
+
+// getBackendRules returns the rules allowing loadBalancers to reach the backend ports of the VMs of a role.
+func (s *ClusterScope) getBackendRules(role infrastructurev1beta2.OscRole) []infrastructurev1beta2.OscSecurityGroupRule {
+	var rules []infrastructurev1beta2.OscSecurityGroupRule
+	for _, listener := range s.getBackendListeners(role) {
+		rules = appendRules(rules, infrastructurev1beta2.OscSecurityGroupRule{
+			Flow: "Inbound", IpProtocol: "tcp", FromPortRange: listener.BackendPort, ToPortRange: listener.BackendPort, IpRange: s.GetNet().IpRange,
+		})
+	}
+	return rules
+}
+
+// appendRules appends rules, unless already present.
+func appendRules(rules []infrastructurev1beta2.OscSecurityGroupRule, add ...infrastructurev1beta2.OscSecurityGroupRule) []infrastructurev1beta2.OscSecurityGroupRule {
+	for _, rule := range add {
+		if !slices.ContainsFunc(rules, func(r infrastructurev1beta2.OscSecurityGroupRule) bool {
+			return equality.Semantic.DeepEqual(r, rule)
+		}) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
 // GetSecurityGroups returns the list of all security groups for the cluster.
 func (s *ClusterScope) GetSecurityGroups() []infrastructurev1beta2.OscSecurityGroup {
 	if len(s.OscCluster.Spec.Network.SecurityGroups) > 0 {
@@ -383,7 +635,7 @@ func (s *ClusterScope) GetSecurityGroups() []infrastructurev1beta2.OscSecurityGr
 func (s *ClusterScope) getManualSecurityGroups() []infrastructurev1beta2.OscSecurityGroup {
 	allowedIn := s.OscCluster.Spec.Network.AllowFromIPRanges
 	allowedOut := s.OscCluster.Spec.Network.AllowToIPRanges
-	if len(allowedIn) == 0 && len(allowedOut) == 0 {
+	if len(allowedIn) == 0 && len(allowedOut) == 0 && !s.hasEgressRules() {
 		return s.OscCluster.Spec.Network.SecurityGroups
 	}
 	sgs := slices.Clone(s.OscCluster.Spec.Network.SecurityGroups)
@@ -404,6 +656,19 @@ func (s *ClusterScope) getManualSecurityGroups() []infrastructurev1beta2.OscSecu
 			}
 		}
 	}
+	if s.hasEgressRules() {
+		// egress rules are added to securityGroups dedicated to a single role
+		for i := range sgs {
+			if len(sgs[i].Roles) != 1 {
+				continue
+			}
+			switch role := sgs[i].Roles[0]; role {
+			case infrastructurev1beta2.RoleControlPlane, infrastructurev1beta2.RoleWorker, infrastructurev1beta2.RoleBastion:
+				sgs[i].SecurityGroupRules = append(sgs[i].SecurityGroupRules, s.getEgressRules(role, allowedOut)...)
+			}
+		}
+		return sgs
+	}
 	if len(allowedOut) > 0 {
 		for i := range sgs {
 			if (slices.Contains(sgs[i].Roles, infrastructurev1beta2.RoleWorker) &&
@@ -432,9 +697,13 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 		}
 		allSN = append(allSN, sn.IpSubnetRange)
 	}
+	vpnIPRanges := s.GetVpnIPRanges()
 	allowedIn := s.OscCluster.Spec.Network.AllowFromIPRanges
-	if len(allowedIn) == 0 {
+	switch {
+	case len(allowedIn) == 0:
 		allowedIn = []string{"0.0.0.0/0"}
+	case len(vpnIPRanges) > 0:
+		allowedIn = append(slices.Clone(allowedIn), vpnIPRanges...)
 	}
 	lb := infrastructurev1beta2.OscSecurityGroup{
 		Name:        s.GetName() + "-lb",
@@ -446,6 +715,7 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 		},
 		Authoritative: true,
 	}
+	lb.SecurityGroupRules = appendRules(lb.SecurityGroupRules, s.getListenerRules(allowedIn)...)
 	lb.SecurityGroupRules = append(lb.SecurityGroupRules, s.getAdditionalRules(infrastructurev1beta2.RoleLoadBalancer)...)
 
 	worker := infrastructurev1beta2.OscSecurityGroup{
@@ -459,6 +729,7 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 		},
 		Authoritative: true,
 	}
+	worker.SecurityGroupRules = appendRules(worker.SecurityGroupRules, s.getBackendRules(infrastructurev1beta2.RoleWorker)...)
 	worker.SecurityGroupRules = append(worker.SecurityGroupRules, s.getAdditionalRules(infrastructurev1beta2.RoleWorker)...)
 
 	controlplane := infrastructurev1beta2.OscSecurityGroup{
@@ -472,6 +743,7 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 		},
 		Authoritative: true,
 	}
+	controlplane.SecurityGroupRules = appendRules(controlplane.SecurityGroupRules, s.getBackendRules(infrastructurev1beta2.RoleControlPlane)...)
 	controlplane.SecurityGroupRules = append(controlplane.SecurityGroupRules, s.getAdditionalRules(infrastructurev1beta2.RoleControlPlane)...)
 
 	allowedOut := s.OscCluster.Spec.Network.AllowToIPRanges
@@ -479,37 +751,40 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 	case len(allowedOut) == 0:
 		allowedOut = []string{"0.0.0.0/0"}
 	case allowedOut[0] == "":
-		allowedOut = nil
+		allowedOut = vpnIPRanges
+	case len(vpnIPRanges) > 0:
+		allowedOut = append(slices.Clone(allowedOut), vpnIPRanges...)
+	}
+	// With egress rules, outbound traffic is allowed per role instead of on the node securityGroup
+	if s.hasEgressRules() {
+		worker.SecurityGroupRules = append(worker.SecurityGroupRules, s.getEgressRules(infrastructurev1beta2.RoleWorker, allowedOut)...)
+		controlplane.SecurityGroupRules = append(controlplane.SecurityGroupRules, s.getEgressRules(infrastructurev1beta2.RoleControlPlane, allowedOut)...)
 	}
 
 	node := infrastructurev1beta2.OscSecurityGroup{
 		Name:        s.GetName() + "-node",
 		Description: "Node securityGroup for " + s.GetName(),
 		Roles:       []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane, infrastructurev1beta2.RoleWorker},
-		SecurityGroupRules: []infrastructurev1beta2.OscSecurityGroupRule{
-			// Calico - see https://docs.tigera.io/calico/latest/getting-started/kubernetes/requirements#network-requirements
-			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 179, ToPortRange: 179, IpRange: s.GetNet().IpRange},     // BGP
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 4789, ToPortRange: 4789, IpRange: s.GetNet().IpRange},   // VXLAN/flannel
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 5473, ToPortRange: 5473, IpRange: s.GetNet().IpRange},   // Typha
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8285, ToPortRange: 8285, IpRange: s.GetNet().IpRange},   // Flannel
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51820, ToPortRange: 51821, IpRange: s.GetNet().IpRange}, // Wiregard
-			{Flow: "Inbound", IpProtocol: "4", FromPortRange: -1, ToPortRange: -1, IpRange: s.GetNet().IpRange},         // IP-in-IP
-
-			// Cillium - see https://docs.cilium.io/en/stable/operations/system_requirements/#firewall-rules
-			{Flow: "Inbound", IpProtocol: "icmp", FromPortRange: 8, ToPortRange: 8, IpRange: s.GetNet().IpRange},        // ICMP
-			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4240, ToPortRange: 4240, IpRange: s.GetNet().IpRange},   // Health
-			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4244, ToPortRange: 4244, IpRange: s.GetNet().IpRange},   // Hubble
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8472, ToPortRange: 8472, IpRange: s.GetNet().IpRange},   // VXLAN
-			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51871, ToPortRange: 51871, IpRange: s.GetNet().IpRange}, // Wiregard
-
-			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 30000, ToPortRange: 32767, IpRange: s.GetNet().IpRange}, // NodePort
-			{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRange: s.GetNet().IpRange},       // internal trafic
-		},
+		SecurityGroupRules: append(s.getCniRules(),
+			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 30000, ToPortRange: 32767, IpRange: s.GetNet().IpRange}, // NodePort
+			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRange: s.GetNet().IpRange},       // internal trafic
+		),
 		Tag:           "OscK8sMainSG",
 		Authoritative: true,
 	}
+	// NodePort access from on-premises networks
+	if len(vpnIPRanges) > 0 {
+		node.SecurityGroupRules = append(node.SecurityGroupRules,
+			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 30000, ToPortRange: 32767, IpRanges: vpnIPRanges},
+		)
+	}
 	// Outbound traffic
-	if len(allowedOut) > 0 {
+	switch {
+	case s.hasEgressRules() && len(vpnIPRanges) > 0:
+		node.SecurityGroupRules = append(node.SecurityGroupRules,
+			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: vpnIPRanges},
+		)
+	case !s.hasEgressRules() && len(allowedOut) > 0:
 		node.SecurityGroupRules = append(node.SecurityGroupRules,
 			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: allowedOut},
 		)
@@ -533,11 +808,7 @@ func (s *ClusterScope) getAutomaticSecurityGroups() []infrastructurev1beta2.OscS
 		Authoritative: true,
 	}
 	// Outbound traffic
-	if len(allowedOut) > 0 {
-		bastion.SecurityGroupRules = append(bastion.SecurityGroupRules,
-			infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: allowedOut},
-		)
-	}
+	bastion.SecurityGroupRules = append(bastion.SecurityGroupRules, s.getEgressRules(infrastructurev1beta2.RoleBastion, allowedOut)...)
 	bastion.SecurityGroupRules = append(bastion.SecurityGroupRules, s.getAdditionalRules(infrastructurev1beta2.RoleBastion)...)
 
 	return []infrastructurev1beta2.OscSecurityGroup{lb, worker, controlplane, node, bastion}
@@ -590,6 +861,8 @@ func (s *ClusterScope) GetSecurityGroupName(sg infrastructurev1beta2.OscSecurity
 
 // SetFailureDomain sets the infrastructure provider failure domain key to the spec given as input.
 func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.OscCluster.Status.FailureDomains == nil {
 		s.OscCluster.Status.FailureDomains = make(clusterv1.FailureDomains)
 	}
@@ -606,6 +879,20 @@ func (s *ClusterScope) GetLoadBalancer() infrastructurev1beta2.OscLoadBalancer {
 	return lb
 }
 
+// GetBackendLoadBalancerNames returns the names of the loadBalancers having the VMs of a role as backends.
+func (s *ClusterScope) GetBackendLoadBalancerNames(role infrastructurev1beta2.OscRole) []string {
+	var names []string
+	if role == infrastructurev1beta2.RoleControlPlane && !s.IsLBDisabled() {
+		names = append(names, s.GetLoadBalancer().LoadBalancerName)
+	}
+	for _, alb := range s.GetNetwork().AdditionalLoadBalancers {
+		if alb.BackendRole == role {
+			names = append(names, alb.LoadBalancerName)
+		}
+	}
+	return names
+}
+
 // GetIpSubnetRange return IpSubnetRang from the subnet
 func (s *ClusterScope) GetIpSubnetRange(name string) string {
 	subnets := s.OscCluster.Spec.Network.Subnets
@@ -630,6 +917,8 @@ func (s *ClusterScope) GetSecurityGroupRule(name string) []infrastructurev1beta2
 
 // SetControlPlaneEndpoint set controlPlane endpoint
 func (s *ClusterScope) SetControlPlaneEndpoint(apiEndpoint clusterv1.APIEndpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.OscCluster.Spec.ControlPlaneEndpoint = apiEndpoint
 }
 
@@ -704,6 +993,8 @@ func (s *ClusterScope) GetBastionSecurityGroups() []infrastructurev1beta2.OscSec
 
 // SetVmState set vmstate
 func (s *ClusterScope) SetVmState(v osc.VmState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.OscCluster.Status.VmState = &v
 }
 
@@ -717,9 +1008,101 @@ func (s *ClusterScope) GetResources() *infrastructurev1beta2.OscClusterResources
 	return &s.OscCluster.Status.Resources
 }
 
+// ClaimSubnets records the IP ranges claimed for allocated subnets. The status is persisted immediately.
+func (s *ClusterScope) ClaimSubnets(ctx context.Context, claims map[string]string) error {
+	patch := make(map[string]*string, len(claims))
+	for key, ipRange := range claims {
+		patch[key] = &ipRange
+	}
+	obj := &infrastructurev1beta2.OscCluster{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscCluster.Namespace, Name: s.OscCluster.Name}}
+	if err := patchSubnetClaims(ctx, s.Client, obj, patch); err != nil {
+		return fmt.Errorf("cannot record subnet claims: %w", err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	rsrc := s.GetResources()
+	if rsrc.SubnetClaims == nil {
+		rsrc.SubnetClaims = map[string]string{}
+	}
+	maps.Copy(rsrc.SubnetClaims, claims)
+	return nil
+}
+
+// ReleaseSubnetClaim removes the IP range claimed for an allocated subnet, for it to be claimed again. The status is persisted immediately.
+func (s *ClusterScope) ReleaseSubnetClaim(ctx context.Context, subnet infrastructurev1beta2.OscSubnet) error {
+	key := SubnetClaimKey(subnet)
+	obj := &infrastructurev1beta2.OscCluster{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscCluster.Namespace, Name: s.OscCluster.Name}}
+	if err := patchSubnetClaims(ctx, s.Client, obj, map[string]*string{key: nil}); err != nil {
+		return fmt.Errorf("cannot release subnet claim: %w", err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	delete(s.GetResources().SubnetClaims, key)
+	return nil
+}
+
+// BeginCreation records in the status that a resource is being created, with the ids of existing resources that
+// might be mistaken with it. The status is persisted immediately.
+func (s *ClusterScope) BeginCreation(ctx context.Context, key string, existing []string) error {
+	return s.setCreation(ctx, key, encodeCreation(existing))
+}
+
+// RecordCreated records in the status the id of a resource that was created but could not be tagged.
+// The status is persisted immediately.
+func (s *ClusterScope) RecordCreated(ctx context.Context, key, id string) error {
+	return s.setCreation(ctx, key, encodeCreated(id))
+}
+
+func (s *ClusterScope) setCreation(ctx context.Context, key, value string) error {
+	obj := &infrastructurev1beta2.OscCluster{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscCluster.Namespace, Name: s.OscCluster.Name}}
+	if err := patchCreation(ctx, s.Client, obj, nil, key, &value); err != nil {
+		return fmt.Errorf("cannot record creation: %w", err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	rsrc := s.GetResources()
+	if rsrc.Creating == nil {
+		rsrc.Creating = map[string]string{}
+	}
+	rsrc.Creating[key] = value
+	return nil
+}
+
+// Creation returns the ids recorded by BeginCreation, the id recorded by RecordCreated, and false if no creation is in progress.
+func (s *ClusterScope) Creation(key string) (existing []string, created string, found bool) {
+	s.Lock()
+	defer s.Unlock()
+	value, found := s.GetResources().Creating[key]
+	existing, created = decodeCreation(value)
+	return existing, created, found
+}
+
+// EndCreation removes a creation from the status. The removal and the tracked resources are persisted immediately.
+func (s *ClusterScope) EndCreation(ctx context.Context, key string) error {
+	s.Lock()
+	rsrc := s.GetResources()
+	_, found := rsrc.Creating[key]
+	delete(rsrc.Creating, key)
+	rsrc = rsrc.DeepCopy()
+	s.Unlock()
+	if !found {
+		return nil
+	}
+	obj := &infrastructurev1beta2.OscCluster{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscCluster.Namespace, Name: s.OscCluster.Name}}
+	if err := patchCreation(ctx, s.Client, obj, rsrc, key, nil); err != nil {
+		return fmt.Errorf("cannot record end of creation: %w", err)
+	}
+	return nil
+}
+
 func (s *ClusterScope) getreconciliationRule(reconciler infrastructurev1beta2.Reconciler) infrastructurev1beta2.OscReconciliationRule {
+	// peering/<name> reconcilers are configured by the peering rules, loadbalancer/<name> reconcilers by the loadbalancer rules.
+	isPeering := strings.HasPrefix(string(reconciler), string(infrastructurev1beta2.ReconcilerPeering)+"/")
+	isLoadBalancer := strings.HasPrefix(string(reconciler), string(infrastructurev1beta2.ReconcilerLoadbalancer)+"/")
 	for _, r := range s.GetNetwork().ReconciliationRules {
-		if slices.Contains(r.AppliesTo, infrastructurev1beta2.ReconcilerAll) || slices.Contains(r.AppliesTo, reconciler) {
+		if slices.Contains(r.AppliesTo, infrastructurev1beta2.ReconcilerAll) || slices.Contains(r.AppliesTo, reconciler) ||
+			(isPeering && slices.Contains(r.AppliesTo, infrastructurev1beta2.ReconcilerPeering)) ||
+			(isLoadBalancer && slices.Contains(r.AppliesTo, infrastructurev1beta2.ReconcilerLoadbalancer)) {
 			return r
 		}
 	}
@@ -742,6 +1125,8 @@ var Rand = func() int {
 
 // NeedReconciliation returns true if a reconciler needs to run.
 func (s *ClusterScope) NeedReconciliation(reconciler infrastructurev1beta2.Reconciler) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.OscCluster.Status.ReconcilerGeneration == nil {
 		return true
 	}
@@ -761,12 +1146,39 @@ func (s *ClusterScope) NeedReconciliation(reconciler infrastructurev1beta2.Recon
 
 // SetReconciliationGeneration marks a reconciler as having finished its job for a specific cluster generation.
 func (s *ClusterScope) SetReconciliationGeneration(reconciler infrastructurev1beta2.Reconciler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.OscCluster.Status.ReconcilerGeneration == nil {
 		s.OscCluster.Status.ReconcilerGeneration = map[infrastructurev1beta2.Reconciler]int64{}
 	}
 	s.OscCluster.Status.ReconcilerGeneration[reconciler] = s.OscCluster.Generation
 }
 
+// ClearReconciliationGeneration forgets a reconciler, whose resources have been removed from the spec.
+func (s *ClusterScope) ClearReconciliationGeneration(reconciler infrastructurev1beta2.Reconciler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.OscCluster.Status.ReconcilerGeneration, reconciler)
+}
+
+// DeleteCondition deletes a condition, whose resources have been removed from the spec.
+func (s *ClusterScope) DeleteCondition(t clusterv1.ConditionType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conditions.Delete(s.OscCluster, t)
+}
+
+// MarkCondition marks a condition as true if err is nil, as false otherwise.
+func (s *ClusterScope) MarkCondition(t clusterv1.ConditionType, reason string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		conditions.MarkFalse(s.OscCluster, t, reason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		return
+	}
+	conditions.MarkTrue(s.OscCluster, t)
+}
+
 // PatchObject keep the cluster configuration and status
 func (s *ClusterScope) PatchObject(ctx context.Context) error {
 	setConditions := []clusterv1.ConditionType{
@@ -6,14 +6,22 @@ SPDX-License-Identifier: BSD-3-Clause
 package scope_test
 
 import (
+	"context"
+	"errors"
+	"net"
+	"slices"
 	"testing"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/osc-sdk-go/v3/pkg/profile"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestClusterScope_GetSubnets(t *testing.T) {
@@ -38,6 +46,53 @@ func TestClusterScope_GetSubnets(t *testing.T) {
 			{IpSubnetRange: "10.1.4.0/24", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, SubregionName: "eu-west2a"},
 		}, subnets)
 	})
+	t.Run("Allocated subnets use claimed ranges", func(t *testing.T) {
+		clusterScope := scope.ClusterScope{OscCluster: &infrastructurev1beta2.OscCluster{}}
+		clusterScope.OscCluster.Spec.Network.SubnetAllocation.Enable = true
+		clusterScope.OscCluster.Spec.Network.SubregionName = "eu-west2a"
+		clusterScope.OscCluster.Status.Resources.SubnetClaims = map[string]string{"eu-west2a/worker": "10.0.8.0/26"}
+		subnets := clusterScope.GetSubnets()
+		assert.Equal(t, []infrastructurev1beta2.OscSubnet{
+			{Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat}, SubregionName: "eu-west2a"},
+			{IpSubnetRange: "10.0.8.0/26", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}, SubregionName: "eu-west2a"},
+			{Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, SubregionName: "eu-west2a"},
+		}, subnets)
+	})
+	t.Run("Subnets follow the subnet layout", func(t *testing.T) {
+		clusterScope := scope.ClusterScope{OscCluster: &infrastructurev1beta2.OscCluster{}}
+		clusterScope.OscCluster.Name = "foo"
+		clusterScope.OscCluster.Spec.Network.Net.IpRange = "10.0.0.0/22"
+		clusterScope.OscCluster.Spec.Network.SubregionName = "eu-west2a"
+		clusterScope.OscCluster.Spec.Network.SubnetLayout = infrastructurev1beta2.OscSubnetLayout{
+			PublicPrefixLength:       27,
+			ControlPlanePrefixLength: 27,
+			WorkerPrefixLength:       25,
+			SeparateNatSubnets:       true,
+			NatPrefixLength:          28,
+			Pools:                    []infrastructurev1beta2.OscSubnetPool{{Name: "gpu", PrefixLength: 26}},
+			ReservedIpRange:          "10.0.0.0/24",
+		}
+		subnets := clusterScope.GetSubnets()
+		assert.Equal(t, []infrastructurev1beta2.OscSubnet{
+			{IpSubnetRange: "10.0.1.0/27", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion}, SubregionName: "eu-west2a"},
+			{IpSubnetRange: "10.0.1.32/28", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleNat}, SubregionName: "eu-west2a"},
+			{IpSubnetRange: "10.0.1.128/25", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}, SubregionName: "eu-west2a"},
+			{IpSubnetRange: "10.0.1.64/27", Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, SubregionName: "eu-west2a"},
+			{
+				Name:          "Subnet for pool gpu of foo/eu-west2a",
+				IpSubnetRange: "10.0.2.0/26",
+				Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				SubregionName: "eu-west2a",
+				Pool:          "gpu",
+			},
+		}, subnets)
+		sn, err := clusterScope.GetSubnet("", infrastructurev1beta2.RoleWorker, "eu-west2a")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.1.128/25", sn.IpSubnetRange)
+		sn, err = clusterScope.GetPoolSubnet("gpu", infrastructurev1beta2.RoleWorker, "eu-west2a")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.2.0/26", sn.IpSubnetRange)
+	})
 }
 
 func TestClusterScope_GetSubnet(t *testing.T) {
@@ -223,6 +278,234 @@ func TestClusterScope_GetSecurityGroups(t *testing.T) {
 	}
 }
 
+func TestClusterScope_GetSecurityGroups_Vpn(t *testing.T) {
+	clusterScope := &scope.ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				UID:  "abcd",
+			},
+		},
+		OscCluster: &infrastructurev1beta2.OscCluster{
+			Spec: infrastructurev1beta2.OscClusterSpec{
+				Network: infrastructurev1beta2.OscNetwork{
+					Vpn: infrastructurev1beta2.OscVpn{
+						Enable: true,
+						Connections: []infrastructurev1beta2.OscVpnConnection{
+							{Name: "paris", ClientGatewayIP: "198.51.100.10", StaticRoutes: []string{"192.168.0.0/16"}},
+							{Name: "lyon", ClientGatewayIP: "198.51.100.20", StaticRoutes: []string{"192.168.0.0/16", "172.16.0.0/16"}},
+						},
+					},
+					AllowFromIPRanges: []string{"1.2.3.0/24"},
+					AllowToIPRanges:   []string{""},
+				},
+			},
+		},
+	}
+	onPrem := []string{"192.168.0.0/16", "172.16.0.0/16"}
+	assert.Equal(t, onPrem, clusterScope.GetVpnIPRanges())
+	sgs := clusterScope.GetSecurityGroups()
+	for _, sg := range sgs {
+		switch sg.Name {
+		case "foo-lb":
+			assert.Contains(t, sg.SecurityGroupRules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRanges: []string{"1.2.3.0/24", "192.168.0.0/16", "172.16.0.0/16"},
+			})
+		case "foo-node":
+			assert.Contains(t, sg.SecurityGroupRules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 30000, ToPortRange: 32767, IpRanges: onPrem,
+			})
+			assert.Contains(t, sg.SecurityGroupRules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: onPrem,
+			})
+		}
+	}
+	assert.Equal(t, []string{"1.2.3.0/24"}, clusterScope.OscCluster.Spec.Network.AllowFromIPRanges, "The source spec must not be changed")
+}
+
+func TestClusterScope_GetSecurityGroups_Cni(t *testing.T) {
+	bgp := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 179, ToPortRange: 179, IpRange: "10.0.0.0/16"}
+	ciliumHealth := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4240, ToPortRange: 4240, IpRange: "10.0.0.0/16"}
+	flannelVxlan := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8472, ToPortRange: 8472, IpRange: "10.0.0.0/16"}
+	custom := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 6081, ToPortRange: 6081, IpRange: "10.0.0.0/16"}
+	customFromRole := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 9000, ToPortRange: 9000, SecurityGroupRoles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cni-rules"},
+		Data: map[string]string{
+			scope.CniProfileRulesKey: `
+- flow: Inbound
+  ipProtocol: udp
+  fromPortRange: 6081
+  toPortRange: 6081
+- flow: Inbound
+  ipProtocol: tcp
+  fromPortRange: 9000
+  toPortRange: 9000
+  securityGroupRoles: [worker]
+`,
+		},
+	}
+	tcs := []struct {
+		name        string
+		cni         infrastructurev1beta2.OscCni
+		has, hasNot []infrastructurev1beta2.OscSecurityGroupRule
+	}{
+		{name: "rules of all CNIs are set by default", has: []infrastructurev1beta2.OscSecurityGroupRule{bgp, ciliumHealth, flannelVxlan}},
+		{name: "calico", cni: infrastructurev1beta2.CniCalico, has: []infrastructurev1beta2.OscSecurityGroupRule{bgp}, hasNot: []infrastructurev1beta2.OscSecurityGroupRule{ciliumHealth, flannelVxlan}},
+		{name: "cilium", cni: infrastructurev1beta2.CniCilium, has: []infrastructurev1beta2.OscSecurityGroupRule{ciliumHealth, flannelVxlan}, hasNot: []infrastructurev1beta2.OscSecurityGroupRule{bgp}},
+		{name: "flannel", cni: infrastructurev1beta2.CniFlannel, has: []infrastructurev1beta2.OscSecurityGroupRule{flannelVxlan}, hasNot: []infrastructurev1beta2.OscSecurityGroupRule{bgp, ciliumHealth}},
+		{name: "custom", cni: infrastructurev1beta2.CniCustom, has: []infrastructurev1beta2.OscSecurityGroupRule{custom, customFromRole}, hasNot: []infrastructurev1beta2.OscSecurityGroupRule{bgp, ciliumHealth, flannelVxlan}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithObjects(cm).Build()
+			clusterScope := &scope.ClusterScope{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+						UID:  "abcd",
+					},
+				},
+				OscCluster: &infrastructurev1beta2.OscCluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+					Spec: infrastructurev1beta2.OscClusterSpec{
+						Network: infrastructurev1beta2.OscNetwork{
+							Cni: tc.cni,
+						},
+					},
+				},
+			}
+			if tc.cni == infrastructurev1beta2.CniCustom {
+				clusterScope.OscCluster.Spec.Network.CniProfileRef = &corev1.LocalObjectReference{Name: "cni-rules"}
+			}
+			err := clusterScope.LoadCniProfile(context.TODO(), client)
+			require.NoError(t, err)
+			sgs := clusterScope.GetSecurityGroups()
+			idx := slices.IndexFunc(sgs, func(sg infrastructurev1beta2.OscSecurityGroup) bool { return sg.Name == "foo-node" })
+			require.GreaterOrEqual(t, idx, 0)
+			for _, rule := range tc.has {
+				assert.Contains(t, sgs[idx].SecurityGroupRules, rule)
+			}
+			for _, rule := range tc.hasNot {
+				assert.NotContains(t, sgs[idx].SecurityGroupRules, rule)
+			}
+		})
+	}
+}
+
+func TestClusterScope_GetSecurityGroups_Egress(t *testing.T) {
+	scope.LookupHost = func(_ context.Context, host string) ([]string, error) {
+		switch host {
+		case "api.eu-west-2.outscale.com":
+			return []string{"198.51.100.2", "198.51.100.1", "2001:db8::1"}, nil
+		default:
+			return nil, errors.New("unknown host")
+		}
+	}
+	defer func() { scope.LookupHost = net.DefaultResolver.LookupHost }()
+	tnt, err := tenant.FromProfile(&profile.Profile{AccessKey: "foo", SecretKey: "bar", Region: "eu-west-2"})
+	require.NoError(t, err)
+	clusterScope := &scope.ClusterScope{
+		Tenant: tnt,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				UID:  "abcd",
+			},
+		},
+		OscCluster: &infrastructurev1beta2.OscCluster{
+			Spec: infrastructurev1beta2.OscClusterSpec{
+				Network: infrastructurev1beta2.OscNetwork{
+					Bastion: infrastructurev1beta2.OscBastion{Enable: true},
+					EgressRules: []infrastructurev1beta2.OscEgressRule{
+						{Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker, infrastructurev1beta2.RoleControlPlane}, IpProtocol: "tcp", FromPortRange: 3128, ToPortRange: 3128, IpRanges: []string{"192.0.2.10/32"}},
+						{Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 443, Destinations: []infrastructurev1beta2.OscEgressDestination{infrastructurev1beta2.EgressDestinationAPI}},
+						{Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleBastion}},
+					},
+				},
+			},
+		},
+	}
+	err = clusterScope.LoadEgressDestinations(context.TODO(), nil, true)
+	require.NoError(t, err)
+	allowAll := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: []string{"0.0.0.0/0"}}
+	proxy := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "tcp", FromPortRange: 3128, ToPortRange: 3128, IpRanges: []string{"192.0.2.10/32"}}
+	api := infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 443, IpRanges: []string{"198.51.100.1/32", "198.51.100.2/32"}}
+	sgs := clusterScope.GetSecurityGroups()
+	require.Len(t, sgs, 5)
+	for _, sg := range sgs {
+		switch sg.Name {
+		case "foo-worker":
+			assert.Contains(t, sg.SecurityGroupRules, proxy)
+			assert.NotContains(t, sg.SecurityGroupRules, api)
+		case "foo-controlplane":
+			assert.Contains(t, sg.SecurityGroupRules, proxy)
+			assert.Contains(t, sg.SecurityGroupRules, api)
+		case "foo-bastion":
+			assert.Contains(t, sg.SecurityGroupRules, infrastructurev1beta2.OscSecurityGroupRule{
+				Flow: "Outbound", IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, IpRange: "10.0.0.0/16",
+			})
+		}
+		assert.NotContains(t, sg.SecurityGroupRules, allowAll, sg.Name)
+	}
+}
+
+func TestClusterScope_GetSecurityGroups_AdditionalLoadBalancers(t *testing.T) {
+	clusterScope := &scope.ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				UID:  "abcd",
+			},
+		},
+		OscCluster: &infrastructurev1beta2.OscCluster{
+			Spec: infrastructurev1beta2.OscClusterSpec{
+				Network: infrastructurev1beta2.OscNetwork{
+					LoadBalancer: infrastructurev1beta2.OscLoadBalancer{
+						AdditionalListeners: []infrastructurev1beta2.OscLoadBalancerListener{{LoadBalancerPort: 8132}},
+					},
+					AdditionalLoadBalancers: []infrastructurev1beta2.OscAdditionalLoadBalancer{{
+						LoadBalancerName: "foo-ingress",
+						BackendRole:      infrastructurev1beta2.RoleWorker,
+						Listeners: []infrastructurev1beta2.OscLoadBalancerListener{
+							{LoadBalancerPort: 80, BackendPort: 30080},
+							{LoadBalancerPort: 443, BackendPort: 30443},
+						},
+					}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"foo-ingress"}, clusterScope.GetBackendLoadBalancerNames(infrastructurev1beta2.RoleWorker))
+	assert.Len(t, clusterScope.GetBackendLoadBalancerNames(infrastructurev1beta2.RoleControlPlane), 1)
+	inbound := func(port int32, ipRanges []string, ipRange string) infrastructurev1beta2.OscSecurityGroupRule {
+		return infrastructurev1beta2.OscSecurityGroupRule{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: port, ToPortRange: port, IpRanges: ipRanges, IpRange: ipRange}
+	}
+	outbound := func(port int32) infrastructurev1beta2.OscSecurityGroupRule {
+		return infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "tcp", FromPortRange: port, ToPortRange: port, IpRange: "10.0.0.0/16"}
+	}
+	sgs := clusterScope.GetSecurityGroups()
+	for _, sg := range sgs {
+		switch sg.Name {
+		case "foo-lb":
+			for _, port := range []int32{8132, 80, 443} {
+				assert.Contains(t, sg.SecurityGroupRules, inbound(port, []string{"0.0.0.0/0"}, ""))
+			}
+			for _, port := range []int32{8132, 30080, 30443} {
+				assert.Contains(t, sg.SecurityGroupRules, outbound(port))
+			}
+		case "foo-controlplane":
+			assert.Contains(t, sg.SecurityGroupRules, inbound(8132, nil, "10.0.0.0/16"))
+			assert.NotContains(t, sg.SecurityGroupRules, inbound(30080, nil, "10.0.0.0/16"))
+		case "foo-worker":
+			assert.Contains(t, sg.SecurityGroupRules, inbound(30080, nil, "10.0.0.0/16"))
+			assert.Contains(t, sg.SecurityGroupRules, inbound(30443, nil, "10.0.0.0/16"))
+			assert.NotContains(t, sg.SecurityGroupRules, inbound(8132, nil, "10.0.0.0/16"))
+		}
+	}
+}
+
 func TestNeedReconciliation(t *testing.T) {
 	newScope := func(r []infrastructurev1beta2.OscReconciliationRule) scope.ClusterScope {
 		return scope.ClusterScope{
@@ -0,0 +1,113 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package scope
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// CniProfileRulesKey is the ConfigMap key storing the rules of a custom CNI.
+const CniProfileRulesKey = "rules"
+
+// LoadCniProfile loads the rules of a custom CNI from its ConfigMap, read from reader as ConfigMaps are not cached.
+func (s *ClusterScope) LoadCniProfile(ctx context.Context, reader client.Reader) error {
+	network := s.GetNetwork()
+	if network.Cni != infrastructurev1beta2.CniCustom {
+		return nil
+	}
+	if network.CniProfileRef == nil {
+		return errors.New("cniProfileRef is required for custom CNIs")
+	}
+	var cm corev1.ConfigMap
+	key := types.NamespacedName{Namespace: s.OscCluster.Namespace, Name: network.CniProfileRef.Name}
+	if err := reader.Get(ctx, key, &cm); err != nil {
+		return fmt.Errorf("get ConfigMap %s: %w", key.Name, err)
+	}
+	data, ok := cm.Data[CniProfileRulesKey]
+	if !ok {
+		return fmt.Errorf("ConfigMap %s has no %s key", key.Name, CniProfileRulesKey)
+	}
+	var rules []infrastructurev1beta2.OscSecurityGroupRule
+	if err := yaml.UnmarshalStrict([]byte(data), &rules); err != nil {
+		return fmt.Errorf("parse ConfigMap %s: %w", key.Name, err)
+	}
+	s.cniProfileRules = rules
+	hash := sha256.Sum256([]byte(data))
+	s.cniProfileHash = fmt.Sprintf("%x", hash[:8])
+	return nil
+}
+
+// CniProfileHash returns the hash of the rules of a custom CNI, loaded by LoadCniProfile.
+func (s *ClusterScope) CniProfileHash() string {
+	return s.cniProfileHash
+}
+
+// getCniRules returns the inbound rules required by the CNI on nodes.
+// Rules from a custom profile having no source allow traffic from the net.
+func (s *ClusterScope) getCniRules() []infrastructurev1beta2.OscSecurityGroupRule {
+	ipRange := s.GetNet().IpRange
+	switch s.GetNetwork().Cni {
+	case infrastructurev1beta2.CniCalico:
+		// see https://docs.tigera.io/calico/latest/getting-started/kubernetes/requirements#network-requirements
+		return []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 179, ToPortRange: 179, IpRange: ipRange},     // BGP
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 4789, ToPortRange: 4789, IpRange: ipRange},   // VXLAN
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 5473, ToPortRange: 5473, IpRange: ipRange},   // Typha
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51820, ToPortRange: 51821, IpRange: ipRange}, // Wireguard
+			{Flow: "Inbound", IpProtocol: "4", FromPortRange: -1, ToPortRange: -1, IpRange: ipRange},         // IP-in-IP
+		}
+	case infrastructurev1beta2.CniCilium:
+		// see https://docs.cilium.io/en/stable/operations/system_requirements/#firewall-rules
+		return []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "icmp", FromPortRange: 8, ToPortRange: 8, IpRange: ipRange},        // ICMP
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4240, ToPortRange: 4240, IpRange: ipRange},   // Health
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4244, ToPortRange: 4244, IpRange: ipRange},   // Hubble
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8472, ToPortRange: 8472, IpRange: ipRange},   // VXLAN
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51871, ToPortRange: 51871, IpRange: ipRange}, // Wireguard
+		}
+	case infrastructurev1beta2.CniFlannel:
+		// see https://github.com/flannel-io/flannel/blob/master/Documentation/backends.md
+		return []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8472, ToPortRange: 8472, IpRange: ipRange}, // VXLAN
+			{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8285, ToPortRange: 8285, IpRange: ipRange}, // UDP
+		}
+	case infrastructurev1beta2.CniCustom:
+		rules := make([]infrastructurev1beta2.OscSecurityGroupRule, 0, len(s.cniProfileRules))
+		for _, rule := range s.cniProfileRules {
+			if rule.IpRange == "" && len(rule.IpRanges) == 0 && len(rule.SecurityGroupNames) == 0 &&
+				len(rule.SecurityGroupRoles) == 0 && len(rule.SecurityGroupIds) == 0 {
+				rule.IpRange = ipRange
+			}
+			rules = append(rules, rule)
+		}
+		return rules
+	}
+	return []infrastructurev1beta2.OscSecurityGroupRule{
+		// Calico - see https://docs.tigera.io/calico/latest/getting-started/kubernetes/requirements#network-requirements
+		{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 179, ToPortRange: 179, IpRange: ipRange},     // BGP
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 4789, ToPortRange: 4789, IpRange: ipRange},   // VXLAN/flannel
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 5473, ToPortRange: 5473, IpRange: ipRange},   // Typha
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8285, ToPortRange: 8285, IpRange: ipRange},   // Flannel
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51820, ToPortRange: 51821, IpRange: ipRange}, // Wiregard
+		{Flow: "Inbound", IpProtocol: "4", FromPortRange: -1, ToPortRange: -1, IpRange: ipRange},         // IP-in-IP
+
+		// Cillium - see https://docs.cilium.io/en/stable/operations/system_requirements/#firewall-rules
+		{Flow: "Inbound", IpProtocol: "icmp", FromPortRange: 8, ToPortRange: 8, IpRange: ipRange},        // ICMP
+		{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4240, ToPortRange: 4240, IpRange: ipRange},   // Health
+		{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 4244, ToPortRange: 4244, IpRange: ipRange},   // Hubble
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 8472, ToPortRange: 8472, IpRange: ipRange},   // VXLAN
+		{Flow: "Inbound", IpProtocol: "udp", FromPortRange: 51871, ToPortRange: 51871, IpRange: ipRange}, // Wiregard
+	}
+}
@@ -0,0 +1,83 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The resources being created are stored in status.resources.creating, with the list of the resources
+// that might be mistaken with the created resource, if the creation is interrupted before the resource is tagged.
+// The status is patched immediately, as the controller may crash before the scope is closed. When the creation ends,
+// the tracked resources are patched with the removal of the entry, in order not to lose the created resource.
+
+// If the resource is created but cannot be tagged, its id is stored with createdPrefix, for the next creation to
+// adopt it without relying on the list of existing resources.
+
+const createdPrefix = "created:"
+
+func encodeCreation(existing []string) string {
+	return strings.Join(existing, ",")
+}
+
+func encodeCreated(id string) string {
+	return createdPrefix + id
+}
+
+func decodeCreation(value string) (existing []string, created string) {
+	if id, ok := strings.CutPrefix(value, createdPrefix); ok {
+		return nil, id
+	}
+	if value == "" {
+		return nil, ""
+	}
+	return strings.Split(value, ","), ""
+}
+
+// patchCreation patches a single status.resources.creating entry, removing it if value is nil, along with resources if not nil.
+// obj is only used to build the request, the object of the scope is not updated.
+func patchCreation(ctx context.Context, c client.Client, obj client.Object, resources any, key string, value *string) error {
+	rsrc := map[string]any{}
+	if resources != nil {
+		data, err := json.Marshal(resources)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &rsrc); err != nil {
+			return err
+		}
+	}
+	rsrc["creating"] = map[string]any{key: value}
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"resources": rsrc,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
+
+// patchSubnetClaims patches status.resources.subnetClaims, adding claims to the existing ones, and removing those with a nil value.
+func patchSubnetClaims(ctx context.Context, c client.Client, obj client.Object, claims map[string]*string) error {
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"resources": map[string]any{
+				"subnetClaims": claims,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
@@ -0,0 +1,152 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/osc-sdk-go/v3/pkg/profile"
+)
+
+// LookupHost resolves the endpoints of egress destinations.
+var LookupHost = net.DefaultResolver.LookupHost
+
+var egressDestinationServices = map[infrastructurev1beta2.OscEgressDestination]profile.OscService{
+	infrastructurev1beta2.EgressDestinationAPI: profile.OscServiceApi,
+	infrastructurev1beta2.EgressDestinationOOS: profile.OscServiceOOS,
+}
+
+// LoadEgressDestinations loads the IP ranges of the named destinations of egress rules, starting from applied, the ranges last applied.
+// If resolve is true, destinations are resolved and the resolved ranges are merged with the applied ones, as an endpoint may only resolve to a subset of its IPs.
+// A destination that cannot be resolved keeps its applied ranges, the resolution errors are returned once all destinations are loaded.
+func (s *ClusterScope) LoadEgressDestinations(ctx context.Context, applied map[string]string, resolve bool) error {
+	destinations := map[infrastructurev1beta2.OscEgressDestination][]string{}
+	var errs []error
+	for _, rule := range s.GetNetwork().EgressRules {
+		for _, dest := range rule.Destinations {
+			if _, found := destinations[dest]; found {
+				continue
+			}
+			var ipRanges []string
+			if applied[string(dest)] != "" {
+				ipRanges = strings.Split(applied[string(dest)], ",")
+			}
+			if resolve {
+				resolved, err := s.resolveEgressDestination(ctx, dest)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("resolve %s: %w", dest, err))
+				}
+				ipRanges = append(ipRanges, resolved...)
+			}
+			slices.Sort(ipRanges)
+			destinations[dest] = slices.Compact(ipRanges)
+		}
+	}
+	s.egressDestinations = destinations
+	return errors.Join(errs...)
+}
+
+// HasEgressDestinations returns true if egress rules have named destinations.
+func (s *ClusterScope) HasEgressDestinations() bool {
+	return slices.ContainsFunc(s.GetNetwork().EgressRules, func(rule infrastructurev1beta2.OscEgressRule) bool {
+		return len(rule.Destinations) > 0
+	})
+}
+
+// EgressDestinations returns the IP ranges of egress destinations loaded by LoadEgressDestinations, as comma-separated IP ranges by destination.
+func (s *ClusterScope) EgressDestinations() map[string]string {
+	var destinations map[string]string
+	for dest, ipRanges := range s.egressDestinations {
+		if len(ipRanges) == 0 {
+			continue
+		}
+		if destinations == nil {
+			destinations = map[string]string{}
+		}
+		destinations[string(dest)] = strings.Join(ipRanges, ",")
+	}
+	return destinations
+}
+
+func (s *ClusterScope) resolveEgressDestination(ctx context.Context, dest infrastructurev1beta2.OscEgressDestination) ([]string, error) {
+	svc, found := egressDestinationServices[dest]
+	if !found {
+		return nil, fmt.Errorf("unknown destination %q", dest)
+	}
+	prof := *s.Tenant.Profile()
+	if prof.Protocol == "" {
+		prof.Protocol = "https"
+	}
+	endpoint, err := prof.GetEndpoint(svc)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	addrs, err := LookupHost(ctx, u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	var ipRanges []string
+	for _, addr := range addrs {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil || !ip.Is4() {
+			continue
+		}
+		ipRanges = append(ipRanges, netip.PrefixFrom(ip, 32).String())
+	}
+	if len(ipRanges) == 0 {
+		return nil, fmt.Errorf("no IPv4 address found for %s", u.Hostname())
+	}
+	slices.Sort(ipRanges)
+	return slices.Compact(ipRanges), nil
+}
+
+// hasEgressRules returns true if egress rules are defined.
+func (s *ClusterScope) hasEgressRules() bool {
+	return len(s.GetNetwork().EgressRules) > 0
+}
+
+// getEgressRules returns the outbound rules of a role.
+// If no egress rule is defined for the role, outbound traffic is allowed to allowedOut.
+func (s *ClusterScope) getEgressRules(role infrastructurev1beta2.OscRole, allowedOut []string) []infrastructurev1beta2.OscSecurityGroupRule {
+	var rules []infrastructurev1beta2.OscSecurityGroupRule
+	found := false
+	for _, egress := range s.GetNetwork().EgressRules {
+		if !slices.Contains(egress.Roles, role) {
+			continue
+		}
+		found = true
+		ipRanges := slices.Clone(egress.IpRanges)
+		for _, dest := range egress.Destinations {
+			ipRanges = append(ipRanges, s.egressDestinations[dest]...)
+		}
+		if len(ipRanges) == 0 {
+			continue
+		}
+		rule := infrastructurev1beta2.OscSecurityGroupRule{
+			Flow: "Outbound", IpProtocol: egress.IpProtocol, FromPortRange: egress.FromPortRange, ToPortRange: egress.ToPortRange, IpRanges: ipRanges,
+		}
+		if rule.IpProtocol == "" || rule.IpProtocol == "-1" {
+			rule.IpProtocol, rule.FromPortRange, rule.ToPortRange = "-1", -1, -1
+		}
+		rules = append(rules, rule)
+	}
+	if !found && len(allowedOut) > 0 {
+		rules = append(rules, infrastructurev1beta2.OscSecurityGroupRule{Flow: "Outbound", IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: allowedOut})
+	}
+	return rules
+}
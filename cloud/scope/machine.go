@@ -17,6 +17,7 @@ import (
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -245,6 +246,52 @@ func (s *MachineScope) GetResources() *infrastructurev1beta2.OscMachineResources
 	return &s.OscMachine.Status.Resources
 }
 
+// BeginCreation records in the status that a resource is being created, with the ids of existing resources that
+// might be mistaken with it. The status is persisted immediately.
+func (s *MachineScope) BeginCreation(ctx context.Context, key string, existing []string) error {
+	return s.setCreation(ctx, key, encodeCreation(existing))
+}
+
+// RecordCreated records in the status the id of a resource that was created but could not be tagged.
+// The status is persisted immediately.
+func (s *MachineScope) RecordCreated(ctx context.Context, key, id string) error {
+	return s.setCreation(ctx, key, encodeCreated(id))
+}
+
+func (s *MachineScope) setCreation(ctx context.Context, key, value string) error {
+	obj := &infrastructurev1beta2.OscMachine{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscMachine.Namespace, Name: s.OscMachine.Name}}
+	if err := patchCreation(ctx, s.client, obj, nil, key, &value); err != nil {
+		return fmt.Errorf("cannot record creation: %w", err)
+	}
+	rsrc := s.GetResources()
+	if rsrc.Creating == nil {
+		rsrc.Creating = map[string]string{}
+	}
+	rsrc.Creating[key] = value
+	return nil
+}
+
+// Creation returns the ids recorded by BeginCreation, the id recorded by RecordCreated, and false if no creation is in progress.
+func (s *MachineScope) Creation(key string) (existing []string, created string, found bool) {
+	value, found := s.GetResources().Creating[key]
+	existing, created = decodeCreation(value)
+	return existing, created, found
+}
+
+// EndCreation removes a creation from the status. The removal and the tracked resources are persisted immediately.
+func (s *MachineScope) EndCreation(ctx context.Context, key string) error {
+	rsrc := s.GetResources()
+	if _, found := rsrc.Creating[key]; !found {
+		return nil
+	}
+	delete(rsrc.Creating, key)
+	obj := &infrastructurev1beta2.OscMachine{ObjectMeta: metav1.ObjectMeta{Namespace: s.OscMachine.Namespace, Name: s.OscMachine.Name}}
+	if err := patchCreation(ctx, s.client, obj, rsrc, key, nil); err != nil {
+		return fmt.Errorf("cannot record end of creation: %w", err)
+	}
+	return nil
+}
+
 // NeedReconciliation returns true if a reconciler needs to run.
 func (s *MachineScope) NeedReconciliation(reconciler infrastructurev1beta2.Reconciler) bool {
 	if s.OscMachine.Status.ReconcilerGeneration == nil {
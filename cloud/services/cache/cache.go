@@ -0,0 +1,268 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+// Cache is a per-tenant read cache, with a short TTL.
+// Reads are batched: all vms and tags of a cluster, and all security groups of a net, are fetched in a single call.
+// Loadbalancers are cached by name, the same loadBalancer being read by all machines of a cluster. Missing loadBalancers are not cached.
+// Items not found in a batch are read directly, a recently created resource possibly missing from a cached batch.
+// All entries of a tenant are invalidated on writes made using the services wrapped by Net, Compute or Tag.
+//
+// A nil Cache is valid and directly calls the services.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	tenants map[string]*tenantCache
+}
+
+type tenantCache struct {
+	vms  map[string]*entry[[]osc.Vm]
+	tags map[string]*entry[[]osc.Tag]
+	sgs  map[string]*entry[[]osc.SecurityGroup]
+	lbs  map[string]*entry[*osc.LoadBalancer]
+}
+
+type entry[T any] struct {
+	mu      sync.Mutex
+	fetched time.Time
+	items   T
+}
+
+// New creates a cache. A nil cache is returned if ttl is not positive.
+func New(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		tenants: map[string]*tenantCache{},
+	}
+}
+
+// Invalidate removes all entries of a tenant.
+func (c *Cache) Invalidate(t tenant.Tenant) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tenants, tenant.Key(t))
+}
+
+func get[T any](c *Cache, t tenant.Tenant, entries func(tc *tenantCache) map[string]*entry[T], key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	tk := tenant.Key(t)
+	tc, found := c.tenants[tk]
+	if !found {
+		tc = &tenantCache{
+			vms:  map[string]*entry[[]osc.Vm]{},
+			tags: map[string]*entry[[]osc.Tag]{},
+			sgs:  map[string]*entry[[]osc.SecurityGroup]{},
+			lbs:  map[string]*entry[*osc.LoadBalancer]{},
+		}
+		c.tenants[tk] = tc
+	}
+	m := entries(tc)
+	e, found := m[key]
+	if !found {
+		e = &entry[T]{}
+		m[key] = e
+	}
+	c.mu.Unlock()
+
+	// Concurrent readers wait for the same fetch.
+	// If the tenant is invalidated during the fetch, the result is stored in a detached entry.
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.fetched.IsZero() && c.now().Sub(e.fetched) < c.ttl {
+		return e.items, nil
+	}
+	items, err := fetch()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	e.items, e.fetched = items, c.now()
+	return items, nil
+}
+
+func vmsOf(tc *tenantCache) map[string]*entry[[]osc.Vm]            { return tc.vms }
+func tagsOf(tc *tenantCache) map[string]*entry[[]osc.Tag]          { return tc.tags }
+func sgsOf(tc *tenantCache) map[string]*entry[[]osc.SecurityGroup] { return tc.sgs }
+func lbsOf(tc *tenantCache) map[string]*entry[*osc.LoadBalancer]   { return tc.lbs }
+
+// GetVm returns a vm from the vms of a cluster.
+// Vms are tagged with the cluster ID once running, other vms are read directly.
+func (c *Cache) GetVm(ctx context.Context, t tenant.Tenant, svc compute.Servicer, clusterID, vmId string) (*osc.Vm, error) {
+	if c == nil {
+		return svc.GetVm(ctx, vmId)
+	}
+	vms, err := get(c, t, vmsOf, clusterID, func() ([]osc.Vm, error) {
+		return svc.GetVmsFromCluster(ctx, clusterID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range vms {
+		if vm.VmId == vmId {
+			return &vm, nil
+		}
+	}
+	return svc.GetVm(ctx, vmId)
+}
+
+// GetVmFromClientToken returns a vm from the vms of a cluster.
+// Vms are tagged with the cluster ID once running, other vms are read directly.
+func (c *Cache) GetVmFromClientToken(ctx context.Context, t tenant.Tenant, svc compute.Servicer, clusterID, clientToken string) (*osc.Vm, error) {
+	if c == nil {
+		return svc.GetVmFromClientToken(ctx, clientToken)
+	}
+	vms, err := get(c, t, vmsOf, clusterID, func() ([]osc.Vm, error) {
+		return svc.GetVmsFromCluster(ctx, clusterID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range vms {
+		if vm.ClientToken != nil && *vm.ClientToken == clientToken {
+			return &vm, nil
+		}
+	}
+	return svc.GetVmFromClientToken(ctx, clientToken)
+}
+
+// ReadOwnedByTag returns the owned tag of a resource type from the tags of a cluster.
+func (c *Cache) ReadOwnedByTag(ctx context.Context, t tenant.Tenant, svc tag.Servicer, rsrcType tag.ResourceType, clusterID string) (*osc.Tag, error) {
+	if c == nil {
+		return svc.ReadOwnedByTag(ctx, rsrcType, clusterID)
+	}
+	tags, err := get(c, t, tagsOf, clusterID, func() ([]osc.Tag, error) {
+		return svc.ReadClusterTags(ctx, clusterID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range tags {
+		if string(tg.ResourceType) == string(rsrcType) && tg.Value == tag.OwnedValue {
+			return &tg, nil
+		}
+	}
+	return svc.ReadOwnedByTag(ctx, rsrcType, clusterID)
+}
+
+// GetSecurityGroup returns a security group from the security groups of a net.
+func (c *Cache) GetSecurityGroup(ctx context.Context, t tenant.Tenant, svc compute.Servicer, netId, securityGroupId string) (*osc.SecurityGroup, error) {
+	if c == nil || netId == "" {
+		return svc.GetSecurityGroup(ctx, securityGroupId)
+	}
+	sgs, err := get(c, t, sgsOf, netId, func() ([]osc.SecurityGroup, error) {
+		return svc.GetSecurityGroupsFromNet(ctx, netId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sg := range sgs {
+		if sg.SecurityGroupId == securityGroupId {
+			return &sg, nil
+		}
+	}
+	return svc.GetSecurityGroup(ctx, securityGroupId)
+}
+
+// GetSecurityGroupFromName returns a security group from the security groups of a net.
+func (c *Cache) GetSecurityGroupFromName(ctx context.Context, t tenant.Tenant, svc compute.Servicer, netId, name string) (*osc.SecurityGroup, error) {
+	if c == nil || netId == "" {
+		return svc.GetSecurityGroupFromName(ctx, name)
+	}
+	sgs, err := get(c, t, sgsOf, netId, func() ([]osc.SecurityGroup, error) {
+		return svc.GetSecurityGroupsFromNet(ctx, netId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sg := range sgs {
+		if sg.SecurityGroupName == name {
+			return &sg, nil
+		}
+	}
+	return svc.GetSecurityGroupFromName(ctx, name)
+}
+
+// errLoadBalancerNotFound is returned by the fetch of a missing loadBalancer, so that misses are not cached.
+var errLoadBalancerNotFound = errors.New("loadBalancer not found")
+
+// GetLoadBalancer returns a loadBalancer by name.
+// Missing loadBalancers are not cached, as they are usually about to be created.
+func (c *Cache) GetLoadBalancer(ctx context.Context, t tenant.Tenant, svc net.Servicer, loadBalancerName string) (*osc.LoadBalancer, error) {
+	if c == nil {
+		return svc.GetLoadBalancer(ctx, loadBalancerName)
+	}
+	lb, err := get(c, t, lbsOf, loadBalancerName, func() (*osc.LoadBalancer, error) {
+		lb, err := svc.GetLoadBalancer(ctx, loadBalancerName)
+		if err == nil && lb == nil {
+			err = errLoadBalancerNotFound
+		}
+		return lb, err
+	})
+	switch {
+	case errors.Is(err, errLoadBalancerNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	// Callers get their own copy.
+	return copyLoadBalancer(lb), nil
+}
+
+// copyLoadBalancer returns a deep copy of a loadBalancer.
+func copyLoadBalancer(lb *osc.LoadBalancer) *osc.LoadBalancer {
+	cp := *lb
+	cp.ApplicationStickyCookiePolicies = slices.Clone(lb.ApplicationStickyCookiePolicies)
+	cp.BackendIps = slices.Clone(lb.BackendIps)
+	cp.BackendVmIds = slices.Clone(lb.BackendVmIds)
+	cp.LoadBalancerStickyCookiePolicies = slices.Clone(lb.LoadBalancerStickyCookiePolicies)
+	cp.SecurityGroups = slices.Clone(lb.SecurityGroups)
+	cp.Subnets = slices.Clone(lb.Subnets)
+	cp.SubregionNames = slices.Clone(lb.SubregionNames)
+	cp.Tags = slices.Clone(lb.Tags)
+	cp.Listeners = slices.Clone(lb.Listeners)
+	for i := range cp.Listeners {
+		cp.Listeners[i].PolicyNames = slices.Clone(cp.Listeners[i].PolicyNames)
+		cp.Listeners[i].ServerCertificateId = clonePtr(cp.Listeners[i].ServerCertificateId)
+	}
+	cp.AccessLog.OsuBucketName = clonePtr(lb.AccessLog.OsuBucketName)
+	cp.AccessLog.OsuBucketPrefix = clonePtr(lb.AccessLog.OsuBucketPrefix)
+	cp.AccessLog.PublicationInterval = clonePtr(lb.AccessLog.PublicationInterval)
+	cp.NetId = clonePtr(lb.NetId)
+	cp.PrivateIp = clonePtr(lb.PrivateIp)
+	cp.PublicIp = clonePtr(lb.PublicIp)
+	return &cp
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,210 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/cache"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute/mock_compute"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net/mock_net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag/mock_tag"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/outscale/osc-sdk-go/v3/pkg/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testTenant struct {
+	tenant.Tenant
+	accessKey string
+}
+
+func (t testTenant) Region() string {
+	return "eu-west-2"
+}
+
+func (t testTenant) Profile() *profile.Profile {
+	return &profile.Profile{AccessKey: t.accessKey}
+}
+
+func TestCache_GetVm(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	vms := []osc.Vm{{VmId: "i-foo", ClientToken: new("token-foo")}, {VmId: "i-bar"}}
+	t.Run("Vms of a cluster are read in a single call", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return(vms, nil)
+		c := cache.New(time.Minute)
+		vm, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+		assert.Equal(t, "i-foo", vm.VmId)
+		vm, err = c.GetVm(context.TODO(), tn, svc, "uid", "i-bar")
+		require.NoError(t, err)
+		assert.Equal(t, "i-bar", vm.VmId)
+		vm, err = c.GetVmFromClientToken(context.TODO(), tn, svc, "uid", "token-foo")
+		require.NoError(t, err)
+		assert.Equal(t, "i-foo", vm.VmId)
+	})
+	t.Run("Vms not found in cache are read directly", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return(vms, nil)
+		svc.EXPECT().GetVm(gomock.Any(), "i-baz").Return(&osc.Vm{VmId: "i-baz"}, nil)
+		svc.EXPECT().GetVmFromClientToken(gomock.Any(), "token-baz").Return(nil, nil)
+		c := cache.New(time.Minute)
+		vm, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-baz")
+		require.NoError(t, err)
+		assert.Equal(t, "i-baz", vm.VmId)
+		vm, err = c.GetVmFromClientToken(context.TODO(), tn, svc, "uid", "token-baz")
+		require.NoError(t, err)
+		assert.Nil(t, vm)
+	})
+	t.Run("Entries are refreshed after their TTL", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return(vms, nil).Times(2)
+		c := cache.New(time.Millisecond)
+		_, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+		_, err = c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+	})
+	t.Run("Entries are invalidated on writes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return(vms, nil).Times(2)
+		svc.EXPECT().StopVm(gomock.Any(), "i-foo").Return(nil)
+		c := cache.New(time.Minute)
+		_, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+		err = c.Compute(tn, svc).StopVm(context.TODO(), "i-foo")
+		require.NoError(t, err)
+		_, err = c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+	})
+	t.Run("Entries are not shared between tenants", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return(vms, nil).Times(2)
+		c := cache.New(time.Minute)
+		_, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+		_, err = c.GetVm(context.TODO(), testTenant{accessKey: "bar"}, svc, "uid", "i-foo")
+		require.NoError(t, err)
+	})
+	t.Run("A nil cache reads directly", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		svc := mock_compute.NewMockServicer(mockCtrl)
+		svc.EXPECT().GetVm(gomock.Any(), "i-foo").Return(&osc.Vm{VmId: "i-foo"}, nil)
+		c := cache.New(0)
+		vm, err := c.GetVm(context.TODO(), tn, svc, "uid", "i-foo")
+		require.NoError(t, err)
+		assert.Equal(t, "i-foo", vm.VmId)
+	})
+}
+
+func TestCache_ReadOwnedByTag(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	mockCtrl := gomock.NewController(t)
+	svc := mock_tag.NewMockServicer(mockCtrl)
+	svc.EXPECT().ReadClusterTags(gomock.Any(), "uid").Return([]osc.Tag{
+		{Key: tag.ClusterKeyPrefix + "uid", Value: tag.OwnedValue, ResourceType: osc.TagResourceType(tag.VmResourceType), ResourceId: "i-foo"},
+		{Key: tag.ClusterKeyPrefix + "uid", Value: tag.OwnedValue, ResourceType: osc.TagResourceType(tag.NetResourceType), ResourceId: "vpc-foo"},
+	}, nil)
+	svc.EXPECT().ReadOwnedByTag(gomock.Any(), tag.NetPeeringResourceType, "uid").Return(nil, nil)
+	c := cache.New(time.Minute)
+	tg, err := c.ReadOwnedByTag(context.TODO(), tn, svc, tag.NetResourceType, "uid")
+	require.NoError(t, err)
+	require.NotNil(t, tg)
+	assert.Equal(t, "vpc-foo", tg.ResourceId)
+	tg, err = c.ReadOwnedByTag(context.TODO(), tn, svc, tag.NetPeeringResourceType, "uid")
+	require.NoError(t, err)
+	assert.Nil(t, tg)
+}
+
+func TestCache_GetSecurityGroup(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	mockCtrl := gomock.NewController(t)
+	svc := mock_compute.NewMockServicer(mockCtrl)
+	svc.EXPECT().GetSecurityGroupsFromNet(gomock.Any(), "vpc-foo").Return([]osc.SecurityGroup{
+		{SecurityGroupId: "sg-foo", SecurityGroupName: "foo"},
+		{SecurityGroupId: "sg-bar", SecurityGroupName: "bar"},
+	}, nil)
+	c := cache.New(time.Minute)
+	sg, err := c.GetSecurityGroup(context.TODO(), tn, svc, "vpc-foo", "sg-bar")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", sg.SecurityGroupName)
+	sg, err = c.GetSecurityGroupFromName(context.TODO(), tn, svc, "vpc-foo", "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "sg-foo", sg.SecurityGroupId)
+}
+
+func TestCache_GetLoadBalancer(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	mockCtrl := gomock.NewController(t)
+	svc := mock_net.NewMockServicer(mockCtrl)
+	svc.EXPECT().GetLoadBalancer(gomock.Any(), "lb-foo").Return(&osc.LoadBalancer{LoadBalancerName: "lb-foo"}, nil).Times(2)
+	svc.EXPECT().LinkLoadBalancerBackendMachines(gomock.Any(), []string{"i-foo"}, "lb-foo").Return(nil)
+	c := cache.New(time.Minute)
+	net := c.Net(tn, svc)
+	lb, err := net.GetLoadBalancer(context.TODO(), "lb-foo")
+	require.NoError(t, err)
+	assert.Equal(t, "lb-foo", lb.LoadBalancerName)
+	_, err = net.GetLoadBalancer(context.TODO(), "lb-foo")
+	require.NoError(t, err)
+	err = net.LinkLoadBalancerBackendMachines(context.TODO(), []string{"i-foo"}, "lb-foo")
+	require.NoError(t, err)
+	_, err = net.GetLoadBalancer(context.TODO(), "lb-foo")
+	require.NoError(t, err)
+}
+
+func TestCache_GetLoadBalancerMissing(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	mockCtrl := gomock.NewController(t)
+	svc := mock_net.NewMockServicer(mockCtrl)
+	svc.EXPECT().GetLoadBalancer(gomock.Any(), "lb-foo").Return(nil, nil)
+	svc.EXPECT().GetLoadBalancer(gomock.Any(), "lb-foo").Return(&osc.LoadBalancer{LoadBalancerName: "lb-foo"}, nil)
+	c := cache.New(time.Minute)
+	lb, err := c.GetLoadBalancer(context.TODO(), tn, svc, "lb-foo")
+	require.NoError(t, err)
+	assert.Nil(t, lb)
+	lb, err = c.GetLoadBalancer(context.TODO(), tn, svc, "lb-foo")
+	require.NoError(t, err)
+	require.NotNil(t, lb)
+	assert.Equal(t, "lb-foo", lb.LoadBalancerName)
+}
+
+func TestCache_GetLoadBalancerCopy(t *testing.T) {
+	tn := testTenant{accessKey: "foo"}
+	mockCtrl := gomock.NewController(t)
+	svc := mock_net.NewMockServicer(mockCtrl)
+	svc.EXPECT().GetLoadBalancer(gomock.Any(), "lb-foo").Return(&osc.LoadBalancer{
+		LoadBalancerName: "lb-foo",
+		BackendVmIds:     []string{"i-foo"},
+		Listeners:        []osc.Listener{{LoadBalancerPort: 6443, ServerCertificateId: new("orn-foo")}},
+		Tags:             []osc.ResourceTag{{Key: "Name", Value: "foo"}},
+	}, nil)
+	c := cache.New(time.Minute)
+	lb, err := c.GetLoadBalancer(context.TODO(), tn, svc, "lb-foo")
+	require.NoError(t, err)
+	lb.BackendVmIds[0] = "i-bar"
+	lb.Listeners[0].LoadBalancerPort = 443
+	*lb.Listeners[0].ServerCertificateId = "orn-bar"
+	lb.Tags[0].Value = "bar"
+	lb, err = c.GetLoadBalancer(context.TODO(), tn, svc, "lb-foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-foo"}, lb.BackendVmIds)
+	assert.Equal(t, 6443, lb.Listeners[0].LoadBalancerPort)
+	assert.Equal(t, "orn-foo", *lb.Listeners[0].ServerCertificateId)
+	assert.Equal(t, "foo", lb.Tags[0].Value)
+}
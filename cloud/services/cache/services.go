@@ -0,0 +1,236 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package cache
+
+import (
+	"context"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+// Tag wraps a tag service, invalidating the tenant entries when tags are added or deleted.
+func (c *Cache) Tag(t tenant.Tenant, svc tag.Servicer) tag.Servicer {
+	if c == nil {
+		return svc
+	}
+	return &tagService{Servicer: svc, c: c, t: t}
+}
+
+type tagService struct {
+	tag.Servicer
+	c *Cache
+	t tenant.Tenant
+}
+
+func (s *tagService) AddTag(ctx context.Context, req osc.CreateTagsRequest, resourceIds []string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.AddTag(ctx, req, resourceIds)
+}
+
+func (s *tagService) DeleteTags(ctx context.Context, req osc.DeleteTagsRequest) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteTags(ctx, req)
+}
+
+// Compute wraps a compute service, invalidating the tenant entries on vm and security group writes.
+func (c *Cache) Compute(t tenant.Tenant, svc compute.Servicer) compute.Servicer {
+	if c == nil {
+		return svc
+	}
+	return &computeService{Servicer: svc, c: c, t: t}
+}
+
+type computeService struct {
+	compute.Servicer
+	c *Cache
+	t tenant.Tenant
+}
+
+func (s *computeService) CreateVm(ctx context.Context,
+	machineScope *scope.MachineScope, spec *infrastructurev1beta2.OscVm, imageId, subnetId string, securityGroupIds []string, privateIps []string, vmName, vmClientToken string, tags map[string]string,
+	volumes []infrastructurev1beta2.OscVolume,
+) (*osc.Vm, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateVm(ctx, machineScope, spec, imageId, subnetId, securityGroupIds, privateIps, vmName, vmClientToken, tags, volumes)
+}
+
+func (s *computeService) CreateVmBastion(ctx context.Context, spec *infrastructurev1beta2.OscBastion, subnetId string, securityGroupIds []string, privateIps []string, vmName, vmClientToken, imageId string, tags map[string]string) (*osc.Vm, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateVmBastion(ctx, spec, subnetId, securityGroupIds, privateIps, vmName, vmClientToken, imageId, tags)
+}
+
+func (s *computeService) DeleteVm(ctx context.Context, vmId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteVm(ctx, vmId)
+}
+
+func (s *computeService) AddCCMTags(ctx context.Context, clusterName string, hostname string, vmId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.AddCCMTags(ctx, clusterName, hostname, vmId)
+}
+
+func (s *computeService) StartVm(ctx context.Context, vmId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.StartVm(ctx, vmId)
+}
+
+func (s *computeService) StopVm(ctx context.Context, vmId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.StopVm(ctx, vmId)
+}
+
+func (s *computeService) CreateSecurityGroup(ctx context.Context, netId, clusterID, securityGroupName, securityGroupDescription, securityGroupTag string, roles []infrastructurev1beta2.OscRole) (*osc.SecurityGroup, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateSecurityGroup(ctx, netId, clusterID, securityGroupName, securityGroupDescription, securityGroupTag, roles)
+}
+
+func (s *computeService) CreateSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateSecurityGroupRules(ctx, securityGroupId, flow, rules)
+}
+
+func (s *computeService) DeleteSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteSecurityGroupRules(ctx, securityGroupId, flow, rules)
+}
+
+func (s *computeService) DeleteSecurityGroup(ctx context.Context, securityGroupId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteSecurityGroup(ctx, securityGroupId)
+}
+
+// Net wraps a net service, reading loadBalancers through the cache, and invalidating the tenant entries when resources are deleted
+// or loadBalancers are updated.
+// Created resources are tagged using a tag service, which should also be wrapped.
+func (c *Cache) Net(t tenant.Tenant, svc net.Servicer) net.Servicer {
+	if c == nil {
+		return svc
+	}
+	return &netService{Servicer: svc, c: c, t: t}
+}
+
+type netService struct {
+	net.Servicer
+	c *Cache
+	t tenant.Tenant
+}
+
+func (s *netService) DeleteNet(ctx context.Context, netId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteNet(ctx, netId)
+}
+
+func (s *netService) DeleteNetPeering(ctx context.Context, netPeeringID string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteNetPeering(ctx, netPeeringID)
+}
+
+func (s *netService) DeleteSubnet(ctx context.Context, subnetId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteSubnet(ctx, subnetId)
+}
+
+func (s *netService) DeleteInternetService(ctx context.Context, internetServiceId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteInternetService(ctx, internetServiceId)
+}
+
+func (s *netService) DeleteNatService(ctx context.Context, natServiceId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteNatService(ctx, natServiceId)
+}
+
+func (s *netService) DeleteNetAccessPoint(ctx context.Context, netAccessPointId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteNetAccessPoint(ctx, netAccessPointId)
+}
+
+func (s *netService) DeleteRouteTable(ctx context.Context, routeTableId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteRouteTable(ctx, routeTableId)
+}
+
+func (s *netService) DeletePublicIp(ctx context.Context, publicIpId string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeletePublicIp(ctx, publicIpId)
+}
+
+func (s *netService) DeleteLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteLoadBalancer(ctx, spec)
+}
+
+func (s *netService) GetLoadBalancer(ctx context.Context, loadBalancerName string) (*osc.LoadBalancer, error) {
+	return s.c.GetLoadBalancer(ctx, s.t, s.Servicer, loadBalancerName)
+}
+
+func (s *netService) CreateLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, subnetId, securityGroupId, publicIp string,
+	serverCertificates map[int32]string, tags []osc.ResourceTag) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateLoadBalancer(ctx, spec, subnetId, securityGroupId, publicIp, serverCertificates, tags)
+}
+
+func (s *netService) ConfigureHealthCheck(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.ConfigureHealthCheck(ctx, spec)
+}
+
+func (s *netService) CreateLoadBalancerListeners(ctx context.Context, loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener,
+	serverCertificates map[int32]string) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateLoadBalancerListeners(ctx, loadBalancerName, listeners, serverCertificates)
+}
+
+func (s *netService) DeleteLoadBalancerListeners(ctx context.Context, loadBalancerName string, loadBalancerPorts []int) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteLoadBalancerListeners(ctx, loadBalancerName, loadBalancerPorts)
+}
+
+func (s *netService) UpdateLoadBalancerSecurityGroups(ctx context.Context, loadBalancerName string, securityGroupIds []string) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.UpdateLoadBalancerSecurityGroups(ctx, loadBalancerName, securityGroupIds)
+}
+
+func (s *netService) UpdateLoadBalancerPublicIp(ctx context.Context, loadBalancerName string, publicIp string) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.UpdateLoadBalancerPublicIp(ctx, loadBalancerName, publicIp)
+}
+
+func (s *netService) UpdateLoadBalancerServerCertificate(ctx context.Context, loadBalancerName string, loadBalancerPort int32, serverCertificateId string) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.UpdateLoadBalancerServerCertificate(ctx, loadBalancerName, loadBalancerPort, serverCertificateId)
+}
+
+func (s *netService) UpdateLoadBalancerAccessLog(ctx context.Context, loadBalancerName string, accessLog infrastructurev1beta2.OscLoadBalancerAccessLog) (*osc.LoadBalancer, error) {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.UpdateLoadBalancerAccessLog(ctx, loadBalancerName, accessLog)
+}
+
+func (s *netService) LinkLoadBalancerBackendMachines(ctx context.Context, vmIds []string, loadBalancerName string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.LinkLoadBalancerBackendMachines(ctx, vmIds, loadBalancerName)
+}
+
+func (s *netService) UnlinkLoadBalancerBackendMachines(ctx context.Context, vmIds []string, loadBalancerName string) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.UnlinkLoadBalancerBackendMachines(ctx, vmIds, loadBalancerName)
+}
+
+func (s *netService) CreateLoadBalancerTag(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, loadBalancerTag *osc.ResourceTag) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.CreateLoadBalancerTag(ctx, spec, loadBalancerTag)
+}
+
+func (s *netService) DeleteLoadBalancerTag(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, loadBalancerTag osc.ResourceLoadBalancerTag) error {
+	defer s.c.Invalidate(s.t)
+	return s.Servicer.DeleteLoadBalancerTag(ctx, spec, loadBalancerTag)
+}
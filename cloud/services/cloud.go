@@ -8,7 +8,9 @@ package services
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/cache"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
@@ -21,15 +23,21 @@ type Servicer interface {
 	Net(t tenant.Tenant) net.Servicer
 	Compute(t tenant.Tenant) compute.Servicer
 	Tag(t tenant.Tenant) tag.Servicer
+
+	Cache() *cache.Cache
 }
 
 type Services struct {
 	mu            sync.Mutex
 	defaultTenant tenant.Tenant
+	cache         *cache.Cache
 }
 
-func NewServices() (*Services, error) {
-	return &Services{}, nil
+// NewServices creates services, with a read cache if cacheTTL is positive.
+func NewServices(cacheTTL time.Duration) (*Services, error) {
+	return &Services{
+		cache: cache.New(cacheTTL),
+	}, nil
 }
 
 func (s *Services) DefaultTenant() (tenant.Tenant, error) {
@@ -47,17 +55,22 @@ func (s *Services) DefaultTenant() (tenant.Tenant, error) {
 
 // Net returns the Net service
 func (s *Services) Net(t tenant.Tenant) net.Servicer {
-	return net.NewService(t, tag.NewService(t))
+	return s.cache.Net(t, net.NewService(t, s.Tag(t)))
 }
 
 // VM returns a VM service
 func (s *Services) Compute(t tenant.Tenant) compute.Servicer {
-	return compute.NewService(t, tag.NewService(t))
+	return s.cache.Compute(t, compute.NewService(t, s.Tag(t)))
 }
 
 // Tag returns a tag service
 func (s *Services) Tag(t tenant.Tenant) tag.Servicer {
-	return tag.NewService(t)
+	return s.cache.Tag(t, tag.NewService(t))
+}
+
+// Cache returns the read cache, nil if disabled
+func (s *Services) Cache() *cache.Cache {
+	return s.cache
 }
 
 var _ Servicer = (*Services)(nil)
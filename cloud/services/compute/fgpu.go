@@ -17,6 +17,7 @@ import (
 
 type FGPUInterface interface {
 	GetFGPU(ctx context.Context, id string) (*osc.FlexibleGpu, error)
+	GetFGPUs(ctx context.Context, ids []string) ([]osc.FlexibleGpu, error)
 	AllocateFGPU(ctx context.Context, model, az string, machineScope *scope.MachineScope) (*osc.FlexibleGpu, error)
 	LinkFGPU(ctx context.Context, fGPUId, vmId string) error
 }
@@ -68,6 +69,19 @@ func (s *Service) GetFGPU(ctx context.Context, id string) (*osc.FlexibleGpu, err
 	}
 }
 
+func (s *Service) GetFGPUs(ctx context.Context, ids []string) ([]osc.FlexibleGpu, error) {
+	req := osc.ReadFlexibleGpusRequest{
+		Filters: &osc.FiltersFlexibleGpu{
+			FlexibleGpuIds: &ids,
+		},
+	}
+	resp, err := s.tenant.Client().ReadFlexibleGpus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.FlexibleGpus, nil
+}
+
 func (s *Service) LinkFGPU(ctx context.Context, fGPUId, vmId string) error {
 	req := osc.LinkFlexibleGpuRequest{
 		FlexibleGpuId: fGPUId,
@@ -87,19 +87,18 @@ func (mr *MockServicerMockRecorder) CreateSecurityGroup(ctx, netId, clusterID, s
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecurityGroup", reflect.TypeOf((*MockServicer)(nil).CreateSecurityGroup), ctx, netId, clusterID, securityGroupName, securityGroupDescription, securityGroupTag, roles)
 }
 
-// CreateSecurityGroupRule mocks base method.
-func (m *MockServicer) CreateSecurityGroupRule(ctx context.Context, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId string, fromPortRange, toPortRange int) (*osc.SecurityGroup, error) {
+// CreateSecurityGroupRules mocks base method.
+func (m *MockServicer) CreateSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSecurityGroupRule", ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange)
-	ret0, _ := ret[0].(*osc.SecurityGroup)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateSecurityGroupRules", ctx, securityGroupId, flow, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateSecurityGroupRule indicates an expected call of CreateSecurityGroupRule.
-func (mr *MockServicerMockRecorder) CreateSecurityGroupRule(ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange any) *gomock.Call {
+// CreateSecurityGroupRules indicates an expected call of CreateSecurityGroupRules.
+func (mr *MockServicerMockRecorder) CreateSecurityGroupRules(ctx, securityGroupId, flow, rules any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecurityGroupRule", reflect.TypeOf((*MockServicer)(nil).CreateSecurityGroupRule), ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecurityGroupRules", reflect.TypeOf((*MockServicer)(nil).CreateSecurityGroupRules), ctx, securityGroupId, flow, rules)
 }
 
 // CreateVm mocks base method.
@@ -146,18 +145,18 @@ func (mr *MockServicerMockRecorder) DeleteSecurityGroup(ctx, securityGroupId any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecurityGroup", reflect.TypeOf((*MockServicer)(nil).DeleteSecurityGroup), ctx, securityGroupId)
 }
 
-// DeleteSecurityGroupRule mocks base method.
-func (m *MockServicer) DeleteSecurityGroupRule(ctx context.Context, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId string, fromPortRange, toPortRange int) error {
+// DeleteSecurityGroupRules mocks base method.
+func (m *MockServicer) DeleteSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSecurityGroupRule", ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange)
+	ret := m.ctrl.Call(m, "DeleteSecurityGroupRules", ctx, securityGroupId, flow, rules)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSecurityGroupRule indicates an expected call of DeleteSecurityGroupRule.
-func (mr *MockServicerMockRecorder) DeleteSecurityGroupRule(ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange any) *gomock.Call {
+// DeleteSecurityGroupRules indicates an expected call of DeleteSecurityGroupRules.
+func (mr *MockServicerMockRecorder) DeleteSecurityGroupRules(ctx, securityGroupId, flow, rules any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecurityGroupRule", reflect.TypeOf((*MockServicer)(nil).DeleteSecurityGroupRule), ctx, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId, fromPortRange, toPortRange)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecurityGroupRules", reflect.TypeOf((*MockServicer)(nil).DeleteSecurityGroupRules), ctx, securityGroupId, flow, rules)
 }
 
 // DeleteVm mocks base method.
@@ -189,6 +188,21 @@ func (mr *MockServicerMockRecorder) GetFGPU(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFGPU", reflect.TypeOf((*MockServicer)(nil).GetFGPU), ctx, id)
 }
 
+// GetFGPUs mocks base method.
+func (m *MockServicer) GetFGPUs(ctx context.Context, ids []string) ([]osc.FlexibleGpu, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFGPUs", ctx, ids)
+	ret0, _ := ret[0].([]osc.FlexibleGpu)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFGPUs indicates an expected call of GetFGPUs.
+func (mr *MockServicerMockRecorder) GetFGPUs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFGPUs", reflect.TypeOf((*MockServicer)(nil).GetFGPUs), ctx, ids)
+}
+
 // GetImage mocks base method.
 func (m *MockServicer) GetImage(ctx context.Context, id string) (*osc.Image, error) {
 	m.ctrl.T.Helper()
@@ -294,33 +308,48 @@ func (mr *MockServicerMockRecorder) GetVmFromClientToken(ctx, clientToken any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVmFromClientToken", reflect.TypeOf((*MockServicer)(nil).GetVmFromClientToken), ctx, clientToken)
 }
 
-// LinkFGPU mocks base method.
-func (m *MockServicer) LinkFGPU(ctx context.Context, fGPUId, vmId string) error {
+// GetVms mocks base method.
+func (m *MockServicer) GetVms(ctx context.Context, vmIds []string) ([]osc.Vm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LinkFGPU", ctx, fGPUId, vmId)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetVms", ctx, vmIds)
+	ret0, _ := ret[0].([]osc.Vm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// LinkFGPU indicates an expected call of LinkFGPU.
-func (mr *MockServicerMockRecorder) LinkFGPU(ctx, fGPUId, vmId any) *gomock.Call {
+// GetVms indicates an expected call of GetVms.
+func (mr *MockServicerMockRecorder) GetVms(ctx, vmIds any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkFGPU", reflect.TypeOf((*MockServicer)(nil).LinkFGPU), ctx, fGPUId, vmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVms", reflect.TypeOf((*MockServicer)(nil).GetVms), ctx, vmIds)
 }
 
-// SecurityGroupHasRule mocks base method.
-func (m *MockServicer) SecurityGroupHasRule(ctx context.Context, securityGroupId, flow, ipProtocols, ipRanges, securityGroupMemberId string, fromPortRanges, toPortRanges int) (bool, error) {
+// GetVmsFromCluster mocks base method.
+func (m *MockServicer) GetVmsFromCluster(ctx context.Context, clusterID string) ([]osc.Vm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SecurityGroupHasRule", ctx, securityGroupId, flow, ipProtocols, ipRanges, securityGroupMemberId, fromPortRanges, toPortRanges)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetVmsFromCluster", ctx, clusterID)
+	ret0, _ := ret[0].([]osc.Vm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SecurityGroupHasRule indicates an expected call of SecurityGroupHasRule.
-func (mr *MockServicerMockRecorder) SecurityGroupHasRule(ctx, securityGroupId, flow, ipProtocols, ipRanges, securityGroupMemberId, fromPortRanges, toPortRanges any) *gomock.Call {
+// GetVmsFromCluster indicates an expected call of GetVmsFromCluster.
+func (mr *MockServicerMockRecorder) GetVmsFromCluster(ctx, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVmsFromCluster", reflect.TypeOf((*MockServicer)(nil).GetVmsFromCluster), ctx, clusterID)
+}
+
+// LinkFGPU mocks base method.
+func (m *MockServicer) LinkFGPU(ctx context.Context, fGPUId, vmId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkFGPU", ctx, fGPUId, vmId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkFGPU indicates an expected call of LinkFGPU.
+func (mr *MockServicerMockRecorder) LinkFGPU(ctx, fGPUId, vmId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityGroupHasRule", reflect.TypeOf((*MockServicer)(nil).SecurityGroupHasRule), ctx, securityGroupId, flow, ipProtocols, ipRanges, securityGroupMemberId, fromPortRanges, toPortRanges)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkFGPU", reflect.TypeOf((*MockServicer)(nil).LinkFGPU), ctx, fGPUId, vmId)
 }
 
 // StartVm mocks base method.
@@ -9,6 +9,10 @@ package compute
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/utils"
@@ -20,11 +24,10 @@ var ErrResourceConflict = errors.New("conflict with existing resource")
 
 type SecurityGroupInterface interface {
 	CreateSecurityGroup(ctx context.Context, netId, clusterID, securityGroupName, securityGroupDescription, securityGroupTag string, roles []infrastructurev1beta2.OscRole) (*osc.SecurityGroup, error)
-	CreateSecurityGroupRule(ctx context.Context, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId string, fromPortRange, toPortRange int) (*osc.SecurityGroup, error)
-	DeleteSecurityGroupRule(ctx context.Context, securityGroupId, flow, ipProtocol, ipRange, securityGroupMemberId string, fromPortRange, toPortRange int) error
+	CreateSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error
+	DeleteSecurityGroupRules(ctx context.Context, securityGroupId, flow string, rules []osc.SecurityGroupRule) error
 	DeleteSecurityGroup(ctx context.Context, securityGroupId string) error
 	GetSecurityGroup(ctx context.Context, securityGroupId string) (*osc.SecurityGroup, error)
-	SecurityGroupHasRule(ctx context.Context, securityGroupId, flow, ipProtocols, ipRanges, securityGroupMemberId string, fromPortRanges, toPortRanges int) (bool, error)
 	GetSecurityGroupsFromNet(ctx context.Context, netId string) ([]osc.SecurityGroup, error)
 	GetSecurityGroupFromName(ctx context.Context, name string) (*osc.SecurityGroup, error)
 }
@@ -72,73 +75,128 @@ func (s *Service) CreateSecurityGroup(ctx context.Context, netId string, cluster
 	return resp.SecurityGroup, nil
 }
 
-// CreateSecurityGroupRule create the security group rule associated with the security group and the net
-func (s *Service) CreateSecurityGroupRule(ctx context.Context, securityGroupId string, flow string, ipProtocol string, ipRange string, securityGroupMemberId string, fromPortRange int, toPortRange int) (*osc.SecurityGroup, error) {
-	var rule osc.SecurityGroupRule
-	switch {
-	case securityGroupMemberId != "" && ipRange == "":
-		securityGroupMember := osc.SecurityGroupsMember{
-			SecurityGroupId: securityGroupMemberId,
-		}
-		rule = osc.SecurityGroupRule{
-			SecurityGroupsMembers: []osc.SecurityGroupsMember{securityGroupMember},
-			IpProtocol:            ipProtocol,
-			FromPortRange:         fromPortRange,
-			ToPortRange:           toPortRange,
+// CreateSecurityGroupRules creates rules in as few calls as possible.
+// Rules sharing the same protocol and ports are merged, and all rules of a flow are sent in a single call.
+// If the call is rejected, rules are split in halves until the failing rules are found, and all errors are returned.
+func (s *Service) CreateSecurityGroupRules(ctx context.Context, securityGroupId string, flow string, rules []osc.SecurityGroupRule) error {
+	return applySecurityGroupRules(splitSecurityGroupRules(rules), func(rules []osc.SecurityGroupRule) error {
+		req := osc.CreateSecurityGroupRuleRequest{
+			Flow:            flow,
+			SecurityGroupId: securityGroupId,
+			Rules:           rules,
 		}
-	case securityGroupMemberId != "" && ipRange != "":
-		return nil, errors.New("ipRange and securityGroupMemberId are incompatible")
-	default:
-		rule = osc.SecurityGroupRule{
-			IpProtocol:    ipProtocol,
-			IpRanges:      []string{ipRange},
-			FromPortRange: fromPortRange,
-			ToPortRange:   toPortRange,
+		_, err := s.tenant.Client().CreateSecurityGroupRule(ctx, req)
+		return err
+	})
+}
+
+// DeleteSecurityGroupRules deletes rules in as few calls as possible.
+// Rules sharing the same protocol and ports are merged, and all rules of a flow are sent in a single call.
+// If the call is rejected, rules are split in halves until the failing rules are found, and all errors are returned.
+func (s *Service) DeleteSecurityGroupRules(ctx context.Context, securityGroupId string, flow string, rules []osc.SecurityGroupRule) error {
+	return applySecurityGroupRules(splitSecurityGroupRules(rules), func(rules []osc.SecurityGroupRule) error {
+		req := osc.DeleteSecurityGroupRuleRequest{
+			Flow:            flow,
+			SecurityGroupId: securityGroupId,
+			Rules:           rules,
 		}
+		_, err := s.tenant.Client().DeleteSecurityGroupRule(ctx, req)
+		return err
+	})
+}
+
+// applySecurityGroupRules applies merged rules, bisecting them when the API rejects a rule.
+// Other errors (network, context, throttling, server errors...) are returned as is.
+func applySecurityGroupRules(rules []osc.SecurityGroupRule, apply func(rules []osc.SecurityGroupRule) error) error {
+	if len(rules) == 0 {
+		return nil
 	}
-	req := osc.CreateSecurityGroupRuleRequest{
-		Flow:            flow,
-		SecurityGroupId: securityGroupId,
-		Rules:           []osc.SecurityGroupRule{rule},
+	err := apply(mergeSecurityGroupRules(rules))
+	switch {
+	case err == nil:
+		return nil
+	case len(rules) == 1:
+		return fmt.Errorf("%s: %w", formatSecurityGroupRule(rules[0]), err)
+	case !isRuleError(err):
+		return err
 	}
+	half := len(rules) / 2
+	return errors.Join(applySecurityGroupRules(rules[:half], apply), applySecurityGroupRules(rules[half:], apply))
+}
 
-	resp, err := s.tenant.Client().CreateSecurityGroupRule(ctx, req)
-	if err != nil {
-		return nil, err
+// isRuleError returns true if the API rejected a rule: invalid parameter, unknown security group member, duplicate or conflicting rule.
+func isRuleError(err error) bool {
+	if osc.IsNotFound(err) || osc.IsConflict(err) {
+		return true
 	}
-	return resp.SecurityGroup, nil
+	resp := osc.AsErrorResponse(err)
+	if resp == nil || osc.IsAuthError(err) {
+		return false
+	}
+	return slices.ContainsFunc(resp.Errors, func(e osc.Errors) bool {
+		// 4xxx codes are validation errors
+		c, err := strconv.Atoi(e.Code)
+		return err == nil && c >= 4000 && c <= 4999
+	})
 }
 
-// DeleteSecurityGroupRule delete the security group rule associated with the security group and the net
-func (s *Service) DeleteSecurityGroupRule(ctx context.Context, securityGroupId string, flow string, ipProtocol string, ipRange string, securityGroupMemberId string, fromPortRange int, toPortRange int) error {
-	var rule osc.SecurityGroupRule
-	if securityGroupMemberId != "" && ipRange == "" {
-		securityGroupMember := osc.SecurityGroupsMember{
-			SecurityGroupId: securityGroupMemberId,
-		}
-		rule = osc.SecurityGroupRule{
-			SecurityGroupsMembers: []osc.SecurityGroupsMember{securityGroupMember},
-			IpProtocol:            ipProtocol,
-			FromPortRange:         fromPortRange,
-			ToPortRange:           toPortRange,
+// splitSecurityGroupRules splits rules into rules having a single IP range or a single security group member.
+func splitSecurityGroupRules(rules []osc.SecurityGroupRule) []osc.SecurityGroupRule {
+	var split []osc.SecurityGroupRule
+	for _, rule := range rules {
+		for _, ipRange := range rule.IpRanges {
+			split = append(split, osc.SecurityGroupRule{
+				IpProtocol:    rule.IpProtocol,
+				IpRanges:      []string{ipRange},
+				FromPortRange: rule.FromPortRange,
+				ToPortRange:   rule.ToPortRange,
+			})
 		}
-	} else {
-		rule = osc.SecurityGroupRule{
-			IpProtocol:    ipProtocol,
-			IpRanges:      []string{ipRange},
-			FromPortRange: fromPortRange,
-			ToPortRange:   toPortRange,
+		for _, member := range rule.SecurityGroupsMembers {
+			split = append(split, osc.SecurityGroupRule{
+				IpProtocol:            rule.IpProtocol,
+				SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: member.SecurityGroupId}},
+				FromPortRange:         rule.FromPortRange,
+				ToPortRange:           rule.ToPortRange,
+			})
 		}
 	}
+	return split
+}
 
-	req := osc.DeleteSecurityGroupRuleRequest{
-		Flow:            flow,
-		SecurityGroupId: securityGroupId,
-		Rules:           []osc.SecurityGroupRule{rule},
+// mergeSecurityGroupRules merges rules sharing the same protocol and ports.
+// IP ranges and security group members are not mixed within a rule.
+func mergeSecurityGroupRules(rules []osc.SecurityGroupRule) []osc.SecurityGroupRule {
+	type key struct {
+		protocol string
+		from, to int
+		member   bool
 	}
+	var merged []osc.SecurityGroupRule
+	idx := map[key]int{}
+	for _, rule := range rules {
+		k := key{protocol: rule.IpProtocol, from: rule.FromPortRange, to: rule.ToPortRange, member: len(rule.SecurityGroupsMembers) > 0}
+		i, found := idx[k]
+		if !found {
+			idx[k] = len(merged)
+			merged = append(merged, osc.SecurityGroupRule{IpProtocol: rule.IpProtocol, FromPortRange: rule.FromPortRange, ToPortRange: rule.ToPortRange})
+			i = len(merged) - 1
+		}
+		merged[i].IpRanges = append(merged[i].IpRanges, rule.IpRanges...)
+		merged[i].SecurityGroupsMembers = append(merged[i].SecurityGroupsMembers, rule.SecurityGroupsMembers...)
+	}
+	return merged
+}
 
-	_, err := s.tenant.Client().DeleteSecurityGroupRule(ctx, req)
-	return err
+func formatSecurityGroupRule(rule osc.SecurityGroupRule) string {
+	var source string
+	switch {
+	case len(rule.IpRanges) > 0:
+		source = rule.IpRanges[0]
+	case len(rule.SecurityGroupsMembers) > 0:
+		source = rule.SecurityGroupsMembers[0].SecurityGroupId
+	}
+	return fmt.Sprintf("rule %s %s %d-%d", source, rule.IpProtocol, rule.FromPortRange, rule.ToPortRange)
 }
 
 // DeleteSecurityGroup delete the securitygroup associated with the net
@@ -188,54 +246,35 @@ func (s *Service) GetSecurityGroupFromName(ctx context.Context, name string) (*o
 }
 
 // SecurityGroupHasRule checks if a security group has a specific rule.
-func (s *Service) SecurityGroupHasRule(ctx context.Context, securityGroupId string, flow string, ipProtocols string, ipRanges string, securityGroupMemberId string, fromPortRanges, toPortRanges int) (bool, error) {
-	var req osc.ReadSecurityGroupsRequest
-	if ipProtocols == "-1" {
-		fromPortRanges = -1
-		toPortRanges = -1
-	}
-
-	switch flow {
-	case "Inbound":
-		req = osc.ReadSecurityGroupsRequest{
-			Filters: &osc.FiltersSecurityGroup{
-				SecurityGroupIds:          &[]string{securityGroupId},
-				InboundRuleProtocols:      &[]string{ipProtocols},
-				InboundRuleFromPortRanges: &[]int{fromPortRanges},
-				InboundRuleToPortRanges:   &[]int{toPortRanges},
-			},
-		}
-	case "Outbound":
-		req = osc.ReadSecurityGroupsRequest{
-			Filters: &osc.FiltersSecurityGroup{
-				SecurityGroupIds:           &[]string{securityGroupId},
-				OutboundRuleProtocols:      &[]string{ipProtocols},
-				OutboundRuleFromPortRanges: &[]int{fromPortRanges},
-				OutboundRuleToPortRanges:   &[]int{toPortRanges},
-			},
-		}
-	default:
-		return false, errors.New("invalid Flow")
+// No call is made, rules are checked against the already fetched security group.
+func SecurityGroupHasRule(sg *osc.SecurityGroup, flow, ipProtocol, ipRange, securityGroupMemberId string, fromPortRange, toPortRange int) bool {
+	if ipProtocol == "-1" {
+		fromPortRange = -1
+		toPortRange = -1
 	}
-
-	switch {
-	case securityGroupMemberId != "" && ipRanges != "":
-		return false, errors.New("ipRange and securityGroupMemberId are incompatible")
-	case securityGroupMemberId != "" && flow == "Inbound":
-		req.Filters.InboundRuleSecurityGroupIds = &[]string{securityGroupMemberId}
-	case securityGroupMemberId != "" && flow == "Outbound":
-		req.Filters.OutboundRuleSecurityGroupIds = &[]string{securityGroupMemberId}
-	case ipRanges != "" && flow == "Inbound":
-		req.Filters.InboundRuleIpRanges = &[]string{ipRanges}
-	case ipRanges != "" && flow == "Outbound":
-		req.Filters.OutboundRuleIpRanges = &[]string{ipRanges}
+	var rules []osc.SecurityGroupRule
+	switch strings.ToLower(flow) {
+	case "inbound":
+		rules = sg.InboundRules
+	case "outbound":
+		rules = sg.OutboundRules
 	}
-
-	resp, err := s.tenant.Client().ReadSecurityGroups(ctx, req)
-	if err != nil {
-		return false, err
+	for _, rule := range rules {
+		if rule.IpProtocol != ipProtocol || rule.FromPortRange != fromPortRange || rule.ToPortRange != toPortRange {
+			continue
+		}
+		switch {
+		case securityGroupMemberId != "":
+			if slices.ContainsFunc(rule.SecurityGroupsMembers, func(member osc.SecurityGroupsMember) bool {
+				return member.SecurityGroupId == securityGroupMemberId
+			}) {
+				return true
+			}
+		case slices.Contains(rule.IpRanges, ipRange):
+			return true
+		}
 	}
-	return len(*resp.SecurityGroups) > 0, nil
+	return false
 }
 
 // GetSecurityGroupsFromNet return the security group id resource that exist from the net id
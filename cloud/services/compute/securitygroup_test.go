@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package compute
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySecurityGroupRules(t *testing.T) {
+	rules := splitSecurityGroupRules([]osc.SecurityGroupRule{
+		{IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, IpRanges: []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}},
+		{IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-foo"}}},
+		{IpProtocol: "udp", FromPortRange: 53, ToPortRange: 53, IpRanges: []string{"10.0.0.0/24"}},
+	})
+	require.Len(t, rules, 5)
+
+	t.Run("All rules are merged into a single call", func(t *testing.T) {
+		var calls [][]osc.SecurityGroupRule
+		err := applySecurityGroupRules(rules, func(rules []osc.SecurityGroupRule) error {
+			calls = append(calls, rules)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][]osc.SecurityGroupRule{{
+			{IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, IpRanges: []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}},
+			{IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-foo"}}},
+			{IpProtocol: "udp", FromPortRange: 53, ToPortRange: 53, IpRanges: []string{"10.0.0.0/24"}},
+		}}, calls)
+	})
+	t.Run("Rejected calls are split until the failing rule is found", func(t *testing.T) {
+		var applied []string
+		err := applySecurityGroupRules(rules, func(rules []osc.SecurityGroupRule) error {
+			for _, rule := range rules {
+				if slices.Contains(rule.IpRanges, "10.0.1.0/24") {
+					return &osc.ErrorResponse{Errors: []osc.Errors{{Code: "4045", Type: "InvalidParameterValue"}}}
+				}
+			}
+			for _, rule := range rules {
+				applied = append(applied, rule.IpRanges...)
+				for _, member := range rule.SecurityGroupsMembers {
+					applied = append(applied, member.SecurityGroupId)
+				}
+			}
+			return nil
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rule 10.0.1.0/24 tcp 22-22")
+		assert.ElementsMatch(t, []string{"10.0.0.0/24", "10.0.2.0/24", "sg-foo", "10.0.0.0/24"}, applied)
+	})
+	t.Run("Other errors are returned without retrying", func(t *testing.T) {
+		calls := 0
+		err := applySecurityGroupRules(rules, func(rules []osc.SecurityGroupRule) error {
+			calls++
+			return context.DeadlineExceeded
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("Duplicate rules are split until the failing rule is found", func(t *testing.T) {
+		calls := 0
+		err := applySecurityGroupRules(rules, func(rules []osc.SecurityGroupRule) error {
+			calls++
+			for _, rule := range rules {
+				if slices.ContainsFunc(rule.SecurityGroupsMembers, func(m osc.SecurityGroupsMember) bool { return m.SecurityGroupId == "sg-foo" }) {
+					return &osc.ErrorResponse{Errors: []osc.Errors{{Code: "9011", Type: "ResourceConflict"}}}
+				}
+			}
+			return nil
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rule sg-foo tcp 22-22")
+		assert.Greater(t, calls, 1)
+	})
+	t.Run("Other API errors are returned without retrying", func(t *testing.T) {
+		calls := 0
+		err := applySecurityGroupRules(rules, func(rules []osc.SecurityGroupRule) error {
+			calls++
+			return &osc.ErrorResponse{Errors: []osc.Errors{{Code: "2000", Type: "InternalError"}}}
+		})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("Nothing is called without rules", func(t *testing.T) {
+		err := applySecurityGroupRules(nil, func(rules []osc.SecurityGroupRule) error {
+			return errors.New("unexpected call")
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestSecurityGroupHasRule(t *testing.T) {
+	sg := &osc.SecurityGroup{
+		InboundRules: []osc.SecurityGroupRule{
+			{IpProtocol: "tcp", FromPortRange: 22, ToPortRange: 22, IpRanges: []string{"10.0.0.0/24"}},
+			{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-foo"}}},
+		},
+		OutboundRules: []osc.SecurityGroupRule{
+			{IpProtocol: "-1", FromPortRange: -1, ToPortRange: -1, IpRanges: []string{"0.0.0.0/0"}},
+		},
+	}
+	assert.True(t, SecurityGroupHasRule(sg, "Inbound", "tcp", "10.0.0.0/24", "", 22, 22))
+	assert.False(t, SecurityGroupHasRule(sg, "Inbound", "tcp", "10.0.1.0/24", "", 22, 22))
+	assert.False(t, SecurityGroupHasRule(sg, "Outbound", "tcp", "10.0.0.0/24", "", 22, 22))
+	assert.True(t, SecurityGroupHasRule(sg, "Inbound", "tcp", "", "sg-foo", 10250, 10250))
+	assert.False(t, SecurityGroupHasRule(sg, "Inbound", "tcp", "", "sg-bar", 10250, 10250))
+	assert.True(t, SecurityGroupHasRule(sg, "Outbound", "-1", "0.0.0.0/0", "", 0, 0))
+}
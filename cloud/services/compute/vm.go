@@ -43,6 +43,8 @@ type VmInterface interface {
 	DeleteVm(ctx context.Context, vmId string) error
 	GetVm(ctx context.Context, vmId string) (*osc.Vm, error)
 	GetVmFromClientToken(ctx context.Context, clientToken string) (*osc.Vm, error)
+	GetVmsFromCluster(ctx context.Context, clusterID string) ([]osc.Vm, error)
+	GetVms(ctx context.Context, vmIds []string) ([]osc.Vm, error)
 	AddCCMTags(ctx context.Context, clusterName string, hostname string, vmId string) error
 	StartVm(ctx context.Context, vmId string) error
 	StopVm(ctx context.Context, vmId string) error
@@ -246,6 +248,36 @@ func (s *Service) GetVmFromClientToken(ctx context.Context, clientToken string)
 	}
 }
 
+// GetVms retrieves vms from their vmIds
+func (s *Service) GetVms(ctx context.Context, vmIds []string) ([]osc.Vm, error) {
+	req := osc.ReadVmsRequest{
+		Filters: &osc.FiltersVm{
+			VmIds: &vmIds,
+		},
+	}
+
+	resp, err := s.tenant.Client().ReadVms(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.Vms, nil
+}
+
+// GetVmsFromCluster retrieves all vms tagged with the cluster ID
+func (s *Service) GetVmsFromCluster(ctx context.Context, clusterID string) ([]osc.Vm, error) {
+	req := osc.ReadVmsRequest{
+		Filters: &osc.FiltersVm{
+			TagKeys: &[]string{tags.ClusterIDKey(clusterID)},
+		},
+	}
+
+	resp, err := s.tenant.Client().ReadVms(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.Vms, nil
+}
+
 // StartVm starts a VM
 func (s *Service) StartVm(ctx context.Context, vmId string) error {
 	req := osc.StartVmsRequest{
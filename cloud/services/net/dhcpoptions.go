@@ -0,0 +1,90 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package net
+
+import (
+	"context"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+const (
+	// DefaultDhcpOptionsSetId is the id used to link the default DHCP options set to a net.
+	DefaultDhcpOptionsSetId = "default"
+	// OutscaleProvidedDNS is the domain name server of DHCP options sets created without domain name servers.
+	OutscaleProvidedDNS = "OutscaleProvidedDNS"
+)
+
+type DhcpOptionsInterface interface {
+	CreateDhcpOptions(ctx context.Context, spec infrastructurev1beta2.OscDhcpOptions, clusterID string) (*osc.DhcpOptionsSet, error)
+	GetDhcpOptions(ctx context.Context, dhcpOptionsSetId string) (*osc.DhcpOptionsSet, error)
+	LinkDhcpOptions(ctx context.Context, dhcpOptionsSetId, netId string) error
+	DeleteDhcpOptions(ctx context.Context, dhcpOptionsSetId string) error
+}
+
+// CreateDhcpOptions creates a DHCP options set owned by the cluster.
+func (s *Service) CreateDhcpOptions(ctx context.Context, spec infrastructurev1beta2.OscDhcpOptions, clusterID string) (*osc.DhcpOptionsSet, error) {
+	req := osc.CreateDhcpOptionsRequest{}
+	if spec.DomainName != "" {
+		req.DomainName = &spec.DomainName
+	}
+	if len(spec.DomainNameServers) > 0 {
+		req.DomainNameServers = &spec.DomainNameServers
+	}
+	if len(spec.NtpServers) > 0 {
+		req.NtpServers = &spec.NtpServers
+	}
+	resp, err := s.tenant.Client().CreateDhcpOptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resourceIds := []string{*resp.DhcpOptionsSet.DhcpOptionsSetId}
+	err = s.tags.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: resourceIds,
+		Tags: []osc.ResourceTag{{
+			Key:   tags.ClusterIDKey(clusterID),
+			Value: "owned",
+		}},
+	}, resourceIds)
+	if err != nil {
+		return nil, err
+	}
+	return resp.DhcpOptionsSet, nil
+}
+
+// GetDhcpOptions retrieves a DHCP options set.
+func (s *Service) GetDhcpOptions(ctx context.Context, dhcpOptionsSetId string) (*osc.DhcpOptionsSet, error) {
+	req := osc.ReadDhcpOptionsRequest{
+		Filters: &osc.FiltersDhcpOptions{
+			DhcpOptionsSetIds: &[]string{dhcpOptionsSetId},
+		},
+	}
+	resp, err := s.tenant.Client().ReadDhcpOptions(ctx, req)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(*resp.DhcpOptionsSets) == 0:
+		return nil, nil
+	default:
+		return &(*resp.DhcpOptionsSets)[0], nil
+	}
+}
+
+// LinkDhcpOptions links a DHCP options set (or the default one, using DefaultDhcpOptionsSetId) to a net.
+func (s *Service) LinkDhcpOptions(ctx context.Context, dhcpOptionsSetId, netId string) error {
+	req := osc.UpdateNetRequest{DhcpOptionsSetId: dhcpOptionsSetId, NetId: netId}
+	_, err := s.tenant.Client().UpdateNet(ctx, req)
+	return err
+}
+
+// DeleteDhcpOptions deletes a DHCP options set.
+func (s *Service) DeleteDhcpOptions(ctx context.Context, dhcpOptionsSetId string) error {
+	req := osc.DeleteDhcpOptionsRequest{DhcpOptionsSetId: dhcpOptionsSetId}
+	_, err := s.tenant.Client().DeleteDhcpOptions(ctx, req)
+	return err
+}
@@ -18,9 +18,11 @@ type InternetServiceInterface interface {
 	UnlinkInternetService(ctx context.Context, internetServiceId, netId string) error
 	GetInternetService(ctx context.Context, internetServiceId string) (*osc.InternetService, error)
 	GetInternetServiceForNet(ctx context.Context, netId string) (*osc.InternetService, error)
+	ListUnlinkedInternetServiceIds(ctx context.Context, internetServiceName, clusterID string) ([]string, error)
 }
 
 // CreateInternetService launch the internet service
+// If the internet service cannot be tagged, the created internet service is returned along with the error.
 func (s *Service) CreateInternetService(ctx context.Context, internetServiceName, clusterID string) (*osc.InternetService, error) {
 	req := osc.CreateInternetServiceRequest{}
 
@@ -43,7 +45,7 @@ func (s *Service) CreateInternetService(ctx context.Context, internetServiceName
 	}
 	err = s.tags.AddTag(ctx, internetServiceTagRequest, resourceIds)
 	if err != nil {
-		return nil, err
+		return resp.InternetService, err
 	}
 	return resp.InternetService, nil
 }
@@ -112,3 +114,19 @@ func (s *Service) GetInternetServiceForNet(ctx context.Context, netId string) (*
 		return &internetService[0], nil
 	}
 }
+
+// ListUnlinkedInternetServiceIds lists the ids of the internet services not linked to a net, having no tags
+// or having the tags set by CreateInternetService.
+func (s *Service) ListUnlinkedInternetServiceIds(ctx context.Context, internetServiceName, clusterID string) ([]string, error) {
+	resp, err := s.tenant.Client().ReadInternetServices(ctx, osc.ReadInternetServicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, is := range *resp.InternetServices {
+		if is.NetId == "" && isUntaggedOrCreatedAs(is.Tags, internetServiceName, clusterID) {
+			ids = append(ids, is.InternetServiceId)
+		}
+	}
+	return ids, nil
+}
@@ -15,8 +15,14 @@ import (
 type LoadBalancerInterface interface {
 	ConfigureHealthCheck(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer) (*osc.LoadBalancer, error)
 	GetLoadBalancer(ctx context.Context, loadBalancerName string) (*osc.LoadBalancer, error)
-	CreateLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, subnetId string, securityGroupId string) (*osc.LoadBalancer, error)
+	CreateLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, subnetId string, securityGroupId string, publicIp string, serverCertificates map[int32]string, tags []osc.ResourceTag) (*osc.LoadBalancer, error)
 	DeleteLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer) error
+	CreateLoadBalancerListeners(ctx context.Context, loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener, serverCertificates map[int32]string) (*osc.LoadBalancer, error)
+	DeleteLoadBalancerListeners(ctx context.Context, loadBalancerName string, loadBalancerPorts []int) (*osc.LoadBalancer, error)
+	UpdateLoadBalancerSecurityGroups(ctx context.Context, loadBalancerName string, securityGroupIds []string) (*osc.LoadBalancer, error)
+	UpdateLoadBalancerPublicIp(ctx context.Context, loadBalancerName string, publicIp string) (*osc.LoadBalancer, error)
+	UpdateLoadBalancerServerCertificate(ctx context.Context, loadBalancerName string, loadBalancerPort int32, serverCertificateId string) (*osc.LoadBalancer, error)
+	UpdateLoadBalancerAccessLog(ctx context.Context, loadBalancerName string, accessLog infrastructurev1beta2.OscLoadBalancerAccessLog) (*osc.LoadBalancer, error)
 	LinkLoadBalancerBackendMachines(ctx context.Context, vmIds []string, loadBalancerName string) error
 	UnlinkLoadBalancerBackendMachines(ctx context.Context, vmIds []string, loadBalancerName string) error
 	CreateLoadBalancerTag(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, loadBalancerTag *osc.ResourceTag) error
@@ -52,6 +58,111 @@ func (s *Service) ConfigureHealthCheck(ctx context.Context, spec *infrastructure
 	return resp.LoadBalancer, nil
 }
 
+// listenersForCreation converts listeners, serverCertificates being the ORN of the server certificates by loadBalancer port
+func listenersForCreation(listeners []infrastructurev1beta2.OscLoadBalancerListener, serverCertificates map[int32]string) []osc.ListenerForCreation {
+	res := make([]osc.ListenerForCreation, 0, len(listeners))
+	for _, listener := range listeners {
+		lfc := osc.ListenerForCreation{
+			BackendPort:          int(listener.BackendPort),
+			BackendProtocol:      &listener.BackendProtocol,
+			LoadBalancerPort:     int(listener.LoadBalancerPort),
+			LoadBalancerProtocol: listener.LoadBalancerProtocol,
+		}
+		if orn, ok := serverCertificates[listener.LoadBalancerPort]; ok {
+			lfc.ServerCertificateId = &orn
+		}
+		res = append(res, lfc)
+	}
+	return res
+}
+
+// CreateLoadBalancerListeners adds listeners to the loadBalancer
+func (s *Service) CreateLoadBalancerListeners(ctx context.Context, loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener, serverCertificates map[int32]string) (*osc.LoadBalancer, error) {
+	req := osc.CreateLoadBalancerListenersRequest{
+		LoadBalancerName: loadBalancerName,
+		Listeners:        listenersForCreation(listeners, serverCertificates),
+	}
+	resp, err := s.tenant.Client().CreateLoadBalancerListeners(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
+// DeleteLoadBalancerListeners removes the listeners of the loadBalancer using loadBalancerPorts
+func (s *Service) DeleteLoadBalancerListeners(ctx context.Context, loadBalancerName string, loadBalancerPorts []int) (*osc.LoadBalancer, error) {
+	req := osc.DeleteLoadBalancerListenersRequest{
+		LoadBalancerName:  loadBalancerName,
+		LoadBalancerPorts: loadBalancerPorts,
+	}
+	resp, err := s.tenant.Client().DeleteLoadBalancerListeners(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
+// UpdateLoadBalancerSecurityGroups replaces the security groups of the loadBalancer
+func (s *Service) UpdateLoadBalancerSecurityGroups(ctx context.Context, loadBalancerName string, securityGroupIds []string) (*osc.LoadBalancer, error) {
+	req := osc.UpdateLoadBalancerRequest{
+		LoadBalancerName: loadBalancerName,
+		SecurityGroups:   &securityGroupIds,
+	}
+	resp, err := s.tenant.Client().UpdateLoadBalancer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
+// UpdateLoadBalancerPublicIp replaces the public IP of the loadBalancer
+func (s *Service) UpdateLoadBalancerPublicIp(ctx context.Context, loadBalancerName string, publicIp string) (*osc.LoadBalancer, error) {
+	req := osc.UpdateLoadBalancerRequest{
+		LoadBalancerName: loadBalancerName,
+		PublicIp:         &publicIp,
+	}
+	resp, err := s.tenant.Client().UpdateLoadBalancer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
+// UpdateLoadBalancerServerCertificate replaces the server certificate of a listener of the loadBalancer
+func (s *Service) UpdateLoadBalancerServerCertificate(ctx context.Context, loadBalancerName string, loadBalancerPort int32, serverCertificateId string) (*osc.LoadBalancer, error) {
+	port := int(loadBalancerPort)
+	req := osc.UpdateLoadBalancerRequest{
+		LoadBalancerName:    loadBalancerName,
+		LoadBalancerPort:    &port,
+		ServerCertificateId: &serverCertificateId,
+	}
+	resp, err := s.tenant.Client().UpdateLoadBalancer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
+// UpdateLoadBalancerAccessLog configures the access logs of the loadBalancer, disabling them if no bucket is set
+func (s *Service) UpdateLoadBalancerAccessLog(ctx context.Context, loadBalancerName string, accessLog infrastructurev1beta2.OscLoadBalancerAccessLog) (*osc.LoadBalancer, error) {
+	al := osc.AccessLog{IsEnabled: accessLog.Bucket != ""}
+	if al.IsEnabled {
+		interval := int(accessLog.Interval)
+		al.OsuBucketName = &accessLog.Bucket
+		al.OsuBucketPrefix = &accessLog.Prefix
+		al.PublicationInterval = &interval
+	}
+	req := osc.UpdateLoadBalancerRequest{
+		LoadBalancerName: loadBalancerName,
+		AccessLog:        &al,
+	}
+	resp, err := s.tenant.Client().UpdateLoadBalancer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadBalancer, nil
+}
+
 // LinkLoadBalancerBackendMachines link the loadBalancer with vm backend
 func (s *Service) LinkLoadBalancerBackendMachines(ctx context.Context, vmIds []string, loadBalancerName string) error {
 	linkLoadBalancerBackendMachinesRequest := osc.LinkLoadBalancerBackendMachinesRequest{
@@ -100,27 +211,20 @@ func (s *Service) CreateLoadBalancerTag(ctx context.Context, spec *infrastructur
 	return err
 }
 
-// CreateLoadBalancer create the load balancer
-func (s *Service) CreateLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, subnetId string, securityGroupId string) (*osc.LoadBalancer, error) {
+// CreateLoadBalancer create the load balancer, tagged on creation, with an optional public IP and the server certificates of HTTPS/SSL listeners
+func (s *Service) CreateLoadBalancer(ctx context.Context, spec *infrastructurev1beta2.OscLoadBalancer, subnetId string, securityGroupId string, publicIp string,
+	serverCertificates map[int32]string, tags []osc.ResourceTag) (*osc.LoadBalancer, error) {
 	loadBalancerType := spec.LoadBalancerType
-	backendPort := spec.Listener.BackendPort
-	loadBalancerPort := spec.Listener.LoadBalancerPort
-	backendProtocol := spec.Listener.BackendProtocol
-	loadBalancerProtocol := spec.Listener.LoadBalancerProtocol
-
-	first_listener := osc.ListenerForCreation{
-		BackendPort:          int(backendPort),
-		BackendProtocol:      &backendProtocol,
-		LoadBalancerPort:     int(loadBalancerPort),
-		LoadBalancerProtocol: loadBalancerProtocol,
-	}
-
 	req := osc.CreateLoadBalancerRequest{
 		LoadBalancerName: spec.LoadBalancerName,
 		LoadBalancerType: &loadBalancerType,
-		Listeners:        []osc.ListenerForCreation{first_listener},
+		Listeners:        listenersForCreation(spec.GetListeners(), serverCertificates),
 		SecurityGroups:   &[]string{securityGroupId},
 		Subnets:          &[]string{subnetId},
+		Tags:             &tags,
+	}
+	if publicIp != "" {
+		req.PublicIp = &publicIp
 	}
 
 	resp, err := s.tenant.Client().CreateLoadBalancer(ctx, req)
@@ -71,6 +71,36 @@ func (mr *MockServicerMockRecorder) ConfigureHealthCheck(ctx, spec any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureHealthCheck", reflect.TypeOf((*MockServicer)(nil).ConfigureHealthCheck), ctx, spec)
 }
 
+// CreateClientGateway mocks base method.
+func (m *MockServicer) CreateClientGateway(ctx context.Context, publicIP string, bgpAsn int, name, clusterID string) (*osc.ClientGateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClientGateway", ctx, publicIP, bgpAsn, name, clusterID)
+	ret0, _ := ret[0].(*osc.ClientGateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClientGateway indicates an expected call of CreateClientGateway.
+func (mr *MockServicerMockRecorder) CreateClientGateway(ctx, publicIP, bgpAsn, name, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClientGateway", reflect.TypeOf((*MockServicer)(nil).CreateClientGateway), ctx, publicIP, bgpAsn, name, clusterID)
+}
+
+// CreateDhcpOptions mocks base method.
+func (m *MockServicer) CreateDhcpOptions(ctx context.Context, spec v1beta2.OscDhcpOptions, clusterID string) (*osc.DhcpOptionsSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDhcpOptions", ctx, spec, clusterID)
+	ret0, _ := ret[0].(*osc.DhcpOptionsSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDhcpOptions indicates an expected call of CreateDhcpOptions.
+func (mr *MockServicerMockRecorder) CreateDhcpOptions(ctx, spec, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDhcpOptions", reflect.TypeOf((*MockServicer)(nil).CreateDhcpOptions), ctx, spec, clusterID)
+}
+
 // CreateInternetService mocks base method.
 func (m *MockServicer) CreateInternetService(ctx context.Context, internetServiceName, clusterID string) (*osc.InternetService, error) {
 	m.ctrl.T.Helper()
@@ -87,18 +117,33 @@ func (mr *MockServicerMockRecorder) CreateInternetService(ctx, internetServiceNa
 }
 
 // CreateLoadBalancer mocks base method.
-func (m *MockServicer) CreateLoadBalancer(ctx context.Context, spec *v1beta2.OscLoadBalancer, subnetId, securityGroupId string) (*osc.LoadBalancer, error) {
+func (m *MockServicer) CreateLoadBalancer(ctx context.Context, spec *v1beta2.OscLoadBalancer, subnetId, securityGroupId, publicIp string, serverCertificates map[int32]string, tags []osc.ResourceTag) (*osc.LoadBalancer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLoadBalancer", ctx, spec, subnetId, securityGroupId)
+	ret := m.ctrl.Call(m, "CreateLoadBalancer", ctx, spec, subnetId, securityGroupId, publicIp, serverCertificates, tags)
 	ret0, _ := ret[0].(*osc.LoadBalancer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateLoadBalancer indicates an expected call of CreateLoadBalancer.
-func (mr *MockServicerMockRecorder) CreateLoadBalancer(ctx, spec, subnetId, securityGroupId any) *gomock.Call {
+func (mr *MockServicerMockRecorder) CreateLoadBalancer(ctx, spec, subnetId, securityGroupId, publicIp, serverCertificates, tags any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadBalancer", reflect.TypeOf((*MockServicer)(nil).CreateLoadBalancer), ctx, spec, subnetId, securityGroupId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadBalancer", reflect.TypeOf((*MockServicer)(nil).CreateLoadBalancer), ctx, spec, subnetId, securityGroupId, publicIp, serverCertificates, tags)
+}
+
+// CreateLoadBalancerListeners mocks base method.
+func (m *MockServicer) CreateLoadBalancerListeners(ctx context.Context, loadBalancerName string, listeners []v1beta2.OscLoadBalancerListener, serverCertificates map[int32]string) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoadBalancerListeners", ctx, loadBalancerName, listeners, serverCertificates)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoadBalancerListeners indicates an expected call of CreateLoadBalancerListeners.
+func (mr *MockServicerMockRecorder) CreateLoadBalancerListeners(ctx, loadBalancerName, listeners, serverCertificates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadBalancerListeners", reflect.TypeOf((*MockServicer)(nil).CreateLoadBalancerListeners), ctx, loadBalancerName, listeners, serverCertificates)
 }
 
 // CreateLoadBalancerTag mocks base method.
@@ -175,6 +220,21 @@ func (mr *MockServicerMockRecorder) CreateNetPeering(ctx, netID, mgmtNetID, mgmt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetPeering", reflect.TypeOf((*MockServicer)(nil).CreateNetPeering), ctx, netID, mgmtNetID, mgmtAccountID, clusterID)
 }
 
+// CreatePeering mocks base method.
+func (m *MockServicer) CreatePeering(ctx context.Context, netID, peerNetID, peerAccountID, clusterID string) (*osc.NetPeering, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePeering", ctx, netID, peerNetID, peerAccountID, clusterID)
+	ret0, _ := ret[0].(*osc.NetPeering)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePeering indicates an expected call of CreatePeering.
+func (mr *MockServicerMockRecorder) CreatePeering(ctx, netID, peerNetID, peerAccountID, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePeering", reflect.TypeOf((*MockServicer)(nil).CreatePeering), ctx, netID, peerNetID, peerAccountID, clusterID)
+}
+
 // CreatePublicIp mocks base method.
 func (m *MockServicer) CreatePublicIp(ctx context.Context, publicIpName, clusterID string) (*osc.PublicIp, error) {
 	m.ctrl.T.Helper()
@@ -220,6 +280,21 @@ func (mr *MockServicerMockRecorder) CreateRouteTable(ctx, netId, clusterID, rout
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouteTable", reflect.TypeOf((*MockServicer)(nil).CreateRouteTable), ctx, netId, clusterID, routeTableName)
 }
 
+// CreateServerCertificate mocks base method.
+func (m *MockServicer) CreateServerCertificate(ctx context.Context, name, path, body, chain, privateKey string) (*osc.ServerCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServerCertificate", ctx, name, path, body, chain, privateKey)
+	ret0, _ := ret[0].(*osc.ServerCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServerCertificate indicates an expected call of CreateServerCertificate.
+func (mr *MockServicerMockRecorder) CreateServerCertificate(ctx, name, path, body, chain, privateKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServerCertificate", reflect.TypeOf((*MockServicer)(nil).CreateServerCertificate), ctx, name, path, body, chain, privateKey)
+}
+
 // CreateSubnet mocks base method.
 func (m *MockServicer) CreateSubnet(ctx context.Context, spec v1beta2.OscSubnet, netId, clusterID, subnetName string) (*osc.Subnet, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +310,78 @@ func (mr *MockServicerMockRecorder) CreateSubnet(ctx, spec, netId, clusterID, su
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubnet", reflect.TypeOf((*MockServicer)(nil).CreateSubnet), ctx, spec, netId, clusterID, subnetName)
 }
 
+// CreateVirtualGateway mocks base method.
+func (m *MockServicer) CreateVirtualGateway(ctx context.Context, clusterID string) (*osc.VirtualGateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVirtualGateway", ctx, clusterID)
+	ret0, _ := ret[0].(*osc.VirtualGateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVirtualGateway indicates an expected call of CreateVirtualGateway.
+func (mr *MockServicerMockRecorder) CreateVirtualGateway(ctx, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVirtualGateway", reflect.TypeOf((*MockServicer)(nil).CreateVirtualGateway), ctx, clusterID)
+}
+
+// CreateVpnConnection mocks base method.
+func (m *MockServicer) CreateVpnConnection(ctx context.Context, clientGatewayID, virtualGatewayID, name, clusterID string) (*osc.VpnConnection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVpnConnection", ctx, clientGatewayID, virtualGatewayID, name, clusterID)
+	ret0, _ := ret[0].(*osc.VpnConnection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVpnConnection indicates an expected call of CreateVpnConnection.
+func (mr *MockServicerMockRecorder) CreateVpnConnection(ctx, clientGatewayID, virtualGatewayID, name, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVpnConnection", reflect.TypeOf((*MockServicer)(nil).CreateVpnConnection), ctx, clientGatewayID, virtualGatewayID, name, clusterID)
+}
+
+// CreateVpnConnectionRoute mocks base method.
+func (m *MockServicer) CreateVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVpnConnectionRoute", ctx, vpnConnectionID, destinationIpRange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVpnConnectionRoute indicates an expected call of CreateVpnConnectionRoute.
+func (mr *MockServicerMockRecorder) CreateVpnConnectionRoute(ctx, vpnConnectionID, destinationIpRange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVpnConnectionRoute", reflect.TypeOf((*MockServicer)(nil).CreateVpnConnectionRoute), ctx, vpnConnectionID, destinationIpRange)
+}
+
+// DeleteClientGateway mocks base method.
+func (m *MockServicer) DeleteClientGateway(ctx context.Context, clientGatewayID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientGateway", ctx, clientGatewayID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClientGateway indicates an expected call of DeleteClientGateway.
+func (mr *MockServicerMockRecorder) DeleteClientGateway(ctx, clientGatewayID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientGateway", reflect.TypeOf((*MockServicer)(nil).DeleteClientGateway), ctx, clientGatewayID)
+}
+
+// DeleteDhcpOptions mocks base method.
+func (m *MockServicer) DeleteDhcpOptions(ctx context.Context, dhcpOptionsSetId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDhcpOptions", ctx, dhcpOptionsSetId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDhcpOptions indicates an expected call of DeleteDhcpOptions.
+func (mr *MockServicerMockRecorder) DeleteDhcpOptions(ctx, dhcpOptionsSetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDhcpOptions", reflect.TypeOf((*MockServicer)(nil).DeleteDhcpOptions), ctx, dhcpOptionsSetId)
+}
+
 // DeleteInternetService mocks base method.
 func (m *MockServicer) DeleteInternetService(ctx context.Context, internetServiceId string) error {
 	m.ctrl.T.Helper()
@@ -263,6 +410,21 @@ func (mr *MockServicerMockRecorder) DeleteLoadBalancer(ctx, spec any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockServicer)(nil).DeleteLoadBalancer), ctx, spec)
 }
 
+// DeleteLoadBalancerListeners mocks base method.
+func (m *MockServicer) DeleteLoadBalancerListeners(ctx context.Context, loadBalancerName string, loadBalancerPorts []int) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoadBalancerListeners", ctx, loadBalancerName, loadBalancerPorts)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLoadBalancerListeners indicates an expected call of DeleteLoadBalancerListeners.
+func (mr *MockServicerMockRecorder) DeleteLoadBalancerListeners(ctx, loadBalancerName, loadBalancerPorts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancerListeners", reflect.TypeOf((*MockServicer)(nil).DeleteLoadBalancerListeners), ctx, loadBalancerName, loadBalancerPorts)
+}
+
 // DeleteLoadBalancerTag mocks base method.
 func (m *MockServicer) DeleteLoadBalancerTag(ctx context.Context, spec *v1beta2.OscLoadBalancer, loadBalancerTag osc.ResourceLoadBalancerTag) error {
 	m.ctrl.T.Helper()
@@ -375,6 +537,20 @@ func (mr *MockServicerMockRecorder) DeleteRouteTable(ctx, routeTableId any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTable", reflect.TypeOf((*MockServicer)(nil).DeleteRouteTable), ctx, routeTableId)
 }
 
+// DeleteServerCertificate mocks base method.
+func (m *MockServicer) DeleteServerCertificate(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServerCertificate", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServerCertificate indicates an expected call of DeleteServerCertificate.
+func (mr *MockServicerMockRecorder) DeleteServerCertificate(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServerCertificate", reflect.TypeOf((*MockServicer)(nil).DeleteServerCertificate), ctx, name)
+}
+
 // DeleteSubnet mocks base method.
 func (m *MockServicer) DeleteSubnet(ctx context.Context, subnetId string) error {
 	m.ctrl.T.Helper()
@@ -389,6 +565,93 @@ func (mr *MockServicerMockRecorder) DeleteSubnet(ctx, subnetId any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubnet", reflect.TypeOf((*MockServicer)(nil).DeleteSubnet), ctx, subnetId)
 }
 
+// DeleteVirtualGateway mocks base method.
+func (m *MockServicer) DeleteVirtualGateway(ctx context.Context, virtualGatewayID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVirtualGateway", ctx, virtualGatewayID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVirtualGateway indicates an expected call of DeleteVirtualGateway.
+func (mr *MockServicerMockRecorder) DeleteVirtualGateway(ctx, virtualGatewayID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualGateway", reflect.TypeOf((*MockServicer)(nil).DeleteVirtualGateway), ctx, virtualGatewayID)
+}
+
+// DeleteVpnConnection mocks base method.
+func (m *MockServicer) DeleteVpnConnection(ctx context.Context, vpnConnectionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVpnConnection", ctx, vpnConnectionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVpnConnection indicates an expected call of DeleteVpnConnection.
+func (mr *MockServicerMockRecorder) DeleteVpnConnection(ctx, vpnConnectionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpnConnection", reflect.TypeOf((*MockServicer)(nil).DeleteVpnConnection), ctx, vpnConnectionID)
+}
+
+// DeleteVpnConnectionRoute mocks base method.
+func (m *MockServicer) DeleteVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVpnConnectionRoute", ctx, vpnConnectionID, destinationIpRange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVpnConnectionRoute indicates an expected call of DeleteVpnConnectionRoute.
+func (mr *MockServicerMockRecorder) DeleteVpnConnectionRoute(ctx, vpnConnectionID, destinationIpRange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpnConnectionRoute", reflect.TypeOf((*MockServicer)(nil).DeleteVpnConnectionRoute), ctx, vpnConnectionID, destinationIpRange)
+}
+
+// GetClientGateway mocks base method.
+func (m *MockServicer) GetClientGateway(ctx context.Context, clientGatewayID string) (*osc.ClientGateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientGateway", ctx, clientGatewayID)
+	ret0, _ := ret[0].(*osc.ClientGateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientGateway indicates an expected call of GetClientGateway.
+func (mr *MockServicerMockRecorder) GetClientGateway(ctx, clientGatewayID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientGateway", reflect.TypeOf((*MockServicer)(nil).GetClientGateway), ctx, clientGatewayID)
+}
+
+// GetClientGatewayFor mocks base method.
+func (m *MockServicer) GetClientGatewayFor(ctx context.Context, publicIP string, bgpAsn int, clusterID string) (*osc.ClientGateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientGatewayFor", ctx, publicIP, bgpAsn, clusterID)
+	ret0, _ := ret[0].(*osc.ClientGateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientGatewayFor indicates an expected call of GetClientGatewayFor.
+func (mr *MockServicerMockRecorder) GetClientGatewayFor(ctx, publicIP, bgpAsn, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientGatewayFor", reflect.TypeOf((*MockServicer)(nil).GetClientGatewayFor), ctx, publicIP, bgpAsn, clusterID)
+}
+
+// GetDhcpOptions mocks base method.
+func (m *MockServicer) GetDhcpOptions(ctx context.Context, dhcpOptionsSetId string) (*osc.DhcpOptionsSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDhcpOptions", ctx, dhcpOptionsSetId)
+	ret0, _ := ret[0].(*osc.DhcpOptionsSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDhcpOptions indicates an expected call of GetDhcpOptions.
+func (mr *MockServicerMockRecorder) GetDhcpOptions(ctx, dhcpOptionsSetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDhcpOptions", reflect.TypeOf((*MockServicer)(nil).GetDhcpOptions), ctx, dhcpOptionsSetId)
+}
+
 // GetInternetService mocks base method.
 func (m *MockServicer) GetInternetService(ctx context.Context, internetServiceId string) (*osc.InternetService, error) {
 	m.ctrl.T.Helper()
@@ -644,6 +907,80 @@ func (mr *MockServicerMockRecorder) GetSubnetFromNet(ctx, netId, ipRange any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetFromNet", reflect.TypeOf((*MockServicer)(nil).GetSubnetFromNet), ctx, netId, ipRange)
 }
 
+// GetSubnetsFromNet mocks base method.
+func (m *MockServicer) GetSubnetsFromNet(ctx context.Context, netId string) ([]osc.Subnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetsFromNet", ctx, netId)
+	ret0, _ := ret[0].([]osc.Subnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetsFromNet indicates an expected call of GetSubnetsFromNet.
+func (mr *MockServicerMockRecorder) GetSubnetsFromNet(ctx, netId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetsFromNet", reflect.TypeOf((*MockServicer)(nil).GetSubnetsFromNet), ctx, netId)
+}
+
+// GetVirtualGateway mocks base method.
+func (m *MockServicer) GetVirtualGateway(ctx context.Context, virtualGatewayID string) (*osc.VirtualGateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVirtualGateway", ctx, virtualGatewayID)
+	ret0, _ := ret[0].(*osc.VirtualGateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVirtualGateway indicates an expected call of GetVirtualGateway.
+func (mr *MockServicerMockRecorder) GetVirtualGateway(ctx, virtualGatewayID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVirtualGateway", reflect.TypeOf((*MockServicer)(nil).GetVirtualGateway), ctx, virtualGatewayID)
+}
+
+// GetVpnConnection mocks base method.
+func (m *MockServicer) GetVpnConnection(ctx context.Context, vpnConnectionID string) (*osc.VpnConnection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVpnConnection", ctx, vpnConnectionID)
+	ret0, _ := ret[0].(*osc.VpnConnection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVpnConnection indicates an expected call of GetVpnConnection.
+func (mr *MockServicerMockRecorder) GetVpnConnection(ctx, vpnConnectionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVpnConnection", reflect.TypeOf((*MockServicer)(nil).GetVpnConnection), ctx, vpnConnectionID)
+}
+
+// GetVpnConnectionFor mocks base method.
+func (m *MockServicer) GetVpnConnectionFor(ctx context.Context, clientGatewayID, virtualGatewayID string) (*osc.VpnConnection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVpnConnectionFor", ctx, clientGatewayID, virtualGatewayID)
+	ret0, _ := ret[0].(*osc.VpnConnection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVpnConnectionFor indicates an expected call of GetVpnConnectionFor.
+func (mr *MockServicerMockRecorder) GetVpnConnectionFor(ctx, clientGatewayID, virtualGatewayID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVpnConnectionFor", reflect.TypeOf((*MockServicer)(nil).GetVpnConnectionFor), ctx, clientGatewayID, virtualGatewayID)
+}
+
+// LinkDhcpOptions mocks base method.
+func (m *MockServicer) LinkDhcpOptions(ctx context.Context, dhcpOptionsSetId, netId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkDhcpOptions", ctx, dhcpOptionsSetId, netId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkDhcpOptions indicates an expected call of LinkDhcpOptions.
+func (mr *MockServicerMockRecorder) LinkDhcpOptions(ctx, dhcpOptionsSetId, netId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkDhcpOptions", reflect.TypeOf((*MockServicer)(nil).LinkDhcpOptions), ctx, dhcpOptionsSetId, netId)
+}
+
 // LinkInternetService mocks base method.
 func (m *MockServicer) LinkInternetService(ctx context.Context, internetServiceId, netId string) error {
 	m.ctrl.T.Helper()
@@ -687,6 +1024,20 @@ func (mr *MockServicerMockRecorder) LinkRouteTable(ctx, routeTableId, subnetId a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkRouteTable", reflect.TypeOf((*MockServicer)(nil).LinkRouteTable), ctx, routeTableId, subnetId)
 }
 
+// LinkVirtualGateway mocks base method.
+func (m *MockServicer) LinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkVirtualGateway", ctx, virtualGatewayID, netID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkVirtualGateway indicates an expected call of LinkVirtualGateway.
+func (mr *MockServicerMockRecorder) LinkVirtualGateway(ctx, virtualGatewayID, netID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkVirtualGateway", reflect.TypeOf((*MockServicer)(nil).LinkVirtualGateway), ctx, virtualGatewayID, netID)
+}
+
 // ListNatServices mocks base method.
 func (m *MockServicer) ListNatServices(tx context.Context, netId string) ([]osc.NatService, error) {
 	m.ctrl.T.Helper()
@@ -747,6 +1098,66 @@ func (mr *MockServicerMockRecorder) ListPublicIpsFromPool(ctx, pool any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicIpsFromPool", reflect.TypeOf((*MockServicer)(nil).ListPublicIpsFromPool), ctx, pool)
 }
 
+// ListServerCertificates mocks base method.
+func (m *MockServicer) ListServerCertificates(ctx context.Context, path string) ([]osc.ServerCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServerCertificates", ctx, path)
+	ret0, _ := ret[0].([]osc.ServerCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServerCertificates indicates an expected call of ListServerCertificates.
+func (mr *MockServicerMockRecorder) ListServerCertificates(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServerCertificates", reflect.TypeOf((*MockServicer)(nil).ListServerCertificates), ctx, path)
+}
+
+// ListUnlinkedInternetServiceIds mocks base method.
+func (m *MockServicer) ListUnlinkedInternetServiceIds(ctx context.Context, internetServiceName, clusterID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnlinkedInternetServiceIds", ctx, internetServiceName, clusterID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnlinkedInternetServiceIds indicates an expected call of ListUnlinkedInternetServiceIds.
+func (mr *MockServicerMockRecorder) ListUnlinkedInternetServiceIds(ctx, internetServiceName, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnlinkedInternetServiceIds", reflect.TypeOf((*MockServicer)(nil).ListUnlinkedInternetServiceIds), ctx, internetServiceName, clusterID)
+}
+
+// ListUnlinkedPublicIpIds mocks base method.
+func (m *MockServicer) ListUnlinkedPublicIpIds(ctx context.Context, publicIpName, clusterID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnlinkedPublicIpIds", ctx, publicIpName, clusterID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnlinkedPublicIpIds indicates an expected call of ListUnlinkedPublicIpIds.
+func (mr *MockServicerMockRecorder) ListUnlinkedPublicIpIds(ctx, publicIpName, clusterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnlinkedPublicIpIds", reflect.TypeOf((*MockServicer)(nil).ListUnlinkedPublicIpIds), ctx, publicIpName, clusterID)
+}
+
+// ListUntaggedNetIds mocks base method.
+func (m *MockServicer) ListUntaggedNetIds(ctx context.Context, ipRange string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUntaggedNetIds", ctx, ipRange)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUntaggedNetIds indicates an expected call of ListUntaggedNetIds.
+func (mr *MockServicerMockRecorder) ListUntaggedNetIds(ctx, ipRange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUntaggedNetIds", reflect.TypeOf((*MockServicer)(nil).ListUntaggedNetIds), ctx, ipRange)
+}
+
 // UnlinkInternetService mocks base method.
 func (m *MockServicer) UnlinkInternetService(ctx context.Context, internetServiceId, netId string) error {
 	m.ctrl.T.Helper()
@@ -788,3 +1199,107 @@ func (mr *MockServicerMockRecorder) UnlinkRouteTable(ctx, linkRouteTableId any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkRouteTable", reflect.TypeOf((*MockServicer)(nil).UnlinkRouteTable), ctx, linkRouteTableId)
 }
+
+// UnlinkVirtualGateway mocks base method.
+func (m *MockServicer) UnlinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkVirtualGateway", ctx, virtualGatewayID, netID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkVirtualGateway indicates an expected call of UnlinkVirtualGateway.
+func (mr *MockServicerMockRecorder) UnlinkVirtualGateway(ctx, virtualGatewayID, netID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkVirtualGateway", reflect.TypeOf((*MockServicer)(nil).UnlinkVirtualGateway), ctx, virtualGatewayID, netID)
+}
+
+// UpdateLoadBalancerAccessLog mocks base method.
+func (m *MockServicer) UpdateLoadBalancerAccessLog(ctx context.Context, loadBalancerName string, accessLog v1beta2.OscLoadBalancerAccessLog) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerAccessLog", ctx, loadBalancerName, accessLog)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLoadBalancerAccessLog indicates an expected call of UpdateLoadBalancerAccessLog.
+func (mr *MockServicerMockRecorder) UpdateLoadBalancerAccessLog(ctx, loadBalancerName, accessLog any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerAccessLog", reflect.TypeOf((*MockServicer)(nil).UpdateLoadBalancerAccessLog), ctx, loadBalancerName, accessLog)
+}
+
+// UpdateLoadBalancerPublicIp mocks base method.
+func (m *MockServicer) UpdateLoadBalancerPublicIp(ctx context.Context, loadBalancerName, publicIp string) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerPublicIp", ctx, loadBalancerName, publicIp)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLoadBalancerPublicIp indicates an expected call of UpdateLoadBalancerPublicIp.
+func (mr *MockServicerMockRecorder) UpdateLoadBalancerPublicIp(ctx, loadBalancerName, publicIp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerPublicIp", reflect.TypeOf((*MockServicer)(nil).UpdateLoadBalancerPublicIp), ctx, loadBalancerName, publicIp)
+}
+
+// UpdateLoadBalancerSecurityGroups mocks base method.
+func (m *MockServicer) UpdateLoadBalancerSecurityGroups(ctx context.Context, loadBalancerName string, securityGroupIds []string) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerSecurityGroups", ctx, loadBalancerName, securityGroupIds)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLoadBalancerSecurityGroups indicates an expected call of UpdateLoadBalancerSecurityGroups.
+func (mr *MockServicerMockRecorder) UpdateLoadBalancerSecurityGroups(ctx, loadBalancerName, securityGroupIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerSecurityGroups", reflect.TypeOf((*MockServicer)(nil).UpdateLoadBalancerSecurityGroups), ctx, loadBalancerName, securityGroupIds)
+}
+
+// UpdateLoadBalancerServerCertificate mocks base method.
+func (m *MockServicer) UpdateLoadBalancerServerCertificate(ctx context.Context, loadBalancerName string, loadBalancerPort int32, serverCertificateId string) (*osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoadBalancerServerCertificate", ctx, loadBalancerName, loadBalancerPort, serverCertificateId)
+	ret0, _ := ret[0].(*osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLoadBalancerServerCertificate indicates an expected call of UpdateLoadBalancerServerCertificate.
+func (mr *MockServicerMockRecorder) UpdateLoadBalancerServerCertificate(ctx, loadBalancerName, loadBalancerPort, serverCertificateId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoadBalancerServerCertificate", reflect.TypeOf((*MockServicer)(nil).UpdateLoadBalancerServerCertificate), ctx, loadBalancerName, loadBalancerPort, serverCertificateId)
+}
+
+// UpdateNatRoute mocks base method.
+func (m *MockServicer) UpdateNatRoute(ctx context.Context, destinationIpRange, routeTableId, natServiceId string) (*osc.RouteTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNatRoute", ctx, destinationIpRange, routeTableId, natServiceId)
+	ret0, _ := ret[0].(*osc.RouteTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNatRoute indicates an expected call of UpdateNatRoute.
+func (mr *MockServicerMockRecorder) UpdateNatRoute(ctx, destinationIpRange, routeTableId, natServiceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNatRoute", reflect.TypeOf((*MockServicer)(nil).UpdateNatRoute), ctx, destinationIpRange, routeTableId, natServiceId)
+}
+
+// UpdatePeeringRoute mocks base method.
+func (m *MockServicer) UpdatePeeringRoute(ctx context.Context, destinationIpRange, routeTableId, netPeeringId string) (*osc.RouteTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePeeringRoute", ctx, destinationIpRange, routeTableId, netPeeringId)
+	ret0, _ := ret[0].(*osc.RouteTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePeeringRoute indicates an expected call of UpdatePeeringRoute.
+func (mr *MockServicerMockRecorder) UpdatePeeringRoute(ctx, destinationIpRange, routeTableId, netPeeringId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePeeringRoute", reflect.TypeOf((*MockServicer)(nil).UpdatePeeringRoute), ctx, destinationIpRange, routeTableId, netPeeringId)
+}
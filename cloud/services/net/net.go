@@ -16,9 +16,11 @@ type NetInterface interface {
 	CreateNet(ctx context.Context, spec infrastructurev1beta2.OscNet, clusterID, netName string) (*osc.Net, error)
 	DeleteNet(ctx context.Context, netId string) error
 	GetNet(ctx context.Context, netId string) (*osc.Net, error)
+	ListUntaggedNetIds(ctx context.Context, ipRange string) ([]string, error)
 }
 
 // CreateNet create the net from spec (in order to retrieve ip range)
+// If the net cannot be tagged, the created net is returned along with the error.
 func (s *Service) CreateNet(ctx context.Context, spec infrastructurev1beta2.OscNet, clusterID, netName string) (*osc.Net, error) {
 	req := osc.CreateNetRequest{
 		IpRange: spec.IpRange,
@@ -44,7 +46,7 @@ func (s *Service) CreateNet(ctx context.Context, spec infrastructurev1beta2.OscN
 
 	err = s.tags.AddTag(ctx, netTagRequest, resourceIds)
 	if err != nil {
-		return nil, err
+		return resp.Net, err
 	}
 	return resp.Net, nil
 }
@@ -74,3 +76,24 @@ func (s *Service) GetNet(ctx context.Context, netId string) (*osc.Net, error) {
 		return &(*resp.Nets)[0], nil
 	}
 }
+
+// ListUntaggedNetIds lists the ids of the nets having an ip range and no tags.
+func (s *Service) ListUntaggedNetIds(ctx context.Context, ipRange string) ([]string, error) {
+	req := osc.ReadNetsRequest{
+		Filters: &osc.FiltersNet{
+			IpRanges: &[]string{ipRange},
+		},
+	}
+
+	resp, err := s.tenant.Client().ReadNets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, n := range *resp.Nets {
+		if len(n.Tags) == 0 {
+			ids = append(ids, n.NetId)
+		}
+	}
+	return ids, nil
+}
@@ -8,11 +8,13 @@ package net
 import (
 	"context"
 
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 )
 
 type NetPeeringInterface interface {
 	CreateNetPeering(ctx context.Context, netID, mgmtNetID, mgmtAccountID, clusterID string) (*osc.NetPeering, error)
+	CreatePeering(ctx context.Context, netID, peerNetID, peerAccountID, clusterID string) (*osc.NetPeering, error)
 	AcceptNetPeering(ctx context.Context, netPeeringID string) error
 	DeleteNetPeering(ctx context.Context, netPeeringID string) error
 	GetNetPeering(ctx context.Context, netPeeringID string) (*osc.NetPeering, error)
@@ -20,12 +22,27 @@ type NetPeeringInterface interface {
 	ListNetPeerings(ctx context.Context, netId string) ([]osc.NetPeering, error)
 }
 
-// CreateNetPeering creates a net peering
+// CreateNetPeering creates a net peering to the management net
 func (s *Service) CreateNetPeering(ctx context.Context, netID, mgmtNetID, mgmtAccountID, clusterID string) (*osc.NetPeering, error) {
+	return s.createNetPeering(ctx, netID, mgmtNetID, mgmtAccountID, osc.ResourceTag{
+		Key:   tag.ClusterKeyPrefix + clusterID,
+		Value: tag.OwnedValue,
+	})
+}
+
+// CreatePeering creates a net peering to a peer net
+func (s *Service) CreatePeering(ctx context.Context, netID, peerNetID, peerAccountID, clusterID string) (*osc.NetPeering, error) {
+	return s.createNetPeering(ctx, netID, peerNetID, peerAccountID, osc.ResourceTag{
+		Key:   tag.ClusterKeyPrefix + clusterID,
+		Value: tag.PeeringValue,
+	})
+}
+
+func (s *Service) createNetPeering(ctx context.Context, netID, accepterNetID, accepterAccountID string, clusterTag osc.ResourceTag) (*osc.NetPeering, error) {
 	req := osc.CreateNetPeeringRequest{
 		SourceNetId:     netID,
-		AccepterNetId:   mgmtNetID,
-		AccepterOwnerId: &mgmtAccountID,
+		AccepterNetId:   accepterNetID,
+		AccepterOwnerId: &accepterAccountID,
 	}
 
 	resp, err := s.tenant.Client().CreateNetPeering(ctx, req)
@@ -33,10 +50,6 @@ func (s *Service) CreateNetPeering(ctx context.Context, netID, mgmtNetID, mgmtAc
 		return nil, err
 	}
 	resourceIds := []string{resp.NetPeering.NetPeeringId}
-	clusterTag := osc.ResourceTag{
-		Key:   "OscK8sClusterID/" + clusterID,
-		Value: "owned",
-	}
 	netPeeringTagRequest := osc.CreateTagsRequest{
 		ResourceIds: resourceIds,
 		Tags:        []osc.ResourceTag{clusterTag},
@@ -81,13 +94,14 @@ func (s *Service) GetNetPeering(ctx context.Context, netPeeringID string) (*osc.
 	}
 }
 
-// GetNetPeeringFromNet retrieves a net peering from its info
+// GetNetPeeringFromNet retrieves an active or pending net peering from its info
 func (s *Service) GetNetPeeringFromNet(ctx context.Context, netID, mgmtNetID, mgmtAccountID string) (*osc.NetPeering, error) {
 	req := osc.ReadNetPeeringsRequest{
 		Filters: &osc.FiltersNetPeering{
 			SourceNetNetIds:       &[]string{netID},
 			AccepterNetNetIds:     &[]string{mgmtNetID},
 			AccepterNetAccountIds: &[]string{mgmtAccountID},
+			StateNames:            &[]osc.NetPeeringStateName{osc.NetPeeringStateNameActive, osc.NetPeeringStateNamePendingAcceptance},
 		},
 	}
 
@@ -25,9 +25,11 @@ type PublicIpInterface interface {
 	GetPublicIp(ctx context.Context, publicIpId string) (*osc.PublicIp, error)
 	GetPublicIpByIp(ctx context.Context, publicIp string) (*osc.PublicIp, error)
 	ListPublicIpsFromPool(ctx context.Context, pool string) ([]osc.PublicIp, error)
+	ListUnlinkedPublicIpIds(ctx context.Context, publicIpName, clusterID string) ([]string, error)
 }
 
 // CreatePublicIp retrieve a publicip associated with you account
+// If the publicip cannot be tagged, the created publicip is returned along with the error.
 func (s *Service) CreatePublicIp(ctx context.Context, publicIpName string, clusterID string) (*osc.PublicIp, error) {
 	resp, err := s.tenant.Client().CreatePublicIp(ctx, osc.CreatePublicIpRequest{})
 	if err != nil {
@@ -49,7 +51,7 @@ func (s *Service) CreatePublicIp(ctx context.Context, publicIpName string, clust
 
 	err = s.tags.AddTag(ctx, req, resourceIds)
 	if err != nil {
-		return nil, err
+		return resp.PublicIp, err
 	}
 	return resp.PublicIp, nil
 }
@@ -117,6 +119,22 @@ func (s *Service) ListPublicIpsFromPool(ctx context.Context, pool string) ([]osc
 	return *resp.PublicIps, nil
 }
 
+// ListUnlinkedPublicIpIds lists the ids of the public ips not linked to a vm or a nic, having no tags
+// or having the tags set by CreatePublicIp.
+func (s *Service) ListUnlinkedPublicIpIds(ctx context.Context, publicIpName, clusterID string) ([]string, error) {
+	resp, err := s.tenant.Client().ReadPublicIps(ctx, osc.ReadPublicIpsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, ip := range *resp.PublicIps {
+		if ip.LinkPublicIpId == nil && isUntaggedOrCreatedAs(ip.Tags, publicIpName, clusterID) {
+			ids = append(ids, ip.PublicIpId)
+		}
+	}
+	return ids, nil
+}
+
 // ValidatePublicIpIds validate the list of id by checking each public ip resource and return only  public ip resource id that currently exist.
 func (s *Service) ValidatePublicIpIds(ctx context.Context, publicIpIds []string) ([]string, error) {
 	req := osc.ReadPublicIpsRequest{
@@ -20,6 +20,8 @@ type RouteTableInterface interface {
 	CreateRoute(ctx context.Context, destinationIpRange, routeTableId, resourceId, targetType string) (*osc.RouteTable, error)
 	DeleteRouteTable(ctx context.Context, routeTableId string) error
 	DeleteRoute(ctx context.Context, destinationIpRange, routeTableId string) error
+	UpdateNatRoute(ctx context.Context, destinationIpRange, routeTableId, natServiceId string) (*osc.RouteTable, error)
+	UpdatePeeringRoute(ctx context.Context, destinationIpRange, routeTableId, netPeeringId string) (*osc.RouteTable, error)
 	GetRouteTable(ctx context.Context, routeTableId string) (*osc.RouteTable, error)
 	GetRouteTableFromRoute(ctx context.Context, routeTableId, resourceId, resourceType string) (*osc.RouteTable, error)
 	LinkRouteTable(ctx context.Context, routeTableId, subnetId string) (string, error)
@@ -28,6 +30,7 @@ type RouteTableInterface interface {
 }
 
 // CreateRouteTable create the routetable associated with the net
+// If the routetable cannot be tagged, the created routetable is returned along with the error.
 func (s *Service) CreateRouteTable(ctx context.Context, netId string, clusterID string, routeTableName string) (*osc.RouteTable, error) {
 	req := osc.CreateRouteTableRequest{
 		NetId: netId,
@@ -52,7 +55,7 @@ func (s *Service) CreateRouteTable(ctx context.Context, netId string, clusterID
 	}
 	err = s.tags.AddTag(ctx, routeTableTagRequest, resourceIds)
 	if err != nil {
-		return nil, err
+		return resp.RouteTable, err
 	}
 
 	return resp.RouteTable, nil
@@ -62,7 +65,7 @@ func (s *Service) CreateRouteTable(ctx context.Context, netId string, clusterID
 func (s *Service) CreateRoute(ctx context.Context, destinationIpRange, routeTableId, resourceId, resourceType string) (*osc.RouteTable, error) {
 	var routeRequest osc.CreateRouteRequest
 	switch resourceType {
-	case "gateway":
+	case "gateway", "virtualGateway":
 		routeRequest = osc.CreateRouteRequest{
 			DestinationIpRange: destinationIpRange,
 			RouteTableId:       routeTableId,
@@ -80,6 +83,18 @@ func (s *Service) CreateRoute(ctx context.Context, destinationIpRange, routeTabl
 			RouteTableId:       routeTableId,
 			NetPeeringId:       &resourceId,
 		}
+	case "vm":
+		routeRequest = osc.CreateRouteRequest{
+			DestinationIpRange: destinationIpRange,
+			RouteTableId:       routeTableId,
+			VmId:               &resourceId,
+		}
+	case "nic":
+		routeRequest = osc.CreateRouteRequest{
+			DestinationIpRange: destinationIpRange,
+			RouteTableId:       routeTableId,
+			NicId:              &resourceId,
+		}
 	default:
 		return nil, fmt.Errorf("invalid type %q", resourceType)
 	}
@@ -109,6 +124,36 @@ func (s *Service) DeleteRoute(ctx context.Context, destinationIpRange string, ro
 	return err
 }
 
+// UpdateNatRoute replaces the target of a route with a nat service
+func (s *Service) UpdateNatRoute(ctx context.Context, destinationIpRange, routeTableId, natServiceId string) (*osc.RouteTable, error) {
+	req := osc.UpdateRouteRequest{
+		DestinationIpRange: destinationIpRange,
+		RouteTableId:       routeTableId,
+		NatServiceId:       &natServiceId,
+	}
+
+	resp, err := s.tenant.Client().UpdateRoute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.RouteTable, nil
+}
+
+// UpdatePeeringRoute replaces the target of a route with a net peering
+func (s *Service) UpdatePeeringRoute(ctx context.Context, destinationIpRange, routeTableId, netPeeringId string) (*osc.RouteTable, error) {
+	req := osc.UpdateRouteRequest{
+		DestinationIpRange: destinationIpRange,
+		RouteTableId:       routeTableId,
+		NetPeeringId:       &netPeeringId,
+	}
+
+	resp, err := s.tenant.Client().UpdateRoute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.RouteTable, nil
+}
+
 // GetRouteTable retrieve routetable object from the route table id
 func (s *Service) GetRouteTable(ctx context.Context, routeTableId string) (*osc.RouteTable, error) {
 	req := osc.ReadRouteTablesRequest{
@@ -0,0 +1,57 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package net
+
+import (
+	"context"
+
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+type ServerCertificateInterface interface {
+	CreateServerCertificate(ctx context.Context, name, path, body, chain, privateKey string) (*osc.ServerCertificate, error)
+	ListServerCertificates(ctx context.Context, path string) ([]osc.ServerCertificate, error)
+	DeleteServerCertificate(ctx context.Context, name string) error
+}
+
+// CreateServerCertificate uploads a server certificate, with an optional chain
+func (s *Service) CreateServerCertificate(ctx context.Context, name, path, body, chain, privateKey string) (*osc.ServerCertificate, error) {
+	req := osc.CreateServerCertificateRequest{
+		Name:       name,
+		Path:       &path,
+		Body:       body,
+		PrivateKey: privateKey,
+	}
+	if chain != "" {
+		req.Chain = &chain
+	}
+	resp, err := s.tenant.Client().CreateServerCertificate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ServerCertificate, nil
+}
+
+// ListServerCertificates lists the server certificates having a path
+func (s *Service) ListServerCertificates(ctx context.Context, path string) ([]osc.ServerCertificate, error) {
+	req := osc.ReadServerCertificatesRequest{Filters: &osc.FiltersServerCertificate{
+		Paths: &[]string{path},
+	}}
+	resp, err := s.tenant.Client().ReadServerCertificates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.ServerCertificates, nil
+}
+
+// DeleteServerCertificate deletes a server certificate
+func (s *Service) DeleteServerCertificate(ctx context.Context, name string) error {
+	req := osc.DeleteServerCertificateRequest{
+		Name: name,
+	}
+	_, err := s.tenant.Client().DeleteServerCertificate(ctx, req)
+	return err
+}
@@ -8,10 +8,13 @@ package net
 import (
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 )
 
 //go:generate ../../../bin/mockgen -destination mock_net/net_mock.go -package mock_net -source ./service.go
 type Servicer interface {
+	DhcpOptionsInterface
 	InternetServiceInterface
 	LoadBalancerInterface
 	NatServiceInterface
@@ -20,7 +23,9 @@ type Servicer interface {
 	NetPeeringInterface
 	PublicIpInterface
 	RouteTableInterface
+	ServerCertificateInterface
 	SubnetInterface
+	VpnInterface
 }
 
 // Service is a collection of interfaces
@@ -38,3 +43,8 @@ func NewService(t tenant.Tenant, tags tag.Servicer) *Service {
 }
 
 var _ Servicer = (*Service)(nil)
+
+// isUntaggedOrCreatedAs returns true if a resource has no tags, or has the name and cluster tags set on creation.
+func isUntaggedOrCreatedAs(t []osc.ResourceTag, name, clusterID string) bool {
+	return len(t) == 0 || (tags.Has(t, tag.NameKey, name) && tags.Has(t, tags.ClusterIDKey(clusterID), tag.OwnedValue))
+}
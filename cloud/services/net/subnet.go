@@ -18,6 +18,7 @@ type SubnetInterface interface {
 	DeleteSubnet(ctx context.Context, subnetId string) error
 	GetSubnet(ctx context.Context, subnetId string) (*osc.Subnet, error)
 	GetSubnetFromNet(ctx context.Context, netId, ipRange string) (*osc.Subnet, error)
+	GetSubnetsFromNet(ctx context.Context, netId string) ([]osc.Subnet, error)
 }
 
 // CreateSubnet create the subnet associate to the net
@@ -100,3 +101,18 @@ func (s *Service) GetSubnetFromNet(ctx context.Context, netId, ipRange string) (
 		return &(*resp.Subnets)[0], nil
 	}
 }
+
+// GetSubnetsFromNet lists the subnets of a net.
+func (s *Service) GetSubnetsFromNet(ctx context.Context, netId string) ([]osc.Subnet, error) {
+	req := osc.ReadSubnetsRequest{
+		Filters: &osc.FiltersSubnet{
+			NetIds: &[]string{netId},
+		},
+	}
+
+	resp, err := s.tenant.Client().ReadSubnets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.Subnets, nil
+}
@@ -0,0 +1,238 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package net
+
+import (
+	"context"
+
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+// VpnConnectionType is the only connection type supported by virtual gateways, client gateways and VPN connections.
+const VpnConnectionType = "ipsec.1"
+
+type VpnInterface interface {
+	CreateVirtualGateway(ctx context.Context, clusterID string) (*osc.VirtualGateway, error)
+	GetVirtualGateway(ctx context.Context, virtualGatewayID string) (*osc.VirtualGateway, error)
+	LinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error
+	UnlinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error
+	DeleteVirtualGateway(ctx context.Context, virtualGatewayID string) error
+
+	CreateClientGateway(ctx context.Context, publicIP string, bgpAsn int, name, clusterID string) (*osc.ClientGateway, error)
+	GetClientGateway(ctx context.Context, clientGatewayID string) (*osc.ClientGateway, error)
+	GetClientGatewayFor(ctx context.Context, publicIP string, bgpAsn int, clusterID string) (*osc.ClientGateway, error)
+	DeleteClientGateway(ctx context.Context, clientGatewayID string) error
+
+	CreateVpnConnection(ctx context.Context, clientGatewayID, virtualGatewayID, name, clusterID string) (*osc.VpnConnection, error)
+	GetVpnConnection(ctx context.Context, vpnConnectionID string) (*osc.VpnConnection, error)
+	GetVpnConnectionFor(ctx context.Context, clientGatewayID, virtualGatewayID string) (*osc.VpnConnection, error)
+	DeleteVpnConnection(ctx context.Context, vpnConnectionID string) error
+	CreateVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error
+	DeleteVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error
+}
+
+// CreateVirtualGateway creates a virtual gateway owned by the cluster.
+func (s *Service) CreateVirtualGateway(ctx context.Context, clusterID string) (*osc.VirtualGateway, error) {
+	req := osc.CreateVirtualGatewayRequest{ConnectionType: VpnConnectionType}
+	resp, err := s.tenant.Client().CreateVirtualGateway(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resourceIds := []string{resp.VirtualGateway.VirtualGatewayId}
+	err = s.tags.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: resourceIds,
+		Tags: []osc.ResourceTag{{
+			Key:   tags.ClusterIDKey(clusterID),
+			Value: "owned",
+		}},
+	}, resourceIds)
+	if err != nil {
+		return nil, err
+	}
+	return resp.VirtualGateway, nil
+}
+
+// GetVirtualGateway retrieves a virtual gateway.
+func (s *Service) GetVirtualGateway(ctx context.Context, virtualGatewayID string) (*osc.VirtualGateway, error) {
+	req := osc.ReadVirtualGatewaysRequest{
+		Filters: &osc.FiltersVirtualGateway{
+			VirtualGatewayIds: &[]string{virtualGatewayID},
+		},
+	}
+	resp, err := s.tenant.Client().ReadVirtualGateways(ctx, req)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(*resp.VirtualGateways) == 0:
+		return nil, nil
+	default:
+		return &(*resp.VirtualGateways)[0], nil
+	}
+}
+
+// LinkVirtualGateway links a virtual gateway to a net.
+func (s *Service) LinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error {
+	req := osc.LinkVirtualGatewayRequest{VirtualGatewayId: virtualGatewayID, NetId: netID}
+	_, err := s.tenant.Client().LinkVirtualGateway(ctx, req)
+	return err
+}
+
+// UnlinkVirtualGateway unlinks a virtual gateway from a net.
+func (s *Service) UnlinkVirtualGateway(ctx context.Context, virtualGatewayID, netID string) error {
+	req := osc.UnlinkVirtualGatewayRequest{VirtualGatewayId: virtualGatewayID, NetId: netID}
+	_, err := s.tenant.Client().UnlinkVirtualGateway(ctx, req)
+	return err
+}
+
+// DeleteVirtualGateway deletes a virtual gateway.
+func (s *Service) DeleteVirtualGateway(ctx context.Context, virtualGatewayID string) error {
+	req := osc.DeleteVirtualGatewayRequest{VirtualGatewayId: virtualGatewayID}
+	_, err := s.tenant.Client().DeleteVirtualGateway(ctx, req)
+	return err
+}
+
+// CreateClientGateway creates a client gateway owned by the cluster.
+func (s *Service) CreateClientGateway(ctx context.Context, publicIP string, bgpAsn int, name, clusterID string) (*osc.ClientGateway, error) {
+	req := osc.CreateClientGatewayRequest{
+		BgpAsn:         bgpAsn,
+		ConnectionType: VpnConnectionType,
+		PublicIp:       publicIP,
+	}
+	resp, err := s.tenant.Client().CreateClientGateway(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resourceIds := []string{resp.ClientGateway.ClientGatewayId}
+	err = s.tags.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: resourceIds,
+		Tags: []osc.ResourceTag{{
+			Key:   "Name",
+			Value: name,
+		}, {
+			Key:   tags.ClusterIDKey(clusterID),
+			Value: "owned",
+		}},
+	}, resourceIds)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ClientGateway, nil
+}
+
+// GetClientGateway retrieves a client gateway.
+func (s *Service) GetClientGateway(ctx context.Context, clientGatewayID string) (*osc.ClientGateway, error) {
+	return s.readClientGateway(ctx, osc.FiltersClientGateway{
+		ClientGatewayIds: &[]string{clientGatewayID},
+	})
+}
+
+// GetClientGatewayFor retrieves an available client gateway owned by the cluster, from its IP and ASN.
+func (s *Service) GetClientGatewayFor(ctx context.Context, publicIP string, bgpAsn int, clusterID string) (*osc.ClientGateway, error) {
+	return s.readClientGateway(ctx, osc.FiltersClientGateway{
+		PublicIps: &[]string{publicIP},
+		BgpAsns:   &[]int{bgpAsn},
+		States:    &[]osc.ClientGatewayState{osc.ClientGatewayStatePending, osc.ClientGatewayStateAvailable},
+		Tags:      &[]string{tags.ClusterIDKey(clusterID) + "=owned"},
+	})
+}
+
+func (s *Service) readClientGateway(ctx context.Context, filters osc.FiltersClientGateway) (*osc.ClientGateway, error) {
+	resp, err := s.tenant.Client().ReadClientGateways(ctx, osc.ReadClientGatewaysRequest{Filters: &filters})
+	switch {
+	case err != nil:
+		return nil, err
+	case len(*resp.ClientGateways) == 0:
+		return nil, nil
+	default:
+		return &(*resp.ClientGateways)[0], nil
+	}
+}
+
+// DeleteClientGateway deletes a client gateway.
+func (s *Service) DeleteClientGateway(ctx context.Context, clientGatewayID string) error {
+	req := osc.DeleteClientGatewayRequest{ClientGatewayId: clientGatewayID}
+	_, err := s.tenant.Client().DeleteClientGateway(ctx, req)
+	return err
+}
+
+// CreateVpnConnection creates a VPN connection using static routes between a client gateway and a virtual gateway.
+func (s *Service) CreateVpnConnection(ctx context.Context, clientGatewayID, virtualGatewayID, name, clusterID string) (*osc.VpnConnection, error) {
+	req := osc.CreateVpnConnectionRequest{
+		ClientGatewayId:  clientGatewayID,
+		VirtualGatewayId: virtualGatewayID,
+		ConnectionType:   VpnConnectionType,
+		StaticRoutesOnly: new(true),
+	}
+	resp, err := s.tenant.Client().CreateVpnConnection(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resourceIds := []string{resp.VpnConnection.VpnConnectionId}
+	err = s.tags.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: resourceIds,
+		Tags: []osc.ResourceTag{{
+			Key:   "Name",
+			Value: name,
+		}, {
+			Key:   tags.ClusterIDKey(clusterID),
+			Value: "owned",
+		}},
+	}, resourceIds)
+	if err != nil {
+		return nil, err
+	}
+	return resp.VpnConnection, nil
+}
+
+// GetVpnConnection retrieves a VPN connection.
+func (s *Service) GetVpnConnection(ctx context.Context, vpnConnectionID string) (*osc.VpnConnection, error) {
+	return s.readVpnConnection(ctx, osc.FiltersVpnConnection{
+		VpnConnectionIds: &[]string{vpnConnectionID},
+	})
+}
+
+// GetVpnConnectionFor retrieves a live VPN connection between a client gateway and a virtual gateway.
+func (s *Service) GetVpnConnectionFor(ctx context.Context, clientGatewayID, virtualGatewayID string) (*osc.VpnConnection, error) {
+	return s.readVpnConnection(ctx, osc.FiltersVpnConnection{
+		ClientGatewayIds:  &[]string{clientGatewayID},
+		VirtualGatewayIds: &[]string{virtualGatewayID},
+		States:            &[]osc.VpnConnectionState{osc.VpnConnectionStatePending, osc.VpnConnectionStateAvailable},
+	})
+}
+
+func (s *Service) readVpnConnection(ctx context.Context, filters osc.FiltersVpnConnection) (*osc.VpnConnection, error) {
+	resp, err := s.tenant.Client().ReadVpnConnections(ctx, osc.ReadVpnConnectionsRequest{Filters: &filters})
+	switch {
+	case err != nil:
+		return nil, err
+	case len(*resp.VpnConnections) == 0:
+		return nil, nil
+	default:
+		return &(*resp.VpnConnections)[0], nil
+	}
+}
+
+// DeleteVpnConnection deletes a VPN connection.
+func (s *Service) DeleteVpnConnection(ctx context.Context, vpnConnectionID string) error {
+	req := osc.DeleteVpnConnectionRequest{VpnConnectionId: vpnConnectionID}
+	_, err := s.tenant.Client().DeleteVpnConnection(ctx, req)
+	return err
+}
+
+// CreateVpnConnectionRoute adds a static route to a VPN connection.
+func (s *Service) CreateVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error {
+	req := osc.CreateVpnConnectionRouteRequest{VpnConnectionId: vpnConnectionID, DestinationIpRange: destinationIpRange}
+	_, err := s.tenant.Client().CreateVpnConnectionRoute(ctx, req)
+	return err
+}
+
+// DeleteVpnConnectionRoute removes a static route from a VPN connection.
+func (s *Service) DeleteVpnConnectionRoute(ctx context.Context, vpnConnectionID, destinationIpRange string) error {
+	req := osc.DeleteVpnConnectionRouteRequest{VpnConnectionId: vpnConnectionID, DestinationIpRange: destinationIpRange}
+	_, err := s.tenant.Client().DeleteVpnConnectionRoute(ctx, req)
+	return err
+}
@@ -56,6 +56,35 @@ func (mr *MockServicerMockRecorder) AddTag(ctx, req, resourceIds any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTag", reflect.TypeOf((*MockServicer)(nil).AddTag), ctx, req, resourceIds)
 }
 
+// DeleteTags mocks base method.
+func (m *MockServicer) DeleteTags(ctx context.Context, req osc.DeleteTagsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTags", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTags indicates an expected call of DeleteTags.
+func (mr *MockServicerMockRecorder) DeleteTags(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTags", reflect.TypeOf((*MockServicer)(nil).DeleteTags), ctx, req)
+}
+
+// ReadClusterTags mocks base method.
+func (m *MockServicer) ReadClusterTags(ctx context.Context, cluster string) ([]osc.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadClusterTags", ctx, cluster)
+	ret0, _ := ret[0].([]osc.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadClusterTags indicates an expected call of ReadClusterTags.
+func (mr *MockServicerMockRecorder) ReadClusterTags(ctx, cluster any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadClusterTags", reflect.TypeOf((*MockServicer)(nil).ReadClusterTags), ctx, cluster)
+}
+
 // ReadOwnedByTag mocks base method.
 func (m *MockServicer) ReadOwnedByTag(ctx context.Context, rsrcType tag.ResourceType, cluster string) (*osc.Tag, error) {
 	m.ctrl.T.Helper()
@@ -19,6 +19,8 @@ type ResourceType string
 const (
 	NetResourceType             ResourceType = "vpc"
 	NetPeeringResourceType      ResourceType = "vpc-peering-connection"
+	VirtualGatewayResourceType  ResourceType = "virtual-private-gateway"
+	DhcpOptionsResourceType     ResourceType = "dhcp-options"
 	SubnetResourceType          ResourceType = "subnet"
 	InternetServiceResourceType ResourceType = "internet-service"
 	NetAccessPointResourceType  ResourceType = "internet-service"
@@ -35,12 +37,17 @@ const (
 
 	ClusterKeyPrefix = "OscK8sClusterID/"
 	OwnedValue       = "owned"
+	SharedValue      = "shared"
+	// PeeringValue tags the peerings of a cluster, which are not owned, not to be mistaken with the management net peering.
+	PeeringValue = "peering"
 )
 
 type TagInterface interface {
 	ReadTag(ctx context.Context, rsrcType ResourceType, key, value string) (*osc.Tag, error)
 	ReadOwnedByTag(ctx context.Context, rsrcType ResourceType, cluster string) (*osc.Tag, error)
+	ReadClusterTags(ctx context.Context, cluster string) ([]osc.Tag, error)
 	AddTag(ctx context.Context, req osc.CreateTagsRequest, resourceIds []string) error
+	DeleteTags(ctx context.Context, req osc.DeleteTagsRequest) error
 }
 
 // AddTag add a tag to a resource
@@ -49,6 +56,12 @@ func (s *Service) AddTag(ctx context.Context, req osc.CreateTagsRequest, resourc
 	return err
 }
 
+// DeleteTags removes tags from resources
+func (s *Service) DeleteTags(ctx context.Context, req osc.DeleteTagsRequest) error {
+	_, err := s.tenant.Client().DeleteTags(ctx, req)
+	return err
+}
+
 // ReadTag read a tag of a resource
 func (s *Service) ReadTag(ctx context.Context, rsrcType ResourceType, key, value string) (*osc.Tag, error) {
 	req := osc.ReadTagsRequest{
@@ -74,6 +87,21 @@ func (s *Service) ReadOwnedByTag(ctx context.Context, rsrcType ResourceType, clu
 	return s.ReadTag(ctx, rsrcType, ClusterKeyPrefix+cluster, OwnedValue)
 }
 
+// ReadClusterTags reads all cluster tags, whatever their resource type or value
+func (s *Service) ReadClusterTags(ctx context.Context, cluster string) ([]osc.Tag, error) {
+	req := osc.ReadTagsRequest{
+		Filters: &osc.FiltersTag{
+			Keys: &[]string{ClusterKeyPrefix + cluster},
+		},
+	}
+
+	resp, err := s.tenant.Client().ReadTags(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *resp.Tags, nil
+}
+
 // ValidateTagNameValue check that tag name value is a valid name
 func ValidateTagNameValue(tagValue string) (string, error) {
 	isValidateTagNameValue := regexp.MustCompile(`^[0-9A-Za-z\-]{0,255}$`).MatchString
@@ -39,6 +39,11 @@ func (t *tenant) Client() *osc.Client {
 	return t.client
 }
 
+// Key returns a key identifying a tenant, without any secret.
+func Key(t Tenant) string {
+	return t.Region() + "/" + t.Profile().AccessKey
+}
+
 func FromProfile(prof *profile.Profile) (Tenant, error) {
 	c, err := newSDKClient(prof)
 	if err != nil {
@@ -0,0 +1,111 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// creationTracker records the resources being created in the status of an object.
+type creationTracker interface {
+	BeginCreation(ctx context.Context, key string, existing []string) error
+	RecordCreated(ctx context.Context, key, id string) error
+	Creation(key string) (existing []string, created string, found bool)
+	EndCreation(ctx context.Context, key string) error
+}
+
+var (
+	_ creationTracker = (*scope.ClusterScope)(nil)
+	_ creationTracker = (*scope.MachineScope)(nil)
+)
+
+func creationKey(rsrcType tag.ResourceType, key string) string {
+	return string(rsrcType) + "/" + key
+}
+
+// creation creates a resource which cannot be tagged atomically, and has no client token nor natural key.
+type creation struct {
+	// candidates lists the resources that might be the result of an interrupted creation.
+	candidates func() ([]string, error)
+	// create creates and tags the resource. If the resource cannot be tagged, its id is returned along with the error.
+	create func() (string, error)
+	// adopt tags a resource whose creation was interrupted.
+	adopt func(id string) error
+}
+
+// createOnce creates a resource, or adopts the resource created by an interrupted creation.
+// Before creating, the existing candidates are recorded in the status. If the resource is created but cannot be
+// tagged, its id is recorded and it is adopted on the next call. If the creation is interrupted before the
+// resource is tracked, the single new candidate is adopted on the next call.
+// Once the resource is tracked, the caller needs to call EndCreation.
+func createOnce(ctx context.Context, ct creationTracker, key string, c creation) (id string, err error) {
+	log := ctrl.LoggerFrom(ctx)
+	existing, created, found := ct.Creation(key)
+	if created != "" {
+		log.V(2).Info("Adopting resource from a failed creation", "creation", key, "id", created)
+		if err := c.adopt(created); err != nil {
+			return "", fmt.Errorf("adopt %s: %w", created, err)
+		}
+		return created, nil
+	}
+	candidates, err := c.candidates()
+	if err != nil {
+		return "", fmt.Errorf("list candidates: %w", err)
+	}
+	if found {
+		created := slices.DeleteFunc(slices.Clone(candidates), func(id string) bool {
+			return slices.Contains(existing, id)
+		})
+		if len(created) == 1 {
+			id = created[0]
+			log.V(2).Info("Adopting resource from an interrupted creation", "creation", key, "id", id)
+			if err := c.adopt(id); err != nil {
+				return "", fmt.Errorf("adopt %s: %w", id, err)
+			}
+			return id, nil
+		}
+		log.V(3).Info("Unable to find resource from an interrupted creation", "creation", key, "candidates", created)
+	}
+	if err := ct.BeginCreation(ctx, key, candidates); err != nil {
+		return "", err
+	}
+	// On error, the creation stays recorded, as the resource might have been created.
+	id, err = c.create()
+	if err != nil && id != "" {
+		if rerr := ct.RecordCreated(ctx, key, id); rerr != nil {
+			log.V(3).Error(rerr, "Unable to record created resource", "creation", key, "id", id)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// isUntaggedOrCreatedAs returns true if a resource has no tags, or has the tags set on creation.
+func isUntaggedOrCreatedAs(t []osc.ResourceTag, name, clusterID string) bool {
+	return len(t) == 0 || (tags.Has(t, tag.NameKey, name) && tags.Has(t, tags.ClusterIDKey(clusterID), tag.OwnedValue))
+}
+
+// tagCreated sets the tags that are set on creation.
+func tagCreated(ctx context.Context, svc tag.Servicer, id, name, clusterID string) error {
+	ids := []string{id}
+	return svc.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: ids,
+		Tags: []osc.ResourceTag{
+			{Key: tag.NameKey, Value: name},
+			{Key: tags.ClusterIDKey(clusterID), Value: tag.OwnedValue},
+		},
+	}, ids)
+}
@@ -6,12 +6,17 @@ SPDX-License-Identifier: BSD-3-Clause
 package controllers_test
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sync"
 	"testing"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/cache"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute/mock_compute"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
@@ -20,6 +25,7 @@ import (
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag/mock_tag"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/outscale/osc-sdk-go/v3/pkg/profile"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -50,13 +56,92 @@ func (t MockTenant) Region() string {
 	return t.region
 }
 
+func (t MockTenant) Profile() *profile.Profile {
+	return &profile.Profile{Region: t.region}
+}
+
 type MockCloudServices struct {
 	defaultTenant MockTenant
+	mu            sync.Mutex
 	tenant        tenant.Tenant
 
 	NetMock     *mock_net.MockServicer
 	ComputeMock *mock_compute.MockServicer
 	TagMock     *mock_tag.MockServicer
+	// ReadCache is the read cache, disabled if nil.
+	ReadCache *cache.Cache
+
+	securityGroupRules      map[securityGroupRulesCall][]string
+	securityGroupRulesOrder []securityGroupRulesCall
+	securityGroupRulesErrs  map[securityGroupRulesCall]error
+}
+
+// securityGroupRulesCall identifies a batched call creating or deleting securityGroup rules.
+type securityGroupRulesCall struct {
+	delete   bool
+	sg, flow string
+}
+
+// expectSecurityGroupRule records a rule expected to be created or deleted.
+// Rules are expected in a single call per securityGroup and flow, registered by expectSecurityGroupRules.
+func (s *MockCloudServices) expectSecurityGroupRule(call securityGroupRulesCall, rule osc.SecurityGroupRule) {
+	if s.securityGroupRules == nil {
+		s.securityGroupRules = map[securityGroupRulesCall][]string{}
+	}
+	if _, found := s.securityGroupRules[call]; !found {
+		s.securityGroupRulesOrder = append(s.securityGroupRulesOrder, call)
+	}
+	s.securityGroupRules[call] = append(s.securityGroupRules[call], formatSecurityGroupRules([]osc.SecurityGroupRule{rule})...)
+}
+
+// expectSecurityGroupRules registers the batched calls of all recorded rules.
+func (s *MockCloudServices) expectSecurityGroupRules() {
+	for _, call := range s.securityGroupRulesOrder {
+		m := securityGroupRulesMatcher(s.securityGroupRules[call])
+		err := s.securityGroupRulesErrs[call]
+		if call.delete {
+			s.ComputeMock.EXPECT().
+				DeleteSecurityGroupRules(gomock.Any(), gomock.Eq(call.sg), gomock.Eq(call.flow), m).
+				Return(err)
+		} else {
+			s.ComputeMock.EXPECT().
+				CreateSecurityGroupRules(gomock.Any(), gomock.Eq(call.sg), gomock.Eq(call.flow), m).
+				Return(err)
+		}
+	}
+	s.securityGroupRules, s.securityGroupRulesOrder, s.securityGroupRulesErrs = nil, nil, nil
+}
+
+// formatSecurityGroupRules returns a sorted list of rules, one per IP range or securityGroup member.
+func formatSecurityGroupRules(rules []osc.SecurityGroupRule) []string {
+	var out []string
+	for _, rule := range rules {
+		for _, ipRange := range rule.IpRanges {
+			out = append(out, fmt.Sprintf("%s %s %d-%d", ipRange, rule.IpProtocol, rule.FromPortRange, rule.ToPortRange))
+		}
+		for _, member := range rule.SecurityGroupsMembers {
+			out = append(out, fmt.Sprintf("%s %s %d-%d", member.SecurityGroupId, rule.IpProtocol, rule.FromPortRange, rule.ToPortRange))
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// securityGroupRulesMatcher matches a list of rules, regardless of order and grouping.
+type securityGroupRulesMatcher []string
+
+func (m securityGroupRulesMatcher) Matches(x any) bool {
+	rules, ok := x.([]osc.SecurityGroupRule)
+	if !ok {
+		return false
+	}
+	expected := slices.Clone([]string(m))
+	slices.Sort(expected)
+	return slices.Equal(expected, formatSecurityGroupRules(rules))
+}
+
+func (m securityGroupRulesMatcher) String() string {
+	return fmt.Sprintf("has rules %v", []string(m))
 }
 
 func newMockCloudServices(mockCtrl *gomock.Controller, region string) *MockCloudServices {
@@ -74,20 +159,30 @@ func (s *MockCloudServices) DefaultTenant() (tenant.Tenant, error) {
 }
 
 func (s *MockCloudServices) Net(t tenant.Tenant) net.Servicer {
+	s.mu.Lock()
 	s.tenant = t
+	s.mu.Unlock()
 	return s.NetMock
 }
 
 func (s *MockCloudServices) Compute(t tenant.Tenant) compute.Servicer {
+	s.mu.Lock()
 	s.tenant = t
+	s.mu.Unlock()
 	return s.ComputeMock
 }
 
 func (s *MockCloudServices) Tag(t tenant.Tenant) tag.Servicer {
+	s.mu.Lock()
 	s.tenant = t
+	s.mu.Unlock()
 	return s.TagMock
 }
 
+func (s *MockCloudServices) Cache() *cache.Cache {
+	return s.ReadCache
+}
+
 type (
 	patchOSCClusterFunc func(m *infrastructurev1beta2.OscCluster)
 	patchOSCMachineFunc func(m *infrastructurev1beta2.OscMachine)
@@ -99,6 +194,7 @@ type (
 	assertOSCMachineFunc func(t *testing.T, m *infrastructurev1beta2.OscMachine)
 	assertOSCClusterFunc func(t *testing.T, c *infrastructurev1beta2.OscCluster)
 	assertTenantFunc     func(t *testing.T, tnt tenant.Tenant)
+	assertKubeFunc       func(t *testing.T, c client.Client)
 )
 
 type testcase struct {
@@ -116,6 +212,7 @@ type testcase struct {
 	clusterAsserts                   []assertOSCClusterFunc
 	machineAsserts                   []assertOSCMachineFunc
 	tenantAsserts                    []assertTenantFunc
+	kubeAsserts                      []assertKubeFunc
 
 	next *testcase
 }
@@ -228,12 +325,48 @@ func mockGetPublicIpByIp(publicIp, publicIpId string) mockFunc {
 
 func mockCreatePublicIp(name, clusterID, publicIpId, publicIp string) mockFunc {
 	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListUnlinkedPublicIpIds(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(nil, nil)
 		s.NetMock.EXPECT().
 			CreatePublicIp(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterID)).
 			Return(&osc.PublicIp{PublicIpId: publicIpId, PublicIp: publicIp}, nil)
 	}
 }
 
+func mockCreatePublicIpTagFails(name, clusterID, publicIpId, publicIp string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListUnlinkedPublicIpIds(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(nil, nil)
+		s.NetMock.EXPECT().
+			CreatePublicIp(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(&osc.PublicIp{PublicIpId: publicIpId, PublicIp: publicIp}, errors.New("tag error"))
+	}
+}
+
+func mockListUnlinkedPublicIpIds(name, clusterID string, ids []string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListUnlinkedPublicIpIds(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(ids, nil)
+	}
+}
+
+func mockTagCreated(id, name, clusterID string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.TagMock.EXPECT().
+			AddTag(gomock.Any(), gomock.Eq(osc.CreateTagsRequest{
+				ResourceIds: []string{id},
+				Tags: []osc.ResourceTag{
+					{Key: tag.NameKey, Value: name},
+					{Key: tag.ClusterKeyPrefix + clusterID, Value: tag.OwnedValue},
+				},
+			}), gomock.Eq([]string{id})).
+			Return(nil)
+	}
+}
+
 func mockListPublicIpsFromPool(pool string, ips []osc.PublicIp) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
@@ -9,24 +9,29 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services"
+	"github.com/outscale/cluster-api-provider-outscale/util/parallel"
 	"github.com/outscale/cluster-api-provider-outscale/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
-	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/labels"
 	predicates "sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -34,21 +39,30 @@ const OscClusterFinalizer = "oscclusters.infrastructure.cluster.x-k8s.io"
 
 // OscClusterReconciler reconciles a OscCluster object
 type OscClusterReconciler struct {
-	Client           client.Client
+	Client client.Client
+	// APIReader reads objects from the API server, bypassing the cache of Client.
+	APIReader        client.Reader
 	Tracker          *ClusterResourceTracker
 	Cloud            services.Servicer
 	Metadata         services.Metadata
 	Recorder         record.EventRecorder
 	ReconcileTimeout time.Duration
 	WatchFilterValue string
+	// Parallelism is the maximum number of sub-reconcilers running concurrently for a cluster.
+	Parallelism int
+
+	// egressResolutions records when the egress destinations of clusters were last resolved (key: cluster UID).
+	egressResolutions sync.Map
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscclusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscnetpools,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscnetpools/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;get;list;patch;update;watch
 
 func (r *OscClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -124,104 +138,150 @@ func (r *OscClusterReconciler) reconcile(ctx context.Context, clusterScope *scop
 	// 	return reconcile.Result{}, errs.ToAggregate()
 	// }
 
-	// Reconcile each element of the cluster
-	_, err := r.reconcileNet(ctx, clusterScope)
-	if err != nil {
-		conditions.MarkFalse(osccluster, infrastructurev1beta2.NetReadyCondition, infrastructurev1beta2.NetReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-		return reconcile.Result{}, fmt.Errorf("reconcile net: %w", err)
+	// Reconcile each element of the cluster, independent elements being reconciled concurrently.
+	exec := parallel.NewExecutor(reconciler.DefaultedParallelism(r.Parallelism))
+	step := func(name string, reconcileFn func(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error),
+		cond clusterv1.ConditionType, reason string, dependsOn ...string) {
+		exec.Add(name, func(ctx context.Context) error {
+			_, err := reconcileFn(ctx, clusterScope)
+			clusterScope.MarkCondition(cond, reason, err)
+			if err != nil {
+				return fmt.Errorf("reconcile %s: %w", name, err)
+			}
+			return nil
+		}, dependsOn...)
 	}
-	conditions.MarkTrue(osccluster, infrastructurev1beta2.NetReadyCondition)
-
-	_, err = r.reconcileSubnets(ctx, clusterScope)
-	if err != nil {
-		conditions.MarkFalse(osccluster, infrastructurev1beta2.SubnetsReadyCondition, infrastructurev1beta2.SubnetsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-		return reconcile.Result{}, fmt.Errorf("reconcile subnets: %w", err)
-	}
-	conditions.MarkTrue(osccluster, infrastructurev1beta2.SubnetsReadyCondition)
+	step("net", r.reconcileNet, infrastructurev1beta2.NetReadyCondition, infrastructurev1beta2.NetReconciliationFailedReason)
+	step("subnets", r.reconcileSubnets, infrastructurev1beta2.SubnetsReadyCondition, infrastructurev1beta2.SubnetsReconciliationFailedReason, "net")
 
 	if !clusterScope.IsInternetDisabled() {
-		_, err = r.reconcileInternetService(ctx, clusterScope)
-		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.InternetServicesReadyCondition, infrastructurev1beta2.InternetServicesFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile internetService: %w", err)
-		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.InternetServicesReadyCondition)
-
+		step("internetService", r.reconcileInternetService, infrastructurev1beta2.InternetServicesReadyCondition, infrastructurev1beta2.InternetServicesFailedReason, "net")
 		// Add public route table to mark public subnet as public & enable NAT creation
-		_, err = r.reconcileRouteTable(ctx, clusterScope, infrastructurev1beta2.RoleNat)
-		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.RouteTablesReadyCondition, infrastructurev1beta2.RouteTableReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile public routeTables: %w", err)
-		}
+		exec.Add("public routeTables", func(ctx context.Context) error {
+			_, err := r.reconcileRouteTable(ctx, clusterScope, infrastructurev1beta2.RoleNat)
+			if err != nil {
+				clusterScope.MarkCondition(infrastructurev1beta2.RouteTablesReadyCondition, infrastructurev1beta2.RouteTableReconciliationFailedReason, err)
+				return fmt.Errorf("reconcile public routeTables: %w", err)
+			}
+			return nil
+		}, "subnets", "internetService")
+		step("natServices", r.reconcileNatService, infrastructurev1beta2.NatServicesReadyCondition, infrastructurev1beta2.NatServicesReconciliationFailedReason, "public routeTables")
+	}
 
-		_, err = r.reconcileNatService(ctx, clusterScope)
+	// Add all other route tables, whose destinations are the NAT services previously created.
+	exec.Add("routeTables", func(ctx context.Context) error {
+		_, err := r.reconcileRouteTable(ctx, clusterScope)
+		clusterScope.MarkCondition(infrastructurev1beta2.RouteTablesReadyCondition, infrastructurev1beta2.RouteTableReconciliationFailedReason, err)
 		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.NatServicesReadyCondition, infrastructurev1beta2.NatServicesReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile natServices: %w", err)
+			return fmt.Errorf("reconcile routeTables: %w", err)
 		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.NatServicesReadyCondition)
+		return nil
+	}, "subnets", "public routeTables", "natServices")
+
+	// Routes to failed natServices are moved to healthy natServices.
+	if clusterScope.IsNatFailoverEnabled() {
+		exec.Add("natFailover", func(ctx context.Context) error {
+			_, err := r.reconcileNatFailover(ctx, clusterScope)
+			if err != nil {
+				clusterScope.MarkCondition(infrastructurev1beta2.NatServicesHealthyCondition, infrastructurev1beta2.NatFailoverFailedReason, err)
+				return fmt.Errorf("reconcile natFailover: %w", err)
+			}
+			return nil
+		}, "routeTables")
 	}
 
-	// Add all other route tables, whose destinations are the NAT services previously created.
-	_, err = r.reconcileRouteTable(ctx, clusterScope)
-	if err != nil {
-		conditions.MarkFalse(osccluster, infrastructurev1beta2.RouteTablesReadyCondition, infrastructurev1beta2.RouteTableReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-		return reconcile.Result{}, fmt.Errorf("reconcile routeTables: %w", err)
+	if clusterScope.GetNetwork().NetPeering.Enable {
+		exec.Add("netPeering", func(ctx context.Context) error {
+			_, err := r.reconcileNetPeering(ctx, clusterScope)
+			if err == nil {
+				_, err = r.reconcileNetPeeringRoutes(ctx, clusterScope)
+			}
+			clusterScope.MarkCondition(infrastructurev1beta2.NetPeeringReadyCondition, infrastructurev1beta2.NetPeeringReconciliationFailedReason, err)
+			if err != nil {
+				return fmt.Errorf("reconcile netPeering: %w", err)
+			}
+			return nil
+		}, "routeTables")
 	}
-	conditions.MarkTrue(osccluster, infrastructurev1beta2.RouteTablesReadyCondition)
 
+	// Peerings are created after the management netPeering, which would otherwise be able to find them by tag.
+	peeringDeps := []string{"routeTables"}
 	if clusterScope.GetNetwork().NetPeering.Enable {
-		_, err = r.reconcileNetPeering(ctx, clusterScope)
+		peeringDeps = append(peeringDeps, "netPeering")
+	}
+	for _, peering := range clusterScope.GetNetwork().Peerings {
+		step("peering/"+peering.Name, func(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+			return r.reconcilePeering(ctx, clusterScope, peering)
+		}, infrastructurev1beta2.PeeringReadyCondition(peering.Name), infrastructurev1beta2.PeeringReconciliationFailedReason, peeringDeps...)
+	}
+	// Peerings removed from the spec are deleted.
+	exec.Add("removed peerings", func(ctx context.Context) error {
+		_, err := r.reconcileRemovedPeerings(ctx, clusterScope)
 		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.NetPeeringReadyCondition, infrastructurev1beta2.NetPeeringReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile netPeering: %w", err)
+			return fmt.Errorf("reconcile removed peerings: %w", err)
 		}
-		_, err = r.reconcileNetPeeringRoutes(ctx, clusterScope)
+		return nil
+	}, "routeTables")
+
+	if clusterScope.GetNetwork().Vpn.Enable {
+		step("vpn", r.reconcileVpn, infrastructurev1beta2.VpnReadyCondition, infrastructurev1beta2.VpnReconciliationFailedReason, "routeTables")
+	}
+	// VPN resources removed from the spec are deleted.
+	exec.Add("removed vpn", func(ctx context.Context) error {
+		_, err := r.reconcileRemovedVpn(ctx, clusterScope)
 		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.NetPeeringReadyCondition, infrastructurev1beta2.NetPeeringReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile netPeering: %w", err)
+			return fmt.Errorf("reconcile removed vpn: %w", err)
 		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.NetPeeringReadyCondition)
-	}
+		return nil
+	}, "routeTables")
 
 	if len(clusterScope.GetNetwork().NetAccessPoints) > 0 {
-		_, err = r.reconcileNetAccessPoints(ctx, clusterScope)
-		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.NetAccessPointsReadyCondition, infrastructurev1beta2.NetAccessPointsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile netAccessPoints: %w", err)
-		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.NetAccessPointsReadyCondition)
+		step("netAccessPoints", r.reconcileNetAccessPoints, infrastructurev1beta2.NetAccessPointsReadyCondition, infrastructurev1beta2.NetAccessPointsReconciliationFailedReason, "routeTables")
 	}
 
 	// Security groups need NAT services to allow NAT to connect to LB.
-	_, err = r.reconcileSecurityGroup(ctx, clusterScope)
-	if err != nil {
-		conditions.MarkFalse(osccluster, infrastructurev1beta2.SecurityGroupReadyCondition, infrastructurev1beta2.SecurityGroupReconciliationFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-		return reconcile.Result{}, fmt.Errorf("reconcile securityGroups: %w", err)
-	}
-	conditions.MarkTrue(osccluster, infrastructurev1beta2.SecurityGroupReadyCondition)
+	step("securityGroups", r.reconcileSecurityGroup, infrastructurev1beta2.SecurityGroupReadyCondition, infrastructurev1beta2.SecurityGroupReconciliationFailedReason, "subnets", "natServices")
 
 	if !clusterScope.IsLBDisabled() {
-		_, err = r.reconcileLoadBalancer(ctx, clusterScope)
-		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.LoadBalancerReadyCondition, infrastructurev1beta2.LoadBalancerFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile loadBalancer: %w", err)
-		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.LoadBalancerReadyCondition)
+		step("loadBalancer", r.reconcileLoadBalancer, infrastructurev1beta2.LoadBalancerReadyCondition, infrastructurev1beta2.LoadBalancerFailedReason, "securityGroups", "routeTables")
+	}
+	for _, alb := range clusterScope.GetNetwork().AdditionalLoadBalancers {
+		step("loadBalancer/"+alb.LoadBalancerName, func(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+			return r.reconcileAdditionalLoadBalancer(ctx, clusterScope, alb)
+		}, infrastructurev1beta2.AdditionalLoadBalancerReadyCondition(alb.LoadBalancerName), infrastructurev1beta2.LoadBalancerFailedReason, "securityGroups", "routeTables")
 	}
 
-	if clusterScope.GetNetwork().Bastion.Enable {
-		_, err := r.reconcileBastion(ctx, clusterScope)
+	// Additional loadBalancers removed from the spec are deleted, once the loadBalancers of the spec are reconciled.
+	lbSteps := []string{"securityGroups", "loadBalancer"}
+	for _, alb := range clusterScope.GetNetwork().AdditionalLoadBalancers {
+		lbSteps = append(lbSteps, "loadBalancer/"+alb.LoadBalancerName)
+	}
+	exec.Add("removed loadBalancers", func(ctx context.Context) error {
+		_, err := r.reconcileRemovedAdditionalLoadBalancers(ctx, clusterScope)
 		if err != nil {
-			conditions.MarkFalse(osccluster, infrastructurev1beta2.VmReadyCondition, infrastructurev1beta2.VmNotReadyReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
-			return reconcile.Result{}, fmt.Errorf("reconcile bastion: %w", err)
+			return fmt.Errorf("reconcile removed loadBalancers: %w", err)
 		}
-		conditions.MarkTrue(osccluster, infrastructurev1beta2.VmReadyCondition)
+		return nil
+	}, lbSteps...)
+
+	if clusterScope.GetNetwork().Bastion.Enable {
+		step("bastion", r.reconcileBastion, infrastructurev1beta2.VmReadyCondition, infrastructurev1beta2.VmNotReadyReason, "securityGroups", "routeTables")
+	}
+
+	if err := exec.Run(ctx); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	log.V(2).Info("OscCluster is ready")
 	clusterScope.SetReady()
-	return reconcile.Result{}, nil
+	var result reconcile.Result
+	if clusterScope.IsNatFailoverEnabled() {
+		result.RequeueAfter = clusterScope.GetNatFailoverCheckInterval()
+	}
+	if clusterScope.HasEgressDestinations() && (result.RequeueAfter == 0 || result.RequeueAfter > reconciler.DefaultEgressResolveInterval) {
+		result.RequeueAfter = reconciler.DefaultEgressResolveInterval
+	}
+	return result, nil
 }
 
 // reconcileDelete reconcile the deletion of the cluster
@@ -259,6 +319,14 @@ func (r *OscClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 			return reconcile.Result{}, fmt.Errorf("reconcile delete loadBalancer: %w", err)
 		}
 	}
+	_, err = r.reconcileRemovedAdditionalLoadBalancers(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile delete removed loadBalancers: %w", err)
+	}
+	_, err = r.reconcileDeleteAdditionalLoadBalancers(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile delete additional loadBalancers: %w", err)
+	}
 
 	if !clusterScope.IsInternetDisabled() {
 		_, err = r.reconcileDeleteNatService(ctx, clusterScope)
@@ -276,6 +344,16 @@ func (r *OscClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 		return reconcile.Result{}, fmt.Errorf("reconcile delete netAccessPoints: %w", err)
 	}
 
+	for _, peering := range clusterScope.GetNetwork().Peerings {
+		_, err = r.reconcileDeletePeering(ctx, clusterScope, peering)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("reconcile delete peering %s: %w", peering.Name, err)
+		}
+	}
+	_, err = r.reconcileRemovedPeerings(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile delete removed peerings: %w", err)
+	}
 	if clusterScope.GetNetwork().NetPeering.Enable {
 		_, err = r.reconcileDeleteNetPeeringRoutes(ctx, clusterScope)
 		if err != nil {
@@ -286,6 +364,16 @@ func (r *OscClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 			return reconcile.Result{}, fmt.Errorf("reconcile delete netPeering: %w", err)
 		}
 	}
+	_, err = r.reconcileRemovedVpn(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile delete removed vpn: %w", err)
+	}
+	if clusterScope.GetNetwork().Vpn.Enable {
+		_, err = r.reconcileDeleteVpn(ctx, clusterScope)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("reconcile delete vpn: %w", err)
+		}
+	}
 	_, err = r.reconcileDeleteRouteTable(ctx, clusterScope)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("reconcile delete routeTables: %w", err)
@@ -310,6 +398,11 @@ func (r *OscClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("reconcile delete net: %w", err)
 	}
+	err = r.releaseNetRange(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("release net range: %w", err)
+	}
+	r.egressResolutions.Delete(clusterScope.GetUID())
 	controllerutil.RemoveFinalizer(osccluster, OscClusterFinalizer)
 	return reconcile.Result{}, nil
 }
@@ -318,7 +411,25 @@ func (r *OscClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 func (r *OscClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
-		For(&infrastructurev1beta2.OscCluster{}).
-		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		For(&infrastructurev1beta2.OscCluster{},
+			builder.WithPredicates(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), ctrl.LoggerFrom(ctx), r.WatchFilterValue)),
+		).
+		// Secrets and ConfigMaps do not have the watch filter label, the mappers filter the OscClusters instead.
+		// Only their metadata is cached, they are read with APIReader when used.
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.SecretToOscClusters(ctx)),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.ConfigMapToOscClusters(ctx)),
+			builder.OnlyMetadata,
+		).
 		Complete(r)
 }
+
+// hasWatchFilterLabel returns true if the cluster is watched by this reconciler.
+func (r *OscClusterReconciler) hasWatchFilterLabel(oscCluster *infrastructurev1beta2.OscCluster) bool {
+	return r.WatchFilterValue == "" || labels.HasWatchLabel(oscCluster, r.WatchFilterValue)
+}
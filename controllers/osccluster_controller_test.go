@@ -7,6 +7,7 @@ package controllers_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -16,6 +17,7 @@ import (
 	tag "github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/cluster-api-provider-outscale/controllers"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -45,7 +47,7 @@ func runClusterTest(t *testing.T, tc testcase) {
 	_ = apiextensionsv1.AddToScheme(fakeScheme)
 	_ = infrastructurev1beta2.AddToScheme(fakeScheme)
 	client := fake.NewClientBuilder().WithScheme(fakeScheme).
-		WithStatusSubresource(oc).WithObjects(c, oc).Build()
+		WithStatusSubresource(oc, &infrastructurev1beta2.OscNetPool{}).WithObjects(c, oc).Build()
 	mockCtrl := gomock.NewController(t)
 	region := tc.region
 	if region == "" {
@@ -53,8 +55,9 @@ func runClusterTest(t *testing.T, tc testcase) {
 	}
 	cs := newMockCloudServices(mockCtrl, region)
 	rec := controllers.OscClusterReconciler{
-		Client:   client,
-		Recorder: record.NewFakeRecorder(100),
+		Client:    client,
+		APIReader: client,
+		Recorder:  record.NewFakeRecorder(100),
 		Tracker: &controllers.ClusterResourceTracker{
 			Cloud: cs,
 		},
@@ -69,6 +72,7 @@ func runClusterTest(t *testing.T, tc testcase) {
 		for _, fn := range step.mockFuncs {
 			fn(cs)
 		}
+		cs.expectSecurityGroupRules()
 		for _, obj := range step.kubeObjects {
 			err := client.Create(context.TODO(), obj)
 			require.NoError(t, err)
@@ -95,6 +99,9 @@ func runClusterTest(t *testing.T, tc testcase) {
 		for _, fn := range step.tenantAsserts {
 			fn(t, cs.tenant)
 		}
+		for _, fn := range step.kubeAsserts {
+			fn(t, client)
+		}
 		step = step.next
 	}
 }
@@ -195,9 +202,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
@@ -326,9 +332,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
@@ -467,9 +472,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockGetVmFromClientToken("bastion-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreatePublicIp("Bastion for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-bastion", "1.2.3.4"),
@@ -629,9 +633,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockGetVmFromClientToken("bastion-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreatePublicIp("Bastion for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-bastion", "7.8.9.10"),
@@ -751,9 +754,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockGetVmFromClientToken("bastion-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreatePublicIp("Bastion for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-bastion", "7.8.9.10"),
@@ -875,9 +877,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockGetVmFromClientToken("bastion-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreatePublicIp("Bastion for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-bastion", "1.2.3.4"),
@@ -1027,9 +1028,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw-2b", "0.0.0.0/0", "nat-foo-2b", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public-2a", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public-2a", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 		},
 		{
@@ -1038,10 +1038,14 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 			clusterBaseSpec: "base",
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetSecurityGroupFromName("test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520",
@@ -1085,9 +1089,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateSecurityGroupRule("sg-node", "Outbound", "-1", "10.0.0.0/16", -1, -1),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
@@ -1098,6 +1101,11 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 						"test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520":           "sg-lb",
 						"test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520":         "sg-node",
 					},
+					SharedRoleTags: map[string]string{
+						"subnet-kcp":    "OscK8sRole/controlplane",
+						"subnet-kw":     "OscK8sRole/worker",
+						"subnet-public": "OscK8sRole/loadbalancer",
+					},
 				}),
 				assertControlPlaneEndpoint("test-cluster-api-k8s.outscale.dev", 6443),
 			},
@@ -1117,6 +1125,11 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 			clusterBaseSpec: "base",
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
+				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockGetSubnet("subnet-public", nil),
 			},
 			hasError: true,
@@ -1127,21 +1140,337 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 			clusterBaseSpec: "base",
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
-				assertStatusClusterResources(infrastructurev1beta2.OscClusterResources{}),
+				assertStatusClusterResources(infrastructurev1beta2.OscClusterResources{
+					SharedRoleTags: map[string]string{
+						"subnet-kcp":    "OscK8sRole/controlplane",
+						"subnet-kw":     "OscK8sRole/worker",
+						"subnet-public": "OscK8sRole/loadbalancer",
+					},
+				}),
+				assertControlPlaneEndpoint("test-cluster-api-k8s.outscale.dev", 6443),
+			},
+		},
+		{
+			name:            "reusing a subnet already having its role tag does not record the role tag",
+			clusterSpec:     "reuse-all-1.0",
+			clusterBaseSpec: "base",
+			mockFuncs: []mockFunc{
+				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+
+				mockGetSubnet("subnet-kcp", &osc.Subnet{
+					SubnetId: "subnet-kcp",
+					Tags:     []osc.ResourceTag{{Key: "OscK8sRole/controlplane"}},
+				}),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
+				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
+
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertStatusClusterResources(infrastructurev1beta2.OscClusterResources{
+					SharedRoleTags: map[string]string{
+						"subnet-kw":     "OscK8sRole/worker",
+						"subnet-public": "OscK8sRole/loadbalancer",
+					},
+				}),
+			},
+		},
+		{
+			name:            "allocating subnets in a shared net",
+			clusterSpec:     "reuse-all-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchSubnetAllocation(26)},
+			kubeObjects: []client.Object{
+				&infrastructurev1beta2.OscCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-cluster",
+						Namespace: "cluster-api-test",
+						UID:       "other-cluster-uid",
+					},
+					Spec: infrastructurev1beta2.OscClusterSpec{
+						Network: infrastructurev1beta2.OscNetwork{
+							Net: infrastructurev1beta2.OscNet{ResourceId: "vpc-foo"},
+						},
+					},
+					Status: infrastructurev1beta2.OscClusterStatus{
+						Resources: infrastructurev1beta2.OscClusterResources{
+							SubnetClaims: map[string]string{"eu-west-2a/worker": "10.0.1.0/26"},
+						},
+					},
+				},
+			},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", IpRange: "10.0.0.0/16", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", IpRange: "10.0.0.0/16", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockGetSubnetsFromNet("vpc-foo", []osc.Subnet{{SubnetId: "subnet-other", IpRange: "10.0.0.0/24", SubregionName: "eu-west-2a"}}),
+
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.64/26", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.64/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "subnet-public"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.128/26", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.128/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "subnet-kw"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.192/26", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.192/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "subnet-kcp"),
+
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSubnetClaims(map[string]string{
+					"eu-west-2a/loadbalancer,bastion,nat": "10.0.1.64/26",
+					"eu-west-2a/worker":                   "10.0.1.128/26",
+					"eu-west-2a/controlplane":             "10.0.1.192/26",
+				}),
+				assertControlPlaneEndpoint("test-cluster-api-k8s.outscale.dev", 6443),
+			},
+		},
+		{
+			name:            "the claim of an allocated subnet is released when its creation fails",
+			clusterSpec:     "reuse-all-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchSubnetAllocation(26)},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", IpRange: "10.0.0.0/16", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", IpRange: "10.0.0.0/16", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockGetSubnetsFromNet("vpc-foo", []osc.Subnet{{SubnetId: "subnet-other", IpRange: "10.0.0.0/24", SubregionName: "eu-west-2a"}}),
+
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.0/26", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.0/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "subnet-public"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.64/26", nil),
+				mockCreateSubnetFails(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.64/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.128/26", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.1.128/26",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "subnet-kcp"),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSubnetClaims(map[string]string{
+					"eu-west-2a/loadbalancer,bastion,nat": "10.0.1.0/26",
+					"eu-west-2a/controlplane":             "10.0.1.128/26",
+				}),
+			},
+		},
+		{
+			name:            "allocated subnets are recovered from tags when status is reset",
+			clusterSpec:     "reuse-all-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchSubnetAllocation(26)},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", IpRange: "10.0.0.0/16", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockGetSubnetsFromNet("vpc-foo", []osc.Subnet{
+					{SubnetId: "subnet-other", IpRange: "10.0.0.0/24", SubregionName: "eu-west-2a"},
+					{SubnetId: "subnet-kw", IpRange: "10.0.1.0/26", SubregionName: "eu-west-2a", Tags: []osc.ResourceTag{
+						{Key: "OscK8sClusterID/9e1db9c4-bf0a-4583-8999-203ec002c520", Value: "owned"}, {Key: "Name", Value: "Worker subnet for test-cluster-api/eu-west-2a"}, {Key: "OscK8sRole/worker"},
+					}},
+					{SubnetId: "subnet-kcp", IpRange: "10.0.1.64/26", SubregionName: "eu-west-2a", Tags: []osc.ResourceTag{
+						{Key: "OscK8sClusterID/9e1db9c4-bf0a-4583-8999-203ec002c520", Value: "owned"}, {Key: "Name", Value: "Controlplane subnet for test-cluster-api/eu-west-2a"}, {Key: "OscK8sRole/controlplane"},
+					}},
+					{SubnetId: "subnet-public", IpRange: "10.0.1.128/26", SubregionName: "eu-west-2a", Tags: []osc.ResourceTag{
+						{Key: "OscK8sClusterID/9e1db9c4-bf0a-4583-8999-203ec002c520", Value: "owned"}, {Key: "Name", Value: "Public subnet for test-cluster-api/eu-west-2a"},
+						{Key: "OscK8sRole/loadbalancer"}, {Key: "OscK8sRole/bastion"}, {Key: "OscK8sRole/nat"},
+					}},
+				}),
+
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.0/26", &osc.Subnet{SubnetId: "subnet-kw"}),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.64/26", &osc.Subnet{SubnetId: "subnet-kcp"}),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.128/26", &osc.Subnet{SubnetId: "subnet-public"}),
+
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSubnetClaims(map[string]string{
+					"eu-west-2a/loadbalancer,bastion,nat": "10.0.1.128/26",
+					"eu-west-2a/worker":                   "10.0.1.0/26",
+					"eu-west-2a/controlplane":             "10.0.1.64/26",
+				}),
+			},
+		},
+		{
+			name:           "allocating the net range from a net pool",
+			clusterSpec:    "base-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNetPool("pool")},
+			kubeObjects: []client.Object{
+				newNetPool("pool", "10.0.0.0/15", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.1.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+			mockFuncs: []mockFunc{
+				mockReadOwnedByTag(tag.NetResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateNet(infrastructurev1beta2.OscNet{
+					IpRange: "10.0.0.0/16",
+					NetPool: "pool",
+				}, "9e1db9c4-bf0a-4583-8999-203ec002c520", "Net for test-cluster-api", "vpc-foo"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.4.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.4.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "subnet-kcp"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.3.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.3.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "subnet-kw"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.2.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.2.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "subnet-public"),
+				mockGetInternetServiceForNet("vpc-foo", nil),
+				mockCreateInternetService("Internet Service for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "igw-foo"),
+				mockLinkInternetService("igw-foo", "vpc-foo"),
+
+				mockGetSecurityGroupFromName("test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Worker securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}, "sg-kw"),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.3.0/24", 10250, 10250),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 10250, 10250),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 443, 443),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 1024, 65535),
+
+				mockGetSecurityGroupFromName("test-cluster-api-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Controlplane securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, "sg-kcp"),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.4.0/24", 10250, 10252),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.0.0/16", 6443, 6443),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.4.0/24", 2378, 2380),
+
+				mockGetSecurityGroupFromName("test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"LB securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer}, "sg-lb"),
+				mockCreateSecurityGroupRule("sg-lb", "Inbound", "tcp", "0.0.0.0/0", 6443, 6443),
+				mockCreateSecurityGroupRule("sg-lb", "Outbound", "tcp", "10.0.4.0/24", 6443, 6443),
+
+				mockGetSecurityGroupFromName("test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Node securityGroup for test-cluster-api", "OscK8sMainSG", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane, infrastructurev1beta2.RoleWorker}, "sg-node"),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 179, 179),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 4789, 4789),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 5473, 5473),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 8285, 8285),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 51820, 51821),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "4", "10.0.0.0/16", -1, -1),
+
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "icmp", "10.0.0.0/16", 8, 8),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 4240, 4240),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 4244, 4244),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 8472, 8472),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 51871, 51871),
+
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 30000, 32767),
+				mockCreateSecurityGroupRule("sg-node", "Outbound", "-1", "0.0.0.0/0", -1, -1),
+				mockCreateSecurityGroupRule("sg-node", "Outbound", "-1", "10.0.0.0/16", -1, -1),
+
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "rtb-public"),
+				mockLinkRouteTable("rtb-public", "subnet-public"),
+				mockCreateRoute("rtb-public", "0.0.0.0/0", "igw-foo", "gateway"),
+
+				mockGetNatServiceFromClientToken("eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreatePublicIp("Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-nat", "1.2.3.4"),
+				mockCreateNatService("ipalloc-nat", "subnet-public", "eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", "Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "nat-foo"),
+
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{
+						RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}},
+						Routes: []osc.Route{{DestinationIpRange: "0.0.0.0/0", GatewayId: new("igw-foo")}},
+					},
+				}),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "rtb-kcp"),
+				mockLinkRouteTable("rtb-kcp", "subnet-kcp"),
+				mockCreateRoute("rtb-kcp", "0.0.0.0/0", "nat-foo", "nat"),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "rtb-kw"),
+				mockLinkRouteTable("rtb-kw", "subnet-kw"),
+				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
+
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNetRange("10.0.0.0/16"),
 				assertControlPlaneEndpoint("test-cluster-api-k8s.outscale.dev", 6443),
 			},
+			kubeAsserts: []assertKubeFunc{
+				assertNetPoolAllocations("pool", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.0.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "test-cluster-api", UID: "9e1db9c4-bf0a-4583-8999-203ec002c520"},
+					"10.1.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+		},
+		{
+			name:           "net range allocation fails if the net pool is full",
+			clusterSpec:    "base-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNetPool("pool")},
+			kubeObjects: []client.Object{
+				newNetPool("pool", "10.0.0.0/16", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.0.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+			hasError: true,
+		},
+		{
+			name:            "net range allocation does not overlap the management net",
+			clusterSpec:     "airgap-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchNetPool("pool")},
+			kubeObjects: []client.Object{
+				newNetPool("pool", "10.0.0.0/15", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.0.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-mgmt", &osc.Net{NetId: "vpc-mgmt", IpRange: "10.1.0.0/16"}),
+			},
+			hasError: true,
 		},
 		{
 			name:           "using NAT IPs from a pool",
@@ -1238,9 +1567,8 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
@@ -1276,9 +1604,12 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 			},
 		},
 		{
-			name:           "disabling LBU",
-			clusterSpec:    "base-1.0",
-			clusterPatches: []patchOSCClusterFunc{patchDisableLB()},
+			name:        "using existing NAT IPs and load balancer",
+			clusterSpec: "base-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchNATPublicIpId("eu-west-2a", "ipalloc-existing"),
+				patchUseExistingLoadBalancer(),
+			},
 			mockFuncs: []mockFunc{
 				mockReadOwnedByTag(tag.NetResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
 				mockCreateNet(infrastructurev1beta2.OscNet{
@@ -1353,8 +1684,7 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRoute("rtb-public", "0.0.0.0/0", "igw-foo", "gateway"),
 
 				mockGetNatServiceFromClientToken("eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
-				mockCreatePublicIp("Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-nat-2a", "1.2.3.4"),
-				mockCreateNatService("ipalloc-nat-2a", "subnet-public", "eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", "Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "nat-foo"),
+				mockCreateNatService("ipalloc-existing", "subnet-public", "eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", "Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "nat-foo"),
 
 				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
 					{
@@ -1368,10 +1698,11 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "rtb-kw"),
 				mockLinkRouteTable("rtb-kw", "subnet-kw"),
 				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
+
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				assertHasClusterFinalizer(),
-				assertControlPlaneEndpoint("api.example.com", 443),
 				assertStatusClusterResources(infrastructurev1beta2.OscClusterResources{
 					Net: map[string]string{
 						"default": "vpc-foo",
@@ -1393,29 +1724,154 @@ func TestReconcileOSCCluster_Create(t *testing.T) {
 					NatService: map[string]string{
 						"eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520": "nat-foo",
 					},
-					PublicIPs: map[string]string{
-						"eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520": "ipalloc-nat-2a",
-					},
 				}),
+				assertControlPlaneEndpoint("test-cluster-api-k8s.lbu.outscale.com", 6443),
 			},
 			next: &testcase{
 				name: "A second run has all references in cache",
 			},
 		},
-	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			runClusterTest(t, tc)
-		})
-	}
-}
-
-func TestReconcileOSCCluster_Multitenant(t *testing.T) {
-	// disable random reconciliation of security groups
-	scope.Rand = func() int { return 100 }
-
-	d := t.TempDir()
-	filepath := d + "tenant.json"
+		{
+			name:           "disabling LBU",
+			clusterSpec:    "base-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDisableLB()},
+			mockFuncs: []mockFunc{
+				mockReadOwnedByTag(tag.NetResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateNet(infrastructurev1beta2.OscNet{
+					IpRange: "10.0.0.0/16",
+				}, "9e1db9c4-bf0a-4583-8999-203ec002c520", "Net for test-cluster-api", "vpc-foo"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.4.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.4.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "subnet-kcp"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.3.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.3.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "subnet-kw"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.2.0/24", nil),
+				mockCreateSubnet(infrastructurev1beta2.OscSubnet{
+					IpSubnetRange: "10.0.2.0/24",
+					SubregionName: "eu-west-2a",
+					Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer, infrastructurev1beta2.RoleBastion, infrastructurev1beta2.RoleNat},
+				}, "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "subnet-public"),
+				mockGetInternetServiceForNet("vpc-foo", nil),
+				mockCreateInternetService("Internet Service for test-cluster-api", "9e1db9c4-bf0a-4583-8999-203ec002c520", "igw-foo"),
+				mockLinkInternetService("igw-foo", "vpc-foo"),
+
+				mockGetSecurityGroupFromName("test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Worker securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}, "sg-kw"),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.3.0/24", 10250, 10250),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 10250, 10250),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 443, 443),
+				mockCreateSecurityGroupRule("sg-kw", "Inbound", "tcp", "10.0.4.0/24", 1024, 65535),
+
+				mockGetSecurityGroupFromName("test-cluster-api-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Controlplane securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane}, "sg-kcp"),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.4.0/24", 10250, 10252),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.0.0/16", 6443, 6443),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.4.0/24", 2378, 2380),
+
+				mockGetSecurityGroupFromName("test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"LB securityGroup for test-cluster-api", "", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleLoadBalancer}, "sg-lb"),
+				mockCreateSecurityGroupRule("sg-lb", "Inbound", "tcp", "0.0.0.0/0", 6443, 6443),
+				mockCreateSecurityGroupRule("sg-lb", "Outbound", "tcp", "10.0.4.0/24", 6443, 6443),
+
+				mockGetSecurityGroupFromName("test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateSecurityGroup("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520",
+					"Node securityGroup for test-cluster-api", "OscK8sMainSG", []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane, infrastructurev1beta2.RoleWorker}, "sg-node"),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 179, 179),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 4789, 4789),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 5473, 5473),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 8285, 8285),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 51820, 51821),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "4", "10.0.0.0/16", -1, -1),
+
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "icmp", "10.0.0.0/16", 8, 8),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 4240, 4240),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 4244, 4244),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 8472, 8472),
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "udp", "10.0.0.0/16", 51871, 51871),
+
+				mockCreateSecurityGroupRule("sg-node", "Inbound", "tcp", "10.0.0.0/16", 30000, 32767),
+				mockCreateSecurityGroupRule("sg-node", "Outbound", "-1", "0.0.0.0/0", -1, -1),
+				mockCreateSecurityGroupRule("sg-node", "Outbound", "-1", "10.0.0.0/16", -1, -1),
+
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Public subnet for test-cluster-api/eu-west-2a", "rtb-public"),
+				mockLinkRouteTable("rtb-public", "subnet-public"),
+				mockCreateRoute("rtb-public", "0.0.0.0/0", "igw-foo", "gateway"),
+
+				mockGetNatServiceFromClientToken("eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreatePublicIp("Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-nat-2a", "1.2.3.4"),
+				mockCreateNatService("ipalloc-nat-2a", "subnet-public", "eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520", "Nat service for test-cluster-api/eu-west-2a", "9e1db9c4-bf0a-4583-8999-203ec002c520", "nat-foo"),
+
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{
+						RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}},
+						Routes: []osc.Route{{DestinationIpRange: "0.0.0.0/0", GatewayId: new("igw-foo")}},
+					},
+				}),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Controlplane subnet for test-cluster-api/eu-west-2a", "rtb-kcp"),
+				mockLinkRouteTable("rtb-kcp", "subnet-kcp"),
+				mockCreateRoute("rtb-kcp", "0.0.0.0/0", "nat-foo", "nat"),
+				mockCreateRouteTable("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520", "Worker subnet for test-cluster-api/eu-west-2a", "rtb-kw"),
+				mockLinkRouteTable("rtb-kw", "subnet-kw"),
+				mockCreateRoute("rtb-kw", "0.0.0.0/0", "nat-foo", "nat"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertHasClusterFinalizer(),
+				assertControlPlaneEndpoint("api.example.com", 443),
+				assertStatusClusterResources(infrastructurev1beta2.OscClusterResources{
+					Net: map[string]string{
+						"default": "vpc-foo",
+					},
+					Subnet: map[string]string{
+						"10.0.2.0/24": "subnet-public",
+						"10.0.3.0/24": "subnet-kw",
+						"10.0.4.0/24": "subnet-kcp",
+					},
+					InternetService: map[string]string{
+						"default": "igw-foo",
+					},
+					SecurityGroup: map[string]string{
+						"test-cluster-api-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520": "sg-kcp",
+						"test-cluster-api-worker-9e1db9c4-bf0a-4583-8999-203ec002c520":       "sg-kw",
+						"test-cluster-api-lb-9e1db9c4-bf0a-4583-8999-203ec002c520":           "sg-lb",
+						"test-cluster-api-node-9e1db9c4-bf0a-4583-8999-203ec002c520":         "sg-node",
+					},
+					NatService: map[string]string{
+						"eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520": "nat-foo",
+					},
+					PublicIPs: map[string]string{
+						"eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520": "ipalloc-nat-2a",
+					},
+				}),
+			},
+			next: &testcase{
+				name: "A second run has all references in cache",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_Multitenant(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	d := t.TempDir()
+	filepath := d + "tenant.json"
 	err := os.WriteFile(filepath, []byte(`{
   "default": {
     "access_key": "ak_default",
@@ -1436,15 +1892,18 @@ func TestReconcileOSCCluster_Multitenant(t *testing.T) {
 			clusterBaseSpec: "base",
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			tenantAsserts: []assertTenantFunc{
 				assertDefaultTenant(),
@@ -1474,15 +1933,18 @@ func TestReconcileOSCCluster_Multitenant(t *testing.T) {
 			},
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			tenantAsserts: []assertTenantFunc{
 				assertTenant("ak_secret", "sk_secret", "region_secret"),
@@ -1499,15 +1961,18 @@ func TestReconcileOSCCluster_Multitenant(t *testing.T) {
 			},
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			tenantAsserts: []assertTenantFunc{
 				assertTenant("ak_default", "sk_default", "region_default"),
@@ -1525,15 +1990,18 @@ func TestReconcileOSCCluster_Multitenant(t *testing.T) {
 			},
 			mockFuncs: []mockFunc{
 				mockNetFound("vpc-foo"),
+				mockTagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 
 				mockSubnetFound("subnet-kcp"),
+				mockTagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
 				mockSubnetFound("subnet-kw"),
+				mockTagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
 				mockSubnetFound("subnet-public"),
+				mockTagShared("subnet-public", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/loadbalancer"),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			tenantAsserts: []assertTenantFunc{
 				assertTenant("ak_alt", "sk_alt", "region_alt"),
@@ -1671,9 +2139,8 @@ func TestReconcileOSCCluster_Airgap(t *testing.T) {
 				mockCreateNetAccessPoint("vpc-foo", "api", "9e1db9c4-bf0a-4583-8999-203ec002c520", []string{"rtb-kw", "rtb-kcp"}),
 
 				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 		},
 		{
@@ -1747,47 +2214,1247 @@ func TestReconcileOSCCluster_Airgap(t *testing.T) {
 				mockGetRouteTablesFromNet("vpc-mgmt", []osc.RouteTable{
 					{RouteTableId: "rtb-mgmt"},
 				}),
-				mockGetNet("vpc-mgmt", &osc.Net{IpRange: "10.1.0.0/16"}),
-				mockCreateRoute("rtb-mgmt", "10.0.0.0/16", "np-foo", "netPeering"),
-				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
-					{RouteTableId: "rtb-public"},
-					{RouteTableId: "rtb-kcp"},
-					{RouteTableId: "rtb-kw"},
+				mockGetNet("vpc-mgmt", &osc.Net{IpRange: "10.1.0.0/16"}),
+				mockCreateRoute("rtb-mgmt", "10.0.0.0/16", "np-foo", "netPeering"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-public"},
+					{RouteTableId: "rtb-kcp"},
+					{RouteTableId: "rtb-kw"},
+				}),
+				mockCreateRoute("rtb-public", "10.1.0.0/16", "np-foo", "netPeering"),
+				mockCreateRoute("rtb-kcp", "10.1.0.0/16", "np-foo", "netPeering"),
+				mockCreateRoute("rtb-kw", "10.1.0.0/16", "np-foo", "netPeering"),
+
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}}},
+					{RouteTableId: "rtb-kcp", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kcp"}}},
+					{RouteTableId: "rtb-kw", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}},
+				}),
+				mockGetNetAccessPoint("vpc-foo", "api", &osc.NetAccessPoint{}),
+
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_Peerings(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	shared := infrastructurev1beta2.OscPeering{Name: "shared", AccountID: "123456789012", NetID: "vpc-shared"}
+	logging := infrastructurev1beta2.OscPeering{
+		Name:          "logging",
+		AccountID:     "210987654321",
+		NetID:         "vpc-logging",
+		PeerSubnetIDs: []string{"subnet-logs"},
+		Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+		AcceptPolicy:  infrastructurev1beta2.PeeringAcceptManual,
+	}
+	tcs := []testcase{
+		{
+			name:           "a peering is created, accepted and routed in both directions",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeerings(shared)},
+			mockFuncs: []mockFunc{
+				mockGetNetPeeringFromNet("vpc-foo", "vpc-shared", "123456789012", nil),
+				mockCreatePeering("vpc-foo", "vpc-shared", "123456789012", "9e1db9c4-bf0a-4583-8999-203ec002c520", "10.10.0.0/16"),
+				mockAcceptNetPeering(),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}}},
+					{RouteTableId: "rtb-kcp", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kcp"}}},
+					{RouteTableId: "rtb-kw", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}},
+				}),
+				mockCreateRoute("rtb-public", "10.10.0.0/16", "np-foo", "netPeering"),
+				mockCreateRoute("rtb-kcp", "10.10.0.0/16", "np-foo", "netPeering"),
+				mockCreateRoute("rtb-kw", "10.10.0.0/16", "np-foo", "netPeering"),
+				mockGetRouteTablesFromNet("vpc-shared", []osc.RouteTable{
+					{RouteTableId: "rtb-shared", Routes: []osc.Route{{DestinationIpRange: "10.10.0.0/16", GatewayId: new("local")}}},
+				}),
+				mockCreateRoute("rtb-shared", "10.0.0.0/16", "np-foo", "netPeering"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertPeering("shared", "np-foo", true),
+			},
+		},
+		{
+			name:           "a manual peering waits for acceptance, then only the selected subnets are routed",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeerings(logging)},
+			mockFuncs: []mockFunc{
+				mockGetNetPeeringFromNet("vpc-foo", "vpc-logging", "210987654321", nil),
+				mockCreatePeering("vpc-foo", "vpc-logging", "210987654321", "9e1db9c4-bf0a-4583-8999-203ec002c520", "10.20.0.0/16"),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertPeering("logging", "np-foo", false),
+			},
+			next: &testcase{
+				mockFuncs: []mockFunc{
+					mockGetNetPeering(osc.NetPeeringStateNameActive),
+					mockGetSubnet("subnet-logs", &osc.Subnet{SubnetId: "subnet-logs", IpRange: "10.20.1.0/24"}),
+					mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+						{RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}}},
+						{RouteTableId: "rtb-kcp", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kcp"}}},
+						{RouteTableId: "rtb-kw", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}},
+					}),
+					mockCreateRoute("rtb-kw", "10.20.1.0/24", "np-foo", "netPeering"),
+				},
+				clusterAsserts: []assertOSCClusterFunc{
+					assertPeering("logging", "np-foo", true),
+				},
+			},
+		},
+		{
+			name:           "a dead peering is recreated, and the routes to the dead peering are updated",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeerings(shared), patchPeeringId("shared", "np-old")},
+			mockFuncs: []mockFunc{
+				mockGetNetPeeringById("np-old", osc.NetPeeringStateNameDeleted),
+				mockCreatePeering("vpc-foo", "vpc-shared", "123456789012", "9e1db9c4-bf0a-4583-8999-203ec002c520", "10.10.0.0/16"),
+				mockAcceptNetPeering(),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}}, Routes: []osc.Route{{DestinationIpRange: "10.10.0.0/16", NetPeeringId: new("np-old")}}},
+					{RouteTableId: "rtb-kcp", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kcp"}}, Routes: []osc.Route{{DestinationIpRange: "10.10.0.0/16", NetPeeringId: new("np-old")}}},
+					{RouteTableId: "rtb-kw", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}},
+				}),
+				mockUpdatePeeringRoute("rtb-public", "10.10.0.0/16", "np-foo"),
+				mockUpdatePeeringRoute("rtb-kcp", "10.10.0.0/16", "np-foo"),
+				mockCreateRoute("rtb-kw", "10.10.0.0/16", "np-foo", "netPeering"),
+				mockGetRouteTablesFromNet("vpc-shared", []osc.RouteTable{
+					{RouteTableId: "rtb-shared", Routes: []osc.Route{
+						{DestinationIpRange: "10.10.0.0/16", GatewayId: new("local")},
+						{DestinationIpRange: "10.0.0.0/16", NetPeeringId: new("np-old")},
+					}},
+				}),
+				mockUpdatePeeringRoute("rtb-shared", "10.0.0.0/16", "np-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertPeering("shared", "np-foo", true),
+			},
+		},
+		{
+			name:           "a manual peering rejected by the peer account is not recreated",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeerings(logging), patchPeeringId("logging", "np-foo")},
+			mockFuncs: []mockFunc{
+				mockGetNetPeering(osc.NetPeeringStateNameRejected),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertPeering("logging", "np-foo", false),
+			},
+		},
+		{
+			name:           "a peering removed from the spec is deleted with its routes",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeeringId("shared", "np-foo"), patchPeeringReady("shared")},
+			mockFuncs: []mockFunc{
+				mockGetPeering("np-foo", "vpc-foo", "123456789012", "vpc-shared", "123456789012"),
+				mockGetRouteTablesFromNet("vpc-shared", []osc.RouteTable{
+					{RouteTableId: "rtb-shared", Routes: []osc.Route{
+						{DestinationIpRange: "10.10.0.0/16", GatewayId: new("local")},
+						{DestinationIpRange: "10.0.0.0/16", NetPeeringId: new("np-foo")},
+					}},
+				}),
+				mockDeleteRoute("rtb-shared", "10.0.0.0/16"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "10.10.0.0/16", NetPeeringId: new("np-foo")}}},
+				}),
+				mockDeleteRoute("rtb-kw", "10.10.0.0/16"),
+				mockDeleteNetPeering("np-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNoPeering("shared"),
+			},
+		},
+		{
+			name:           "a peering to another account removed from the spec is deleted, without touching the peer routes",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeeringId("logging", "np-foo")},
+			mockFuncs: []mockFunc{
+				mockGetPeering("np-foo", "vpc-foo", "123456789012", "vpc-logging", "210987654321"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "10.20.1.0/24", NetPeeringId: new("np-foo")}}},
+				}),
+				mockDeleteRoute("rtb-kw", "10.20.1.0/24"),
+				mockDeleteNetPeering("np-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNoPeering("logging"),
+			},
+		},
+		{
+			name:           "a peering and its routes are deleted with the cluster",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchPeerings(shared), patchPeeringId("shared", "np-foo"), patchDeleteCluster()},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockDeleteLoadBalancer("test-cluster-api-k8s"),
+
+				mockListNatServices("vpc-foo", nil),
+				mockPublicIpFound("ipalloc-nat", &osc.PublicIp{PublicIpId: "ipalloc-nat", Tags: []osc.ResourceTag{{Key: net.NoDeleteTag}}}),
+
+				mockListNetAccessPoints("vpc-foo", nil),
+
+				mockGetNetPeering(osc.NetPeeringStateNameActive),
+				mockGetRouteTablesFromNet("vpc-shared", []osc.RouteTable{
+					{RouteTableId: "rtb-shared", Routes: []osc.Route{
+						{DestinationIpRange: "10.10.0.0/16", GatewayId: new("local")},
+						{DestinationIpRange: "10.0.0.0/16", NetPeeringId: new("np-foo")},
+					}},
+				}),
+				mockDeleteRoute("rtb-shared", "10.0.0.0/16"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "10.10.0.0/16", NetPeeringId: new("np-foo")}}},
+				}),
+				mockDeleteRoute("rtb-kw", "10.10.0.0/16"),
+				mockDeleteNetPeering("np-foo"),
+
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockGetSecurityGroupsFromNet("vpc-foo", nil),
+				mockInternetServiceFound("vpc-foo", "igw-foo"),
+				mockUnlinkInternetService("igw-foo", "vpc-foo"),
+				mockDeleteInternetService("igw-foo"),
+
+				mockSubnetFound("subnet-public"),
+				mockDeleteSubnet("subnet-public"),
+				mockSubnetFound("subnet-kcp"),
+				mockDeleteSubnet("subnet-kcp"),
+				mockSubnetFound("subnet-kw"),
+				mockDeleteSubnet("subnet-kw"),
+				mockNetFound("vpc-foo"),
+				mockDeleteNet("vpc-foo"),
+			},
+			assertDeleted: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_RouteTargets(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	link := []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}
+	withGateway := []osc.RouteTable{{
+		RouteTableId:    "rtb-kw",
+		LinkRouteTables: link,
+		Routes: []osc.Route{
+			{DestinationIpRange: "0.0.0.0/0", GatewayId: new("igw-foo"), CreationMethod: "CreateRoute"},
+		},
+	}}
+	withExtraRoutes := []osc.RouteTable{{
+		RouteTableId:    "rtb-kw",
+		LinkRouteTables: link,
+		Routes: []osc.Route{
+			{DestinationIpRange: "10.0.0.0/16", GatewayId: new("local"), CreationMethod: "CreateRouteTable"},
+			{DestinationIpRange: "0.0.0.0/0", GatewayId: new("igw-foo"), CreationMethod: "CreateRoute"},
+			{DestinationIpRange: "192.168.0.0/16", VmId: new("i-old"), CreationMethod: "CreateRoute"},
+			{DestinationIpRange: "172.16.0.0/12", NicId: new("eni-manual"), CreationMethod: "CreateRoute"},
+		},
+	}}
+	tcs := []testcase{
+		{
+			name:        "routes to a vm and a nic are created",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchRouteTables(infrastructurev1beta2.OscRouteTable{
+				Name: "test-cluster-api-routetable-kw",
+				Role: infrastructurev1beta2.RoleWorker,
+				Routes: []infrastructurev1beta2.OscRoute{
+					{Destination: "0.0.0.0/0", TargetType: "gateway"},
+					{Destination: "192.168.0.0/16", TargetType: "vm", TargetId: "i-router"},
+					{Destination: "192.168.1.0/24", TargetType: "nic", TargetId: "eni-router"},
+				},
+			})},
+			mockFuncs: []mockFunc{
+				// route tables are first reconciled for nat subnets, then for all subnets
+				mockGetRouteTablesFromNet("vpc-foo", withGateway),
+				mockGetRouteTablesFromNet("vpc-foo", withGateway),
+				mockCreateRoute("rtb-kw", "192.168.0.0/16", "i-router", "vm"),
+				mockCreateRoute("rtb-kw", "192.168.1.0/24", "eni-router", "nic"),
+			},
+		},
+		{
+			name:        "an authoritative route table replaces routes with another target and deletes routes not in spec",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchRouteTables(infrastructurev1beta2.OscRouteTable{
+				Name:          "test-cluster-api-routetable-kw",
+				Role:          infrastructurev1beta2.RoleWorker,
+				Authoritative: true,
+				Routes: []infrastructurev1beta2.OscRoute{
+					{Destination: "0.0.0.0/0", TargetType: "gateway"},
+					{Destination: "192.168.0.0/16", TargetType: "vm", TargetId: "i-router"},
+				},
+			})},
+			mockFuncs: []mockFunc{
+				// route tables are first reconciled for nat subnets, then for all subnets
+				mockGetRouteTablesFromNet("vpc-foo", withExtraRoutes),
+				mockGetRouteTablesFromNet("vpc-foo", withExtraRoutes),
+				mockDeleteRoute("rtb-kw", "192.168.0.0/16"),
+				mockCreateRoute("rtb-kw", "192.168.0.0/16", "i-router", "vm"),
+				mockDeleteRoute("rtb-kw", "172.16.0.0/12"),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_SecurityGroupMembers(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	kcp := infrastructurev1beta2.OscSecurityGroup{
+		Name:  "test-cluster-api-controlplane",
+		Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+		SecurityGroupRules: []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRange: "10.0.0.0/16"},
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupRoles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker}},
+		},
+	}
+	kw := infrastructurev1beta2.OscSecurityGroup{
+		Name:  "test-cluster-api-worker",
+		Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleWorker},
+		SecurityGroupRules: []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupNames: []string{"test-cluster-api-controlplane"}, SecurityGroupIds: []string{"sg-other"}},
+		},
+	}
+	authoritativeKcp := kcp
+	authoritativeKcp.Authoritative = true
+	tcs := []testcase{
+		{
+			name:           "rules referencing security groups by name, role or id are created, only created rules are tracked",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchSecurityGroups(kcp, kw)},
+			mockFuncs: []mockFunc{
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRanges: []string{"10.0.0.0/16"}},
+					},
+				}),
+				mockGetSecurityGroup("sg-kw", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-other"}}},
+					},
+				}),
+				mockCreateSecurityGroupMemberRule("sg-kcp", "Inbound", "tcp", "sg-kw", 10250, 10250),
+				mockCreateSecurityGroupMemberRule("sg-kw", "Inbound", "tcp", "sg-kcp", 10250, 10250),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSecurityGroupMemberRules(map[string]string{
+					"sg-kcp/inbound/tcp/10250/10250/sg-kw": "sg-kw",
+					"sg-kw/inbound/tcp/10250/10250/sg-kcp": "sg-kcp",
+				}),
+			},
+		},
+		{
+			name:        "an authoritative security group only deletes member rules created by the cluster",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(authoritativeKcp, kw),
+				patchSecurityGroupMemberRules(map[string]string{
+					"sg-kcp/inbound/tcp/10250/10250/sg-kw": "sg-kw",
+					"sg-kcp/inbound/tcp/2379/2379/sg-kw":   "sg-kw",
+				}),
+			},
+			mockFuncs: []mockFunc{
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRanges: []string{"10.0.0.0/16"}},
+						{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-kw"}}},
+						{IpProtocol: "tcp", FromPortRange: 2379, ToPortRange: 2379, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-kw"}}},
+						{IpProtocol: "tcp", FromPortRange: 30000, ToPortRange: 32767, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-ccm"}}},
+					},
+				}),
+				mockGetSecurityGroup("sg-kw", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-other"}, {SecurityGroupId: "sg-kcp"}}},
+					},
+				}),
+				mockDeleteSecurityGroupRule("sg-kcp", "Inbound", "tcp", "", "sg-kw", 2379, 2379),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSecurityGroupMemberRules(map[string]string{
+					"sg-kcp/inbound/tcp/10250/10250/sg-kw": "sg-kw",
+				}),
+			},
+		},
+		{
+			name:           "member rules created by a partially failed batched creation are tracked",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchSecurityGroups(kcp, kw)},
+			mockFuncs: []mockFunc{
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{}),
+				mockGetSecurityGroup("sg-kw", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-other"}, {SecurityGroupId: "sg-kcp"}}},
+					},
+				}),
+				mockCreateSecurityGroupRule("sg-kcp", "Inbound", "tcp", "10.0.0.0/16", 6443, 6443),
+				mockCreateSecurityGroupMemberRule("sg-kcp", "Inbound", "tcp", "sg-kw", 10250, 10250),
+				mockCreateSecurityGroupRulesError("sg-kcp", "Inbound", errors.New("rule 10.0.0.0/16 tcp 6443-6443: [9999] Error")),
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 10250, ToPortRange: 10250, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: "sg-kw"}}},
+					},
+				}),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertSecurityGroupMemberRules(map[string]string{
+					"sg-kcp/inbound/tcp/10250/10250/sg-kw": "sg-kw",
+				}),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_CniProfile(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	kcp := infrastructurev1beta2.OscSecurityGroup{
+		Name:  "test-cluster-api-controlplane",
+		Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+		SecurityGroupRules: []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRange: "10.0.0.0/16"},
+		},
+	}
+	profile := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cni-profile",
+				Namespace: "cluster-api-test",
+			},
+			Data: map[string]string{
+				"rules": "- flow: Inbound\n  ipProtocol: udp\n  fromPortRange: 6081\n  toPortRange: 6081\n",
+			},
+		}
+	}
+	tcs := []testcase{
+		{
+			name:        "security groups are reconciled when the CNI profile is updated",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(kcp),
+				patchCniProfile("cni-profile", "0123456789abcdef"),
+				patchReconciled(infrastructurev1beta2.ReconcilerSecurityGroup),
+			},
+			kubeObjects: []client.Object{profile()},
+			mockFuncs: []mockFunc{
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRanges: []string{"10.0.0.0/16"}},
+					},
+				}),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertCniProfileHash("8bb5575651a3bdd5"),
+			},
+		},
+		{
+			name:        "security groups are not reconciled when the CNI profile is unchanged",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(kcp),
+				patchCniProfile("cni-profile", "8bb5575651a3bdd5"),
+				patchReconciled(infrastructurev1beta2.ReconcilerSecurityGroup),
+			},
+			kubeObjects: []client.Object{profile()},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertCniProfileHash("8bb5575651a3bdd5"),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_EgressDestinations(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+	lookupHost := scope.LookupHost
+	var lookups int
+	scope.LookupHost = func(_ context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"198.51.100.2"}, nil
+	}
+	defer func() { scope.LookupHost = lookupHost }()
+
+	kcp := infrastructurev1beta2.OscSecurityGroup{
+		Name:  "test-cluster-api-controlplane",
+		Roles: []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+		SecurityGroupRules: []infrastructurev1beta2.OscSecurityGroupRule{
+			{Flow: "Inbound", IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRange: "10.0.0.0/16"},
+		},
+		Authoritative: true,
+	}
+	egress := infrastructurev1beta2.OscEgressRule{
+		Roles:         []infrastructurev1beta2.OscRole{infrastructurev1beta2.RoleControlPlane},
+		IpProtocol:    "tcp",
+		FromPortRange: 443,
+		ToPortRange:   443,
+		Destinations:  []infrastructurev1beta2.OscEgressDestination{infrastructurev1beta2.EgressDestinationAPI},
+	}
+	tcs := []testcase{
+		{
+			name:        "egress rules are updated when a destination resolves to new IP ranges, previous ranges are kept",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(kcp),
+				patchEgressRules(egress),
+				patchEgressDestinations(map[string]string{"api": "198.51.100.1/32"}),
+				patchReconciled(infrastructurev1beta2.ReconcilerSecurityGroup),
+			},
+			mockFuncs: []mockFunc{
+				mockGetSecurityGroup("sg-kcp", &osc.SecurityGroup{
+					InboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 6443, ToPortRange: 6443, IpRanges: []string{"10.0.0.0/16"}},
+					},
+					OutboundRules: []osc.SecurityGroupRule{
+						{IpProtocol: "tcp", FromPortRange: 443, ToPortRange: 443, IpRanges: []string{"198.51.100.1/32"}},
+					},
+				}),
+				mockCreateSecurityGroupRule("sg-kcp", "Outbound", "tcp", "198.51.100.2/32", 443, 443),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertEgressDestinations(map[string]string{"api": "198.51.100.1/32,198.51.100.2/32"}),
+			},
+			requeue: true,
+			next: &testcase{
+				name: "destinations are not resolved again before the resolution interval",
+				clusterAsserts: []assertOSCClusterFunc{
+					func(t *testing.T, _ *infrastructurev1beta2.OscCluster) {
+						assert.Equal(t, 1, lookups)
+					},
+					assertEgressDestinations(map[string]string{"api": "198.51.100.1/32,198.51.100.2/32"}),
+				},
+				requeue: true,
+			},
+		},
+		{
+			name:        "security groups are not reconciled when the IP ranges of destinations are unchanged",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(kcp),
+				patchEgressRules(egress),
+				patchEgressDestinations(map[string]string{"api": "198.51.100.2/32"}),
+				patchReconciled(infrastructurev1beta2.ReconcilerSecurityGroup),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertEgressDestinations(map[string]string{"api": "198.51.100.2/32"}),
+			},
+			requeue: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+	t.Run("the IP ranges last applied are kept when a destination cannot be resolved", func(t *testing.T) {
+		scope.LookupHost = func(_ context.Context, host string) ([]string, error) {
+			return nil, errors.New("no such host")
+		}
+		runClusterTest(t, testcase{
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchSecurityGroups(kcp),
+				patchEgressRules(egress),
+				patchEgressDestinations(map[string]string{"api": "198.51.100.1/32"}),
+				patchReconciled(infrastructurev1beta2.ReconcilerSecurityGroup),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertEgressDestinations(map[string]string{"api": "198.51.100.1/32"}),
+			},
+			requeue: true,
+		})
+	})
+}
+
+func TestReconcileOSCCluster_DhcpOptions(t *testing.T) {
+	dhcp := infrastructurev1beta2.OscDhcpOptions{
+		DomainName:        "corp.example.com",
+		DomainNameServers: []string{"10.1.0.53"},
+	}
+	tcs := []testcase{
+		{
+			name:           "a dhcp options set is created and linked to the net",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDhcpOptions(dhcp)},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "default"}),
+				mockReadOwnedByTag(tag.DhcpOptionsResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateDhcpOptions(dhcp, "9e1db9c4-bf0a-4583-8999-203ec002c520", "dopt-foo"),
+				mockLinkDhcpOptions("dopt-foo", "vpc-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions("dopt-foo"),
+			},
+		},
+		{
+			name:           "nothing is done if the dhcp options set is up to date",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDhcpOptions(dhcp), patchDhcpOptionsId("dopt-foo")},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-foo"}),
+				mockGetDhcpOptions("dopt-foo", &osc.DhcpOptionsSet{
+					DhcpOptionsSetId:  new("dopt-foo"),
+					DomainName:        new("corp.example.com"),
+					DomainNameServers: &[]string{"10.1.0.53"},
+				}),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions("dopt-foo"),
+			},
+		},
+		{
+			name:        "servers listed in another order do not replace the dhcp options set",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchDhcpOptions(infrastructurev1beta2.OscDhcpOptions{
+					DomainName:        "corp.example.com",
+					DomainNameServers: []string{"10.1.0.53", "10.2.0.53"},
+					NtpServers:        []string{"10.1.0.123", "10.2.0.123"},
+				}),
+				patchDhcpOptionsId("dopt-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-foo"}),
+				mockGetDhcpOptions("dopt-foo", &osc.DhcpOptionsSet{
+					DhcpOptionsSetId:  new("dopt-foo"),
+					DomainName:        new("corp.example.com"),
+					DomainNameServers: &[]string{"10.2.0.53", "10.1.0.53"},
+					NtpServers:        &[]string{"10.2.0.123", "10.1.0.123"},
+				}),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions("dopt-foo"),
+			},
+		},
+		{
+			name:           "a dhcp options set having drifted is replaced",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDhcpOptions(dhcp), patchDhcpOptionsId("dopt-old")},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-old"}),
+				mockGetDhcpOptions("dopt-old", &osc.DhcpOptionsSet{
+					DhcpOptionsSetId:  new("dopt-old"),
+					DomainName:        new("corp.example.com"),
+					DomainNameServers: &[]string{net.OutscaleProvidedDNS},
+				}),
+				mockCreateDhcpOptions(dhcp, "9e1db9c4-bf0a-4583-8999-203ec002c520", "dopt-new"),
+				mockLinkDhcpOptions("dopt-new", "vpc-foo"),
+				mockDeleteDhcpOptions("dopt-old"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions("dopt-new"),
+			},
+		},
+		{
+			name:           "the replaced dhcp options set stays tracked until the new set is linked",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDhcpOptions(dhcp), patchDhcpOptionsId("dopt-old")},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-old"}),
+				mockGetDhcpOptions("dopt-old", &osc.DhcpOptionsSet{
+					DhcpOptionsSetId:  new("dopt-old"),
+					DomainName:        new("corp.example.com"),
+					DomainNameServers: &[]string{net.OutscaleProvidedDNS},
+				}),
+				mockCreateDhcpOptions(dhcp, "9e1db9c4-bf0a-4583-8999-203ec002c520", "dopt-new"),
+				mockLinkDhcpOptionsFails("dopt-new", "vpc-foo"),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions("dopt-new"),
+				assertPreviousDhcpOptions("dopt-old"),
+			},
+			next: &testcase{
+				mockFuncs: []mockFunc{
+					mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-old"}),
+					mockGetDhcpOptions("dopt-new", &osc.DhcpOptionsSet{
+						DhcpOptionsSetId:  new("dopt-new"),
+						DomainName:        new("corp.example.com"),
+						DomainNameServers: &[]string{"10.1.0.53"},
+					}),
+					mockLinkDhcpOptions("dopt-new", "vpc-foo"),
+					mockDeleteDhcpOptions("dopt-old"),
+				},
+				clusterAsserts: []assertOSCClusterFunc{
+					assertDhcpOptions("dopt-new"),
+					assertPreviousDhcpOptions(""),
+				},
+			},
+		},
+		{
+			name:        "the default dhcp options are restored when dhcp options are removed from spec",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchDhcpOptions(infrastructurev1beta2.OscDhcpOptions{}),
+				patchDhcpOptionsId("dopt-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-foo"}),
+				mockGetDhcpOptions("dopt-foo", &osc.DhcpOptionsSet{DhcpOptionsSetId: new("dopt-foo")}),
+				mockLinkDhcpOptions("default", "vpc-foo"),
+				mockDeleteDhcpOptions("dopt-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertDhcpOptions(""),
+			},
+		},
+		{
+			name:        "the default dhcp options are restored before deleting the net",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchDhcpOptions(dhcp),
+				patchDhcpOptionsId("dopt-foo"),
+				patchDeleteCluster(),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockDeleteLoadBalancer("test-cluster-api-k8s"),
+
+				mockListNatServices("vpc-foo", nil),
+				mockPublicIpFound("ipalloc-nat", &osc.PublicIp{PublicIpId: "ipalloc-nat", Tags: []osc.ResourceTag{{Key: net.NoDeleteTag}}}),
+
+				mockListNetAccessPoints("vpc-foo", nil),
+
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockGetSecurityGroupsFromNet("vpc-foo", nil),
+				mockInternetServiceFound("vpc-foo", "igw-foo"),
+				mockUnlinkInternetService("igw-foo", "vpc-foo"),
+				mockDeleteInternetService("igw-foo"),
+
+				mockSubnetFound("subnet-public"),
+				mockDeleteSubnet("subnet-public"),
+				mockSubnetFound("subnet-kcp"),
+				mockDeleteSubnet("subnet-kcp"),
+				mockSubnetFound("subnet-kw"),
+				mockDeleteSubnet("subnet-kw"),
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", DhcpOptionsSetId: "dopt-foo"}),
+				mockGetDhcpOptions("dopt-foo", &osc.DhcpOptionsSet{DhcpOptionsSetId: new("dopt-foo")}),
+				mockLinkDhcpOptions("default", "vpc-foo"),
+				mockDeleteDhcpOptions("dopt-foo"),
+				mockDeleteNet("vpc-foo"),
+			},
+			assertDeleted: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_Vpn(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	paris := infrastructurev1beta2.OscVpnConnection{Name: "paris", ClientGatewayIP: "198.51.100.10", StaticRoutes: []string{"192.168.0.0/16"}}
+	tcs := []testcase{
+		{
+			name:        "a virtual gateway is created and linked, and on-premises networks are routed through a VPN connection",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchVpn(infrastructurev1beta2.OscVpn{
+				Enable:      true,
+				Connections: []infrastructurev1beta2.OscVpnConnection{paris},
+			})},
+			mockFuncs: []mockFunc{
+				mockReadOwnedByTag(tag.VirtualGatewayResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateVirtualGateway("9e1db9c4-bf0a-4583-8999-203ec002c520", "vgw-foo"),
+				mockLinkVirtualGateway("vgw-foo", "vpc-foo"),
+				mockGetClientGatewayFor("198.51.100.10", 65000, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockCreateClientGateway("198.51.100.10", 65000, "test-cluster-api-paris", "9e1db9c4-bf0a-4583-8999-203ec002c520", "cgw-paris"),
+				mockGetVpnConnectionFor("cgw-paris", "vgw-foo", nil),
+				mockCreateVpnConnection("cgw-paris", "vgw-foo", "test-cluster-api-paris", "9e1db9c4-bf0a-4583-8999-203ec002c520", "vpn-paris"),
+				mockCreateVpnConnectionRoute("vpn-paris", "192.168.0.0/16"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-public"},
+					{RouteTableId: "rtb-kcp"},
+					{RouteTableId: "rtb-kw"},
+				}),
+				mockCreateRoute("rtb-public", "192.168.0.0/16", "vgw-foo", "virtualGateway"),
+				mockCreateRoute("rtb-kcp", "192.168.0.0/16", "vgw-foo", "virtualGateway"),
+				mockCreateRoute("rtb-kw", "192.168.0.0/16", "vgw-foo", "virtualGateway"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertVpn("vgw-foo", "paris", "cgw-paris", "vpn-paris"),
+			},
+		},
+		{
+			name:        "an existing virtual gateway is used, and routes no longer in spec are removed",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchVpn(infrastructurev1beta2.OscVpn{
+					Enable:           true,
+					VirtualGatewayID: "vgw-existing",
+					Connections:      []infrastructurev1beta2.OscVpnConnection{paris},
+				}),
+				patchVpnIds("", "paris", "cgw-paris", "vpn-paris"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVirtualGateway("vgw-existing", "vpc-foo"),
+				mockGetClientGateway("cgw-paris"),
+				mockGetVpnConnection("vpn-paris", "192.168.0.0/16", "10.99.0.0/16"),
+				mockDeleteVpnConnectionRoute("vpn-paris", "10.99.0.0/16"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kcp"},
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{
+						{DestinationIpRange: "192.168.0.0/16", GatewayId: new("vgw-existing")},
+						{DestinationIpRange: "10.99.0.0/16", GatewayId: new("vgw-existing")},
+					}},
+				}),
+				mockCreateRoute("rtb-kcp", "192.168.0.0/16", "vgw-existing", "virtualGateway"),
+				mockDeleteRoute("rtb-kw", "10.99.0.0/16"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertVpn("vgw-existing", "paris", "cgw-paris", "vpn-paris"),
+			},
+		},
+		{
+			name:        "a VPN connection removed from the spec is deleted",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchVpn(infrastructurev1beta2.OscVpn{
+					Enable: true,
+				}),
+				patchVpnIds("vgw-foo", "paris", "cgw-paris", "vpn-paris"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVirtualGateway("vgw-foo", "vpc-foo"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw"},
+				}),
+				mockGetVpnConnection("vpn-paris", "192.168.0.0/16"),
+				mockDeleteVpnConnection("vpn-paris"),
+				mockGetClientGateway("cgw-paris"),
+				mockDeleteClientGateway("cgw-paris"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNoVpnConnection("paris"),
+			},
+		},
+		{
+			name:        "disabling the VPN deletes the VPN connections, the routes and the virtual gateway",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchVpnIds("vgw-foo", "paris", "cgw-paris", "vpn-paris"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVpnConnection("vpn-paris", "192.168.0.0/16"),
+				mockDeleteVpnConnection("vpn-paris"),
+				mockGetClientGateway("cgw-paris"),
+				mockDeleteClientGateway("cgw-paris"),
+				mockGetVirtualGatewayOwnedBy("vgw-foo", "vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "192.168.0.0/16", GatewayId: new("vgw-foo")}}},
+				}),
+				mockDeleteRoute("rtb-kw", "192.168.0.0/16"),
+				mockUnlinkVirtualGateway("vgw-foo", "vpc-foo"),
+				mockDeleteVirtualGateway("vgw-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNoVpn(),
+			},
+		},
+		{
+			name:        "disabling the VPN unlinks an existing virtual gateway without deleting it",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchVpnIds("vgw-existing", "paris", "cgw-paris", "vpn-paris"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVpnConnection("vpn-paris", "192.168.0.0/16"),
+				mockDeleteVpnConnection("vpn-paris"),
+				mockGetClientGateway("cgw-paris"),
+				mockDeleteClientGateway("cgw-paris"),
+				mockGetVirtualGateway("vgw-existing", "vpc-foo"),
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockUnlinkVirtualGateway("vgw-existing", "vpc-foo"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNoVpn(),
+			},
+		},
+		{
+			name:        "the VPN connections are deleted, and the virtual gateway unlinked and deleted with the cluster",
+			clusterSpec: "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{
+				patchVpn(infrastructurev1beta2.OscVpn{
+					Enable:      true,
+					Connections: []infrastructurev1beta2.OscVpnConnection{paris},
+				}),
+				patchVpnIds("vgw-foo", "paris", "cgw-paris", "vpn-paris"),
+				patchDeleteCluster(),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockDeleteLoadBalancer("test-cluster-api-k8s"),
+
+				mockListNatServices("vpc-foo", nil),
+				mockPublicIpFound("ipalloc-nat", &osc.PublicIp{PublicIpId: "ipalloc-nat", Tags: []osc.ResourceTag{{Key: net.NoDeleteTag}}}),
+
+				mockListNetAccessPoints("vpc-foo", nil),
+
+				mockGetVirtualGateway("vgw-foo", "vpc-foo"),
+				mockGetClientGateway("cgw-paris"),
+				mockGetVpnConnection("vpn-paris", "192.168.0.0/16"),
+				mockDeleteVpnConnection("vpn-paris"),
+				mockDeleteClientGateway("cgw-paris"),
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "192.168.0.0/16", GatewayId: new("vgw-foo")}}},
+				}),
+				mockDeleteRoute("rtb-kw", "192.168.0.0/16"),
+				mockUnlinkVirtualGateway("vgw-foo", "vpc-foo"),
+				mockDeleteVirtualGateway("vgw-foo"),
+
+				mockGetRouteTablesFromNet("vpc-foo", nil),
+				mockGetSecurityGroupsFromNet("vpc-foo", nil),
+				mockInternetServiceFound("vpc-foo", "igw-foo"),
+				mockUnlinkInternetService("igw-foo", "vpc-foo"),
+				mockDeleteInternetService("igw-foo"),
+
+				mockSubnetFound("subnet-public"),
+				mockDeleteSubnet("subnet-public"),
+				mockSubnetFound("subnet-kcp"),
+				mockDeleteSubnet("subnet-kcp"),
+				mockSubnetFound("subnet-kw"),
+				mockDeleteSubnet("subnet-kw"),
+				mockNetFound("vpc-foo"),
+				mockDeleteNet("vpc-foo"),
+			},
+			assertDeleted: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
+
+func TestReconcileOSCCluster_Update(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	healthCheck04 := osc.HealthCheck{
+		CheckInterval: 5, HealthyThreshold: 5, UnhealthyThreshold: 2, Timeout: 5, Port: 6443, Protocol: "TCP",
+	}
+
+	tcs := []testcase{
+		{
+			name:            "reconciliation on a reconciled cluster does nothing",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+		},
+		{
+			name:            "A loadBalancer having drifted is updated in place",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFoundWith(&osc.LoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s",
+					DnsName:          "test-cluster-api-k8s.lbu.outscale.com",
+					Tags:             []osc.ResourceTag{{Key: tag.NameKey, Value: "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"}},
+					HealthCheck: osc.HealthCheck{
+						CheckInterval: 30, HealthyThreshold: 2, UnhealthyThreshold: 3, Timeout: 10, Port: 6443, Protocol: "TCP",
+					},
+					Listeners: []osc.Listener{
+						{BackendPort: 8443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP"},
+						{BackendPort: 80, BackendProtocol: "HTTP", LoadBalancerPort: 80, LoadBalancerProtocol: "HTTP"},
+					},
+					SecurityGroups: []string{"sg-old"},
+				}),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+				mockDeleteLoadBalancerListeners("test-cluster-api-k8s", []int{6443}),
+				mockCreateLoadBalancerListeners("test-cluster-api-k8s", []infrastructurev1beta2.OscLoadBalancerListener{{
+					BackendPort: 6443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP",
+				}}),
+				mockDeleteLoadBalancerListeners("test-cluster-api-k8s", []int{80}),
+				mockUpdateLoadBalancerSecurityGroups("test-cluster-api-k8s", []string{"sg-lb"}),
+			},
+		},
+		{
+			name:            "A replaced listener is restored if the new one cannot be created",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFoundWith(&osc.LoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s",
+					DnsName:          "test-cluster-api-k8s.lbu.outscale.com",
+					Tags:             []osc.ResourceTag{{Key: tag.NameKey, Value: "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"}},
+					HealthCheck: osc.HealthCheck{
+						CheckInterval: 10, HealthyThreshold: 2, UnhealthyThreshold: 3, Timeout: 10, Port: 6443, Protocol: "TCP",
+					},
+					Listeners: []osc.Listener{
+						{BackendPort: 8443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP"},
+						{BackendPort: 80, BackendProtocol: "HTTP", LoadBalancerPort: 80, LoadBalancerProtocol: "HTTP"},
+					},
+					SecurityGroups: []string{"sg-lb"},
+				}),
+				mockDeleteLoadBalancerListeners("test-cluster-api-k8s", []int{6443}),
+				mockCreateLoadBalancerListenersFail("test-cluster-api-k8s", []infrastructurev1beta2.OscLoadBalancerListener{{
+					BackendPort: 6443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP",
+				}}),
+				mockCreateLoadBalancerListeners("test-cluster-api-k8s", []infrastructurev1beta2.OscLoadBalancerListener{{
+					BackendPort: 8443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP",
+				}}),
+			},
+			hasError: true,
+		},
+		{
+			name:            "A created loadBalancer missing its name tag is tagged",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockCreateLoadBalancerReturningTags("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb", "",
+					"test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520", []osc.ResourceTag{{Key: "foo", Value: "bar"}}),
+				mockConfigureHealthCheck("test-cluster-api-k8s"),
+				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+		},
+		{
+			name:            "A public IP from a pool is associated with an existing loadBalancer",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchLoadBalancerPublicIp("pool-foo"),
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockListPublicIpsFromPool("pool-foo", []osc.PublicIp{{PublicIpId: "ipalloc-lb", PublicIp: "192.0.2.10"}}),
+				mockUpdateLoadBalancerPublicIp("test-cluster-api-k8s", "192.0.2.10"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertPublicIps(map[string]string{
+					"eu-west-2a-9e1db9c4-bf0a-4583-8999-203ec002c520": "ipalloc-nat",
+					"loadbalancer": "ipalloc-lb",
+				}),
+			},
+		},
+		{
+			name:            "An HTTPS listener is added with a server certificate uploaded from a Secret",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchLoadBalancerAdditionalListeners(infrastructurev1beta2.OscLoadBalancerListener{
+					BackendPort: 6443, BackendProtocol: "HTTPS", LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS", ServerCertificateSecretName: "api-tls",
+				}),
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			kubeObjects: []client.Object{newTLSSecret("api-tls", "cert", "key")},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockListServerCertificates("/9e1db9c4-bf0a-4583-8999-203ec002c520/", nil),
+				mockCreateServerCertificate("test-cluster-api-k8s-443-06298432e8066b29", "/9e1db9c4-bf0a-4583-8999-203ec002c520/", "cert", "key"),
+				mockCreateLoadBalancerListenersWithServerCertificates("test-cluster-api-k8s", []infrastructurev1beta2.OscLoadBalancerListener{{
+					BackendPort: 6443, BackendProtocol: "HTTPS", LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS", ServerCertificateSecretName: "api-tls",
+				}}, map[int32]string{
+					443: "orn:ows:idauth::012345678910:server-certificate/9e1db9c4-bf0a-4583-8999-203ec002c520/test-cluster-api-k8s-443-06298432e8066b29",
+				}),
+				mockListServerCertificates("/9e1db9c4-bf0a-4583-8999-203ec002c520/", []osc.ServerCertificate{
+					{Name: lo.ToPtr("test-cluster-api-k8s-443-06298432e8066b29")},
+				}),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertServerCertificates(map[string]string{"test-cluster-api-k8s/443": "test-cluster-api-k8s-443-06298432e8066b29"}),
+			},
+		},
+		{
+			name:            "A rotated server certificate is uploaded again and the old one is deleted",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchLoadBalancerAdditionalListeners(infrastructurev1beta2.OscLoadBalancerListener{
+					BackendPort: 6443, BackendProtocol: "HTTPS", LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS", ServerCertificateSecretName: "api-tls",
+				}),
+				patchServerCertificate("test-cluster-api-k8s/443", "test-cluster-api-k8s-443-06298432e8066b29"),
+			},
+			kubeObjects: []client.Object{newTLSSecret("api-tls", "cert2", "key2")},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFoundWith(&osc.LoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s",
+					DnsName:          "test-cluster-api-k8s.lbu.outscale.com",
+					Tags:             []osc.ResourceTag{{Key: tag.NameKey, Value: "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"}},
+					HealthCheck: osc.HealthCheck{
+						CheckInterval: 10, HealthyThreshold: 2, UnhealthyThreshold: 3, Timeout: 10, Port: 6443, Protocol: "TCP",
+					},
+					Listeners: []osc.Listener{
+						{BackendPort: 6443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP"},
+						{
+							BackendPort: 6443, BackendProtocol: "HTTPS", LoadBalancerPort: 443, LoadBalancerProtocol: "HTTPS",
+							ServerCertificateId: lo.ToPtr("orn:ows:idauth::012345678910:server-certificate/9e1db9c4-bf0a-4583-8999-203ec002c520/test-cluster-api-k8s-443-06298432e8066b29"),
+						},
+					},
+					SecurityGroups: []string{"sg-lb"},
+				}),
+				mockListServerCertificates("/9e1db9c4-bf0a-4583-8999-203ec002c520/", []osc.ServerCertificate{
+					{Name: lo.ToPtr("test-cluster-api-k8s-443-06298432e8066b29")},
+				}),
+				mockCreateServerCertificate("test-cluster-api-k8s-443-cdf9e092139ce788", "/9e1db9c4-bf0a-4583-8999-203ec002c520/", "cert2", "key2"),
+				mockUpdateLoadBalancerServerCertificate("test-cluster-api-k8s", 443,
+					"orn:ows:idauth::012345678910:server-certificate/9e1db9c4-bf0a-4583-8999-203ec002c520/test-cluster-api-k8s-443-cdf9e092139ce788"),
+				mockListServerCertificates("/9e1db9c4-bf0a-4583-8999-203ec002c520/", []osc.ServerCertificate{
+					{Name: lo.ToPtr("test-cluster-api-k8s-443-06298432e8066b29")},
+					{Name: lo.ToPtr("test-cluster-api-k8s-443-cdf9e092139ce788")},
+				}),
+				mockDeleteServerCertificate("test-cluster-api-k8s-443-06298432e8066b29"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertServerCertificates(map[string]string{"test-cluster-api-k8s/443": "test-cluster-api-k8s-443-cdf9e092139ce788"}),
+			},
+		},
+		{
+			name:            "Access logs are enabled on an existing loadBalancer",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchLoadBalancerAccessLog(infrastructurev1beta2.OscLoadBalancerAccessLog{Bucket: "logs", Prefix: "k8s", Interval: 5}),
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockUpdateLoadBalancerAccessLog("test-cluster-api-k8s", infrastructurev1beta2.OscLoadBalancerAccessLog{Bucket: "logs", Prefix: "k8s", Interval: 5}),
+			},
+		},
+		{
+			name:            "An additional loadBalancer may be used as control plane endpoint",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchAdditionalLoadBalancers(infrastructurev1beta2.OscAdditionalLoadBalancer{
+					LoadBalancerName:          "test-cluster-api-k8s-public",
+					BackendRole:               infrastructurev1beta2.RoleControlPlane,
+					Listeners:                 []infrastructurev1beta2.OscLoadBalancerListener{{LoadBalancerPort: 6443}},
+					UseAsControlPlaneEndpoint: true,
+				}),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s-public", "test-cluster-api-k8s-public-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertControlPlaneEndpoint("test-cluster-api-k8s-public.lbu.outscale.com", 6443),
+				assertAdditionalLoadBalancers(map[string]string{"test-cluster-api-k8s-public": "test-cluster-api-k8s-public.lbu.outscale.com"}),
+			},
+		},
+		{
+			name:            "An additional listener is added to the API loadBalancer",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchLoadBalancerAdditionalListeners(infrastructurev1beta2.OscLoadBalancerListener{
+					BackendPort: 8132, BackendProtocol: "TCP", LoadBalancerPort: 8132, LoadBalancerProtocol: "TCP",
 				}),
-				mockCreateRoute("rtb-public", "10.1.0.0/16", "np-foo", "netPeering"),
-				mockCreateRoute("rtb-kcp", "10.1.0.0/16", "np-foo", "netPeering"),
-				mockCreateRoute("rtb-kw", "10.1.0.0/16", "np-foo", "netPeering"),
-
-				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
-					{RouteTableId: "rtb-public", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-public"}}},
-					{RouteTableId: "rtb-kcp", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kcp"}}},
-					{RouteTableId: "rtb-kw", LinkRouteTables: []osc.LinkRouteTable{{SubnetId: "subnet-kw"}}},
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockCreateLoadBalancerListeners("test-cluster-api-k8s", []infrastructurev1beta2.OscLoadBalancerListener{{
+					BackendPort: 8132, BackendProtocol: "TCP", LoadBalancerPort: 8132, LoadBalancerProtocol: "TCP",
+				}}),
+			},
+		},
+		{
+			name:            "An additional loadBalancer is created and tracked",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchAdditionalLoadBalancers(infrastructurev1beta2.OscAdditionalLoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s-ingress",
+					BackendRole:      infrastructurev1beta2.RoleWorker,
+					Listeners:        []infrastructurev1beta2.OscLoadBalancerListener{{LoadBalancerPort: 443, BackendPort: 30443}},
 				}),
-				mockGetNetAccessPoint("vpc-foo", "api", &osc.NetAccessPoint{}),
-
-				mockGetLoadBalancer("test-cluster-api-k8s", nil),
-				mockCreateLoadBalancer("test-cluster-api-k8s", "internet-facing", "subnet-public", "sg-lb"),
-				mockConfigureHealthCheck("test-cluster-api-k8s"),
-				mockCreateLoadBalancerTag("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s-ingress", nil),
+				mockCreateLoadBalancer("test-cluster-api-k8s-ingress", "internet-facing", "subnet-public", "sg-lb", "test-cluster-api-k8s-ingress-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockConfigureHealthCheck("test-cluster-api-k8s-ingress"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertAdditionalLoadBalancers(map[string]string{"test-cluster-api-k8s-ingress": "test-cluster-api-k8s-ingress.outscale.dev"}),
 			},
 		},
-	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			runClusterTest(t, tc)
-		})
-	}
-}
-
-func TestReconcileOSCCluster_Update(t *testing.T) {
-	// disable random reconciliation of security groups
-	scope.Rand = func() int { return 100 }
-
-	tcs := []testcase{
 		{
-			name:            "reconciliation on a reconciled cluster does nothing",
+			name:            "An additional loadBalancer removed from the spec is deleted",
 			clusterSpec:     "ready-1.0",
 			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchAdditionalLoadBalancerTracked("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress.lbu.outscale.com"),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockDeleteLoadBalancer("test-cluster-api-k8s-ingress"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertAdditionalLoadBalancers(nil),
+			},
+		},
+		{
+			name:            "A removed additional loadBalancer is not deleted when the loadBalancer step fails",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchReconcile(infrastructurev1beta2.ReconcilerLoadbalancer),
+				patchAdditionalLoadBalancerTracked("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress.lbu.outscale.com"),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-other-uid"),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertAdditionalLoadBalancers(map[string]string{"test-cluster-api-k8s-ingress": "test-cluster-api-k8s-ingress.lbu.outscale.com"}),
+			},
+		},
+		{
+			name:            "A removed additional loadBalancer belonging to another cluster is not deleted",
+			clusterSpec:     "ready-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{
+				patchAdditionalLoadBalancerTracked("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress.lbu.outscale.com"),
+			},
+			mockFuncs: []mockFunc{
+				mockLoadBalancerFound("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress-other-uid"),
+			},
+			clusterAsserts: []assertOSCClusterFunc{
+				assertAdditionalLoadBalancers(nil),
+			},
 		},
 		{
 			name:        "An inbound rule may be added to a 0.4 cluster (IpRange)",
@@ -1876,7 +3543,7 @@ func TestReconcileOSCCluster_Update(t *testing.T) {
 					},
 				}),
 
-				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLoadBalancerFoundWithConfig("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520", healthCheck04, "sg-7eb16ccb"),
 			},
 		},
 		{
@@ -1966,7 +3633,7 @@ func TestReconcileOSCCluster_Update(t *testing.T) {
 					},
 				}),
 
-				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLoadBalancerFoundWithConfig("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520", healthCheck04, "sg-7eb16ccb"),
 			},
 		},
 		{
@@ -2057,7 +3724,7 @@ func TestReconcileOSCCluster_Update(t *testing.T) {
 					},
 				}),
 
-				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLoadBalancerFoundWithConfig("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520", healthCheck04, "sg-7eb16ccb"),
 			},
 		},
 		{
@@ -2438,7 +4105,7 @@ func TestReconcileOSCCluster_Update(t *testing.T) {
 					},
 				}),
 
-				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLoadBalancerFoundWithConfig("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520", healthCheck04, "sg-7eb16ccb"),
 			},
 			clusterAsserts: []assertOSCClusterFunc{
 				// All other resources have a ResourceId field, no need to store a ref in status.
@@ -2828,6 +4495,61 @@ func TestReconcileOSCCluster_Delete(t *testing.T) {
 			},
 			assertDeleted: true,
 		},
+		{
+			name:           "Deleting a v1.0 cluster with an existing load balancer",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchDeleteCluster(), patchUseExistingLoadBalancer()},
+			mockFuncs: []mockFunc{
+				mockListNatServices("vpc-foo", []osc.NatService{{
+					NatServiceId: "nat-223a4dd4",
+					PublicIps: []osc.PublicIpLight{{
+						PublicIpId: "ipalloc-nat",
+					}},
+				}}),
+				mockDeleteNatService("nat-223a4dd4"),
+				mockPublicIpFound("ipalloc-nat"),
+				mockDeletePublicIp("ipalloc-nat"),
+
+				mockGetRouteTablesFromNet("vpc-foo", []osc.RouteTable{
+					{RouteTableId: "rtb-0a4640a6", LinkRouteTables: []osc.LinkRouteTable{{LinkRouteTableId: "rtbassoc-643430b3", SubnetId: "subnet-1555ea91"}}},
+					{RouteTableId: "rtb-194c971e", LinkRouteTables: []osc.LinkRouteTable{{LinkRouteTableId: "rtbassoc-09475c37", SubnetId: "subnet-c1a282b0"}}},
+					{RouteTableId: "rtb-eeacfe8a", LinkRouteTables: []osc.LinkRouteTable{{LinkRouteTableId: "rtbassoc-90bda9c8", SubnetId: "subnet-174f5ec4"}}},
+				}),
+				mockUnlinkRouteTable("rtbassoc-643430b3"),
+				mockDeleteRouteTable("rtb-0a4640a6"),
+				mockUnlinkRouteTable("rtbassoc-09475c37"),
+				mockDeleteRouteTable("rtb-194c971e"),
+				mockUnlinkRouteTable("rtbassoc-90bda9c8"),
+				mockDeleteRouteTable("rtb-eeacfe8a"),
+
+				mockGetSecurityGroupsFromNet("vpc-foo", []osc.SecurityGroup{
+					{
+						SecurityGroupId: "sg-a093d014", InboundRules: []osc.SecurityGroupRule{{}, {}}, OutboundRules: []osc.SecurityGroupRule{{}},
+					},
+					{
+						SecurityGroupId: "sg-750ae810", InboundRules: []osc.SecurityGroupRule{{}}, OutboundRules: []osc.SecurityGroupRule{{}},
+					},
+				}),
+				mockDeleteSecurityGroup("sg-a093d014", nil),
+				mockDeleteSecurityGroup("sg-750ae810", nil),
+
+				mockInternetServiceFound("vpc-foo", "igw-foo"),
+				mockUnlinkInternetService("igw-foo", "vpc-foo"),
+				mockDeleteInternetService("igw-foo"),
+
+				mockListNetAccessPoints("vpc-foo", nil),
+
+				mockSubnetFound("subnet-public"),
+				mockDeleteSubnet("subnet-public"),
+				mockSubnetFound("subnet-kcp"),
+				mockDeleteSubnet("subnet-kcp"),
+				mockSubnetFound("subnet-kw"),
+				mockDeleteSubnet("subnet-kw"),
+				mockNetFound("vpc-foo"),
+				mockDeleteNet("vpc-foo"),
+			},
+			assertDeleted: true,
+		},
 		{
 			name:           "A NAT public IP is not deleted if properly tagged",
 			clusterSpec:    "ready-1.0",
@@ -2896,6 +4618,28 @@ func TestReconcileOSCCluster_Delete(t *testing.T) {
 			},
 			assertDeleted: true,
 		},
+		{
+			name:           "Deleting a cluster releases its net pool allocation",
+			clusterSpec:    "base-0.4",
+			clusterPatches: []patchOSCClusterFunc{patchNetPool("pool"), patchDeleteCluster()},
+			kubeObjects: []client.Object{
+				newNetPool("pool", "10.0.0.0/8", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.0.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "test-cluster-api", UID: "9e1db9c4-bf0a-4583-8999-203ec002c520"},
+					"10.1.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockReadOwnedByTag(tag.NetResourceType, "9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.NetResourceType, "test-cluster-api-net-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			kubeAsserts: []assertKubeFunc{
+				assertNetPoolAllocations("pool", map[string]infrastructurev1beta2.OscNetPoolAllocation{
+					"10.1.0.0/16": infrastructurev1beta2.OscNetPoolAllocation{Namespace: "cluster-api-test", Name: "other-cluster", UID: "other-cluster-uid"},
+				}),
+			},
+			assertDeleted: true,
+		},
 		{
 			name:            "An airgapped cluster is deleted even if no resource have been created",
 			clusterSpec:     "airgap-1.0",
@@ -3033,6 +4777,92 @@ func TestReconcileOSCCluster_Delete(t *testing.T) {
 			mockFuncs: []mockFunc{
 				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-k8s-9e1db9c4-bf0a-4583-8999-203ec002c520"),
 				mockDeleteLoadBalancer("test-cluster-api-k8s"),
+				mockSubnetFound("subnet-c1a282b0"),
+				mockSubnetFound("subnet-1555ea91"),
+				mockSubnetFound("subnet-174f5ec4"),
+				mockNetFound("vpc-24ba90ce"),
+			},
+			assertDeleted: true,
+		},
+		{
+			name:            "Deleting a cluster with allocated subnets deletes them",
+			clusterSpec:     "reuse-all-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchSubnetAllocation(26), patchDeleteCluster()},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockGetSubnetsFromNet("vpc-foo", []osc.Subnet{
+					{SubnetId: "subnet-kw", IpRange: "10.0.1.0/26", SubregionName: "eu-west-2a", Tags: []osc.ResourceTag{
+						{Key: "OscK8sClusterID/9e1db9c4-bf0a-4583-8999-203ec002c520", Value: "owned"}, {Key: "Name", Value: "Worker subnet for test-cluster-api/eu-west-2a"}, {Key: "OscK8sRole/worker"},
+					}},
+					{SubnetId: "subnet-kcp", IpRange: "10.0.1.64/26", SubregionName: "eu-west-2a", Tags: []osc.ResourceTag{
+						{Key: "OscK8sClusterID/9e1db9c4-bf0a-4583-8999-203ec002c520", Value: "owned"}, {Key: "Name", Value: "Controlplane subnet for test-cluster-api/eu-west-2a"}, {Key: "OscK8sRole/controlplane"},
+					}},
+				}),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.0/26", &osc.Subnet{SubnetId: "subnet-kw"}),
+				mockDeleteSubnet("subnet-kw"),
+				mockGetSubnetFromNet("vpc-foo", "10.0.1.64/26", &osc.Subnet{SubnetId: "subnet-kcp"}),
+				mockDeleteSubnet("subnet-kcp"),
+				mockGetNet("vpc-foo", &osc.Net{NetId: "vpc-foo", Tags: sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520")}),
+				mockUntagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			assertDeleted: true,
+		},
+		{
+			name:            "Deleting a cluster based on an existing network removes the shared tags",
+			clusterSpec:     "reuse-net-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches: []patchOSCClusterFunc{patchDeleteCluster(), patchSharedRoleTags(map[string]string{
+				"subnet-kcp": "OscK8sRole/controlplane",
+				"subnet-kw":  "OscK8sRole/worker",
+			})},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockGetSecurityGroupsFromNet("vpc-foo", nil),
+				mockGetSubnet("subnet-kcp", &osc.Subnet{
+					SubnetId: "subnet-kcp",
+					Tags:     sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
+				}),
+				mockUntagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
+				mockGetSubnet("subnet-kw", &osc.Subnet{
+					SubnetId: "subnet-kw",
+					Tags: append(sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
+						osc.ResourceTag{Key: tag.ClusterKeyPrefix + "other-cluster", Value: tag.SharedValue}),
+				}),
+				mockUntagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockSubnetFound("subnet-public"),
+				mockGetNet("vpc-foo", &osc.Net{
+					NetId: "vpc-foo",
+					Tags:  sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				}),
+				mockUntagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			assertDeleted: true,
+		},
+		{
+			name:            "Deleting a cluster based on an existing network keeps the role tags not added by the cluster",
+			clusterSpec:     "reuse-net-1.0",
+			clusterBaseSpec: "base",
+			clusterPatches:  []patchOSCClusterFunc{patchDeleteCluster()},
+			mockFuncs: []mockFunc{
+				mockGetLoadBalancer("test-cluster-api-k8s", nil),
+				mockGetSecurityGroupsFromNet("vpc-foo", nil),
+				mockGetSubnet("subnet-kcp", &osc.Subnet{
+					SubnetId: "subnet-kcp",
+					Tags:     sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/controlplane"),
+				}),
+				mockUntagShared("subnet-kcp", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockGetSubnet("subnet-kw", &osc.Subnet{
+					SubnetId: "subnet-kw",
+					Tags:     sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520", "OscK8sRole/worker"),
+				}),
+				mockUntagShared("subnet-kw", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockSubnetFound("subnet-public"),
+				mockGetNet("vpc-foo", &osc.Net{
+					NetId: "vpc-foo",
+					Tags:  sharedResourceTags("9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				}),
+				mockUntagShared("vpc-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
 			},
 			assertDeleted: true,
 		},
@@ -3051,3 +4881,104 @@ func TestReconcileOSCCluster_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileOSCCluster_NatFailover(t *testing.T) {
+	// disable random reconciliation of security groups
+	scope.Rand = func() int { return 100 }
+
+	natIds := map[string]string{"eu-west-2a": "nat-a", "eu-west-2b": "nat-b"}
+	rtbls := func(kwNat string) []osc.RouteTable {
+		return []osc.RouteTable{
+			{RouteTableId: "rtb-public", Routes: []osc.Route{{DestinationIpRange: "0.0.0.0/0", GatewayId: new("igw-foo")}}},
+			{RouteTableId: "rtb-kw", Routes: []osc.Route{{DestinationIpRange: "0.0.0.0/0", NatServiceId: new(kwNat)}}},
+			{RouteTableId: "rtb-kcp", Routes: []osc.Route{{DestinationIpRange: "0.0.0.0/0", NatServiceId: new("nat-b")}}},
+		}
+	}
+	nats := func(aState, bState osc.NatServiceState) []osc.NatService {
+		return []osc.NatService{{NatServiceId: "nat-a", State: aState}, {NatServiceId: "nat-b", State: bState}}
+	}
+	uid := "9e1db9c4-bf0a-4583-8999-203ec002c520"
+	tcs := []testcase{
+		{
+			name:           "routes to a failed natService are moved to another subregion, and restored on recovery",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNatFailover(uid, natIds, nil)},
+			mockFuncs: []mockFunc{
+				mockListNatServices("vpc-foo", nats(osc.NatServiceStateDeleted, osc.NatServiceStateAvailable)),
+				mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-a")),
+				mockUpdateNatRoute("rtb-kw", "0.0.0.0/0", "nat-b"),
+			},
+			requeue: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNatFailover(map[string]string{"rtb-kw/0.0.0.0/0": "eu-west-2a-" + uid}, infrastructurev1beta2.NatFailoverReason),
+			},
+			next: &testcase{
+				name: "the route stays on the healthy natService while the failed natService is down",
+				mockFuncs: []mockFunc{
+					mockListNatServices("vpc-foo", nats(osc.NatServiceStateDeleted, osc.NatServiceStateAvailable)),
+					mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-b")),
+				},
+				requeue: true,
+				clusterAsserts: []assertOSCClusterFunc{
+					assertNatFailover(map[string]string{"rtb-kw/0.0.0.0/0": "eu-west-2a-" + uid}, infrastructurev1beta2.NatFailoverReason),
+				},
+				next: &testcase{
+					name: "the route is restored once the natService has recovered",
+					mockFuncs: []mockFunc{
+						mockListNatServices("vpc-foo", nats(osc.NatServiceStateAvailable, osc.NatServiceStateAvailable)),
+						mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-b")),
+						mockUpdateNatRoute("rtb-kw", "0.0.0.0/0", "nat-a"),
+					},
+					requeue: true,
+					clusterAsserts: []assertOSCClusterFunc{
+						assertNatFailover(nil, ""),
+					},
+				},
+			},
+		},
+		{
+			name:           "routes to a pending natService are not moved",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNatFailover(uid, natIds, nil)},
+			mockFuncs: []mockFunc{
+				mockListNatServices("vpc-foo", nats(osc.NatServiceStatePending, osc.NatServiceStateAvailable)),
+				mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-a")),
+			},
+			requeue: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNatFailover(nil, ""),
+			},
+		},
+		{
+			name:           "routes are never moved to a pending natService",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNatFailover(uid, natIds, nil)},
+			mockFuncs: []mockFunc{
+				mockListNatServices("vpc-foo", nats(osc.NatServiceStateDeleted, osc.NatServiceStatePending)),
+				mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-a")),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNatFailover(nil, infrastructurev1beta2.NatFailoverFailedReason),
+			},
+		},
+		{
+			name:           "reconciliation fails if no natService is healthy",
+			clusterSpec:    "ready-1.0",
+			clusterPatches: []patchOSCClusterFunc{patchNatFailover(uid, natIds, nil)},
+			mockFuncs: []mockFunc{
+				mockListNatServices("vpc-foo", nats(osc.NatServiceStateDeleted, osc.NatServiceStateDeleting)),
+				mockGetRouteTablesFromNet("vpc-foo", rtbls("nat-a")),
+			},
+			hasError: true,
+			clusterAsserts: []assertOSCClusterFunc{
+				assertNatFailover(nil, infrastructurev1beta2.NatFailoverFailedReason),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runClusterTest(t, tc)
+		})
+	}
+}
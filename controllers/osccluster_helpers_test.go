@@ -6,16 +6,26 @@ SPDX-License-Identifier: BSD-3-Clause
 package controllers_test
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
 	"testing"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	tag "github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/cluster-api-provider-outscale/controllers"
+	"github.com/outscale/goutils/k8s/tags"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -90,6 +100,24 @@ func patchIncrementGeneration() patchOSCClusterFunc {
 	}
 }
 
+func patchReconcile(reconciler infrastructurev1beta2.Reconciler) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.ReconcilerGeneration[reconciler] = m.Generation - 1
+	}
+}
+
+func patchReconciled(reconciler infrastructurev1beta2.Reconciler) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.ReconcilerGeneration[reconciler] = m.Generation
+	}
+}
+
+func patchAdditionalLoadBalancers(albs ...infrastructurev1beta2.OscAdditionalLoadBalancer) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.AdditionalLoadBalancers = albs
+	}
+}
+
 func patchSubregions(subregions ...string) patchOSCClusterFunc {
 	return func(m *infrastructurev1beta2.OscCluster) {
 		m.Spec.Network.Subregions = subregions
@@ -102,6 +130,200 @@ func patchNATIPFromPool(name string) patchOSCClusterFunc {
 	}
 }
 
+func patchNATPublicIpId(subregion, publicIpId string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.NatServices = []infrastructurev1beta2.OscNatService{{SubregionName: subregion, PublicIpId: publicIpId}}
+	}
+}
+
+func patchNatFailover(uid string, natIds map[string]string, failovers map[string]string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.NatFailover = infrastructurev1beta2.OscNatFailover{Enable: true}
+		m.Spec.Network.NatServices = nil
+		m.Status.Resources.NatService = map[string]string{}
+		for _, subregion := range slices.Sorted(maps.Keys(natIds)) {
+			m.Spec.Network.NatServices = append(m.Spec.Network.NatServices, infrastructurev1beta2.OscNatService{SubregionName: subregion})
+			m.Status.Resources.NatService[subregion+"-"+uid] = natIds[subregion]
+		}
+		m.Status.Resources.NatFailover = failovers
+	}
+}
+
+func patchLoadBalancerPublicIp(pool string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.LoadBalancer.PublicIp = true
+		m.Spec.Network.LoadBalancer.PublicIpPool = pool
+	}
+}
+
+func patchLoadBalancerAdditionalListeners(listeners ...infrastructurev1beta2.OscLoadBalancerListener) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.LoadBalancer.AdditionalListeners = listeners
+	}
+}
+
+func patchLoadBalancerAccessLog(accessLog infrastructurev1beta2.OscLoadBalancerAccessLog) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.LoadBalancer.AccessLog = accessLog
+	}
+}
+
+func patchServerCertificate(key, name string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		if m.Status.Resources.ServerCertificates == nil {
+			m.Status.Resources.ServerCertificates = map[string]string{}
+		}
+		m.Status.Resources.ServerCertificates[key] = name
+	}
+}
+
+func patchAdditionalLoadBalancerTracked(name, dnsName string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		if m.Status.Resources.AdditionalLoadBalancers == nil {
+			m.Status.Resources.AdditionalLoadBalancers = map[string]string{}
+		}
+		m.Status.Resources.AdditionalLoadBalancers[name] = dnsName
+	}
+}
+
+func patchSharedRoleTags(roleTags map[string]string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.Resources.SharedRoleTags = roleTags
+	}
+}
+
+func newTLSSecret(name, cert, key string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster-api-test"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(cert),
+			corev1.TLSPrivateKeyKey: []byte(key),
+		},
+	}
+}
+
+func patchUseExistingLoadBalancer() patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.UseExisting.LoadBalancer = true
+	}
+}
+
+func patchSubnetAllocation(prefixLength int32) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Subnets = nil
+		m.Spec.Network.SubnetAllocation = infrastructurev1beta2.OscSubnetAllocation{Enable: true, PrefixLength: prefixLength}
+	}
+}
+
+func patchNetPool(name string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Net.NetPool = name
+	}
+}
+
+func newNetPool(name, ipRange string, allocations map[string]infrastructurev1beta2.OscNetPoolAllocation) *infrastructurev1beta2.OscNetPool {
+	return &infrastructurev1beta2.OscNetPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       infrastructurev1beta2.OscNetPoolSpec{IpRange: ipRange},
+		Status:     infrastructurev1beta2.OscNetPoolStatus{Allocations: allocations},
+	}
+}
+
+func patchPeerings(peerings ...infrastructurev1beta2.OscPeering) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Peerings = append(m.Spec.Network.Peerings, peerings...)
+	}
+}
+
+func patchPeeringId(name, id string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		if m.Status.Resources.NetPeering == nil {
+			m.Status.Resources.NetPeering = map[string]string{}
+		}
+		m.Status.Resources.NetPeering[name] = id
+	}
+}
+
+func patchPeeringReady(name string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		conditions.MarkTrue(m, infrastructurev1beta2.PeeringReadyCondition(name))
+		m.Status.ReconcilerGeneration[infrastructurev1beta2.PeeringReconciler(name)] = m.Generation
+	}
+}
+
+func patchRouteTables(rtbls ...infrastructurev1beta2.OscRouteTable) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.RouteTables = rtbls
+		delete(m.Status.ReconcilerGeneration, infrastructurev1beta2.ReconcilerRouteTable)
+	}
+}
+
+func patchSecurityGroups(sgs ...infrastructurev1beta2.OscSecurityGroup) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.SecurityGroups = sgs
+		delete(m.Status.ReconcilerGeneration, infrastructurev1beta2.ReconcilerSecurityGroup)
+	}
+}
+
+func patchCniProfile(configMapName, hash string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Cni = infrastructurev1beta2.CniCustom
+		m.Spec.Network.CniProfileRef = &corev1.LocalObjectReference{Name: configMapName}
+		if hash != "" {
+			m.Status.Resources.CniProfile = map[string]string{"default": hash}
+		}
+	}
+}
+
+func patchEgressRules(rules ...infrastructurev1beta2.OscEgressRule) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.EgressRules = rules
+	}
+}
+
+func patchEgressDestinations(destinations map[string]string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.Resources.EgressDestinations = destinations
+	}
+}
+
+func patchSecurityGroupMemberRules(rules map[string]string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.Resources.SecurityGroupMemberRules = rules
+	}
+}
+
+func patchDhcpOptions(dhcp infrastructurev1beta2.OscDhcpOptions) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Net.DhcpOptions = dhcp
+		delete(m.Status.ReconcilerGeneration, infrastructurev1beta2.ReconcilerNet)
+	}
+}
+
+func patchDhcpOptionsId(dhcpOptionsSetId string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Status.Resources.DhcpOptions = map[string]string{"default": dhcpOptionsSetId}
+	}
+}
+
+func patchVpn(vpn infrastructurev1beta2.OscVpn) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		m.Spec.Network.Vpn = vpn
+	}
+}
+
+func patchVpnIds(virtualGatewayId, name, clientGatewayId, vpnConnectionId string) patchOSCClusterFunc {
+	return func(m *infrastructurev1beta2.OscCluster) {
+		rsrc := &m.Status.Resources
+		if virtualGatewayId != "" {
+			rsrc.VirtualGateway = map[string]string{"default": virtualGatewayId}
+		}
+		rsrc.ClientGateway = map[string]string{name: clientGatewayId}
+		rsrc.VpnConnection = map[string]string{name: vpnConnectionId}
+	}
+}
+
 func patchUseCredentials(c infrastructurev1beta2.OscCredentials) patchOSCClusterFunc {
 	return func(m *infrastructurev1beta2.OscCluster) {
 		m.Spec.Credentials = c
@@ -137,6 +359,9 @@ func mockGetNet(id string, net *osc.Net) mockFunc {
 
 func mockCreateNet(spec infrastructurev1beta2.OscNet, clusterID, netName, netId string) mockFunc {
 	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListUntaggedNetIds(gomock.Any(), gomock.Eq(spec.IpRange)).
+			Return(nil, nil)
 		s.NetMock.EXPECT().
 			CreateNet(gomock.Any(), gomock.Eq(spec), gomock.Eq(clusterID), gomock.Eq(netName)).
 			Return(&osc.Net{NetId: netId}, nil)
@@ -175,6 +400,14 @@ func mockSubnetFound(id string) mockFunc {
 	}
 }
 
+func mockGetSubnetsFromNet(netId string, subnets []osc.Subnet) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			GetSubnetsFromNet(gomock.Any(), gomock.Eq(netId)).
+			Return(subnets, nil)
+	}
+}
+
 func mockCreateSubnet(spec infrastructurev1beta2.OscSubnet, netId, clusterID, name, subnetId string) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
@@ -183,6 +416,14 @@ func mockCreateSubnet(spec infrastructurev1beta2.OscSubnet, netId, clusterID, na
 	}
 }
 
+func mockCreateSubnetFails(spec infrastructurev1beta2.OscSubnet, netId, clusterID, name string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			CreateSubnet(gomock.Any(), gomock.Eq(spec), gomock.Eq(netId), gomock.Eq(clusterID), gomock.Eq(name)).
+			Return(nil, errors.New("InvalidParameterValue"))
+	}
+}
+
 func mockDeleteSubnet(id string) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
@@ -223,6 +464,9 @@ func mockGetInternetServiceForNet(netId string, is *osc.InternetService) mockFun
 
 func mockCreateInternetService(name, clusterId, internetServiceId string) mockFunc {
 	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListUnlinkedInternetServiceIds(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterId)).
+			Return(nil, nil)
 		s.NetMock.EXPECT().
 			CreateInternetService(gomock.Any(), gomock.Eq(name), gomock.Eq(clusterId)).
 			Return(&osc.InternetService{
@@ -280,19 +524,40 @@ func mockCreateSecurityGroup(netId, clusterId, name, description, tag string, ro
 	}
 }
 
-func mockCreateSecurityGroupRule(sg, flow, proto, ipRanges string, fromPort, toPort int) mockFunc {
+func mockCreateSecurityGroupRule(sg, flow, proto, ipRange string, fromPort, toPort int) mockFunc {
 	return func(s *MockCloudServices) {
-		s.ComputeMock.EXPECT().
-			CreateSecurityGroupRule(gomock.Any(), sg, flow, proto, ipRanges, "", fromPort, toPort).
-			Return(&osc.SecurityGroup{SecurityGroupId: sg}, nil)
+		s.expectSecurityGroupRule(securityGroupRulesCall{sg: sg, flow: flow}, osc.SecurityGroupRule{
+			IpProtocol: proto, IpRanges: []string{ipRange}, FromPortRange: fromPort, ToPortRange: toPort,
+		})
+	}
+}
+
+func mockCreateSecurityGroupMemberRule(sg, flow, proto, sgMember string, fromPort, toPort int) mockFunc {
+	return func(s *MockCloudServices) {
+		s.expectSecurityGroupRule(securityGroupRulesCall{sg: sg, flow: flow}, osc.SecurityGroupRule{
+			IpProtocol: proto, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: sgMember}}, FromPortRange: fromPort, ToPortRange: toPort,
+		})
+	}
+}
+
+func mockCreateSecurityGroupRulesError(sg, flow string, err error) mockFunc {
+	return func(s *MockCloudServices) {
+		if s.securityGroupRulesErrs == nil {
+			s.securityGroupRulesErrs = map[securityGroupRulesCall]error{}
+		}
+		s.securityGroupRulesErrs[securityGroupRulesCall{sg: sg, flow: flow}] = err
 	}
 }
 
 func mockDeleteSecurityGroupRule(sg, flow, proto, ipRange, sgMember string, fromPort, toPort int) mockFunc {
 	return func(s *MockCloudServices) {
-		s.ComputeMock.EXPECT().
-			DeleteSecurityGroupRule(gomock.Any(), gomock.Eq(sg), gomock.Eq(flow), gomock.Eq(proto), gomock.Eq(ipRange), gomock.Eq(sgMember), gomock.Eq(fromPort), gomock.Eq(toPort)).
-			Return(nil)
+		rule := osc.SecurityGroupRule{IpProtocol: proto, FromPortRange: fromPort, ToPortRange: toPort}
+		if sgMember != "" {
+			rule.SecurityGroupsMembers = []osc.SecurityGroupsMember{{SecurityGroupId: sgMember}}
+		} else {
+			rule.IpRanges = []string{ipRange}
+		}
+		s.expectSecurityGroupRule(securityGroupRulesCall{delete: true, sg: sg, flow: flow}, rule)
 	}
 }
 
@@ -419,28 +684,52 @@ func mockListNetAccessPoints(netId string, naps []osc.NetAccessPoint) mockFunc {
 }
 
 func mockLoadBalancerFound(name, nameTag string) mockFunc {
+	return mockLoadBalancerFoundWithConfig(name, nameTag, osc.HealthCheck{
+		CheckInterval: 10, HealthyThreshold: 2, UnhealthyThreshold: 3, Timeout: 10, Port: 6443, Protocol: "TCP",
+	}, "sg-lb")
+}
+
+func mockLoadBalancerFoundWithConfig(name, nameTag string, hc osc.HealthCheck, securityGroupId string) mockFunc {
+	return mockLoadBalancerFoundWith(&osc.LoadBalancer{
+		LoadBalancerName: name,
+		DnsName:          name + ".lbu.outscale.com",
+		Tags:             []osc.ResourceTag{{Key: tag.NameKey, Value: nameTag}},
+		HealthCheck:      hc,
+		Listeners: []osc.Listener{{
+			BackendPort: 6443, BackendProtocol: "TCP", LoadBalancerPort: 6443, LoadBalancerProtocol: "TCP",
+		}},
+		SecurityGroups: []string{securityGroupId},
+	})
+}
+
+func mockLoadBalancerFoundWith(lb *osc.LoadBalancer) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
-			GetLoadBalancer(gomock.Any(), gomock.Eq(name)).
-			Return(&osc.LoadBalancer{
-				LoadBalancerName: name,
-				DnsName:          name + ".lbu.outscale.com",
-				Tags:             []osc.ResourceTag{{Key: tag.NameKey, Value: nameTag}},
-				HealthCheck:      osc.HealthCheck{},
-			}, nil)
+			GetLoadBalancer(gomock.Any(), gomock.Eq(lb.LoadBalancerName)).
+			Return(lb, nil)
 	}
 }
 
-func mockCreateLoadBalancer(loadBalancerName, loadBalancerType, subnetId, securityGroupId string) mockFunc {
+func mockCreateLoadBalancer(loadBalancerName, loadBalancerType, subnetId, securityGroupId, nameTag string) mockFunc {
+	return mockCreateLoadBalancerWithPublicIp(loadBalancerName, loadBalancerType, subnetId, securityGroupId, "", nameTag)
+}
+
+func mockCreateLoadBalancerWithPublicIp(loadBalancerName, loadBalancerType, subnetId, securityGroupId, publicIp, nameTag string) mockFunc {
+	return mockCreateLoadBalancerReturningTags(loadBalancerName, loadBalancerType, subnetId, securityGroupId, publicIp, nameTag, []osc.ResourceTag{{Key: tag.NameKey, Value: nameTag}})
+}
+
+func mockCreateLoadBalancerReturningTags(loadBalancerName, loadBalancerType, subnetId, securityGroupId, publicIp, nameTag string, returnedTags []osc.ResourceTag) mockFunc {
 	return func(s *MockCloudServices) {
+		tags := []osc.ResourceTag{{Key: tag.NameKey, Value: nameTag}}
 		s.NetMock.EXPECT().
 			CreateLoadBalancer(gomock.Any(), gomock.Cond(func(spec *infrastructurev1beta2.OscLoadBalancer) bool {
 				return spec.LoadBalancerName == loadBalancerName && spec.LoadBalancerType == loadBalancerType
-			}), gomock.Eq(subnetId), gomock.Eq(securityGroupId)).
+			}), gomock.Eq(subnetId), gomock.Eq(securityGroupId), gomock.Eq(publicIp), gomock.Nil(), gomock.Eq(tags)).
 			Return(&osc.LoadBalancer{
 				LoadBalancerName: loadBalancerName,
 				DnsName:          loadBalancerName + ".outscale.dev",
 				Listeners:        []osc.Listener{{LoadBalancerPort: 6443}},
+				Tags:             returnedTags,
 			}, nil)
 	}
 }
@@ -459,6 +748,22 @@ func mockConfigureHealthCheck(loadBalancerName string) mockFunc {
 	}
 }
 
+func mockCreateLoadBalancerListeners(loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			CreateLoadBalancerListeners(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(listeners), gomock.Nil()).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockCreateLoadBalancerListenersFail(loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			CreateLoadBalancerListeners(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(listeners), gomock.Nil()).
+			Return(nil, errors.New("CreateLoadBalancerListeners error"))
+	}
+}
+
 func mockCreateLoadBalancerTag(loadBalancerName, nameTag string) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
@@ -469,6 +774,79 @@ func mockCreateLoadBalancerTag(loadBalancerName, nameTag string) mockFunc {
 	}
 }
 
+func mockCreateLoadBalancerListenersWithServerCertificates(loadBalancerName string, listeners []infrastructurev1beta2.OscLoadBalancerListener,
+	serverCertificates map[int32]string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			CreateLoadBalancerListeners(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(listeners), gomock.Eq(serverCertificates)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockUpdateLoadBalancerServerCertificate(loadBalancerName string, port int32, serverCertificateId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdateLoadBalancerServerCertificate(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(port), gomock.Eq(serverCertificateId)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockUpdateLoadBalancerAccessLog(loadBalancerName string, accessLog infrastructurev1beta2.OscLoadBalancerAccessLog) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdateLoadBalancerAccessLog(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(accessLog)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockListServerCertificates(path string, scs []osc.ServerCertificate) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			ListServerCertificates(gomock.Any(), gomock.Eq(path)).
+			Return(scs, nil)
+	}
+}
+
+func mockCreateServerCertificate(name, path, body, privateKey string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			CreateServerCertificate(gomock.Any(), gomock.Eq(name), gomock.Eq(path), gomock.Eq(body), gomock.Eq(""), gomock.Eq(privateKey)).
+			Return(&osc.ServerCertificate{Name: &name, Path: &path, Orn: lo.ToPtr("orn:ows:idauth::012345678910:server-certificate" + path + name)}, nil)
+	}
+}
+
+func mockDeleteServerCertificate(name string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			DeleteServerCertificate(gomock.Any(), gomock.Eq(name)).
+			Return(nil)
+	}
+}
+
+func mockDeleteLoadBalancerListeners(loadBalancerName string, ports []int) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			DeleteLoadBalancerListeners(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(ports)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockUpdateLoadBalancerSecurityGroups(loadBalancerName string, securityGroupIds []string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdateLoadBalancerSecurityGroups(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(securityGroupIds)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName}, nil)
+	}
+}
+
+func mockUpdateLoadBalancerPublicIp(loadBalancerName, publicIp string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdateLoadBalancerPublicIp(gomock.Any(), gomock.Eq(loadBalancerName), gomock.Eq(publicIp)).
+			Return(&osc.LoadBalancer{LoadBalancerName: loadBalancerName, PublicIp: &publicIp}, nil)
+	}
+}
+
 func mockDeleteLoadBalancer(name string) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().
@@ -487,6 +865,36 @@ func mockReadOwnedByTag(rsrcType tag.ResourceType, clusterID string, tag *osc.Ta
 	}
 }
 
+func sharedResourceTags(clusterID string, roleKeys ...string) []osc.ResourceTag {
+	rtags := []osc.ResourceTag{{Key: tag.ClusterKeyPrefix + clusterID, Value: tag.SharedValue}}
+	for _, k := range roleKeys {
+		rtags = append(rtags, osc.ResourceTag{Key: k})
+	}
+	return rtags
+}
+
+func mockTagShared(id, clusterID string, roleKeys ...string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.TagMock.EXPECT().
+			AddTag(gomock.Any(), gomock.Eq(osc.CreateTagsRequest{
+				ResourceIds: []string{id},
+				Tags:        sharedResourceTags(clusterID, roleKeys...),
+			}), gomock.Eq([]string{id})).
+			Return(nil)
+	}
+}
+
+func mockUntagShared(id, clusterID string, roleKeys ...string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.TagMock.EXPECT().
+			DeleteTags(gomock.Any(), gomock.Eq(osc.DeleteTagsRequest{
+				ResourceIds: []string{id},
+				Tags:        sharedResourceTags(clusterID, roleKeys...),
+			})).
+			Return(nil)
+	}
+}
+
 func mockCreateVmBastion(vmId, subnetId string, securityGroupIds, privateIps []string, vmName, clientToken, imageId string, vmTags map[string]string) mockFunc {
 	created := []osc.BlockDeviceMappingCreated{{
 		DeviceName: "/dev/sda1",
@@ -529,10 +937,14 @@ func mockAcceptNetPeering() mockFunc {
 }
 
 func mockGetNetPeering(state osc.NetPeeringStateName) mockFunc {
+	return mockGetNetPeeringById("np-foo", state)
+}
+
+func mockGetNetPeeringById(id string, state osc.NetPeeringStateName) mockFunc {
 	return func(s *MockCloudServices) {
-		s.NetMock.EXPECT().GetNetPeering(gomock.Any(), gomock.Eq("np-foo")).
+		s.NetMock.EXPECT().GetNetPeering(gomock.Any(), gomock.Eq(id)).
 			Return(&osc.NetPeering{
-				NetPeeringId: "np-foo",
+				NetPeeringId: id,
 				State: osc.NetPeeringState{
 					Name: state,
 				},
@@ -540,6 +952,236 @@ func mockGetNetPeering(state osc.NetPeeringStateName) mockFunc {
 	}
 }
 
+func mockGetPeering(id, netID, accountID, peerNetID, peerAccountID string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetNetPeering(gomock.Any(), gomock.Eq(id)).
+			Return(&osc.NetPeering{
+				NetPeeringId: id,
+				SourceNet:    osc.SourceNet{AccountId: &accountID, NetId: &netID},
+				AccepterNet:  osc.AccepterNet{AccountId: &peerAccountID, NetId: &peerNetID},
+				State:        osc.NetPeeringState{Name: osc.NetPeeringStateNameActive},
+			}, nil).Times(2)
+	}
+}
+
+func mockCreatePeering(netID, peerNetID, peerAccountID, clusterID, peerIPRange string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreatePeering(gomock.Any(), gomock.Eq(netID), gomock.Eq(peerNetID), gomock.Eq(peerAccountID), gomock.Eq(clusterID)).
+			Return(&osc.NetPeering{
+				NetPeeringId: "np-foo",
+				AccepterNet: osc.AccepterNet{
+					AccountId: &peerAccountID,
+					IpRange:   &peerIPRange,
+					NetId:     &peerNetID,
+				},
+				State: osc.NetPeeringState{
+					Name: osc.NetPeeringStateNamePendingAcceptance,
+				},
+			}, nil)
+	}
+}
+
+func mockGetNetPeeringFromNet(netID, peerNetID, peerAccountID string, np *osc.NetPeering) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetNetPeeringFromNet(gomock.Any(), gomock.Eq(netID), gomock.Eq(peerNetID), gomock.Eq(peerAccountID)).
+			Return(np, nil)
+	}
+}
+
+func mockDeleteNetPeering(id string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteNetPeering(gomock.Any(), gomock.Eq(id)).
+			Return(nil)
+	}
+}
+
+func mockDeleteRoute(routeTableId, dest string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			DeleteRoute(gomock.Any(), gomock.Eq(dest), gomock.Eq(routeTableId)).
+			Return(nil)
+	}
+}
+
+func mockUpdateNatRoute(routeTableId, dest, natServiceId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdateNatRoute(gomock.Any(), gomock.Eq(dest), gomock.Eq(routeTableId), gomock.Eq(natServiceId)).
+			Return(&osc.RouteTable{RouteTableId: routeTableId}, nil)
+	}
+}
+
+func mockUpdatePeeringRoute(routeTableId, dest, netPeeringId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().
+			UpdatePeeringRoute(gomock.Any(), gomock.Eq(dest), gomock.Eq(routeTableId), gomock.Eq(netPeeringId)).
+			Return(&osc.RouteTable{RouteTableId: routeTableId}, nil)
+	}
+}
+
+func mockCreateDhcpOptions(spec infrastructurev1beta2.OscDhcpOptions, clusterID, dhcpOptionsSetId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreateDhcpOptions(gomock.Any(), gomock.Eq(spec), gomock.Eq(clusterID)).
+			Return(&osc.DhcpOptionsSet{DhcpOptionsSetId: &dhcpOptionsSetId}, nil)
+	}
+}
+
+func mockGetDhcpOptions(dhcpOptionsSetId string, dhcp *osc.DhcpOptionsSet) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetDhcpOptions(gomock.Any(), gomock.Eq(dhcpOptionsSetId)).
+			Return(dhcp, nil)
+	}
+}
+
+func mockLinkDhcpOptions(dhcpOptionsSetId, netId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().LinkDhcpOptions(gomock.Any(), gomock.Eq(dhcpOptionsSetId), gomock.Eq(netId)).
+			Return(nil)
+	}
+}
+
+func mockLinkDhcpOptionsFails(dhcpOptionsSetId, netId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().LinkDhcpOptions(gomock.Any(), gomock.Eq(dhcpOptionsSetId), gomock.Eq(netId)).
+			Return(errors.New("link error"))
+	}
+}
+
+func mockDeleteDhcpOptions(dhcpOptionsSetId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteDhcpOptions(gomock.Any(), gomock.Eq(dhcpOptionsSetId)).
+			Return(nil)
+	}
+}
+
+func mockCreateVirtualGateway(clusterID, virtualGatewayId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreateVirtualGateway(gomock.Any(), gomock.Eq(clusterID)).
+			Return(&osc.VirtualGateway{VirtualGatewayId: virtualGatewayId, State: osc.VirtualGatewayStatePending}, nil)
+	}
+}
+
+func mockGetVirtualGateway(virtualGatewayId string, linkedNetId string) mockFunc {
+	return func(s *MockCloudServices) {
+		vgw := &osc.VirtualGateway{VirtualGatewayId: virtualGatewayId, State: osc.VirtualGatewayStateAvailable}
+		if linkedNetId != "" {
+			vgw.NetToVirtualGatewayLinks = []osc.NetToVirtualGatewayLink{{NetId: &linkedNetId, State: new(osc.NetToVirtualGatewayLinkStateAttached)}}
+		}
+		s.NetMock.EXPECT().GetVirtualGateway(gomock.Any(), gomock.Eq(virtualGatewayId)).
+			Return(vgw, nil)
+	}
+}
+
+func mockGetVirtualGatewayOwnedBy(virtualGatewayId string, linkedNetId string, clusterID string) mockFunc {
+	return func(s *MockCloudServices) {
+		vgw := &osc.VirtualGateway{
+			VirtualGatewayId: virtualGatewayId,
+			State:            osc.VirtualGatewayStateAvailable,
+			Tags:             []osc.ResourceTag{{Key: tags.ClusterIDKey(clusterID), Value: tag.OwnedValue}},
+		}
+		if linkedNetId != "" {
+			vgw.NetToVirtualGatewayLinks = []osc.NetToVirtualGatewayLink{{NetId: &linkedNetId, State: new(osc.NetToVirtualGatewayLinkStateAttached)}}
+		}
+		s.NetMock.EXPECT().GetVirtualGateway(gomock.Any(), gomock.Eq(virtualGatewayId)).
+			Return(vgw, nil)
+	}
+}
+
+func mockLinkVirtualGateway(virtualGatewayId, netId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().LinkVirtualGateway(gomock.Any(), gomock.Eq(virtualGatewayId), gomock.Eq(netId)).
+			Return(nil)
+	}
+}
+
+func mockUnlinkVirtualGateway(virtualGatewayId, netId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().UnlinkVirtualGateway(gomock.Any(), gomock.Eq(virtualGatewayId), gomock.Eq(netId)).
+			Return(nil)
+	}
+}
+
+func mockDeleteVirtualGateway(virtualGatewayId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteVirtualGateway(gomock.Any(), gomock.Eq(virtualGatewayId)).
+			Return(nil)
+	}
+}
+
+func mockGetClientGatewayFor(publicIP string, bgpAsn int, clusterID string, cgw *osc.ClientGateway) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetClientGatewayFor(gomock.Any(), gomock.Eq(publicIP), gomock.Eq(bgpAsn), gomock.Eq(clusterID)).
+			Return(cgw, nil)
+	}
+}
+
+func mockCreateClientGateway(publicIP string, bgpAsn int, name, clusterID, clientGatewayId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreateClientGateway(gomock.Any(), gomock.Eq(publicIP), gomock.Eq(bgpAsn), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(&osc.ClientGateway{ClientGatewayId: clientGatewayId, State: osc.ClientGatewayStatePending}, nil)
+	}
+}
+
+func mockGetClientGateway(clientGatewayId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetClientGateway(gomock.Any(), gomock.Eq(clientGatewayId)).
+			Return(&osc.ClientGateway{ClientGatewayId: clientGatewayId, State: osc.ClientGatewayStateAvailable}, nil)
+	}
+}
+
+func mockDeleteClientGateway(clientGatewayId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteClientGateway(gomock.Any(), gomock.Eq(clientGatewayId)).
+			Return(nil)
+	}
+}
+
+func mockGetVpnConnectionFor(clientGatewayId, virtualGatewayId string, vpn *osc.VpnConnection) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().GetVpnConnectionFor(gomock.Any(), gomock.Eq(clientGatewayId), gomock.Eq(virtualGatewayId)).
+			Return(vpn, nil)
+	}
+}
+
+func mockCreateVpnConnection(clientGatewayId, virtualGatewayId, name, clusterID, vpnConnectionId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreateVpnConnection(gomock.Any(), gomock.Eq(clientGatewayId), gomock.Eq(virtualGatewayId), gomock.Eq(name), gomock.Eq(clusterID)).
+			Return(&osc.VpnConnection{VpnConnectionId: vpnConnectionId, State: osc.VpnConnectionStatePending}, nil)
+	}
+}
+
+func mockGetVpnConnection(vpnConnectionId string, routes ...string) mockFunc {
+	return func(s *MockCloudServices) {
+		vpn := &osc.VpnConnection{VpnConnectionId: vpnConnectionId, State: osc.VpnConnectionStateAvailable}
+		for _, r := range routes {
+			vpn.Routes = append(vpn.Routes, osc.RouteLight{DestinationIpRange: r, State: osc.RouteLightStateAvailable})
+		}
+		s.NetMock.EXPECT().GetVpnConnection(gomock.Any(), gomock.Eq(vpnConnectionId)).
+			Return(vpn, nil)
+	}
+}
+
+func mockDeleteVpnConnection(vpnConnectionId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteVpnConnection(gomock.Any(), gomock.Eq(vpnConnectionId)).
+			Return(nil)
+	}
+}
+
+func mockCreateVpnConnectionRoute(vpnConnectionId, dest string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().CreateVpnConnectionRoute(gomock.Any(), gomock.Eq(vpnConnectionId), gomock.Eq(dest)).
+			Return(nil)
+	}
+}
+
+func mockDeleteVpnConnectionRoute(vpnConnectionId, dest string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.NetMock.EXPECT().DeleteVpnConnectionRoute(gomock.Any(), gomock.Eq(vpnConnectionId), gomock.Eq(dest)).
+			Return(nil)
+	}
+}
+
 func mockCreateNetAccessPoint(netID, service, clusterID string, routeTables []string) mockFunc {
 	return func(s *MockCloudServices) {
 		s.NetMock.EXPECT().CreateNetAccessPoint(gomock.Any(), gomock.Eq(netID), gomock.Eq("eu-west-2"), gomock.Eq(service), gomock.Eq(routeTables), gomock.Eq(clusterID)).
@@ -561,6 +1203,13 @@ func assertStatusClusterResources(rsrcs infrastructurev1beta2.OscClusterResource
 	}
 }
 
+func assertSubnetClaims(claims map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, claims, c.Status.Resources.SubnetClaims)
+	}
+}
+
 func assertControlPlaneEndpoint(endpoint string, port int32) assertOSCClusterFunc {
 	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
 		t.Helper()
@@ -569,9 +1218,140 @@ func assertControlPlaneEndpoint(endpoint string, port int32) assertOSCClusterFun
 	}
 }
 
+func assertPublicIps(ips map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, ips, c.Status.Resources.PublicIPs)
+	}
+}
+
+func assertServerCertificates(scs map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, scs, c.Status.Resources.ServerCertificates)
+	}
+}
+
 func assertHasClusterFinalizer() assertOSCClusterFunc {
 	return func(t *testing.T, m *infrastructurev1beta2.OscCluster) {
 		t.Helper()
 		assert.True(t, controllerutil.ContainsFinalizer(m, controllers.OscClusterFinalizer))
 	}
 }
+
+func assertNetRange(ipRange string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, ipRange, c.Spec.Network.Net.IpRange)
+	}
+}
+
+func assertNetPoolAllocations(name string, allocations map[string]infrastructurev1beta2.OscNetPoolAllocation) assertKubeFunc {
+	return func(t *testing.T, c client.Client) {
+		t.Helper()
+		var pool infrastructurev1beta2.OscNetPool
+		err := c.Get(context.TODO(), client.ObjectKey{Name: name}, &pool)
+		require.NoError(t, err)
+		assert.Equal(t, allocations, pool.Status.Allocations)
+	}
+}
+
+func assertPeering(name, id string, ready bool) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, id, c.Status.Resources.NetPeering[name])
+		assert.Equal(t, ready, conditions.IsTrue(c, infrastructurev1beta2.PeeringReadyCondition(name)))
+	}
+}
+
+func assertNoPeering(name string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.NotContains(t, c.Status.Resources.NetPeering, name)
+		assert.Nil(t, conditions.Get(c, infrastructurev1beta2.PeeringReadyCondition(name)))
+		assert.NotContains(t, c.Status.ReconcilerGeneration, infrastructurev1beta2.PeeringReconciler(name))
+	}
+}
+
+func assertNoVpnConnection(name string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.NotContains(t, c.Status.Resources.ClientGateway, name)
+		assert.NotContains(t, c.Status.Resources.VpnConnection, name)
+	}
+}
+
+func assertNoVpn() assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Empty(t, c.Status.Resources.VirtualGateway)
+		assert.Empty(t, c.Status.Resources.ClientGateway)
+		assert.Empty(t, c.Status.Resources.VpnConnection)
+		assert.Nil(t, conditions.Get(c, infrastructurev1beta2.VpnReadyCondition))
+		assert.NotContains(t, c.Status.ReconcilerGeneration, infrastructurev1beta2.ReconcilerVpn)
+	}
+}
+
+func assertAdditionalLoadBalancers(albs map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, albs, c.Status.Resources.AdditionalLoadBalancers)
+	}
+}
+
+func assertCniProfileHash(hash string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, hash, c.Status.Resources.CniProfile["default"])
+	}
+}
+
+func assertEgressDestinations(destinations map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, destinations, c.Status.Resources.EgressDestinations)
+	}
+}
+
+func assertDhcpOptions(dhcpOptionsSetId string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, dhcpOptionsSetId, c.Status.Resources.DhcpOptions["default"])
+	}
+}
+
+func assertPreviousDhcpOptions(dhcpOptionsSetId string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, dhcpOptionsSetId, c.Status.Resources.DhcpOptions["previous"])
+	}
+}
+
+func assertSecurityGroupMemberRules(rules map[string]string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, rules, c.Status.Resources.SecurityGroupMemberRules)
+	}
+}
+
+func assertNatFailover(failovers map[string]string, reason string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, failovers, c.Status.Resources.NatFailover)
+		if reason == "" {
+			assert.True(t, conditions.IsTrue(c, infrastructurev1beta2.NatServicesHealthyCondition))
+		} else {
+			assert.Equal(t, reason, conditions.GetReason(c, infrastructurev1beta2.NatServicesHealthyCondition))
+		}
+	}
+}
+
+func assertVpn(virtualGatewayId, name, clientGatewayId, vpnConnectionId string) assertOSCClusterFunc {
+	return func(t *testing.T, c *infrastructurev1beta2.OscCluster) {
+		t.Helper()
+		assert.Equal(t, virtualGatewayId, c.Status.Resources.VirtualGateway["default"])
+		assert.Equal(t, clientGatewayId, c.Status.Resources.ClientGateway[name])
+		assert.Equal(t, vpnConnectionId, c.Status.Resources.VpnConnection[name])
+		assert.True(t, conditions.IsTrue(c, infrastructurev1beta2.VpnReadyCondition))
+	}
+}
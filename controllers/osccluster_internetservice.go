@@ -11,6 +11,7 @@ import (
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -30,6 +31,7 @@ func (r *OscClusterReconciler) reconcileInternetService(ctx context.Context, clu
 
 	log.V(4).Info("Reconciling internetService")
 
+	key := creationKey(tag.InternetServiceResourceType, defaultResource)
 	internetService, err := r.Tracker.getInternetService(ctx, clusterScope)
 	switch {
 	case IsNotFound(err):
@@ -37,6 +39,9 @@ func (r *OscClusterReconciler) reconcileInternetService(ctx context.Context, clu
 		return reconcile.Result{}, fmt.Errorf("get existing: %w", err)
 	case internetService.NetId != "":
 		log.V(4).Info("Found existing internetService", "internetServiceId", internetService.InternetServiceId)
+		if err := clusterScope.EndCreation(ctx, key); err != nil {
+			return reconcile.Result{}, err
+		}
 		clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerInternetService)
 		return reconcile.Result{}, nil
 	}
@@ -45,21 +50,43 @@ func (r *OscClusterReconciler) reconcileInternetService(ctx context.Context, clu
 		return reconcile.Result{}, err
 	}
 	svc := r.Cloud.Net(clusterScope.Tenant)
-	if internetService == nil {
+	var internetServiceId string
+	if internetService != nil {
+		internetServiceId = internetService.InternetServiceId
+	} else {
 		log.V(3).Info("Creating internet service")
-		internetService, err = svc.CreateInternetService(ctx, clusterScope.GetInternetServiceName(), clusterScope.GetUID())
+		name := clusterScope.GetInternetServiceName()
+		internetServiceId, err = createOnce(ctx, clusterScope, key, creation{
+			candidates: func() ([]string, error) {
+				return svc.ListUnlinkedInternetServiceIds(ctx, name, clusterScope.GetUID())
+			},
+			create: func() (string, error) {
+				is, err := svc.CreateInternetService(ctx, name, clusterScope.GetUID())
+				if is == nil {
+					return "", err
+				}
+				return is.InternetServiceId, err
+			},
+			adopt: func(id string) error {
+				return tagCreated(ctx, r.Cloud.Tag(clusterScope.Tenant), id, name, clusterScope.GetUID())
+			},
+		})
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("cannot create internetService: %w", err)
 		}
-		log.V(2).Info("Created internet service", "internetServiceId", internetService.InternetServiceId)
+		r.Tracker.setInternetServiceId(clusterScope, internetServiceId)
+		if err := clusterScope.EndCreation(ctx, key); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.V(2).Info("Created internet service", "internetServiceId", internetServiceId)
 		r.Recorder.Event(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.InternetServicesCreatedReason, "Internet service created")
 	}
-	log.V(2).Info("Linking internet service to net", "internetServiceId", internetService.InternetServiceId, "netId", netId)
-	err = svc.LinkInternetService(ctx, internetService.InternetServiceId, netId)
+	log.V(2).Info("Linking internet service to net", "internetServiceId", internetServiceId, "netId", netId)
+	err = svc.LinkInternetService(ctx, internetServiceId, netId)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot link internetService: %w", err)
 	}
-	r.Tracker.setInternetServiceId(clusterScope, internetService.InternetServiceId)
+	r.Tracker.setInternetServiceId(clusterScope, internetServiceId)
 	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerInternetService)
 	return reconcile.Result{}, nil
 }
@@ -9,12 +9,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/goutils/k8s/tags"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -29,109 +31,444 @@ func getLoadBalancerNameTag(lb *osc.LoadBalancer) string {
 // reconcileLoadBalancer reconciles the loadBalancer of the cluster.
 func (r *OscClusterReconciler) reconcileLoadBalancer(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
-	if !clusterScope.NeedReconciliation(infrastructurev1beta2.ReconcilerLoadbalancer) {
+	useExisting := clusterScope.GetNetwork().UseExisting.LoadBalancer
+	// A rotated server certificate needs to be uploaded again, even if the spec is unchanged.
+	if !clusterScope.NeedReconciliation(infrastructurev1beta2.ReconcilerLoadbalancer) &&
+		(useExisting || !r.serverCertificatesChanged(ctx, clusterScope, clusterScope.GetLoadBalancer())) {
 		log.V(4).Info("No need for loadbalancer reconciliation")
 		return reconcile.Result{}, nil
 	}
 	log.V(4).Info("Reconciling loadBalancer")
 
-	loadBalancerSpec := clusterScope.GetLoadBalancer()
+	loadbalancer, err := r.reconcileLoadBalancerSpec(ctx, clusterScope, clusterScope.GetLoadBalancer(), useExisting)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if clusterScope.GetNetwork().GetEndpointLoadBalancerName() == "" {
+		r.setControlPlaneEndpoint(ctx, clusterScope, loadbalancer, clusterScope.GetLoadBalancer().Listener.LoadBalancerPort)
+	}
+	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerLoadbalancer)
+	return reconcile.Result{}, nil
+}
+
+// reconcileAdditionalLoadBalancer reconciles an additional loadBalancer of the cluster.
+func (r *OscClusterReconciler) reconcileAdditionalLoadBalancer(ctx context.Context, clusterScope *scope.ClusterScope, alb infrastructurev1beta2.OscAdditionalLoadBalancer) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("loadBalancerName", alb.LoadBalancerName)
+	reconciler := infrastructurev1beta2.AdditionalLoadBalancerReconciler(alb.LoadBalancerName)
+	loadBalancerSpec := alb.GetLoadBalancer()
+	// Additional loadBalancers created before being tracked are reconciled once to be tracked.
+	if !clusterScope.NeedReconciliation(reconciler) && !r.serverCertificatesChanged(ctx, clusterScope, loadBalancerSpec) &&
+		r.Tracker.hasAdditionalLoadBalancer(clusterScope, alb.LoadBalancerName) {
+		log.V(4).Info("No need for additional loadbalancer reconciliation")
+		return reconcile.Result{}, nil
+	}
+	log.V(4).Info("Reconciling additional loadBalancer")
+
+	loadbalancer, err := r.reconcileLoadBalancerSpec(ctrl.LoggerInto(ctx, log), clusterScope, loadBalancerSpec, false)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.Tracker.setAdditionalLoadBalancer(clusterScope, alb.LoadBalancerName, loadbalancer.DnsName)
+	if alb.UseAsControlPlaneEndpoint {
+		r.setControlPlaneEndpoint(ctx, clusterScope, loadbalancer, loadBalancerSpec.Listener.LoadBalancerPort)
+	}
+	clusterScope.SetReconciliationGeneration(reconciler)
+	return reconcile.Result{}, nil
+}
+
+// reconcileLoadBalancerSpec creates or updates a loadBalancer, and returns it.
+func (r *OscClusterReconciler) reconcileLoadBalancerSpec(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer, useExisting bool) (*osc.LoadBalancer, error) {
+	log := ctrl.LoggerFrom(ctx)
 	loadBalancerName := loadBalancerSpec.LoadBalancerName
 	svc := r.Cloud.Net(clusterScope.Tenant)
 	loadbalancer, err := svc.GetLoadBalancer(ctx, loadBalancerName)
 	if err != nil {
-		return reconcile.Result{}, fmt.Errorf("cannot get loadbalancer: %w", err)
+		return nil, fmt.Errorf("cannot get loadbalancer: %w", err)
 	}
 
 	nameTag := loadBalancerName + "-" + clusterScope.GetUID()
+	switch {
+	case !useExisting:
+	case loadbalancer == nil:
+		return nil, fmt.Errorf("existing loadBalancer %s not found", loadBalancerName)
+	default:
+		log.V(3).Info("Reusing existing loadBalancer", "loadBalancerName", loadBalancerName)
+		return loadbalancer, nil
+	}
 	if loadbalancer != nil {
 		lbName := getLoadBalancerNameTag(loadbalancer)
 		if lbName == "" && loadbalancer.LoadBalancerName == loadBalancerName {
-			return reconcile.Result{}, fmt.Errorf("a LoadBalancer with name %s already exists", loadBalancerName)
+			return nil, fmt.Errorf("a LoadBalancer with name %s already exists", loadBalancerName)
 		}
 		if lbName != "" && lbName != nameTag {
-			return reconcile.Result{}, fmt.Errorf("a LoadBalancer %s already exists for another cluster", loadBalancerName)
+			return nil, fmt.Errorf("a LoadBalancer %s already exists for another cluster", loadBalancerName)
 		}
 	}
 
+	securityGroupId, err := r.getLoadBalancerSecurityGroupId(ctx, clusterScope, loadBalancerSpec)
+	if err != nil {
+		return nil, err
+	}
+	var publicIp string
+	if loadBalancerSpec.PublicIp {
+		publicIp, err = r.getLoadBalancerPublicIp(ctx, clusterScope, loadBalancerSpec, loadbalancer)
+		if err != nil {
+			return nil, fmt.Errorf("get public IP: %w", err)
+		}
+	}
+	serverCertificates, err := r.reconcileServerCertificates(ctx, clusterScope, loadBalancerSpec)
+	if err != nil {
+		return nil, err
+	}
 	if loadbalancer == nil {
 		subnetSpec, err := clusterScope.GetSubnet(loadBalancerSpec.SubnetName, infrastructurev1beta2.RoleLoadBalancer, "")
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("find subnet: %w", err)
+			return nil, fmt.Errorf("find subnet: %w", err)
 		}
 		subnetId, err := r.Tracker.getSubnetId(ctx, subnetSpec, clusterScope)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("get subnet: %w", err)
+			return nil, fmt.Errorf("get subnet: %w", err)
 		}
-		var sgSpecs []infrastructurev1beta2.OscSecurityGroup
-		if loadBalancerSpec.SecurityGroupName != "" {
-			sgSpecs, err = clusterScope.GetSecurityGroupsFor([]infrastructurev1beta2.OscSecurityGroupElement{{Name: loadBalancerSpec.SecurityGroupName}}, infrastructurev1beta2.RoleLoadBalancer)
-		} else {
-			sgSpecs, err = clusterScope.GetSecurityGroupsFor(nil, infrastructurev1beta2.RoleLoadBalancer)
+		log.V(2).Info("Creating loadBalancer", "loadBalancerName", loadBalancerName, "subnet", subnetId, "securityGroupId", securityGroupId)
+		loadbalancer, err = svc.CreateLoadBalancer(ctx, &loadBalancerSpec, subnetId, securityGroupId, publicIp, serverCertificates, []osc.ResourceTag{{Key: tag.NameKey, Value: nameTag}})
+		if err != nil {
+			return nil, fmt.Errorf("cannot create loadBalancer: %w", err)
+		}
+		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.LoadBalancerCreatedReason, "Loadbalancer %s created", loadBalancerName)
+		// An existing loadBalancer without the name tag is rejected above, only a created loadBalancer may miss it.
+		if getLoadBalancerNameTag(loadbalancer) != nameTag {
+			log.V(2).Info("Creating loadBalancer name tag", "loadBalancerName", loadBalancerName)
+			err = svc.CreateLoadBalancerTag(ctx, &loadBalancerSpec, &osc.ResourceTag{Key: tag.NameKey, Value: nameTag})
+			if err != nil {
+				return nil, fmt.Errorf("cannot tag loadBalancer: %w", err)
+			}
 		}
+		log.V(2).Info("Configuring loadBalancer healthcheck", "loadBalancerName", loadBalancerName)
+		_, err = svc.ConfigureHealthCheck(ctx, &loadBalancerSpec)
+		log.V(4).Info("Get loadbalancer", "loadbalancer", loadbalancer)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("find securityGroup: %w", err)
+			return nil, fmt.Errorf("cannot configure healthcheck: %w", err)
 		}
-		if len(sgSpecs) == 0 {
-			return reconcile.Result{}, errors.New("no security group found")
+		if loadBalancerSpec.AccessLog.Bucket != "" {
+			log.V(2).Info("Configuring loadBalancer access logs", "loadBalancerName", loadBalancerName)
+			_, err = svc.UpdateLoadBalancerAccessLog(ctx, loadBalancerName, loadBalancerSpec.AccessLog)
+			if err != nil {
+				return nil, fmt.Errorf("cannot configure access logs: %w", err)
+			}
 		}
-		securityGroupId, err := r.Tracker.getSecurityGroupId(ctx, sgSpecs[0], clusterScope)
+	} else {
+		err = r.updateLoadBalancer(ctx, clusterScope, loadBalancerSpec, loadbalancer, securityGroupId, publicIp, serverCertificates)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot get security group: %w", err)
+			return nil, err
 		}
-		log.V(2).Info("Creating loadBalancer", "loadBalancerName", loadBalancerName, "subnet", subnetId, "securityGroupId", securityGroupId)
-		loadbalancer, err = svc.CreateLoadBalancer(ctx, &loadBalancerSpec, subnetId, securityGroupId)
+	}
+	err = r.deleteUnusedServerCertificates(ctx, clusterScope, loadBalancerSpec)
+	if err != nil {
+		return nil, err
+	}
+	return loadbalancer, nil
+}
+
+// getLoadBalancerSecurityGroupId returns the id of the security group of the loadBalancer.
+func (r *OscClusterReconciler) getLoadBalancerSecurityGroupId(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer) (string, error) {
+	var (
+		sgSpecs []infrastructurev1beta2.OscSecurityGroup
+		err     error
+	)
+	if loadBalancerSpec.SecurityGroupName != "" {
+		sgSpecs, err = clusterScope.GetSecurityGroupsFor([]infrastructurev1beta2.OscSecurityGroupElement{{Name: loadBalancerSpec.SecurityGroupName}}, infrastructurev1beta2.RoleLoadBalancer)
+	} else {
+		sgSpecs, err = clusterScope.GetSecurityGroupsFor(nil, infrastructurev1beta2.RoleLoadBalancer)
+	}
+	if err != nil {
+		return "", fmt.Errorf("find securityGroup: %w", err)
+	}
+	if len(sgSpecs) == 0 {
+		return "", errors.New("no security group found")
+	}
+	securityGroupId, err := r.Tracker.getSecurityGroupId(ctx, sgSpecs[0], clusterScope)
+	if err != nil {
+		return "", fmt.Errorf("cannot get security group: %w", err)
+	}
+	return securityGroupId, nil
+}
+
+// getLoadBalancerPublicIp returns the public IP of the loadBalancer, allocating it if the loadBalancer does not already use it.
+func (r *OscClusterReconciler) getLoadBalancerPublicIp(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer,
+	loadbalancer *osc.LoadBalancer) (string, error) {
+	allocator := r.Tracker.IPAllocator(clusterScope)
+	// An IP linked to the loadBalancer would be seen as used by the allocator, and reallocated.
+	if loadbalancer != nil && loadbalancer.PublicIp != nil {
+		err := allocator.RetrackIP(ctx, loadBalancerIPResourceKey, *loadbalancer.PublicIp, clusterScope)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot create loadBalancer: %w", err)
+			return "", err
 		}
-		r.Recorder.Event(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.LoadBalancerCreatedReason, "Loadbalancer created")
-		log.V(2).Info("Configuring loadBalancer healthcheck", "loadBalancerName", loadBalancerName)
-		_, err = svc.ConfigureHealthCheck(ctx, &loadBalancerSpec)
-		log.V(4).Info("Get loadbalancer", "loadbalancer", loadbalancer)
+		if id, found := r.Tracker.getPublicIps(clusterScope)[loadBalancerIPResourceKey]; found {
+			pip, err := r.Cloud.Net(clusterScope.Tenant).GetPublicIp(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			if pip != nil && pip.PublicIp == *loadbalancer.PublicIp {
+				return pip.PublicIp, nil
+			}
+		}
+	}
+	_, publicIp, err := allocator.AllocateIP(ctx, loadBalancerIPResourceKey, loadBalancerSpec.LoadBalancerName, loadBalancerSpec.PublicIpPool, clusterScope)
+	return publicIp, err
+}
+
+// updateLoadBalancer updates the healthcheck, listeners, server certificates, access logs, security groups and public IP of an existing loadBalancer, if they differ from the spec.
+// The name, type and subnet of a loadBalancer cannot be changed, and are rejected by the webhook.
+func (r *OscClusterReconciler) updateLoadBalancer(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer,
+	loadbalancer *osc.LoadBalancer, securityGroupId, publicIp string, serverCertificates map[int32]string) error {
+	log := ctrl.LoggerFrom(ctx)
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	loadBalancerName := loadBalancerSpec.LoadBalancerName
+
+	if !healthCheckMatches(loadbalancer.HealthCheck, loadBalancerSpec.HealthCheck) {
+		log.V(2).Info("Updating loadBalancer healthcheck", "loadBalancerName", loadBalancerName)
+		_, err := svc.ConfigureHealthCheck(ctx, &loadBalancerSpec)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot configure healthcheck: %w", err)
+			return fmt.Errorf("cannot configure healthcheck: %w", err)
 		}
 	}
-	if len(loadbalancer.Tags) == 0 {
-		log.V(2).Info("Creating loadBalancer name tag", "loadBalancerName", loadBalancerName)
-		err = svc.CreateLoadBalancerTag(ctx, &loadBalancerSpec, &osc.ResourceTag{Key: tag.NameKey, Value: nameTag})
+
+	err := r.updateLoadBalancerListeners(ctx, clusterScope, loadBalancerSpec, loadbalancer, serverCertificates)
+	if err != nil {
+		return err
+	}
+	// Server certificates of listeners not recreated are updated in place.
+	for _, live := range loadbalancer.Listeners {
+		orn, found := serverCertificates[int32(live.LoadBalancerPort)] //nolint:gosec
+		if !found || lo.FromPtr(live.ServerCertificateId) == orn || !slices.ContainsFunc(loadBalancerSpec.GetListeners(), func(listener infrastructurev1beta2.OscLoadBalancerListener) bool {
+			return listenerMatches(live, listener)
+		}) {
+			continue
+		}
+		log.V(2).Info("Updating listener server certificate", "loadBalancerName", loadBalancerName, "port", live.LoadBalancerPort)
+		_, err := svc.UpdateLoadBalancerServerCertificate(ctx, loadBalancerName, int32(live.LoadBalancerPort), orn) //nolint:gosec
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot tag loadBalancer: %w", err)
+			return fmt.Errorf("cannot update server certificate: %w", err)
 		}
 	}
-	controlPlaneEndpoint := loadbalancer.DnsName
-	log.V(4).Info("Set controlPlaneEndpoint", "endpoint", controlPlaneEndpoint)
 
-	controlPlanePort := loadBalancerSpec.Listener.LoadBalancerPort
+	if !accessLogMatches(loadbalancer.AccessLog, loadBalancerSpec.AccessLog) {
+		log.V(2).Info("Updating loadBalancer access logs", "loadBalancerName", loadBalancerName)
+		_, err := svc.UpdateLoadBalancerAccessLog(ctx, loadBalancerName, loadBalancerSpec.AccessLog)
+		if err != nil {
+			return fmt.Errorf("cannot configure access logs: %w", err)
+		}
+	}
+
+	if len(loadbalancer.SecurityGroups) != 1 || loadbalancer.SecurityGroups[0] != securityGroupId {
+		log.V(2).Info("Updating loadBalancer security groups", "loadBalancerName", loadBalancerName, "securityGroupId", securityGroupId)
+		_, err := svc.UpdateLoadBalancerSecurityGroups(ctx, loadBalancerName, []string{securityGroupId})
+		if err != nil {
+			return fmt.Errorf("cannot update security groups: %w", err)
+		}
+	}
+
+	if publicIp != "" && (loadbalancer.PublicIp == nil || *loadbalancer.PublicIp != publicIp) {
+		log.V(2).Info("Updating loadBalancer public IP", "loadBalancerName", loadBalancerName, "publicIp", publicIp)
+		_, err := svc.UpdateLoadBalancerPublicIp(ctx, loadBalancerName, publicIp)
+		if err != nil {
+			return fmt.Errorf("cannot update public IP: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateLoadBalancerListeners creates, replaces and deletes listeners so that they match the spec.
+// Listeners on new ports are created first and listeners removed from the spec are deleted last.
+// A listener whose backend port or protocols change on the same port is deleted then created again, and restored if the creation fails:
+// the port is unavailable in between. Server certificates are not compared, they are updated in place by updateLoadBalancer.
+func (r *OscClusterReconciler) updateLoadBalancerListeners(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer,
+	loadbalancer *osc.LoadBalancer, serverCertificates map[int32]string) error {
+	log := ctrl.LoggerFrom(ctx)
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	loadBalancerName := loadBalancerSpec.LoadBalancerName
+
+	listeners := loadBalancerSpec.GetListeners()
+	var (
+		toCreate, toReplace []infrastructurev1beta2.OscLoadBalancerListener
+		replaced            []osc.Listener
+		toDelete            []int
+	)
+	for _, listener := range listeners {
+		if slices.ContainsFunc(loadbalancer.Listeners, func(live osc.Listener) bool {
+			return listenerMatches(live, listener)
+		}) {
+			continue
+		}
+		idx := slices.IndexFunc(loadbalancer.Listeners, func(live osc.Listener) bool {
+			return live.LoadBalancerPort == int(listener.LoadBalancerPort)
+		})
+		if idx >= 0 {
+			toReplace = append(toReplace, listener)
+			replaced = append(replaced, loadbalancer.Listeners[idx])
+		} else {
+			toCreate = append(toCreate, listener)
+		}
+	}
+	for _, live := range loadbalancer.Listeners {
+		if !slices.ContainsFunc(listeners, func(listener infrastructurev1beta2.OscLoadBalancerListener) bool {
+			return live.LoadBalancerPort == int(listener.LoadBalancerPort)
+		}) {
+			toDelete = append(toDelete, live.LoadBalancerPort)
+		}
+	}
+
+	if len(toCreate) > 0 {
+		log.V(2).Info("Creating loadBalancer listeners", "loadBalancerName", loadBalancerName, "listeners", toCreate)
+		_, err := svc.CreateLoadBalancerListeners(ctx, loadBalancerName, toCreate, serverCertificates)
+		if err != nil {
+			return fmt.Errorf("cannot create listeners: %w", err)
+		}
+	}
+	if len(toReplace) > 0 {
+		ports := make([]int, 0, len(replaced))
+		for _, live := range replaced {
+			ports = append(ports, live.LoadBalancerPort)
+		}
+		log.V(2).Info("Replacing loadBalancer listeners", "loadBalancerName", loadBalancerName, "listeners", toReplace)
+		_, err := svc.DeleteLoadBalancerListeners(ctx, loadBalancerName, ports)
+		if err != nil {
+			return fmt.Errorf("cannot delete listeners: %w", err)
+		}
+		_, err = svc.CreateLoadBalancerListeners(ctx, loadBalancerName, toReplace, serverCertificates)
+		if err != nil {
+			log.V(2).Info("Restoring loadBalancer listeners", "loadBalancerName", loadBalancerName, "ports", ports)
+			oldListeners, oldServerCertificates := specListeners(replaced)
+			_, rerr := svc.CreateLoadBalancerListeners(ctx, loadBalancerName, oldListeners, oldServerCertificates)
+			if rerr != nil {
+				return fmt.Errorf("cannot create listeners: %w (cannot restore previous listeners: %w)", err, rerr)
+			}
+			return fmt.Errorf("cannot create listeners: %w", err)
+		}
+	}
+	if len(toDelete) > 0 {
+		log.V(2).Info("Deleting loadBalancer listeners", "loadBalancerName", loadBalancerName, "ports", toDelete)
+		_, err := svc.DeleteLoadBalancerListeners(ctx, loadBalancerName, toDelete)
+		if err != nil {
+			return fmt.Errorf("cannot delete listeners: %w", err)
+		}
+	}
+	return nil
+}
+
+// specListeners converts live listeners into listener specs, with their server certificates.
+func specListeners(live []osc.Listener) ([]infrastructurev1beta2.OscLoadBalancerListener, map[int32]string) {
+	var serverCertificates map[int32]string
+	listeners := make([]infrastructurev1beta2.OscLoadBalancerListener, 0, len(live))
+	for _, l := range live {
+		listeners = append(listeners, infrastructurev1beta2.OscLoadBalancerListener{
+			BackendPort:          int32(l.BackendPort), //nolint:gosec
+			BackendProtocol:      l.BackendProtocol,
+			LoadBalancerPort:     int32(l.LoadBalancerPort), //nolint:gosec
+			LoadBalancerProtocol: l.LoadBalancerProtocol,
+		})
+		if l.ServerCertificateId != nil {
+			if serverCertificates == nil {
+				serverCertificates = map[int32]string{}
+			}
+			serverCertificates[int32(l.LoadBalancerPort)] = *l.ServerCertificateId //nolint:gosec
+		}
+	}
+	return listeners, serverCertificates
+}
+
+func healthCheckMatches(live osc.HealthCheck, spec infrastructurev1beta2.OscLoadBalancerHealthCheck) bool {
+	return live.CheckInterval == int(spec.CheckInterval) &&
+		live.HealthyThreshold == int(spec.HealthyThreshold) &&
+		live.Port == int(spec.Port) &&
+		live.Protocol == spec.Protocol &&
+		live.Timeout == int(spec.Timeout) &&
+		live.UnhealthyThreshold == int(spec.UnhealthyThreshold)
+}
+
+func accessLogMatches(live osc.AccessLog, spec infrastructurev1beta2.OscLoadBalancerAccessLog) bool {
+	if spec.Bucket == "" {
+		return !live.IsEnabled
+	}
+	return live.IsEnabled &&
+		lo.FromPtr(live.OsuBucketName) == spec.Bucket &&
+		lo.FromPtr(live.OsuBucketPrefix) == spec.Prefix &&
+		lo.FromPtr(live.PublicationInterval) == int(spec.Interval)
+}
+
+func listenerMatches(live osc.Listener, spec infrastructurev1beta2.OscLoadBalancerListener) bool {
+	return live.LoadBalancerPort == int(spec.LoadBalancerPort) &&
+		live.LoadBalancerProtocol == spec.LoadBalancerProtocol &&
+		live.BackendPort == int(spec.BackendPort) &&
+		live.BackendProtocol == spec.BackendProtocol
+}
+
+// setControlPlaneEndpoint sets the control plane endpoint to the DNS name of a loadBalancer.
+func (r *OscClusterReconciler) setControlPlaneEndpoint(ctx context.Context, clusterScope *scope.ClusterScope, loadbalancer *osc.LoadBalancer, controlPlanePort int32) {
+	controlPlaneEndpoint := loadbalancer.DnsName
+	ctrl.LoggerFrom(ctx).V(4).Info("Set controlPlaneEndpoint", "endpoint", controlPlaneEndpoint)
 
 	clusterScope.SetControlPlaneEndpoint(clusterv1.APIEndpoint{
 		Host: controlPlaneEndpoint,
 		Port: controlPlanePort,
 	})
-	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerLoadbalancer)
-	return reconcile.Result{}, nil
 }
 
 // reconcileDeleteLoadBalancer reconcile the destruction of the LoadBalancer of the cluster.
-
 func (r *OscClusterReconciler) reconcileDeleteLoadBalancer(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	loadBalancerSpec := clusterScope.GetLoadBalancer()
-	loadBalancerName := loadBalancerSpec.LoadBalancerName
+	if clusterScope.GetNetwork().UseExisting.LoadBalancer {
+		log.V(3).Info("Not deleting existing loadBalancer", "loadBalancerName", loadBalancerSpec.LoadBalancerName)
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, r.deleteLoadBalancer(ctx, clusterScope, loadBalancerSpec)
+}
 
+// reconcileDeleteAdditionalLoadBalancers reconcile the destruction of the additional LoadBalancers of the cluster.
+func (r *OscClusterReconciler) reconcileDeleteAdditionalLoadBalancers(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	for _, alb := range clusterScope.GetNetwork().AdditionalLoadBalancers {
+		err := r.deleteLoadBalancer(ctx, clusterScope, alb.GetLoadBalancer())
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("loadBalancer %s: %w", alb.LoadBalancerName, err)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileRemovedAdditionalLoadBalancers deletes the additional loadBalancers removed from the spec.
+func (r *OscClusterReconciler) reconcileRemovedAdditionalLoadBalancers(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	for _, name := range r.Tracker.getRemovedAdditionalLoadBalancers(clusterScope) {
+		log.V(3).Info("Additional loadBalancer removed from the spec", "loadBalancerName", name)
+		err := r.deleteLoadBalancer(ctx, clusterScope, infrastructurev1beta2.OscLoadBalancer{LoadBalancerName: name})
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("loadBalancer %s: %w", name, err)
+		}
+		r.Tracker.unsetAdditionalLoadBalancer(clusterScope, name)
+		clusterScope.ClearReconciliationGeneration(infrastructurev1beta2.AdditionalLoadBalancerReconciler(name))
+		clusterScope.DeleteCondition(infrastructurev1beta2.AdditionalLoadBalancerReadyCondition(name))
+	}
+	return reconcile.Result{}, nil
+}
+
+// deleteLoadBalancer deletes a loadBalancer, if owned by the cluster.
+func (r *OscClusterReconciler) deleteLoadBalancer(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer) error {
+	log := ctrl.LoggerFrom(ctx)
+	loadBalancerName := loadBalancerSpec.LoadBalancerName
 	svc := r.Cloud.Net(clusterScope.Tenant)
 	loadbalancer, err := svc.GetLoadBalancer(ctx, loadBalancerName)
 	if err != nil {
-		return reconcile.Result{}, err
+		return err
 	}
 	if loadbalancer == nil {
 		log.V(4).Info("The loadBalancer is already deleted", "loadBalancerName", loadBalancerName)
-		return reconcile.Result{}, nil
+		return r.deleteServerCertificates(ctx, clusterScope, loadBalancerName)
 	}
 	name := loadBalancerName + "-" + clusterScope.GetUID()
 	if name != getLoadBalancerNameTag(loadbalancer) {
 		log.V(3).Info("Loadbalancer belongs to another cluster, not deleting", "loadBalancer", loadBalancerName)
-		return reconcile.Result{}, nil
+		return nil
 	}
 
 	// err = svc.UnlinkLoadBalancerBackendMachines(ctx, loadbalancer.GetBackendIps(), loadBalancerName)
@@ -142,7 +479,7 @@ func (r *OscClusterReconciler) reconcileDeleteLoadBalancer(ctx context.Context,
 	log.V(2).Info("Deleting loadBalancer", "loadBalancerName", loadBalancerName)
 	err = svc.DeleteLoadBalancer(ctx, &loadBalancerSpec)
 	if err != nil {
-		return reconcile.Result{}, fmt.Errorf("cannot delete loadBalancer: %w", err)
+		return fmt.Errorf("cannot delete loadBalancer: %w", err)
 	}
-	return reconcile.Result{}, nil
+	return r.deleteServerCertificates(ctx, clusterScope, loadBalancerName)
 }
@@ -0,0 +1,161 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// natFailoverTarget is a natService of the cluster, with its health.
+type natFailoverTarget struct {
+	clientToken string
+	id          string
+	subregion   string
+	healthy     bool
+	failed      bool
+}
+
+// getNatFailoverTargets returns the natServices of the cluster.
+// A natService is healthy if it is available, and failed if it is deleting, deleted or missing. A pending natService is neither.
+func (r *OscClusterReconciler) getNatFailoverTargets(ctx context.Context, clusterScope *scope.ClusterScope, netId string) ([]natFailoverTarget, error) {
+	nats, err := r.Cloud.Net(clusterScope.Tenant).ListNatServices(ctx, netId)
+	if err != nil {
+		return nil, fmt.Errorf("list natServices: %w", err)
+	}
+	natSpecs := clusterScope.GetNatServices()
+	targets := make([]natFailoverTarget, 0, len(natSpecs))
+	for _, natSpec := range natSpecs {
+		id, err := r.Tracker.getNatServiceId(ctx, natSpec, clusterScope)
+		if err != nil && !IsNotFound(err) {
+			return nil, fmt.Errorf("get natService: %w", err)
+		}
+		idx := slices.IndexFunc(nats, func(nat osc.NatService) bool {
+			return id != "" && nat.NatServiceId == id
+		})
+		target := natFailoverTarget{
+			clientToken: clusterScope.GetNatServiceClientToken(natSpec),
+			id:          id,
+			subregion:   clusterScope.GetNatServiceSubregion(natSpec),
+			failed:      true,
+		}
+		if idx >= 0 {
+			target.healthy = nats[idx].State == osc.NatServiceStateAvailable
+			target.failed = nats[idx].State == osc.NatServiceStateDeleting || nats[idx].State == osc.NatServiceStateDeleted
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// getNatFailoverBackup returns the index of a healthy natService, in another subregion if possible.
+func getNatFailoverBackup(targets []natFailoverTarget, failed natFailoverTarget) int {
+	if idx := slices.IndexFunc(targets, func(target natFailoverTarget) bool {
+		return target.healthy && target.subregion != failed.subregion
+	}); idx >= 0 {
+		return idx
+	}
+	return slices.IndexFunc(targets, func(target natFailoverTarget) bool {
+		return target.healthy && target.id != failed.id
+	})
+}
+
+// reconcileNatFailover moves routes from failed natServices to healthy natServices, and moves them back once natServices have recovered.
+func (r *OscClusterReconciler) reconcileNatFailover(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.V(4).Info("Checking natServices health")
+
+	netId, err := r.Tracker.getNetId(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	targets, err := r.getNatFailoverTargets(ctx, clusterScope, netId)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	rtbls, err := svc.GetRouteTablesFromNet(ctx, netId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("list routeTables: %w", err)
+	}
+	var failedOver, noBackup []string
+	for _, rtbl := range rtbls {
+		for _, route := range rtbl.Routes {
+			if route.NatServiceId == nil {
+				continue
+			}
+			current := slices.IndexFunc(targets, func(target natFailoverTarget) bool {
+				return target.id == *route.NatServiceId
+			})
+			if current < 0 {
+				continue
+			}
+			key := natFailoverKey(rtbl.RouteTableId, route.DestinationIpRange)
+			primary := current
+			if token := r.Tracker.getNatFailover(clusterScope, key); token != "" {
+				primary = slices.IndexFunc(targets, func(target natFailoverTarget) bool {
+					return target.clientToken == token
+				})
+				if primary < 0 {
+					log.V(3).Info("Failed natService was removed, keeping route", "routeTableId", rtbl.RouteTableId, "destination", route.DestinationIpRange)
+					r.Tracker.unsetNatFailover(clusterScope, key)
+					primary = current
+				}
+			}
+			switch {
+			case primary != current && targets[primary].healthy:
+				log.V(2).Info("Restoring route to recovered natService", "routeTableId", rtbl.RouteTableId, "destination", route.DestinationIpRange, "natServiceId", targets[primary].id)
+				_, err := svc.UpdateNatRoute(ctx, route.DestinationIpRange, rtbl.RouteTableId, targets[primary].id)
+				if err != nil {
+					return reconcile.Result{}, fmt.Errorf("cannot restore route: %w", err)
+				}
+				r.Tracker.unsetNatFailover(clusterScope, key)
+				r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.NatRestoredReason,
+					"Route %s of %s restored to NAT %s", route.DestinationIpRange, rtbl.RouteTableId, targets[primary].subregion)
+			case !targets[current].failed:
+				// the natService is healthy, or pending and not failed yet
+				if primary != current {
+					failedOver = append(failedOver, key)
+				}
+			default:
+				backup := getNatFailoverBackup(targets, targets[current])
+				if backup < 0 {
+					noBackup = append(noBackup, key)
+					continue
+				}
+				log.V(2).Info("Moving route to healthy natService", "routeTableId", rtbl.RouteTableId, "destination", route.DestinationIpRange,
+					"failedNatServiceId", targets[current].id, "natServiceId", targets[backup].id)
+				_, err := svc.UpdateNatRoute(ctx, route.DestinationIpRange, rtbl.RouteTableId, targets[backup].id)
+				if err != nil {
+					return reconcile.Result{}, fmt.Errorf("cannot move route: %w", err)
+				}
+				r.Tracker.setNatFailover(clusterScope, key, targets[primary].clientToken)
+				r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeWarning, infrastructurev1beta2.NatFailoverReason,
+					"Route %s of %s moved from NAT %s to NAT %s", route.DestinationIpRange, rtbl.RouteTableId, targets[current].subregion, targets[backup].subregion)
+				failedOver = append(failedOver, key)
+			}
+		}
+	}
+	if len(noBackup) > 0 {
+		return reconcile.Result{}, fmt.Errorf("no healthy natService for routes %s", strings.Join(noBackup, ", "))
+	}
+	if len(failedOver) > 0 {
+		clusterScope.MarkCondition(infrastructurev1beta2.NatServicesHealthyCondition, infrastructurev1beta2.NatFailoverReason,
+			fmt.Errorf("routes moved to another natService: %s", strings.Join(failedOver, ", ")))
+		return reconcile.Result{}, nil
+	}
+	clusterScope.MarkCondition(infrastructurev1beta2.NatServicesHealthyCondition, "", nil)
+	return reconcile.Result{}, nil
+}
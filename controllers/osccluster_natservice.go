@@ -11,6 +11,8 @@ import (
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/util/parallel"
+	"github.com/outscale/cluster-api-provider-outscale/util/reconciler"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,41 +38,48 @@ func (r *OscClusterReconciler) reconcileNatService(ctx context.Context, clusterS
 	log.V(4).Info("Reconciling natServices")
 
 	natServiceSpecs := clusterScope.GetNatServices()
-	for _, natServiceSpec := range natServiceSpecs {
+	err := parallel.ForEach(ctx, reconciler.DefaultedParallelism(r.Parallelism), natServiceSpecs, func(ctx context.Context, natServiceSpec infrastructurev1beta2.OscNatService) error {
 		natService, err := r.Tracker.getNatService(ctx, natServiceSpec, clusterScope)
 		switch {
 		case IsNotFound(err):
 		case err != nil:
-			return reconcile.Result{}, fmt.Errorf("find existing: %w", err)
+			return fmt.Errorf("find existing: %w", err)
 		default:
 			log.V(4).Info("Found existing natService", "natServiceId", natService.NatServiceId)
-			continue
+			return nil
 		}
 
-		publicIpId, _, err := r.Tracker.IPAllocator(clusterScope).AllocateIP(ctx,
-			clusterScope.GetNatServiceClientToken(natServiceSpec), clusterScope.GetNatServiceName(natServiceSpec), clusterScope.GetNetwork().NatPublicIpPool, clusterScope)
-		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("allocate IP: %w", err)
+		publicIpId := natServiceSpec.PublicIpId
+		if publicIpId == "" {
+			publicIpId, _, err = r.Tracker.IPAllocator(clusterScope).AllocateIP(ctx,
+				clusterScope.GetNatServiceClientToken(natServiceSpec), clusterScope.GetNatServiceName(natServiceSpec), clusterScope.GetNetwork().NatPublicIpPool, clusterScope)
+			if err != nil {
+				return fmt.Errorf("allocate IP: %w", err)
+			}
 		}
 
 		subnetSpec, err := clusterScope.GetSubnet(natServiceSpec.SubnetName, infrastructurev1beta2.RoleNat, natServiceSpec.SubregionName)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("find subnet: %w", err)
+			return fmt.Errorf("find subnet: %w", err)
 		}
 		subnetId, err := r.Tracker.getSubnetId(ctx, subnetSpec, clusterScope)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("get subnet: %w", err)
+			return fmt.Errorf("get subnet: %w", err)
 		}
 
 		log.V(3).Info("Creating natService")
 		natService, err = r.Cloud.Net(clusterScope.Tenant).CreateNatService(ctx, publicIpId, subnetId,
 			clusterScope.GetNatServiceClientToken(natServiceSpec), clusterScope.GetNatServiceName(natServiceSpec), clusterScope.GetUID())
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot create natService: %w", err)
+			return fmt.Errorf("cannot create natService: %w", err)
 		}
 		log.V(2).Info("Created natService", "natServiceId", natService.NatServiceId)
 		r.Tracker.setNatServiceId(clusterScope, natServiceSpec, natService.NatServiceId)
 		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.NatServicesCreatedReason, "NAT created %s", natServiceSpec.SubregionName)
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerNatService)
 	return reconcile.Result{}, nil
@@ -8,9 +8,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/sdk/ptr"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -24,7 +29,11 @@ func (r *OscClusterReconciler) reconcileNet(ctx context.Context, clusterScope *s
 		return reconcile.Result{}, nil
 	}
 	log.V(4).Info("Reconciling net")
+	if err := r.allocateNetRange(ctx, clusterScope); err != nil {
+		return reconcile.Result{}, err
+	}
 
+	key := creationKey(tag.NetResourceType, defaultResource)
 	net, err := r.Tracker.getNet(ctx, clusterScope)
 	switch {
 	case IsNotFound(err) && !clusterScope.GetNetwork().UseExisting.Net:
@@ -32,17 +41,53 @@ func (r *OscClusterReconciler) reconcileNet(ctx context.Context, clusterScope *s
 		return reconcile.Result{}, fmt.Errorf("find existing: %w", err)
 	default:
 		log.V(4).Info("Found existing net", "netId", net.NetId)
+		if clusterScope.GetNetwork().UseExisting.Net {
+			_, tagged, err := tagShared(ctx, r.Cloud.Tag(clusterScope.Tenant), net.NetId, net.Tags, clusterScope.GetUID(), nil)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot tag existing net: %w", err)
+			}
+			if tagged {
+				log.V(2).Info("Tagged existing net as shared", "netId", net.NetId)
+			}
+		}
+		if err := r.reconcileDhcpOptions(ctx, clusterScope, net.NetId, net.DhcpOptionsSetId); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := clusterScope.EndCreation(ctx, key); err != nil {
+			return reconcile.Result{}, err
+		}
 		clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerNet)
 		return reconcile.Result{}, nil
 	}
 	log.V(3).Info("Creating net")
 	netSpec := clusterScope.GetNet()
-	net, err = r.Cloud.Net(clusterScope.Tenant).CreateNet(ctx, netSpec, clusterScope.GetUID(), clusterScope.GetNetName())
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	netId, err := createOnce(ctx, clusterScope, key, creation{
+		candidates: func() ([]string, error) {
+			return svc.ListUntaggedNetIds(ctx, netSpec.IpRange)
+		},
+		create: func() (string, error) {
+			net, err := svc.CreateNet(ctx, netSpec, clusterScope.GetUID(), clusterScope.GetNetName())
+			if net == nil {
+				return "", err
+			}
+			return net.NetId, err
+		},
+		adopt: func(id string) error {
+			return tagCreated(ctx, r.Cloud.Tag(clusterScope.Tenant), id, clusterScope.GetNetName(), clusterScope.GetUID())
+		},
+	})
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot create net: %w", err)
 	}
-	log.V(2).Info("Created net", "netId", net.NetId)
-	r.Tracker.setNetId(clusterScope, net.NetId)
+	r.Tracker.setNetId(clusterScope, netId)
+	if err := clusterScope.EndCreation(ctx, key); err != nil {
+		return reconcile.Result{}, err
+	}
+	log.V(2).Info("Created net", "netId", netId)
+	if err := r.reconcileDhcpOptions(ctx, clusterScope, netId, ""); err != nil {
+		return reconcile.Result{}, err
+	}
 	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerNet)
 	r.Recorder.Event(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.NetCreatedReason, "Net created")
 	return reconcile.Result{}, nil
@@ -51,10 +96,6 @@ func (r *OscClusterReconciler) reconcileNet(ctx context.Context, clusterScope *s
 // reconcileDeleteNet reconcile the destruction of the Net of the cluster.
 func (r *OscClusterReconciler) reconcileDeleteNet(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
-	if clusterScope.GetNetwork().UseExisting.Net {
-		log.V(4).Info("Not deleting existing net")
-		return reconcile.Result{}, nil
-	}
 	net, err := r.Tracker.getNet(ctx, clusterScope)
 	switch {
 	case IsNotFound(err):
@@ -63,6 +104,20 @@ func (r *OscClusterReconciler) reconcileDeleteNet(ctx context.Context, clusterSc
 	case err != nil:
 		return reconcile.Result{}, fmt.Errorf("find existing: %w", err)
 	}
+	if err := r.reconcileDeleteDhcpOptions(ctx, clusterScope, net.NetId, net.DhcpOptionsSetId); err != nil {
+		return reconcile.Result{}, err
+	}
+	if clusterScope.GetNetwork().UseExisting.Net {
+		log.V(4).Info("Not deleting existing net")
+		untagged, err := untagShared(ctx, r.Cloud.Tag(clusterScope.Tenant), net.NetId, net.Tags, clusterScope.GetUID(), nil)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot untag existing net: %w", err)
+		}
+		if untagged {
+			log.V(2).Info("Removed shared tags from existing net", "netId", net.NetId)
+		}
+		return reconcile.Result{}, nil
+	}
 	log.V(2).Info("Deleting net", "netId", net.NetId)
 	err = r.Cloud.Net(clusterScope.Tenant).DeleteNet(ctx, net.NetId)
 	if err != nil {
@@ -70,3 +125,152 @@ func (r *OscClusterReconciler) reconcileDeleteNet(ctx context.Context, clusterSc
 	}
 	return reconcile.Result{}, nil
 }
+
+// hasDhcpOptions returns true if the cluster has, or had, a DHCP options set.
+func hasDhcpOptions(clusterScope *scope.ClusterScope) bool {
+	if !clusterScope.GetNetwork().Net.DhcpOptions.IsZero() {
+		return true
+	}
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(defaultResource, clusterScope.GetResources().DhcpOptions) != ""
+}
+
+// dhcpOptionsMatch returns true if a DHCP options set has the options of the spec.
+func dhcpOptionsMatch(spec infrastructurev1beta2.OscDhcpOptions, dhcp *osc.DhcpOptionsSet) bool {
+	servers := ptr.From(dhcp.DomainNameServers)
+	if len(spec.DomainNameServers) == 0 {
+		// the DNS provided by Outscale is used by default
+		servers = slices.DeleteFunc(slices.Clone(servers), func(s string) bool { return s == net.OutscaleProvidedDNS })
+	}
+	return spec.DomainName == ptr.From(dhcp.DomainName) &&
+		sameSet(spec.DomainNameServers, servers) &&
+		sameSet(spec.NtpServers, ptr.From(dhcp.NtpServers))
+}
+
+// sameSet returns true if both lists have the same items, regardless of their order.
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// reconcileDhcpOptions links the DHCP options set of the spec to the net.
+// DHCP options sets cannot be updated, a new set replaces the previous one when the spec changes.
+// The previous set stays tracked until the new set is linked and the previous set is deleted.
+func (r *OscClusterReconciler) reconcileDhcpOptions(ctx context.Context, clusterScope *scope.ClusterScope, netId, dhcpOptionsSetId string) error {
+	log := ctrl.LoggerFrom(ctx)
+	if !hasDhcpOptions(clusterScope) {
+		return nil
+	}
+	spec := clusterScope.GetNetwork().Net.DhcpOptions
+	if spec.IsZero() {
+		log.V(3).Info("DHCP options are no longer in spec")
+		return r.reconcileDeleteDhcpOptions(ctx, clusterScope, netId, dhcpOptionsSetId)
+	}
+	dhcp, err := r.Tracker.getDhcpOptions(ctx, clusterScope)
+	switch {
+	case IsNotFound(err):
+		r.Tracker.setDhcpOptionsId(clusterScope, "")
+	case err != nil:
+		return err
+	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	if dhcp != nil && !dhcpOptionsMatch(spec, dhcp) {
+		id := ptr.From(dhcp.DhcpOptionsSetId)
+		log.V(3).Info("DHCP options have changed", "dhcpOptionsSetId", id)
+		if id == dhcpOptionsSetId {
+			// The set is linked to the net, it is deleted once the new set is linked.
+			if err := r.deletePreviousDhcpOptions(ctx, clusterScope); err != nil {
+				return err
+			}
+			r.Tracker.setPreviousDhcpOptionsId(clusterScope, id)
+		} else {
+			log.V(2).Info("Deleting unlinked dhcp options", "dhcpOptionsSetId", id)
+			if err := svc.DeleteDhcpOptions(ctx, id); err != nil {
+				return fmt.Errorf("cannot delete dhcp options: %w", err)
+			}
+		}
+		r.Tracker.setDhcpOptionsId(clusterScope, "")
+		dhcp = nil
+	}
+	if dhcp == nil {
+		log.V(3).Info("Creating dhcp options")
+		dhcp, err = svc.CreateDhcpOptions(ctx, spec, clusterScope.GetUID())
+		if err != nil {
+			return fmt.Errorf("cannot create dhcp options: %w", err)
+		}
+		r.Tracker.setDhcpOptionsId(clusterScope, ptr.From(dhcp.DhcpOptionsSetId))
+		log.V(2).Info("Created dhcp options", "dhcpOptionsSetId", ptr.From(dhcp.DhcpOptionsSetId))
+		r.Recorder.Event(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.DhcpOptionsCreatedReason, "DHCP options created")
+	}
+	if id := ptr.From(dhcp.DhcpOptionsSetId); id != dhcpOptionsSetId {
+		log.V(2).Info("Linking dhcp options to net", "dhcpOptionsSetId", id, "netId", netId)
+		err = svc.LinkDhcpOptions(ctx, id, netId)
+		if err != nil {
+			return fmt.Errorf("cannot link dhcp options: %w", err)
+		}
+	}
+	return r.deletePreviousDhcpOptions(ctx, clusterScope)
+}
+
+// deletePreviousDhcpOptions deletes the DHCP options set replaced by the current one, which is no longer linked to the net.
+func (r *OscClusterReconciler) deletePreviousDhcpOptions(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	previousId := r.Tracker.getPreviousDhcpOptionsId(clusterScope)
+	if previousId == "" {
+		return nil
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Deleting previous dhcp options", "dhcpOptionsSetId", previousId)
+	err := r.Cloud.Net(clusterScope.Tenant).DeleteDhcpOptions(ctx, previousId)
+	if err != nil {
+		return fmt.Errorf("cannot delete previous dhcp options: %w", err)
+	}
+	r.Tracker.setPreviousDhcpOptionsId(clusterScope, "")
+	return nil
+}
+
+// reconcileDeleteDhcpOptions restores the default DHCP options of the net, and deletes the DHCP options sets of the cluster.
+func (r *OscClusterReconciler) reconcileDeleteDhcpOptions(ctx context.Context, clusterScope *scope.ClusterScope, netId, dhcpOptionsSetId string) error {
+	log := ctrl.LoggerFrom(ctx)
+	if !hasDhcpOptions(clusterScope) {
+		return nil
+	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	previousId := r.Tracker.getPreviousDhcpOptionsId(clusterScope)
+	if previousId != "" && previousId == dhcpOptionsSetId {
+		log.V(2).Info("Restoring default dhcp options", "netId", netId)
+		err := svc.LinkDhcpOptions(ctx, net.DefaultDhcpOptionsSetId, netId)
+		if err != nil {
+			return fmt.Errorf("cannot restore default dhcp options: %w", err)
+		}
+		dhcpOptionsSetId = net.DefaultDhcpOptionsSetId
+	}
+	if err := r.deletePreviousDhcpOptions(ctx, clusterScope); err != nil {
+		return err
+	}
+	dhcp, err := r.Tracker.getDhcpOptions(ctx, clusterScope)
+	switch {
+	case IsNotFound(err):
+		log.V(4).Info("The dhcp options are already deleted")
+		r.Tracker.setDhcpOptionsId(clusterScope, "")
+		return nil
+	case err != nil:
+		return err
+	}
+	id := ptr.From(dhcp.DhcpOptionsSetId)
+	if id == dhcpOptionsSetId {
+		log.V(2).Info("Restoring default dhcp options", "netId", netId)
+		err = svc.LinkDhcpOptions(ctx, net.DefaultDhcpOptionsSetId, netId)
+		if err != nil {
+			return fmt.Errorf("cannot restore default dhcp options: %w", err)
+		}
+	}
+	log.V(2).Info("Deleting dhcp options", "dhcpOptionsSetId", id)
+	err = svc.DeleteDhcpOptions(ctx, id)
+	if err != nil {
+		return fmt.Errorf("cannot delete dhcp options: %w", err)
+	}
+	r.Tracker.setDhcpOptionsId(clusterScope, "")
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/util/cidr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// allocateNetRange allocates the ip range of the net from the net pool, if no ip range is set.
+// The allocation is recorded in the status of the pool, and the ip range is set in the spec of the cluster.
+// Concurrent allocations from the same pool conflict on the status update, and are retried.
+func (r *OscClusterReconciler) allocateNetRange(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	netSpec := &clusterScope.GetNetwork().Net
+	if netSpec.NetPool == "" || netSpec.IpRange != "" || clusterScope.GetNetwork().UseExisting.Net {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	var pool infrastructurev1beta2.OscNetPool
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: netSpec.NetPool}, &pool); err != nil {
+		return fmt.Errorf("get net pool: %w", err)
+	}
+	for ipRange, alloc := range pool.Status.Allocations {
+		if alloc.UID == clusterScope.GetUID() {
+			log.V(3).Info("Found net range allocation", "netPool", pool.Name, "ipRange", ipRange)
+			netSpec.IpRange = ipRange
+			return nil
+		}
+	}
+
+	parent, err := netip.ParsePrefix(pool.Spec.IpRange)
+	if err != nil {
+		return fmt.Errorf("invalid net pool range: %w", err)
+	}
+	var used []netip.Prefix
+	for _, ipRange := range pool.Spec.ExcludedIpRanges {
+		if p, err := netip.ParsePrefix(ipRange); err == nil {
+			used = append(used, p)
+		}
+	}
+	for ipRange := range pool.Status.Allocations {
+		if p, err := netip.ParsePrefix(ipRange); err == nil {
+			used = append(used, p)
+		}
+	}
+	if clusterScope.GetNetwork().NetPeering.Enable {
+		mgmtRange, err := r.getMgmtNetRange(ctx, clusterScope)
+		if err != nil {
+			return err
+		}
+		used = append(used, mgmtRange)
+	}
+	p, err := cidr.Allocate(parent, used, pool.GetPrefixLength())
+	if err != nil {
+		return fmt.Errorf("allocate from net pool %s: %w", pool.Name, err)
+	}
+	if pool.Status.Allocations == nil {
+		pool.Status.Allocations = map[string]infrastructurev1beta2.OscNetPoolAllocation{}
+	}
+	pool.Status.Allocations[p.String()] = infrastructurev1beta2.OscNetPoolAllocation{
+		Namespace: clusterScope.GetNamespace(),
+		Name:      clusterScope.GetName(),
+		UID:       clusterScope.GetUID(),
+	}
+	if err := r.Client.Status().Update(ctx, &pool); err != nil {
+		return fmt.Errorf("record net pool allocation: %w", err)
+	}
+	log.V(2).Info("Allocated net range", "netPool", pool.Name, "ipRange", p.String())
+	netSpec.IpRange = p.String()
+	r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.NetRangeAllocatedReason,
+		"Net range %s allocated from %s", p.String(), pool.Name)
+	return nil
+}
+
+// getMgmtNetRange returns the ip range of the management net, which must not overlap with a peered net.
+func (r *OscClusterReconciler) getMgmtNetRange(ctx context.Context, clusterScope *scope.ClusterScope) (netip.Prefix, error) {
+	mgmt, err := getMgmtTenant(ctx, r.Client, r.Cloud, clusterScope.OscCluster)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("cannot get mgmt credentials: %w", err)
+	}
+	n, err := r.Cloud.Net(mgmt).GetNet(ctx, r.getMgmtNetID(clusterScope))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("get mgmt net: %w", err)
+	}
+	p, err := netip.ParsePrefix(n.IpRange)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid mgmt net range: %w", err)
+	}
+	return p, nil
+}
+
+// releaseNetRange releases the ip range allocated to the cluster in the net pool.
+func (r *OscClusterReconciler) releaseNetRange(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	name := clusterScope.GetNetwork().Net.NetPool
+	if name == "" {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	var pool infrastructurev1beta2.OscNetPool
+	err := r.Client.Get(ctx, client.ObjectKey{Name: name}, &pool)
+	switch {
+	case apierrors.IsNotFound(err):
+		log.V(3).Info("Net pool not found, no range to release", "netPool", name)
+		return nil
+	case err != nil:
+		return fmt.Errorf("get net pool: %w", err)
+	}
+	var released []string
+	for ipRange, alloc := range pool.Status.Allocations {
+		if alloc.UID == clusterScope.GetUID() {
+			delete(pool.Status.Allocations, ipRange)
+			released = append(released, ipRange)
+		}
+	}
+	if len(released) == 0 {
+		return nil
+	}
+	if err := r.Client.Status().Update(ctx, &pool); err != nil {
+		return fmt.Errorf("release net pool allocation: %w", err)
+	}
+	log.V(2).Info("Released net range", "netPool", name, "ipRanges", released)
+	return nil
+}
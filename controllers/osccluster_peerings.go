@@ -0,0 +1,326 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/goutils/sdk/ptr"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func isPeeringAlive(np *osc.NetPeering) bool {
+	return np.State.Name == osc.NetPeeringStateNameActive || np.State.Name == osc.NetPeeringStateNamePendingAcceptance
+}
+
+// reconcilePeeringRoute routes an IP range to a peering.
+// A route to another peering, left by a peering that has been recreated, is updated.
+func reconcilePeeringRoute(ctx context.Context, svc net.Servicer, rtbl osc.RouteTable, ipRange, netPeeringId string) error {
+	log := ctrl.LoggerFrom(ctx)
+	idx := slices.IndexFunc(rtbl.Routes, func(r osc.Route) bool {
+		return r.DestinationIpRange == ipRange
+	})
+	switch {
+	case idx >= 0 && ptr.From(rtbl.Routes[idx].NetPeeringId) == netPeeringId:
+		return nil
+	case idx >= 0 && rtbl.Routes[idx].NetPeeringId != nil:
+		log.V(3).Info("Updating route to peering", "routeTableId", rtbl.RouteTableId, "IPRange", ipRange, "previousNetPeeringId", *rtbl.Routes[idx].NetPeeringId)
+		_, err := svc.UpdatePeeringRoute(ctx, ipRange, rtbl.RouteTableId, netPeeringId)
+		return err
+	default:
+		log.V(3).Info("Creating route to peering", "routeTableId", rtbl.RouteTableId, "IPRange", ipRange)
+		_, err := svc.CreateRoute(ctx, ipRange, rtbl.RouteTableId, netPeeringId, "netPeering")
+		return err
+	}
+}
+
+// getPeerIPRanges returns the peer IP ranges to route to the peering.
+func (r *OscClusterReconciler) getPeerIPRanges(ctx context.Context, peer tenant.Tenant, peering infrastructurev1beta2.OscPeering, np *osc.NetPeering) ([]string, error) {
+	if len(peering.PeerSubnetIDs) == 0 {
+		return []string{ptr.From(np.AccepterNet.IpRange)}, nil
+	}
+	peerSvc := r.Cloud.Net(peer)
+	ipRanges := make([]string, 0, len(peering.PeerSubnetIDs))
+	for _, subnetId := range peering.PeerSubnetIDs {
+		sn, err := peerSvc.GetSubnet(ctx, subnetId)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("get peer subnet: %w", err)
+		case sn == nil:
+			return nil, fmt.Errorf("peer subnet %s not found", subnetId)
+		}
+		ipRanges = append(ipRanges, sn.IpRange)
+	}
+	return ipRanges, nil
+}
+
+// getPeerRouteTables returns the peer route tables that need to be updated.
+func (r *OscClusterReconciler) getPeerRouteTables(ctx context.Context, peer tenant.Tenant, peering infrastructurev1beta2.OscPeering) ([]osc.RouteTable, error) {
+	rtbls, err := r.Cloud.Net(peer).GetRouteTablesFromNet(ctx, peering.NetID)
+	if err != nil {
+		return nil, fmt.Errorf("list peer route tables: %w", err)
+	}
+	if len(peering.PeerSubnetIDs) == 0 {
+		return rtbls, nil
+	}
+	var linked []osc.RouteTable
+	for _, subnetId := range peering.PeerSubnetIDs {
+		idx := slices.IndexFunc(rtbls, func(rtbl osc.RouteTable) bool {
+			return slices.ContainsFunc(rtbl.LinkRouteTables, func(l osc.LinkRouteTable) bool {
+				return l.SubnetId == subnetId
+			})
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("no route table is linked to peer subnet %s", subnetId)
+		}
+		if !slices.ContainsFunc(linked, func(rtbl osc.RouteTable) bool {
+			return rtbl.RouteTableId == rtbls[idx].RouteTableId
+		}) {
+			linked = append(linked, rtbls[idx])
+		}
+	}
+	return linked, nil
+}
+
+// getClusterRouteTablesAndIPRanges computes the cluster route tables that need to be updated, and the cluster IP ranges routed to the peering.
+func (r *OscClusterReconciler) getClusterRouteTablesAndIPRanges(ctx context.Context, clusterScope *scope.ClusterScope, peering infrastructurev1beta2.OscPeering) ([]osc.RouteTable, []string, error) {
+	netId, err := r.Tracker.getNetId(ctx, clusterScope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get net: %w", err)
+	}
+	rtbls, err := r.Cloud.Net(clusterScope.Tenant).GetRouteTablesFromNet(ctx, netId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list route tables: %w", err)
+	}
+	if len(peering.Roles) == 0 {
+		return rtbls, []string{clusterScope.GetNet().IpRange}, nil
+	}
+	var (
+		subnetIds []string
+		ipRanges  []string
+	)
+	for _, subnet := range clusterScope.GetSubnets() {
+		if !slices.ContainsFunc(subnet.Roles, func(role infrastructurev1beta2.OscRole) bool {
+			return slices.Contains(peering.Roles, role)
+		}) {
+			continue
+		}
+		subnetId, err := r.Tracker.getSubnetId(ctx, subnet, clusterScope)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get subnet: %w", err)
+		}
+		subnetIds = append(subnetIds, subnetId)
+		ipRanges = append(ipRanges, subnet.IpSubnetRange)
+	}
+	rtbls = slices.DeleteFunc(rtbls, func(rtbl osc.RouteTable) bool {
+		return !slices.ContainsFunc(rtbl.LinkRouteTables, func(l osc.LinkRouteTable) bool {
+			return slices.Contains(subnetIds, l.SubnetId)
+		})
+	})
+	return rtbls, ipRanges, nil
+}
+
+// reconcilePeering reconciles a peering of the cluster and its routes.
+func (r *OscClusterReconciler) reconcilePeering(ctx context.Context, clusterScope *scope.ClusterScope, peering infrastructurev1beta2.OscPeering) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("peering", peering.Name)
+	if !clusterScope.NeedReconciliation(infrastructurev1beta2.PeeringReconciler(peering.Name)) {
+		log.V(4).Info("No need for peering reconciliation")
+		return reconcile.Result{}, nil
+	}
+	log.V(4).Info("Reconciling peering")
+
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	np, err := r.Tracker.getPeering(ctx, peering, clusterScope)
+	switch {
+	case IsNotFound(err):
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("get existing: %w", err)
+	}
+	manual := peering.AcceptPolicy == infrastructurev1beta2.PeeringAcceptManual
+	// A peering rejected by the peer account is not recreated, the peering needs to be removed from the spec and added again.
+	if np != nil && manual && np.State.Name == osc.NetPeeringStateNameRejected {
+		return reconcile.Result{}, fmt.Errorf("netPeering %s has been rejected by account %s", np.NetPeeringId, peering.AccountID)
+	}
+	if np == nil || !isPeeringAlive(np) {
+		netId, err := r.Tracker.getNetId(ctx, clusterScope)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		log.V(3).Info("Creating peering", "netId", peering.NetID, "accountId", peering.AccountID)
+		np, err = svc.CreatePeering(ctx, netId, peering.NetID, peering.AccountID, clusterScope.GetUID())
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot create netPeering: %w", err)
+		}
+		log.V(2).Info("Created peering", "netPeeringId", np.NetPeeringId)
+		r.Tracker.setPeeringId(clusterScope, peering, np.NetPeeringId)
+		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.PeeringCreatedReason, "Peering %s created", peering.Name)
+	}
+
+	peer, err := getPeeringTenant(ctx, r.Client, clusterScope, peering)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot get peer credentials: %w", err)
+	}
+	if np.State.Name == osc.NetPeeringStateNamePendingAcceptance {
+		if manual {
+			return reconcile.Result{}, fmt.Errorf("netPeering %s is waiting to be accepted by account %s", np.NetPeeringId, peering.AccountID)
+		}
+		log.V(2).Info("Accepting peering", "netPeeringId", np.NetPeeringId)
+		err = r.Cloud.Net(peer).AcceptNetPeering(ctx, np.NetPeeringId)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot accept netPeering: %w", err)
+		}
+		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.PeeringAcceptedReason, "Peering %s accepted", peering.Name)
+	}
+
+	// Add routes to cluster route tables
+	peerIPRanges, err := r.getPeerIPRanges(ctx, peer, peering, np)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	rtbls, ipRanges, err := r.getClusterRouteTablesAndIPRanges(ctx, clusterScope, peering)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	for _, rtbl := range rtbls {
+		for _, peerIPRange := range peerIPRanges {
+			if err := reconcilePeeringRoute(ctx, svc, rtbl, peerIPRange, np.NetPeeringId); err != nil {
+				return reconcile.Result{}, fmt.Errorf("reconcile route: %w", err)
+			}
+		}
+	}
+
+	// Add routes to peer route tables, unless the peer account manages them
+	if !manual {
+		peerSvc := r.Cloud.Net(peer)
+		peerRtbls, err := r.getPeerRouteTables(ctx, peer, peering)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		for _, peerRtbl := range peerRtbls {
+			for _, ipRange := range ipRanges {
+				if err := reconcilePeeringRoute(ctx, peerSvc, peerRtbl, ipRange, np.NetPeeringId); err != nil {
+					return reconcile.Result{}, fmt.Errorf("reconcile peer route: %w", err)
+				}
+			}
+		}
+	}
+
+	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.PeeringReconciler(peering.Name))
+	return reconcile.Result{}, nil
+}
+
+// reconcileDeletePeering reconciles the destruction of a peering of the cluster and its routes.
+func (r *OscClusterReconciler) reconcileDeletePeering(ctx context.Context, clusterScope *scope.ClusterScope, peering infrastructurev1beta2.OscPeering) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("peering", peering.Name)
+	netId, err := r.Tracker.getNetId(ctx, clusterScope)
+	switch {
+	case IsNotFound(err):
+		log.V(4).Info("The net is already deleted, no peering expected")
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("find net: %w", err)
+	}
+	np, err := r.Tracker.getPeering(ctx, peering, clusterScope)
+	switch {
+	case IsNotFound(err):
+		log.V(4).Info("The peering is already deleted")
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+
+	// remove routes from peer route tables
+	if peering.AcceptPolicy != infrastructurev1beta2.PeeringAcceptManual {
+		peer, err := getPeeringTenant(ctx, r.Client, clusterScope, peering)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot get peer credentials: %w", err)
+		}
+		peerSvc := r.Cloud.Net(peer)
+		peerRtbls, err := peerSvc.GetRouteTablesFromNet(ctx, peering.NetID)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("list peer route tables: %w", err)
+		}
+		for _, peerRtbl := range peerRtbls {
+			for _, r := range peerRtbl.Routes {
+				if ptr.From(r.NetPeeringId) != np.NetPeeringId {
+					continue
+				}
+				log.V(3).Info("Deleting peer route to peering", "routeTableId", peerRtbl.RouteTableId, "IPRange", r.DestinationIpRange)
+				err = peerSvc.DeleteRoute(ctx, r.DestinationIpRange, peerRtbl.RouteTableId)
+				if err != nil {
+					return reconcile.Result{}, fmt.Errorf("delete peer route: %w", err)
+				}
+			}
+		}
+	}
+
+	// remove routes from cluster route tables
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	rtbls, err := svc.GetRouteTablesFromNet(ctx, netId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("list route tables: %w", err)
+	}
+	for _, rtbl := range rtbls {
+		for _, r := range rtbl.Routes {
+			if ptr.From(r.NetPeeringId) != np.NetPeeringId {
+				continue
+			}
+			log.V(3).Info("Deleting route to peering", "routeTableId", rtbl.RouteTableId, "IPRange", r.DestinationIpRange)
+			err := svc.DeleteRoute(ctx, r.DestinationIpRange, rtbl.RouteTableId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("delete route: %w", err)
+			}
+		}
+	}
+
+	if !isPeeringAlive(np) {
+		return reconcile.Result{}, nil
+	}
+	log.V(2).Info("Deleting peering", "netPeeringId", np.NetPeeringId)
+	err = svc.DeleteNetPeering(ctx, np.NetPeeringId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot delete netPeering: %w", err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileRemovedPeerings deletes the peerings removed from the spec, along with their routes.
+// The credentials of a removed peering are no longer known: routes of the peer net are only deleted if it belongs to the account of the cluster.
+func (r *OscClusterReconciler) reconcileRemovedPeerings(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	for _, name := range r.Tracker.getRemovedPeerings(clusterScope) {
+		peering := infrastructurev1beta2.OscPeering{Name: name}
+		np, err := r.Tracker.getPeering(ctx, peering, clusterScope)
+		switch {
+		case IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("get removed peering %s: %w", name, err)
+		default:
+			log.V(3).Info("Deleting removed peering", "peering", name, "netPeeringId", np.NetPeeringId)
+			peering.NetID = ptr.From(np.AccepterNet.NetId)
+			peering.AccountID = ptr.From(np.AccepterNet.AccountId)
+			if peering.AccountID != ptr.From(np.SourceNet.AccountId) {
+				peering.AcceptPolicy = infrastructurev1beta2.PeeringAcceptManual
+			}
+			if _, err := r.reconcileDeletePeering(ctx, clusterScope, peering); err != nil {
+				return reconcile.Result{}, fmt.Errorf("delete removed peering %s: %w", name, err)
+			}
+		}
+		r.Tracker.unsetPeeringId(clusterScope, name)
+		clusterScope.ClearReconciliationGeneration(infrastructurev1beta2.PeeringReconciler(name))
+		clusterScope.DeleteCondition(infrastructurev1beta2.PeeringReadyCondition(name))
+	}
+	return reconcile.Result{}, nil
+}
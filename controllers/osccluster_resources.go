@@ -8,6 +8,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
@@ -16,7 +20,10 @@ import (
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 )
 
-const bastionIPResourceKey = "bastion"
+const (
+	bastionIPResourceKey      = "bastion"
+	loadBalancerIPResourceKey = "loadbalancer"
+)
 
 type ClusterResourceTracker struct {
 	Cloud services.Servicer
@@ -38,6 +45,16 @@ func (t *ClusterResourceTracker) getNet(ctx context.Context, clusterScope *scope
 	}
 }
 
+// trackedNetId returns the id for the cluster network if known without any API call, an empty string otherwise.
+func (t *ClusterResourceTracker) trackedNetId(clusterScope *scope.ClusterScope) string {
+	if id := clusterScope.GetNet().ResourceId; id != "" {
+		return id
+	}
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(defaultResource, clusterScope.GetResources().Net)
+}
+
 // getNetId returns the id for the cluster network, a wrapped ErrNoResourceFound error otherwise.
 func (t *ClusterResourceTracker) getNetId(ctx context.Context, clusterScope *scope.ClusterScope) (string, error) {
 	id := clusterScope.GetNet().ResourceId
@@ -45,13 +62,14 @@ func (t *ClusterResourceTracker) getNetId(ctx context.Context, clusterScope *sco
 		return id, nil
 	}
 
-	rsrc := clusterScope.GetResources()
-	id = getResource(defaultResource, rsrc.Net)
+	clusterScope.Lock()
+	id = getResource(defaultResource, clusterScope.GetResources().Net)
+	clusterScope.Unlock()
 	if id != "" {
 		return id, nil
 	}
 	// Search by OscK8sClusterID/(uid): owned tag
-	tg, err := t.Cloud.Tag(clusterScope.Tenant).ReadOwnedByTag(ctx, tag.NetResourceType, clusterScope.GetUID())
+	tg, err := t.Cloud.Cache().ReadOwnedByTag(ctx, clusterScope.Tenant, t.Cloud.Tag(clusterScope.Tenant), tag.NetResourceType, clusterScope.GetUID())
 	if err != nil {
 		return "", fmt.Errorf("get net: %w", err)
 	}
@@ -75,6 +93,8 @@ func (t *ClusterResourceTracker) getNetId(ctx context.Context, clusterScope *sco
 }
 
 func (t *ClusterResourceTracker) setNetId(clusterScope *scope.ClusterScope, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.Net == nil {
 		rsrc.Net = map[string]string{}
@@ -100,13 +120,14 @@ func (t *ClusterResourceTracker) getNetPeering(ctx context.Context, clusterScope
 
 // getNetPeeringId returns the id for the netpeering, a wrapped ErrNoResourceFound error otherwise.
 func (t *ClusterResourceTracker) getNetPeeringId(ctx context.Context, clusterScope *scope.ClusterScope) (string, error) {
-	rsrc := clusterScope.GetResources()
-	id := getResource(defaultResource, rsrc.NetPeering)
+	clusterScope.Lock()
+	id := getResource(defaultResource, clusterScope.GetResources().NetPeering)
+	clusterScope.Unlock()
 	if id != "" {
 		return id, nil
 	}
 	// Search by OscK8sClusterID/(uid): owned tag
-	tg, err := t.Cloud.Tag(clusterScope.Tenant).ReadOwnedByTag(ctx, tag.NetPeeringResourceType, clusterScope.GetUID())
+	tg, err := t.Cloud.Cache().ReadOwnedByTag(ctx, clusterScope.Tenant, t.Cloud.Tag(clusterScope.Tenant), tag.NetPeeringResourceType, clusterScope.GetUID())
 	if err != nil {
 		return "", fmt.Errorf("get net peering: %w", err)
 	}
@@ -118,6 +139,8 @@ func (t *ClusterResourceTracker) getNetPeeringId(ctx context.Context, clusterSco
 }
 
 func (t *ClusterResourceTracker) setNetPeeringId(clusterScope *scope.ClusterScope, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.NetPeering == nil {
 		rsrc.NetPeering = map[string]string{}
@@ -125,9 +148,378 @@ func (t *ClusterResourceTracker) setNetPeeringId(clusterScope *scope.ClusterScop
 	rsrc.NetPeering[defaultResource] = id
 }
 
-func (t *ClusterResourceTracker) _getInternetServiceOrId(ctx context.Context, clusterScope *scope.ClusterScope) (*osc.InternetService, string, error) {
+// getPeering returns the netpeering of a peering, a wrapped ErrNoResourceFound error otherwise.
+func (t *ClusterResourceTracker) getPeering(ctx context.Context, peering infrastructurev1beta2.OscPeering, clusterScope *scope.ClusterScope) (*osc.NetPeering, error) {
+	clusterScope.Lock()
+	id := getResource(peering.Name, clusterScope.GetResources().NetPeering)
+	clusterScope.Unlock()
+	svc := t.Cloud.Net(clusterScope.Tenant)
+	if id != "" {
+		np, err := svc.GetNetPeering(ctx, id)
+		switch {
+		case err != nil:
+			return nil, err
+		case np == nil:
+			return nil, fmt.Errorf("get net peering %s: %w", id, ErrMissingResource)
+		default:
+			return np, nil
+		}
+	}
+	// Search by source and accepter nets
+	netId, err := t.getNetId(ctx, clusterScope)
+	if err != nil {
+		return nil, err
+	}
+	np, err := svc.GetNetPeeringFromNet(ctx, netId, peering.NetID, peering.AccountID)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("get net peering: %w", err)
+	case np == nil:
+		return nil, fmt.Errorf("get net peering: %w", ErrNoResourceFound)
+	}
+	t.setPeeringId(clusterScope, peering, np.NetPeeringId)
+	return np, nil
+}
+
+func (t *ClusterResourceTracker) setPeeringId(clusterScope *scope.ClusterScope, peering infrastructurev1beta2.OscPeering, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.NetPeering == nil {
+		rsrc.NetPeering = map[string]string{}
+	}
+	rsrc.NetPeering[peering.Name] = id
+}
+
+// getRemovedPeerings returns the names of the tracked peerings that are no longer in the spec.
+func (t *ClusterResourceTracker) getRemovedPeerings(clusterScope *scope.ClusterScope) []string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	var names []string
+	for name := range clusterScope.GetResources().NetPeering {
+		if name == defaultResource {
+			continue
+		}
+		if !slices.ContainsFunc(clusterScope.GetNetwork().Peerings, func(p infrastructurev1beta2.OscPeering) bool { return p.Name == name }) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (t *ClusterResourceTracker) unsetPeeringId(clusterScope *scope.ClusterScope, name string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().NetPeering, name)
+}
+
+// getVirtualGateway returns the virtual gateway linked to the cluster net, a wrapped ErrNoResourceFound error otherwise.
+func (t *ClusterResourceTracker) getVirtualGateway(ctx context.Context, clusterScope *scope.ClusterScope) (*osc.VirtualGateway, error) {
+	id := clusterScope.GetNetwork().Vpn.VirtualGatewayID
+	if id == "" {
+		clusterScope.Lock()
+		id = getResource(defaultResource, clusterScope.GetResources().VirtualGateway)
+		clusterScope.Unlock()
+	}
+	if id == "" {
+		// Search by OscK8sClusterID/(uid): owned tag
+		tg, err := t.Cloud.Cache().ReadOwnedByTag(ctx, clusterScope.Tenant, t.Cloud.Tag(clusterScope.Tenant), tag.VirtualGatewayResourceType, clusterScope.GetUID())
+		if err != nil {
+			return nil, fmt.Errorf("get virtual gateway: %w", err)
+		}
+		if tg == nil || tg.ResourceId == "" {
+			return nil, fmt.Errorf("get virtual gateway: %w", ErrNoResourceFound)
+		}
+		id = tg.ResourceId
+		t.setVirtualGatewayId(clusterScope, id)
+	}
+	vgw, err := t.Cloud.Net(clusterScope.Tenant).GetVirtualGateway(ctx, id)
+	switch {
+	case err != nil:
+		return nil, err
+	case vgw == nil:
+		return nil, fmt.Errorf("get virtual gateway %s: %w", id, ErrMissingResource)
+	default:
+		return vgw, nil
+	}
+}
+
+func (t *ClusterResourceTracker) setVirtualGatewayId(clusterScope *scope.ClusterScope, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.VirtualGateway == nil {
+		rsrc.VirtualGateway = map[string]string{}
+	}
+	rsrc.VirtualGateway[defaultResource] = id
+}
+
+// getVirtualGatewayId returns the id of the tracked virtual gateway, if any.
+func (t *ClusterResourceTracker) getVirtualGatewayId(clusterScope *scope.ClusterScope) string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(defaultResource, clusterScope.GetResources().VirtualGateway)
+}
+
+func (t *ClusterResourceTracker) unsetVirtualGatewayId(clusterScope *scope.ClusterScope) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().VirtualGateway, defaultResource)
+}
+
+// getDhcpOptions returns the DHCP options set of the cluster, a wrapped ErrNoResourceFound error otherwise.
+func (t *ClusterResourceTracker) getDhcpOptions(ctx context.Context, clusterScope *scope.ClusterScope) (*osc.DhcpOptionsSet, error) {
+	clusterScope.Lock()
+	id := getResource(defaultResource, clusterScope.GetResources().DhcpOptions)
+	clusterScope.Unlock()
+	if id == "" {
+		// Search by OscK8sClusterID/(uid): owned tag
+		tg, err := t.Cloud.Cache().ReadOwnedByTag(ctx, clusterScope.Tenant, t.Cloud.Tag(clusterScope.Tenant), tag.DhcpOptionsResourceType, clusterScope.GetUID())
+		if err != nil {
+			return nil, fmt.Errorf("get dhcp options: %w", err)
+		}
+		if tg == nil || tg.ResourceId == "" {
+			return nil, fmt.Errorf("get dhcp options: %w", ErrNoResourceFound)
+		}
+		id = tg.ResourceId
+		t.setDhcpOptionsId(clusterScope, id)
+	}
+	dhcp, err := t.Cloud.Net(clusterScope.Tenant).GetDhcpOptions(ctx, id)
+	switch {
+	case err != nil:
+		return nil, err
+	case dhcp == nil:
+		return nil, fmt.Errorf("get dhcp options %s: %w", id, ErrMissingResource)
+	default:
+		return dhcp, nil
+	}
+}
+
+// hasAdditionalLoadBalancer returns true if an additional loadBalancer is tracked.
+func (t *ClusterResourceTracker) hasAdditionalLoadBalancer(clusterScope *scope.ClusterScope, name string) bool {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	_, found := clusterScope.GetResources().AdditionalLoadBalancers[name]
+	return found
+}
+
+// getRemovedAdditionalLoadBalancers returns the names of the tracked additional loadBalancers that are no longer in the spec.
+func (t *ClusterResourceTracker) getRemovedAdditionalLoadBalancers(clusterScope *scope.ClusterScope) []string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	albs := clusterScope.GetNetwork().AdditionalLoadBalancers
+	var names []string
+	for name := range clusterScope.GetResources().AdditionalLoadBalancers {
+		if slices.ContainsFunc(albs, func(alb infrastructurev1beta2.OscAdditionalLoadBalancer) bool { return alb.LoadBalancerName == name }) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (t *ClusterResourceTracker) setAdditionalLoadBalancer(clusterScope *scope.ClusterScope, name, dnsName string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.AdditionalLoadBalancers == nil {
+		rsrc.AdditionalLoadBalancers = map[string]string{}
+	}
+	rsrc.AdditionalLoadBalancers[name] = dnsName
+}
+
+func (t *ClusterResourceTracker) unsetAdditionalLoadBalancer(clusterScope *scope.ClusterScope, name string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().AdditionalLoadBalancers, name)
+}
+
+// getEgressDestinations returns the IP ranges of the egress destinations last applied to the security groups.
+func (t *ClusterResourceTracker) getEgressDestinations(clusterScope *scope.ClusterScope) map[string]string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return maps.Clone(clusterScope.GetResources().EgressDestinations)
+}
+
+func (t *ClusterResourceTracker) setEgressDestinations(clusterScope *scope.ClusterScope, destinations map[string]string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	clusterScope.GetResources().EgressDestinations = destinations
+}
+
+// getCniProfileHash returns the hash of the custom CNI profile last applied to the security groups.
+func (t *ClusterResourceTracker) getCniProfileHash(clusterScope *scope.ClusterScope) string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(defaultResource, clusterScope.GetResources().CniProfile)
+}
+
+func (t *ClusterResourceTracker) setCniProfileHash(clusterScope *scope.ClusterScope, hash string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if hash == "" {
+		delete(rsrc.CniProfile, defaultResource)
+		return
+	}
+	if rsrc.CniProfile == nil {
+		rsrc.CniProfile = map[string]string{}
+	}
+	rsrc.CniProfile[defaultResource] = hash
+}
+
+func (t *ClusterResourceTracker) setDhcpOptionsId(clusterScope *scope.ClusterScope, id string) {
+	t.setDhcpOptionsIdForKey(clusterScope, defaultResource, id)
+}
+
+// previousDhcpOptionsResource is the key of a replaced DHCP options set, tracked until it is deleted.
+const previousDhcpOptionsResource = "previous"
+
+// getPreviousDhcpOptionsId returns the id of the DHCP options set replaced by the current one, if not yet deleted.
+func (t *ClusterResourceTracker) getPreviousDhcpOptionsId(clusterScope *scope.ClusterScope) string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(previousDhcpOptionsResource, clusterScope.GetResources().DhcpOptions)
+}
+
+func (t *ClusterResourceTracker) setPreviousDhcpOptionsId(clusterScope *scope.ClusterScope, id string) {
+	t.setDhcpOptionsIdForKey(clusterScope, previousDhcpOptionsResource, id)
+}
+
+func (t *ClusterResourceTracker) setDhcpOptionsIdForKey(clusterScope *scope.ClusterScope, key, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
-	id := getResource(defaultResource, rsrc.InternetService)
+	if id == "" {
+		delete(rsrc.DhcpOptions, key)
+		return
+	}
+	if rsrc.DhcpOptions == nil {
+		rsrc.DhcpOptions = map[string]string{}
+	}
+	rsrc.DhcpOptions[key] = id
+}
+
+// getClientGateway returns the client gateway of a VPN connection, a wrapped ErrNoResourceFound error otherwise.
+func (t *ClusterResourceTracker) getClientGateway(ctx context.Context, conn infrastructurev1beta2.OscVpnConnection, clusterScope *scope.ClusterScope) (*osc.ClientGateway, error) {
+	clusterScope.Lock()
+	id := getResource(conn.Name, clusterScope.GetResources().ClientGateway)
+	clusterScope.Unlock()
+	svc := t.Cloud.Net(clusterScope.Tenant)
+	if id != "" {
+		cgw, err := svc.GetClientGateway(ctx, id)
+		switch {
+		case err != nil:
+			return nil, err
+		case cgw == nil:
+			return nil, fmt.Errorf("get client gateway %s: %w", id, ErrMissingResource)
+		default:
+			return cgw, nil
+		}
+	}
+	// Search by IP and ASN
+	cgw, err := svc.GetClientGatewayFor(ctx, conn.ClientGatewayIP, getBgpAsn(conn), clusterScope.GetUID())
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("get client gateway: %w", err)
+	case cgw == nil:
+		return nil, fmt.Errorf("get client gateway: %w", ErrNoResourceFound)
+	}
+	t.setClientGatewayId(clusterScope, conn, cgw.ClientGatewayId)
+	return cgw, nil
+}
+
+func (t *ClusterResourceTracker) setClientGatewayId(clusterScope *scope.ClusterScope, conn infrastructurev1beta2.OscVpnConnection, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.ClientGateway == nil {
+		rsrc.ClientGateway = map[string]string{}
+	}
+	rsrc.ClientGateway[conn.Name] = id
+}
+
+// getVpnConnection returns a VPN connection, a wrapped ErrNoResourceFound error otherwise.
+func (t *ClusterResourceTracker) getVpnConnection(ctx context.Context, conn infrastructurev1beta2.OscVpnConnection, clientGatewayId, virtualGatewayId string, clusterScope *scope.ClusterScope) (*osc.VpnConnection, error) {
+	clusterScope.Lock()
+	id := getResource(conn.Name, clusterScope.GetResources().VpnConnection)
+	clusterScope.Unlock()
+	svc := t.Cloud.Net(clusterScope.Tenant)
+	if id != "" {
+		vpn, err := svc.GetVpnConnection(ctx, id)
+		switch {
+		case err != nil:
+			return nil, err
+		case vpn == nil:
+			return nil, fmt.Errorf("get vpn connection %s: %w", id, ErrMissingResource)
+		default:
+			return vpn, nil
+		}
+	}
+	if clientGatewayId == "" || virtualGatewayId == "" {
+		return nil, fmt.Errorf("get vpn connection: %w", ErrNoResourceFound)
+	}
+	// Search by gateways
+	vpn, err := svc.GetVpnConnectionFor(ctx, clientGatewayId, virtualGatewayId)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("get vpn connection: %w", err)
+	case vpn == nil:
+		return nil, fmt.Errorf("get vpn connection: %w", ErrNoResourceFound)
+	}
+	t.setVpnConnectionId(clusterScope, conn, vpn.VpnConnectionId)
+	return vpn, nil
+}
+
+// getRemovedVpnConnections returns the names of the tracked VPN connections that are no longer in the spec, or all of them if the VPN is disabled.
+func (t *ClusterResourceTracker) getRemovedVpnConnections(clusterScope *scope.ClusterScope) []string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	vpn := clusterScope.GetNetwork().Vpn
+	rsrc := clusterScope.GetResources()
+	var names []string
+	for _, name := range slices.Concat(slices.Collect(maps.Keys(rsrc.ClientGateway)), slices.Collect(maps.Keys(rsrc.VpnConnection))) {
+		if vpn.Enable && slices.ContainsFunc(vpn.Connections, func(c infrastructurev1beta2.OscVpnConnection) bool { return c.Name == name }) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// getVpnConnectionIds returns the tracked client gateway and VPN connection ids of a VPN connection.
+func (t *ClusterResourceTracker) getVpnConnectionIds(clusterScope *scope.ClusterScope, name string) (clientGatewayId, vpnConnectionId string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	return getResource(name, rsrc.ClientGateway), getResource(name, rsrc.VpnConnection)
+}
+
+func (t *ClusterResourceTracker) unsetVpnConnectionIds(clusterScope *scope.ClusterScope, name string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	delete(rsrc.ClientGateway, name)
+	delete(rsrc.VpnConnection, name)
+}
+
+func (t *ClusterResourceTracker) setVpnConnectionId(clusterScope *scope.ClusterScope, conn infrastructurev1beta2.OscVpnConnection, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.VpnConnection == nil {
+		rsrc.VpnConnection = map[string]string{}
+	}
+	rsrc.VpnConnection[conn.Name] = id
+}
+
+func (t *ClusterResourceTracker) _getInternetServiceOrId(ctx context.Context, clusterScope *scope.ClusterScope) (*osc.InternetService, string, error) {
+	clusterScope.Lock()
+	id := getResource(defaultResource, clusterScope.GetResources().InternetService)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
@@ -173,6 +565,8 @@ func (t *ClusterResourceTracker) getInternetServiceId(ctx context.Context, clust
 }
 
 func (t *ClusterResourceTracker) setInternetServiceId(clusterScope *scope.ClusterScope, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.InternetService == nil {
 		rsrc.InternetService = map[string]string{}
@@ -181,8 +575,9 @@ func (t *ClusterResourceTracker) setInternetServiceId(clusterScope *scope.Cluste
 }
 
 func (t *ClusterResourceTracker) _getNetAccessPointOrId(ctx context.Context, service infrastructurev1beta2.OscNetAccessPointService, clusterScope *scope.ClusterScope) (*osc.NetAccessPoint, string, error) {
-	rsrc := clusterScope.GetResources()
-	id := getResource(string(service), rsrc.NetAccessPoint)
+	clusterScope.Lock()
+	id := getResource(string(service), clusterScope.GetResources().NetAccessPoint)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
@@ -222,6 +617,8 @@ func (t *ClusterResourceTracker) getNetAccessPoint(ctx context.Context, service
 }
 
 func (t *ClusterResourceTracker) setNetAccessPointId(clusterScope *scope.ClusterScope, service infrastructurev1beta2.OscNetAccessPointService, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.NetAccessPoint == nil {
 		rsrc.NetAccessPoint = map[string]string{}
@@ -235,8 +632,9 @@ func (t *ClusterResourceTracker) _getSubnetOrId(ctx context.Context, subnet infr
 		return nil, id, nil
 	}
 
-	rsrc := clusterScope.GetResources()
-	id = getResource(subnet.IpSubnetRange, rsrc.Subnet)
+	clusterScope.Lock()
+	id = getResource(subnet.IpSubnetRange, clusterScope.GetResources().Subnet)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
@@ -282,6 +680,8 @@ func (t *ClusterResourceTracker) getSubnetId(ctx context.Context, subnet infrast
 }
 
 func (t *ClusterResourceTracker) setSubnetId(clusterScope *scope.ClusterScope, subnet infrastructurev1beta2.OscSubnet, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.Subnet == nil {
 		rsrc.Subnet = map[string]string{}
@@ -290,10 +690,10 @@ func (t *ClusterResourceTracker) setSubnetId(clusterScope *scope.ClusterScope, s
 }
 
 func (t *ClusterResourceTracker) _getNatServiceOrId(ctx context.Context, nat infrastructurev1beta2.OscNatService, clusterScope *scope.ClusterScope) (*osc.NatService, string, error) {
-	rsrc := clusterScope.GetResources()
-
 	clientToken := clusterScope.GetNatServiceClientToken(nat)
-	id := getResource(clientToken, rsrc.NatService)
+	clusterScope.Lock()
+	id := getResource(clientToken, clusterScope.GetResources().NatService)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
@@ -326,8 +726,8 @@ func (t *ClusterResourceTracker) getNatService(ctx context.Context, nat infrastr
 	case err != nil:
 		return nil, err
 	case ns != nil:
-		// update IP tracking, in case status was reset
-		if len(ns.PublicIps) > 0 {
+		// update IP tracking, in case status was reset (existing IPs are not tracked, not to be deleted)
+		if len(ns.PublicIps) > 0 && nat.PublicIpId == "" {
 			t.trackIP(clusterScope, clusterScope.GetNatServiceClientToken(nat), ns.PublicIps[0].PublicIpId)
 		}
 		return ns, nil
@@ -339,8 +739,8 @@ func (t *ClusterResourceTracker) getNatService(ctx context.Context, nat infrastr
 	case ns == nil:
 		return nil, fmt.Errorf("get nat service %s: %w", id, ErrMissingResource)
 	default:
-		// update IP tracking, in case status was reset
-		if len(ns.PublicIps) > 0 {
+		// update IP tracking, in case status was reset (existing IPs are not tracked, not to be deleted)
+		if len(ns.PublicIps) > 0 && nat.PublicIpId == "" {
 			t.trackIP(clusterScope, clusterScope.GetNatServiceClientToken(nat), ns.PublicIps[0].PublicIpId)
 		}
 		return ns, nil
@@ -360,6 +760,8 @@ func (t *ClusterResourceTracker) getNatServiceId(ctx context.Context, nat infras
 }
 
 func (t *ClusterResourceTracker) setNatServiceId(clusterScope *scope.ClusterScope, nat infrastructurev1beta2.OscNatService, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.NatService == nil {
 		rsrc.NatService = map[string]string{}
@@ -368,8 +770,10 @@ func (t *ClusterResourceTracker) setNatServiceId(clusterScope *scope.ClusterScop
 }
 
 func (t *ClusterResourceTracker) getPublicIps(clusterScope *scope.ClusterScope) map[string]string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
-	return rsrc.PublicIPs
+	return maps.Clone(rsrc.PublicIPs)
 }
 
 func (t *ClusterResourceTracker) getBastion(ctx context.Context, clusterScope *scope.ClusterScope) (*osc.Vm, error) {
@@ -406,8 +810,9 @@ func (t *ClusterResourceTracker) _getBastionOrId(ctx context.Context, clusterSco
 		return nil, id, nil
 	}
 
-	rsrc := clusterScope.GetResources()
-	id = getResource(defaultResource, rsrc.Bastion)
+	clusterScope.Lock()
+	id = getResource(defaultResource, clusterScope.GetResources().Bastion)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
@@ -436,6 +841,8 @@ func (t *ClusterResourceTracker) _getBastionOrId(ctx context.Context, clusterSco
 }
 
 func (t *ClusterResourceTracker) setBastionId(clusterScope *scope.ClusterScope, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.Bastion == nil {
 		rsrc.Bastion = map[string]string{}
@@ -448,13 +855,14 @@ func (t *ClusterResourceTracker) _getSecurityGroupOrId(ctx context.Context, sg i
 		return nil, sg.ResourceId, nil
 	}
 
-	rsrc := clusterScope.GetResources()
 	name := clusterScope.GetSecurityGroupName(sg)
-	id := getResource(name, rsrc.SecurityGroup)
+	clusterScope.Lock()
+	id := getResource(name, clusterScope.GetResources().SecurityGroup)
+	clusterScope.Unlock()
 	if id != "" {
 		return nil, id, nil
 	}
-	ns, err := t.Cloud.Compute(clusterScope.Tenant).GetSecurityGroupFromName(ctx, name)
+	ns, err := t.Cloud.Cache().GetSecurityGroupFromName(ctx, clusterScope.Tenant, t.Cloud.Compute(clusterScope.Tenant), t.trackedNetId(clusterScope), name)
 	switch {
 	case err != nil:
 		return nil, "", fmt.Errorf("get securityGroup from securityGroupName: %w", err)
@@ -474,7 +882,7 @@ func (t *ClusterResourceTracker) getSecurityGroup(ctx context.Context, sg infras
 	case ns != nil:
 		return ns, nil
 	}
-	ns, err = t.Cloud.Compute(clusterScope.Tenant).GetSecurityGroup(ctx, id)
+	ns, err = t.Cloud.Cache().GetSecurityGroup(ctx, clusterScope.Tenant, t.Cloud.Compute(clusterScope.Tenant), t.trackedNetId(clusterScope), id)
 	switch {
 	case err != nil:
 		return nil, err
@@ -498,6 +906,8 @@ func (t *ClusterResourceTracker) getSecurityGroupId(ctx context.Context, sg infr
 }
 
 func (t *ClusterResourceTracker) setSecurityGroupId(clusterScope *scope.ClusterScope, sg infrastructurev1beta2.OscSecurityGroup, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.SecurityGroup == nil {
 		rsrc.SecurityGroup = map[string]string{}
@@ -505,10 +915,136 @@ func (t *ClusterResourceTracker) setSecurityGroupId(clusterScope *scope.ClusterS
 	rsrc.SecurityGroup[clusterScope.GetSecurityGroupName(sg)] = id
 }
 
+// securityGroupMemberRuleKey returns the key used to track a security group rule having a security group member.
+func securityGroupMemberRuleKey(securityGroupId, flow, protocol string, fromPort, toPort int, memberId string) string {
+	return fmt.Sprintf("%s/%s/%s/%d/%d/%s", securityGroupId, strings.ToLower(flow), protocol, fromPort, toPort, memberId)
+}
+
+// hasSecurityGroupMemberRule returns true if a security group rule having a security group member was created by the cluster.
+func (t *ClusterResourceTracker) hasSecurityGroupMemberRule(clusterScope *scope.ClusterScope, key string) bool {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(key, clusterScope.GetResources().SecurityGroupMemberRules) != ""
+}
+
+func (t *ClusterResourceTracker) trackSecurityGroupMemberRule(clusterScope *scope.ClusterScope, key, memberId string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.SecurityGroupMemberRules == nil {
+		rsrc.SecurityGroupMemberRules = map[string]string{}
+	}
+	rsrc.SecurityGroupMemberRules[key] = memberId
+}
+
+func (t *ClusterResourceTracker) untrackSecurityGroupMemberRule(clusterScope *scope.ClusterScope, key string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().SecurityGroupMemberRules, key)
+}
+
+// natFailoverKey returns the key of a route moved to another natService.
+func natFailoverKey(routeTableId, destination string) string {
+	return routeTableId + "/" + destination
+}
+
+// getNatFailover returns the client token of the failed natService of a route moved to another natService.
+func (t *ClusterResourceTracker) getNatFailover(clusterScope *scope.ClusterScope, key string) string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	return getResource(key, clusterScope.GetResources().NatFailover)
+}
+
+func (t *ClusterResourceTracker) setNatFailover(clusterScope *scope.ClusterScope, key, clientToken string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.NatFailover == nil {
+		rsrc.NatFailover = map[string]string{}
+	}
+	rsrc.NatFailover[key] = clientToken
+}
+
+func (t *ClusterResourceTracker) unsetNatFailover(clusterScope *scope.ClusterScope, key string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().NatFailover, key)
+}
+
+// serverCertificateKey returns the key of the server certificate of a loadBalancer listener.
+func serverCertificateKey(loadBalancerName string, loadBalancerPort int32) string {
+	return loadBalancerName + "/" + strconv.Itoa(int(loadBalancerPort))
+}
+
+// getServerCertificates returns the names of the server certificates uploaded for the listeners of a loadBalancer, by key.
+func (t *ClusterResourceTracker) getServerCertificates(clusterScope *scope.ClusterScope, loadBalancerName string) map[string]string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	res := map[string]string{}
+	for key, name := range clusterScope.GetResources().ServerCertificates {
+		if strings.HasPrefix(key, loadBalancerName+"/") {
+			res[key] = name
+		}
+	}
+	return res
+}
+
+func (t *ClusterResourceTracker) setServerCertificate(clusterScope *scope.ClusterScope, key, name string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.ServerCertificates == nil {
+		rsrc.ServerCertificates = map[string]string{}
+	}
+	rsrc.ServerCertificates[key] = name
+}
+
+func (t *ClusterResourceTracker) unsetServerCertificate(clusterScope *scope.ClusterScope, key string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().ServerCertificates, key)
+}
+
+// getSharedRoleTags returns the keys of the role tags added by the cluster to a reused subnet.
+func (t *ClusterResourceTracker) getSharedRoleTags(clusterScope *scope.ClusterScope, subnetId string) []string {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	keys := clusterScope.GetResources().SharedRoleTags[subnetId]
+	if keys == "" {
+		return nil
+	}
+	return strings.Split(keys, ",")
+}
+
+// addSharedRoleTags records the keys of role tags added by the cluster to a reused subnet.
+func (t *ClusterResourceTracker) addSharedRoleTags(clusterScope *scope.ClusterScope, subnetId string, keys []string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	if rsrc.SharedRoleTags == nil {
+		rsrc.SharedRoleTags = map[string]string{}
+	}
+	all := keys
+	if prev := rsrc.SharedRoleTags[subnetId]; prev != "" {
+		all = append(strings.Split(prev, ","), keys...)
+		slices.Sort(all)
+		all = slices.Compact(all)
+	}
+	rsrc.SharedRoleTags[subnetId] = strings.Join(all, ",")
+}
+
+func (t *ClusterResourceTracker) unsetSharedRoleTags(clusterScope *scope.ClusterScope, subnetId string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	delete(clusterScope.GetResources().SharedRoleTags, subnetId)
+}
+
 func (t *ClusterResourceTracker) IPAllocator(clusterScope *scope.ClusterScope) IPAllocatorInterface {
 	return &IPAllocator{
 		Cloud: t.Cloud,
 		getPublicIP: func(key string) (id string, found bool) {
+			clusterScope.Lock()
+			defer clusterScope.Unlock()
 			rsrc := clusterScope.GetResources()
 			if rsrc.PublicIPs == nil {
 				return "", false
@@ -517,16 +1053,21 @@ func (t *ClusterResourceTracker) IPAllocator(clusterScope *scope.ClusterScope) I
 			return ip, ip != ""
 		},
 		setPublicIP: func(key, id string) {
+			clusterScope.Lock()
+			defer clusterScope.Unlock()
 			rsrc := clusterScope.GetResources()
 			if rsrc.PublicIPs == nil {
 				rsrc.PublicIPs = map[string]string{}
 			}
 			rsrc.PublicIPs[key] = id
 		},
+		creations: clusterScope,
 	}
 }
 
 func (t *ClusterResourceTracker) trackIP(clusterScope *scope.ClusterScope, key, id string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.PublicIPs == nil {
 		rsrc.PublicIPs = map[string]string{}
@@ -535,6 +1076,8 @@ func (t *ClusterResourceTracker) trackIP(clusterScope *scope.ClusterScope, key,
 }
 
 func (t *ClusterResourceTracker) untrackIP(clusterScope *scope.ClusterScope, name string) {
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
 	rsrc := clusterScope.GetResources()
 	if rsrc.PublicIPs == nil {
 		return
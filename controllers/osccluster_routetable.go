@@ -8,34 +8,91 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/sdk/ptr"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// routeHasTarget returns true if a route targets a resource.
+func routeHasTarget(route osc.Route, targetType, resourceId string) bool {
+	switch targetType {
+	case "gateway", "virtualGateway":
+		return ptr.From(route.GatewayId) == resourceId
+	case "nat":
+		return ptr.From(route.NatServiceId) == resourceId
+	case "netPeering":
+		return ptr.From(route.NetPeeringId) == resourceId
+	case "vm":
+		return ptr.From(route.VmId) == resourceId
+	case "nic":
+		return ptr.From(route.NicId) == resourceId
+	default:
+		return false
+	}
+}
+
+// isRouteManagedElsewhere returns true if a route is not managed by the route table spec (local routes, net access point routes,
+// and routes to the peerings and to the virtual gateway of the cluster).
+func isRouteManagedElsewhere(clusterScope *scope.ClusterScope, route osc.Route) bool {
+	if route.CreationMethod != "CreateRoute" || route.NetAccessPointId != nil || route.DestinationServiceId != nil {
+		return true
+	}
+	clusterScope.Lock()
+	defer clusterScope.Unlock()
+	rsrc := clusterScope.GetResources()
+	switch {
+	case route.NetPeeringId != nil:
+		for _, id := range rsrc.NetPeering {
+			if id == *route.NetPeeringId {
+				return true
+			}
+		}
+	case route.GatewayId != nil:
+		if *route.GatewayId == clusterScope.GetNetwork().Vpn.VirtualGatewayID {
+			return true
+		}
+		for _, id := range rsrc.VirtualGateway {
+			if id == *route.GatewayId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // reconcileRoute reconcile the RouteTable and the Route of the cluster.
 func (r *OscClusterReconciler) reconcileRoute(ctx context.Context, clusterScope *scope.ClusterScope, routeTableSpec infrastructurev1beta2.OscRouteTable, routeSpec infrastructurev1beta2.OscRoute, routeTable *osc.RouteTable) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	destinationIpRange := routeSpec.Destination
-	for _, route := range routeTable.Routes {
-		if route.DestinationIpRange == destinationIpRange {
-			return reconcile.Result{}, nil
-		}
+	idx := slices.IndexFunc(routeTable.Routes, func(route osc.Route) bool {
+		return route.DestinationIpRange == destinationIpRange
+	})
+	if idx >= 0 && !routeTableSpec.Authoritative {
+		return reconcile.Result{}, nil
 	}
 	var resourceId string
 	var err error
-	switch routeSpec.TargetType {
+	targetType := routeSpec.TargetType
+	switch targetType {
 	case "gateway":
 		resourceId, err = r.Tracker.getInternetServiceId(ctx, clusterScope)
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("find internetService for route: %w", err)
 		}
-	case "nat":
+	case "nat", "nat-service":
+		targetType = "nat"
+		if idx >= 0 && r.Tracker.getNatFailover(clusterScope, natFailoverKey(routeTable.RouteTableId, destinationIpRange)) != "" {
+			log.V(3).Info("Route was moved to another natService, skipping", "destination", destinationIpRange)
+			return reconcile.Result{}, nil
+		}
 		natSpec, err := clusterScope.GetNatService(routeSpec.TargetName, routeTableSpec.SubregionName)
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("find natService for route: %w", err)
@@ -44,12 +101,25 @@ func (r *OscClusterReconciler) reconcileRoute(ctx context.Context, clusterScope
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("find natService for route: %w", err)
 		}
+	case "vm", "nic", "netPeering", "virtualGateway":
+		resourceId = routeSpec.TargetId
 	default:
 		log.V(3).Info("Route has no target !", "destinationIpRange", destinationIpRange)
 		return reconcile.Result{}, nil
 	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	if idx >= 0 {
+		if routeHasTarget(routeTable.Routes[idx], targetType, resourceId) {
+			return reconcile.Result{}, nil
+		}
+		log.V(2).Info("Deleting route with another target", "destination", destinationIpRange)
+		err = svc.DeleteRoute(ctx, destinationIpRange, routeTable.RouteTableId)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot delete route: %w", err)
+		}
+	}
 	log.V(2).Info("Creating route", "destination", destinationIpRange, "resourceId", resourceId)
-	_, err = r.Cloud.Net(clusterScope.Tenant).CreateRoute(ctx, destinationIpRange, routeTable.RouteTableId, resourceId, routeSpec.TargetType)
+	_, err = svc.CreateRoute(ctx, destinationIpRange, routeTable.RouteTableId, resourceId, targetType)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot create route: %w", err)
 	}
@@ -57,6 +127,24 @@ func (r *OscClusterReconciler) reconcileRoute(ctx context.Context, clusterScope
 	return reconcile.Result{}, nil
 }
 
+// reconcileDeleteExtraRoutes deletes the routes of an authoritative route table which are not in spec.
+func (r *OscClusterReconciler) reconcileDeleteExtraRoutes(ctx context.Context, clusterScope *scope.ClusterScope, routeTableSpec infrastructurev1beta2.OscRouteTable, routeTable *osc.RouteTable) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	for _, route := range routeTable.Routes {
+		if slices.ContainsFunc(routeTableSpec.Routes, func(routeSpec infrastructurev1beta2.OscRoute) bool {
+			return routeSpec.Destination == route.DestinationIpRange
+		}) || isRouteManagedElsewhere(clusterScope, route) {
+			continue
+		}
+		log.V(2).Info("Deleting route not in spec", "routeTableId", routeTable.RouteTableId, "destination", route.DestinationIpRange)
+		err := r.Cloud.Net(clusterScope.Tenant).DeleteRoute(ctx, route.DestinationIpRange, routeTable.RouteTableId)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot delete route: %w", err)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
 // reconcileRouteTable reconcile the RouteTable and the Route of the cluster.
 func (r *OscClusterReconciler) reconcileRouteTable(ctx context.Context, clusterScope *scope.ClusterScope, roles ...infrastructurev1beta2.OscRole) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -105,14 +193,46 @@ func (r *OscClusterReconciler) reconcileRouteTable(ctx context.Context, clusterS
 			if err != nil {
 				return reconcile.Result{}, fmt.Errorf("cannot get subnet: %w", err)
 			}
+			key := creationKey(tag.RouteTableResourceType, routeTableSpec.Name)
 			switch {
 			case rtbl == nil && rtblForSubnet[subnetId] != nil:
 				log.V(5).Info("Subnet has a route table", "subnetId", subnetId)
 				rtbl = rtblForSubnet[subnetId]
+				if err := clusterScope.EndCreation(ctx, key); err != nil {
+					return reconcile.Result{}, err
+				}
 				continue
 			case rtbl == nil && rtblForSubnet[subnetId] == nil:
 				log.V(2).Info("Creating routetable", "subnetId", subnetId)
-				rtbl, err = svc.CreateRouteTable(ctx, netId, clusterScope.GetUID(), routeTableSpec.Name)
+				_, err = createOnce(ctx, clusterScope, key, creation{
+					candidates: func() ([]string, error) {
+						var ids []string
+						for _, rt := range rtbls {
+							if len(rt.LinkRouteTables) == 0 && isUntaggedOrCreatedAs(rt.Tags, routeTableSpec.Name, clusterScope.GetUID()) {
+								ids = append(ids, rt.RouteTableId)
+							}
+						}
+						return ids, nil
+					},
+					create: func() (string, error) {
+						created, err := svc.CreateRouteTable(ctx, netId, clusterScope.GetUID(), routeTableSpec.Name)
+						if created == nil {
+							return "", err
+						}
+						rtbl = created
+						return rtbl.RouteTableId, err
+					},
+					adopt: func(id string) error {
+						i := slices.IndexFunc(rtbls, func(rt osc.RouteTable) bool {
+							return rt.RouteTableId == id
+						})
+						if i < 0 {
+							return fmt.Errorf("get routetable %s: %w", id, ErrMissingResource)
+						}
+						rtbl = &rtbls[i]
+						return tagCreated(ctx, r.Cloud.Tag(clusterScope.Tenant), id, routeTableSpec.Name, clusterScope.GetUID())
+					},
+				})
 				if err != nil {
 					return reconcile.Result{}, fmt.Errorf("cannot create routetable: %w", err)
 				}
@@ -125,6 +245,9 @@ func (r *OscClusterReconciler) reconcileRouteTable(ctx context.Context, clusterS
 				if err != nil {
 					return reconcile.Result{}, fmt.Errorf("cannot link routetable to subnet: %w", err)
 				}
+				if err := clusterScope.EndCreation(ctx, key); err != nil {
+					return reconcile.Result{}, err
+				}
 			}
 		}
 		if rtbl == nil {
@@ -136,6 +259,12 @@ func (r *OscClusterReconciler) reconcileRouteTable(ctx context.Context, clusterS
 				return reconcile.Result{}, err
 			}
 		}
+		if routeTableSpec.Authoritative {
+			_, err = r.reconcileDeleteExtraRoutes(ctx, clusterScope, routeTableSpec, rtbl)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
 	}
 	if len(roles) == 0 {
 		clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerRouteTable)
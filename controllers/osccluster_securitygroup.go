@@ -8,68 +8,142 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
+	"github.com/outscale/cluster-api-provider-outscale/util/parallel"
+	"github.com/outscale/cluster-api-provider-outscale/util/reconciler"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// resolveSecurityGroupRules resolves security group names and roles referenced by rules into security group ids.
+func (r *OscClusterReconciler) resolveSecurityGroupRules(ctx context.Context, clusterScope *scope.ClusterScope, securityGroupRulesSpec []infrastructurev1beta2.OscSecurityGroupRule) ([]infrastructurev1beta2.OscSecurityGroupRule, error) {
+	securityGroupsSpec := clusterScope.GetSecurityGroups()
+	rules := make([]infrastructurev1beta2.OscSecurityGroupRule, 0, len(securityGroupRulesSpec))
+	for _, rule := range securityGroupRulesSpec {
+		memberIds := slices.Clone(rule.SecurityGroupIds)
+		for _, sg := range securityGroupsSpec {
+			if !slices.Contains(rule.SecurityGroupNames, sg.Name) && !slices.ContainsFunc(rule.SecurityGroupRoles, sg.HasRole) {
+				continue
+			}
+			id, err := r.Tracker.getSecurityGroupId(ctx, sg, clusterScope)
+			if err != nil {
+				return nil, fmt.Errorf("get securityGroup member %s: %w", sg.Name, err)
+			}
+			if !slices.Contains(memberIds, id) {
+				memberIds = append(memberIds, id)
+			}
+		}
+		rule.SecurityGroupNames = nil
+		rule.SecurityGroupRoles = nil
+		rule.SecurityGroupIds = memberIds
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// appendSecurityGroupRule adds a rule to the inbound or outbound rules of a securityGroup.
+func appendSecurityGroupRule(sg *osc.SecurityGroup, flow string, rule osc.SecurityGroupRule) {
+	switch strings.ToLower(flow) {
+	case "inbound":
+		sg.InboundRules = append(sg.InboundRules, rule)
+	case "outbound":
+		sg.OutboundRules = append(sg.OutboundRules, rule)
+	}
+}
+
 // reconcileSecurityGroupAddRules reconciles rules for a securityGroup.
+// Missing rules are created in a single call per flow.
 func (r *OscClusterReconciler) reconcileSecurityGroupAddRules(ctx context.Context, clusterScope *scope.ClusterScope, securityGroupRulesSpec []infrastructurev1beta2.OscSecurityGroupRule, sg *osc.SecurityGroup) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	svc := r.Cloud.Compute(clusterScope.Tenant)
+	missing := osc.SecurityGroup{SecurityGroupId: sg.SecurityGroupId}
+	hasRule := func(flow, protocol, ipRange, memberId string, fromPort, toPort int) bool {
+		return compute.SecurityGroupHasRule(sg, flow, protocol, ipRange, memberId, fromPort, toPort) ||
+			compute.SecurityGroupHasRule(&missing, flow, protocol, ipRange, memberId, fromPort, toPort)
+	}
 	for _, securityGroupRuleSpec := range securityGroupRulesSpec {
-		var rules []osc.SecurityGroupRule
-		switch strings.ToLower(securityGroupRuleSpec.Flow) {
-		case "inbound":
-			rules = sg.InboundRules
-		case "outbound":
-			rules = sg.OutboundRules
-		}
 		flow := securityGroupRuleSpec.Flow
 		protocol := securityGroupRuleSpec.IpProtocol
 		fromPort := int(securityGroupRuleSpec.FromPortRange)
 		toPort := int(securityGroupRuleSpec.ToPortRange)
-		var existingRanges []string
-		for _, rule := range rules {
-			if rule.FromPortRange != fromPort || rule.ToPortRange != toPort || rule.IpProtocol != protocol {
+		for _, ipRange := range securityGroupRuleSpec.GetIpRanges() {
+			if hasRule(flow, protocol, ipRange, "", fromPort, toPort) {
 				continue
 			}
-			existingRanges = append(existingRanges, rule.IpRanges...)
+			log.V(2).Info("Creating securityGroupRule", "flow", flow, "ipRange", ipRange, "protocol", protocol, "fromPort", fromPort, "toPort", toPort)
+			appendSecurityGroupRule(&missing, flow, osc.SecurityGroupRule{
+				IpProtocol: protocol, IpRanges: []string{ipRange}, FromPortRange: fromPort, ToPortRange: toPort,
+			})
 		}
-		ipRanges := securityGroupRuleSpec.GetIpRanges()
-		for _, ipRange := range ipRanges {
-			if slices.Contains(existingRanges, ipRange) {
+		for _, memberId := range securityGroupRuleSpec.SecurityGroupIds {
+			if hasRule(flow, protocol, "", memberId, fromPort, toPort) {
 				continue
 			}
-			log.V(2).Info("Creating securityGroupRule", "flow", flow, "ipRange", ipRange, "protocol", protocol, "fromPort", fromPort, "toPort", toPort)
-			_, err := svc.CreateSecurityGroupRule(ctx, sg.SecurityGroupId, flow, protocol, ipRange, "", fromPort, toPort)
-			if err != nil {
-				return reconcile.Result{}, fmt.Errorf("cannot create securityGroupRule: %w", err)
+			log.V(2).Info("Creating securityGroupRule", "flow", flow, "securityGroupMember", memberId, "protocol", protocol, "fromPort", fromPort, "toPort", toPort)
+			appendSecurityGroupRule(&missing, flow, osc.SecurityGroupRule{
+				IpProtocol: protocol, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: memberId}}, FromPortRange: fromPort, ToPortRange: toPort,
+			})
+		}
+	}
+	for _, flow := range []string{"Inbound", "Outbound"} {
+		rules := missing.InboundRules
+		if flow == "Outbound" {
+			rules = missing.OutboundRules
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		err := svc.CreateSecurityGroupRules(ctx, sg.SecurityGroupId, flow, rules)
+		if err != nil {
+			// Some rules may have been created by a partially failed call, the member rules now found were created by the cluster.
+			if current, rerr := svc.GetSecurityGroup(ctx, sg.SecurityGroupId); rerr == nil && current != nil {
+				r.trackSecurityGroupMemberRules(clusterScope, sg.SecurityGroupId, flow, rules, current)
+			} else if rerr != nil {
+				log.V(3).Error(rerr, "Cannot read securityGroup after a failed rule creation")
 			}
+			return reconcile.Result{}, fmt.Errorf("cannot create %s securityGroupRules: %w", strings.ToLower(flow), err)
 		}
+		r.trackSecurityGroupMemberRules(clusterScope, sg.SecurityGroupId, flow, rules, nil)
 	}
 	return reconcile.Result{}, nil
 }
 
+// trackSecurityGroupMemberRules tracks the member rules created by the cluster.
+// If created is set, only the rules found in the created securityGroup are tracked.
+func (r *OscClusterReconciler) trackSecurityGroupMemberRules(clusterScope *scope.ClusterScope, securityGroupId, flow string, rules []osc.SecurityGroupRule, created *osc.SecurityGroup) {
+	for _, rule := range rules {
+		for _, member := range rule.SecurityGroupsMembers {
+			if created != nil && !compute.SecurityGroupHasRule(created, flow, rule.IpProtocol, "", member.SecurityGroupId, rule.FromPortRange, rule.ToPortRange) {
+				continue
+			}
+			key := securityGroupMemberRuleKey(securityGroupId, flow, rule.IpProtocol, rule.FromPortRange, rule.ToPortRange, member.SecurityGroupId)
+			r.Tracker.trackSecurityGroupMemberRule(clusterScope, key, member.SecurityGroupId)
+		}
+	}
+}
+
 // reconcileSecurityGroupDeleteRules deletes all rules not in spec for a securityGroup.
+// Rules are deleted in a single call per flow.
 func (r *OscClusterReconciler) reconcileSecurityGroupDeleteRules(ctx context.Context, clusterScope *scope.ClusterScope, securityGroupRulesSpec []infrastructurev1beta2.OscSecurityGroupRule, sg *osc.SecurityGroup) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	svc := r.Cloud.Compute(clusterScope.Tenant)
 	checkRules := func(flow string, rules []osc.SecurityGroupRule) error {
+		var deleted []osc.SecurityGroupRule
+		var keys []string
 		for _, rule := range rules {
-			// Skipping rule created by CCM. There is no way to be sure this comes from the CCM,
-			// but the CCM creates rules with an associated SG, and the config from the CRD only uses ipRanges.
-			if len(rule.SecurityGroupsMembers) > 0 {
-				log.V(5).Info("Skipping rule associated with another SG")
-				continue
-			}
-			var okRanges []string
+			var okRanges, okMembers []string
 			for _, spec := range securityGroupRulesSpec {
 				if flow != spec.Flow ||
 					rule.FromPortRange != int(spec.FromPortRange) || rule.ToPortRange != int(spec.ToPortRange) ||
@@ -77,19 +151,44 @@ func (r *OscClusterReconciler) reconcileSecurityGroupDeleteRules(ctx context.Con
 					continue
 				}
 				okRanges = append(okRanges, spec.GetIpRanges()...)
+				okMembers = append(okMembers, spec.SecurityGroupIds...)
+			}
+			for _, member := range rule.SecurityGroupsMembers {
+				if slices.Contains(okMembers, member.SecurityGroupId) {
+					continue
+				}
+				// Rules having a security group member may have been created by the CCM, only rules created by the cluster are deleted.
+				key := securityGroupMemberRuleKey(sg.SecurityGroupId, flow, rule.IpProtocol, rule.FromPortRange, rule.ToPortRange, member.SecurityGroupId)
+				if !r.Tracker.hasSecurityGroupMemberRule(clusterScope, key) {
+					log.V(5).Info("Skipping rule associated with another SG", "securityGroupMember", member.SecurityGroupId)
+					continue
+				}
+				log.V(2).Info("Deleting securityGroupRule", "flow", flow, "securityGroupMember", member.SecurityGroupId, "protocol", rule.IpProtocol, "fromPort", rule.FromPortRange, "toPort", rule.ToPortRange)
+				deleted = append(deleted, osc.SecurityGroupRule{
+					IpProtocol: rule.IpProtocol, SecurityGroupsMembers: []osc.SecurityGroupsMember{{SecurityGroupId: member.SecurityGroupId}}, FromPortRange: rule.FromPortRange, ToPortRange: rule.ToPortRange,
+				})
+				keys = append(keys, key)
 			}
-			ipRanges := rule.IpRanges
-			for _, ipRange := range ipRanges {
+			for _, ipRange := range rule.IpRanges {
 				if slices.Contains(okRanges, ipRange) {
 					continue
 				}
 				log.V(2).Info("Deleting securityGroupRule", "flow", flow, "ipRange", ipRange, "protocol", rule.IpProtocol, "fromPort", rule.FromPortRange, "toPort", rule.ToPortRange)
-				err := svc.DeleteSecurityGroupRule(ctx, sg.SecurityGroupId, flow, rule.IpProtocol, ipRange, "", rule.FromPortRange, rule.ToPortRange)
-				if err != nil {
-					return fmt.Errorf("cannot create securityGroupRule: %w", err)
-				}
+				deleted = append(deleted, osc.SecurityGroupRule{
+					IpProtocol: rule.IpProtocol, IpRanges: []string{ipRange}, FromPortRange: rule.FromPortRange, ToPortRange: rule.ToPortRange,
+				})
 			}
 		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		err := svc.DeleteSecurityGroupRules(ctx, sg.SecurityGroupId, flow, deleted)
+		if err != nil {
+			return fmt.Errorf("cannot delete %s securityGroupRules: %w", strings.ToLower(flow), err)
+		}
+		for _, key := range keys {
+			r.Tracker.untrackSecurityGroupMemberRule(clusterScope, key)
+		}
 		return nil
 	}
 	err := checkRules("Inbound", sg.InboundRules)
@@ -107,7 +206,9 @@ func (r *OscClusterReconciler) reconcileSecurityGroupDeleteRules(ctx context.Con
 func (r *OscClusterReconciler) reconcileSecurityGroup(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
-	if !clusterScope.NeedReconciliation(infrastructurev1beta2.ReconcilerSecurityGroup) {
+	egressChanged := r.egressDestinationsChanged(ctx, clusterScope)
+	if !clusterScope.NeedReconciliation(infrastructurev1beta2.ReconcilerSecurityGroup) &&
+		!r.cniProfileChanged(ctx, clusterScope) && !egressChanged {
 		log.V(4).Info("No need for securityGroup reconciliation")
 		return reconcile.Result{}, nil
 	}
@@ -121,9 +222,16 @@ func (r *OscClusterReconciler) reconcileSecurityGroup(ctx context.Context, clust
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = clusterScope.LoadCniProfile(ctx, r.APIReader)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot load CNI profile: %w", err)
+	}
 	securityGroupSvc := r.Cloud.Compute(clusterScope.Tenant)
 	securityGroupsSpec := clusterScope.GetSecurityGroups()
-	for _, securityGroupSpec := range securityGroupsSpec {
+	// All securityGroups are created before rules, as rules may reference other securityGroups.
+	var mu sync.Mutex
+	securityGroups := make(map[string]*osc.SecurityGroup, len(securityGroupsSpec))
+	err = parallel.ForEach(ctx, reconciler.DefaultedParallelism(r.Parallelism), securityGroupsSpec, func(ctx context.Context, securityGroupSpec infrastructurev1beta2.OscSecurityGroup) error {
 		securityGroup, err := r.Tracker.getSecurityGroup(ctx, securityGroupSpec, clusterScope)
 		switch {
 		case IsNotFound(err):
@@ -131,19 +239,32 @@ func (r *OscClusterReconciler) reconcileSecurityGroup(ctx context.Context, clust
 			name := clusterScope.GetSecurityGroupName(securityGroupSpec)
 			securityGroup, err = securityGroupSvc.CreateSecurityGroup(ctx, netId, clusterScope.GetUID(), name, securityGroupSpec.Description, securityGroupSpec.Tag, securityGroupSpec.Roles)
 			if err != nil {
-				return reconcile.Result{}, fmt.Errorf("cannot create securityGroup: %w", err)
+				return fmt.Errorf("cannot create securityGroup: %w", err)
 			}
 			log.V(2).Info("Created securityGroup", "securityGroupId", securityGroup.SecurityGroupId)
 			r.Tracker.setSecurityGroupId(clusterScope, securityGroupSpec, securityGroup.SecurityGroupId)
 			r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.SecurityGroupCreatedReason, "Security group created %v", securityGroupSpec.Roles)
 		case err != nil:
-			return reconcile.Result{}, fmt.Errorf("get existing: %w", err)
+			return fmt.Errorf("get existing: %w", err)
+		}
+		mu.Lock()
+		securityGroups[clusterScope.GetSecurityGroupName(securityGroupSpec)] = securityGroup
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = parallel.ForEach(ctx, reconciler.DefaultedParallelism(r.Parallelism), securityGroupsSpec, func(ctx context.Context, securityGroupSpec infrastructurev1beta2.OscSecurityGroup) error {
+		securityGroup := securityGroups[clusterScope.GetSecurityGroupName(securityGroupSpec)]
+		securityGroupRulesSpec, err := r.resolveSecurityGroupRules(ctx, clusterScope, securityGroupSpec.SecurityGroupRules)
+		if err != nil {
+			return fmt.Errorf("resolve rules: %w", err)
 		}
-		securityGroupRulesSpec := securityGroupSpec.SecurityGroupRules
 		if securityGroupSpec.HasRole(infrastructurev1beta2.RoleLoadBalancer) && clusterScope.HasIPRestriction() {
 			ips, err := r.listNATPublicIPs(ctx, clusterScope, true)
 			if err != nil {
-				return reconcile.Result{}, fmt.Errorf("cannot list NAT public IPs: %w", err)
+				return fmt.Errorf("cannot list NAT public IPs: %w", err)
 			}
 			securityGroupRulesSpec = append(securityGroupRulesSpec, infrastructurev1beta2.OscSecurityGroupRule{
 				Flow:          "Inbound",
@@ -159,13 +280,74 @@ func (r *OscClusterReconciler) reconcileSecurityGroup(ctx context.Context, clust
 			_, err = r.reconcileSecurityGroupDeleteRules(ctx, clusterScope, securityGroupRulesSpec, securityGroup)
 		}
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("check rules: %w", err)
+			return fmt.Errorf("check rules: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
+	r.Tracker.setCniProfileHash(clusterScope, clusterScope.CniProfileHash())
+	r.Tracker.setEgressDestinations(clusterScope, clusterScope.EgressDestinations())
 	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerSecurityGroup)
 	return reconcile.Result{}, nil
 }
 
+// cniProfileChanged checks if the ConfigMap of a custom CNI profile has been updated since its rules were last applied.
+func (r *OscClusterReconciler) cniProfileChanged(ctx context.Context, clusterScope *scope.ClusterScope) bool {
+	if clusterScope.GetNetwork().Cni != infrastructurev1beta2.CniCustom {
+		return false
+	}
+	err := clusterScope.LoadCniProfile(ctx, r.APIReader)
+	return err != nil || clusterScope.CniProfileHash() != r.Tracker.getCniProfileHash(clusterScope)
+}
+
+// egressDestinationsChanged loads the IP ranges of the named destinations of egress rules, and checks if they have changed since they were last applied.
+// Destinations are resolved every DefaultEgressResolveInterval, a destination that cannot be resolved keeps the IP ranges last applied.
+func (r *OscClusterReconciler) egressDestinationsChanged(ctx context.Context, clusterScope *scope.ClusterScope) bool {
+	log := ctrl.LoggerFrom(ctx)
+	applied := r.Tracker.getEgressDestinations(clusterScope)
+	resolve := clusterScope.HasEgressDestinations() && r.egressResolutionDue(clusterScope)
+	err := clusterScope.LoadEgressDestinations(ctx, applied, resolve)
+	if err != nil {
+		log.V(2).Error(err, "Cannot resolve egress destinations, keeping the IP ranges last applied")
+	}
+	return !maps.Equal(clusterScope.EgressDestinations(), applied)
+}
+
+// egressResolutionDue checks if the egress destinations of a cluster need to be resolved, and records the resolution.
+func (r *OscClusterReconciler) egressResolutionDue(clusterScope *scope.ClusterScope) bool {
+	now := time.Now()
+	last, found := r.egressResolutions.Load(clusterScope.GetUID())
+	if found && now.Sub(last.(time.Time)) < reconciler.DefaultEgressResolveInterval {
+		return false
+	}
+	r.egressResolutions.Store(clusterScope.GetUID(), now)
+	return true
+}
+
+// ConfigMapToOscClusters maps a ConfigMap to the OscClusters using it as a CNI profile.
+func (r *OscClusterReconciler) ConfigMapToOscClusters(ctx context.Context) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		log := ctrl.LoggerFrom(ctx).WithValues("objectMapper", "configMapToOscCluster", "namespace", o.GetNamespace())
+		var clusters infrastructurev1beta2.OscClusterList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(o.GetNamespace())); err != nil {
+			log.V(1).Error(err, "failed to list OscClusters, skipping mapping.")
+			return nil
+		}
+		var result []reconcile.Request
+		for _, c := range clusters.Items {
+			if !r.hasWatchFilterLabel(&c) {
+				continue
+			}
+			if ref := c.Spec.Network.CniProfileRef; ref != nil && ref.Name == o.GetName() {
+				result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&c)})
+			}
+		}
+		return result
+	}
+}
+
 // reconcileDeleteSecurityGroup reconcile the deletetion of securityGroup of the cluster.
 func (r *OscClusterReconciler) reconcileDeleteSecurityGroup(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -194,22 +376,24 @@ func (r *OscClusterReconciler) reconcileDeleteSecurityGroup(ctx context.Context,
 		if securityGroup.SecurityGroupName == "default" {
 			continue
 		}
-		log.V(2).Info("Deleting inbound rules", "securityGroupId", securityGroup.SecurityGroupId)
-		for _, rule := range securityGroup.InboundRules {
-			for _, member := range rule.SecurityGroupsMembers {
-				err = securityGroupSvc.DeleteSecurityGroupRule(ctx, securityGroup.SecurityGroupId, "Inbound", rule.IpProtocol, "", member.SecurityGroupId, rule.FromPortRange, rule.ToPortRange)
-				if err != nil {
-					return reconcile.Result{}, fmt.Errorf("cannot delete rule: %w", err)
-				}
+		for _, flow := range []string{"Inbound", "Outbound"} {
+			rules := securityGroup.InboundRules
+			if flow == "Outbound" {
+				rules = securityGroup.OutboundRules
 			}
-		}
-		log.V(2).Info("Deleting outbound rules", "securityGroupId", securityGroup.SecurityGroupId)
-		for _, rule := range securityGroup.OutboundRules {
-			for _, member := range rule.SecurityGroupsMembers {
-				err = securityGroupSvc.DeleteSecurityGroupRule(ctx, securityGroup.SecurityGroupId, "Outbound", rule.IpProtocol, "", member.SecurityGroupId, rule.FromPortRange, rule.ToPortRange)
-				if err != nil {
-					return reconcile.Result{}, fmt.Errorf("cannot delete rule: %w", err)
-				}
+			memberRules := slices.DeleteFunc(slices.Clone(rules), func(rule osc.SecurityGroupRule) bool {
+				return len(rule.SecurityGroupsMembers) == 0
+			})
+			if len(memberRules) == 0 {
+				continue
+			}
+			log.V(2).Info("Deleting rules referencing securityGroups", "securityGroupId", securityGroup.SecurityGroupId, "flow", flow)
+			for i := range memberRules {
+				memberRules[i].IpRanges = nil
+			}
+			err = securityGroupSvc.DeleteSecurityGroupRules(ctx, securityGroup.SecurityGroupId, flow, memberRules)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot delete rules: %w", err)
 			}
 		}
 	}
@@ -0,0 +1,211 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// caCertKey is the key of the optional certificate chain in a kubernetes.io/tls Secret.
+const caCertKey = "ca.crt"
+
+// isServerCertificateSuffix matches the <port>-<hash> suffix of the name of an uploaded server certificate.
+var isServerCertificateSuffix = regexp.MustCompile(`^[0-9]+-[0-9a-f]{16}$`).MatchString
+
+// serverCertificatePath returns the path under which the server certificates of a cluster are uploaded.
+func serverCertificatePath(clusterScope *scope.ClusterScope) string {
+	return "/" + clusterScope.GetUID() + "/"
+}
+
+// isServerCertificateOf checks if a server certificate has been uploaded for a listener of a loadBalancer.
+func isServerCertificateOf(name, loadBalancerName string) bool {
+	suffix, found := strings.CutPrefix(name, loadBalancerName+"-")
+	return found && isServerCertificateSuffix(suffix)
+}
+
+type serverCertificateSecret struct {
+	name                    string
+	body, chain, privateKey string
+}
+
+// getServerCertificateSecret reads the server certificate of a listener from its Secret.
+// The certificate is uploaded under a name derived from its content, a rotated certificate being uploaded under a new name.
+func (r *OscClusterReconciler) getServerCertificateSecret(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerName string,
+	listener infrastructurev1beta2.OscLoadBalancerListener) (*serverCertificateSecret, error) {
+	// Secrets are only watched by their metadata, and are read from the API server.
+	var secret corev1.Secret
+	err := r.APIReader.Get(ctx, client.ObjectKey{
+		Name:      listener.ServerCertificateSecretName,
+		Namespace: clusterScope.OscCluster.Namespace,
+	}, &secret)
+	if err != nil {
+		return nil, fmt.Errorf("server certificate from secret: %w", err)
+	}
+	body, privateKey := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(body) == 0 || len(privateKey) == 0 {
+		return nil, fmt.Errorf("server certificate from secret %s: %s and %s are required", listener.ServerCertificateSecretName, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	chain := secret.Data[caCertKey]
+	hash := sha256.Sum256(slices.Concat(body, chain))
+	return &serverCertificateSecret{
+		name:       fmt.Sprintf("%s-%d-%x", loadBalancerName, listener.LoadBalancerPort, hash[:8]),
+		body:       string(body),
+		chain:      string(chain),
+		privateKey: string(privateKey),
+	}, nil
+}
+
+// serverCertificatesChanged checks if the Secret of a listener of a loadBalancer has been updated since its last upload.
+func (r *OscClusterReconciler) serverCertificatesChanged(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer) bool {
+	uploaded := r.Tracker.getServerCertificates(clusterScope, loadBalancerSpec.LoadBalancerName)
+	for _, listener := range loadBalancerSpec.GetListeners() {
+		if listener.ServerCertificateSecretName == "" {
+			continue
+		}
+		sc, err := r.getServerCertificateSecret(ctx, clusterScope, loadBalancerSpec.LoadBalancerName, listener)
+		if err != nil || sc.name != uploaded[serverCertificateKey(loadBalancerSpec.LoadBalancerName, listener.LoadBalancerPort)] {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileServerCertificates uploads the server certificates of the HTTPS/SSL listeners of a loadBalancer, and returns their ORN by loadBalancer port.
+func (r *OscClusterReconciler) reconcileServerCertificates(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer) (map[int32]string, error) {
+	log := ctrl.LoggerFrom(ctx)
+	listeners := slices.DeleteFunc(loadBalancerSpec.GetListeners(), func(listener infrastructurev1beta2.OscLoadBalancerListener) bool {
+		return listener.ServerCertificateSecretName == ""
+	})
+	if len(listeners) == 0 {
+		return nil, nil
+	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	path := serverCertificatePath(clusterScope)
+	existing, err := svc.ListServerCertificates(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list server certificates: %w", err)
+	}
+	orns := make(map[int32]string, len(listeners))
+	for _, listener := range listeners {
+		secret, err := r.getServerCertificateSecret(ctx, clusterScope, loadBalancerSpec.LoadBalancerName, listener)
+		if err != nil {
+			return nil, err
+		}
+		var sc *osc.ServerCertificate
+		if idx := slices.IndexFunc(existing, func(sc osc.ServerCertificate) bool {
+			return lo.FromPtr(sc.Name) == secret.name
+		}); idx >= 0 {
+			sc = &existing[idx]
+		} else {
+			log.V(2).Info("Uploading server certificate", "name", secret.name, "secret", listener.ServerCertificateSecretName)
+			sc, err = svc.CreateServerCertificate(ctx, secret.name, path, secret.body, secret.chain, secret.privateKey)
+			if err != nil {
+				return nil, fmt.Errorf("cannot upload server certificate: %w", err)
+			}
+		}
+		if sc.Orn == nil {
+			return nil, fmt.Errorf("server certificate %s has no ORN", secret.name)
+		}
+		orns[listener.LoadBalancerPort] = *sc.Orn
+		r.Tracker.setServerCertificate(clusterScope, serverCertificateKey(loadBalancerSpec.LoadBalancerName, listener.LoadBalancerPort), secret.name)
+	}
+	return orns, nil
+}
+
+// deleteUnusedServerCertificates deletes the server certificates uploaded for a loadBalancer and no longer used by its listeners.
+func (r *OscClusterReconciler) deleteUnusedServerCertificates(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerSpec infrastructurev1beta2.OscLoadBalancer) error {
+	log := ctrl.LoggerFrom(ctx)
+	loadBalancerName := loadBalancerSpec.LoadBalancerName
+	uploaded := r.Tracker.getServerCertificates(clusterScope, loadBalancerName)
+	used := map[string]bool{}
+	for _, listener := range loadBalancerSpec.GetListeners() {
+		key := serverCertificateKey(loadBalancerName, listener.LoadBalancerPort)
+		if listener.ServerCertificateSecretName != "" {
+			used[uploaded[key]] = true
+			delete(uploaded, key)
+		}
+	}
+	if len(uploaded) == 0 && len(used) == 0 {
+		return nil
+	}
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	existing, err := svc.ListServerCertificates(ctx, serverCertificatePath(clusterScope))
+	if err != nil {
+		return fmt.Errorf("cannot list server certificates: %w", err)
+	}
+	for _, sc := range existing {
+		name := lo.FromPtr(sc.Name)
+		if !isServerCertificateOf(name, loadBalancerName) || used[name] {
+			continue
+		}
+		log.V(2).Info("Deleting server certificate", "name", name)
+		err := svc.DeleteServerCertificate(ctx, name)
+		if err != nil {
+			return fmt.Errorf("cannot delete server certificate: %w", err)
+		}
+	}
+	for key := range uploaded {
+		r.Tracker.unsetServerCertificate(clusterScope, key)
+	}
+	return nil
+}
+
+// deleteServerCertificates deletes all the server certificates uploaded for a deleted loadBalancer.
+func (r *OscClusterReconciler) deleteServerCertificates(ctx context.Context, clusterScope *scope.ClusterScope, loadBalancerName string) error {
+	return r.deleteUnusedServerCertificates(ctx, clusterScope, infrastructurev1beta2.OscLoadBalancer{LoadBalancerName: loadBalancerName})
+}
+
+// SecretToOscClusters maps a Secret to the OscClusters having a listener using it as server certificate.
+func (r *OscClusterReconciler) SecretToOscClusters(ctx context.Context) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		log := ctrl.LoggerFrom(ctx).WithValues("objectMapper", "secretToOscCluster", "namespace", o.GetNamespace())
+		var clusters infrastructurev1beta2.OscClusterList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(o.GetNamespace())); err != nil {
+			log.V(1).Error(err, "failed to list OscClusters, skipping mapping.")
+			return nil
+		}
+		var result []reconcile.Request
+		for _, c := range clusters.Items {
+			if !r.hasWatchFilterLabel(&c) {
+				continue
+			}
+			if slices.Contains(getServerCertificateSecretNames(&c.Spec.Network), o.GetName()) {
+				result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&c)})
+			}
+		}
+		return result
+	}
+}
+
+// getServerCertificateSecretNames returns the names of the Secrets used as server certificates by the listeners of a cluster.
+func getServerCertificateSecretNames(network *infrastructurev1beta2.OscNetwork) []string {
+	var names []string
+	listeners := network.LoadBalancer.GetListeners()
+	for _, alb := range network.AdditionalLoadBalancers {
+		listeners = append(listeners, alb.Listeners...)
+	}
+	for _, listener := range listeners {
+		if listener.ServerCertificateSecretName != "" {
+			names = append(names, listener.ServerCertificateSecretName)
+		}
+	}
+	return names
+}
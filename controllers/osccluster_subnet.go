@@ -11,6 +11,9 @@ import (
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/utils"
+	"github.com/outscale/cluster-api-provider-outscale/util/parallel"
+	"github.com/outscale/cluster-api-provider-outscale/util/reconciler"
 	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,26 +33,56 @@ func (r *OscClusterReconciler) reconcileSubnets(ctx context.Context, clusterScop
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if clusterScope.AllocatesSubnets() {
+		if err := r.reconcileSubnetClaims(ctx, clusterScope, netId, true); err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot claim subnet ranges: %w", err)
+		}
+	}
+	// Allocated subnets are created, even in an existing net.
+	useExisting := clusterScope.GetNetwork().UseExisting.Net && !clusterScope.AllocatesSubnets()
 	svc := r.Cloud.Net(clusterScope.Tenant)
-	for _, subnetSpec := range clusterScope.GetSubnets() {
+	err = parallel.ForEach(ctx, reconciler.DefaultedParallelism(r.Parallelism), clusterScope.GetSubnets(), func(ctx context.Context, subnetSpec infrastructurev1beta2.OscSubnet) error {
 		subnet, err := r.Tracker.getSubnet(ctx, subnetSpec, clusterScope)
 		switch {
-		case IsNotFound(err) && !clusterScope.GetNetwork().UseExisting.Net:
+		case IsNotFound(err) && !useExisting:
 		case err != nil:
-			return reconcile.Result{}, fmt.Errorf("get existing: %w", err)
+			return fmt.Errorf("get existing: %w", err)
 		default:
 			log.V(4).Info("Found existing subnet", "roles", subnetSpec.Roles, "subregion", subnetSpec.SubregionName, "subnetId", subnet.SubnetId)
-			continue
+			if !useExisting {
+				return nil
+			}
+			added, tagged, err := tagShared(ctx, r.Cloud.Tag(clusterScope.Tenant), subnet.SubnetId, subnet.Tags, clusterScope.GetUID(), utils.RoleTags(subnetSpec.Roles))
+			if err != nil {
+				return fmt.Errorf("cannot tag existing subnet: %w", err)
+			}
+			if len(added) > 0 {
+				r.Tracker.addSharedRoleTags(clusterScope, subnet.SubnetId, added)
+			}
+			if tagged {
+				log.V(2).Info("Tagged existing subnet as shared", "subnetId", subnet.SubnetId)
+			}
+			return nil
 		}
 		subnetSpec.SubregionName = clusterScope.GetSubnetSubregion(subnetSpec)
 		log.V(3).Info("Creating subnet", "roles", subnetSpec.Roles, "subregion", subnetSpec.SubregionName)
 		subnet, err = svc.CreateSubnet(ctx, subnetSpec, netId, clusterScope.GetUID(), clusterScope.GetSubnetName(subnetSpec))
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("cannot create subnet: %w", err)
+			// The range might be the cause of the error, it is claimed again on the next reconciliation.
+			if clusterScope.AllocatesSubnets() {
+				if rerr := clusterScope.ReleaseSubnetClaim(ctx, subnetSpec); rerr != nil {
+					log.V(3).Error(rerr, "Unable to release subnet claim")
+				}
+			}
+			return fmt.Errorf("cannot create subnet: %w", err)
 		}
 		log.V(2).Info("Created subnet", "subnetId", subnet.SubnetId)
 		r.Tracker.setSubnetId(clusterScope, subnetSpec, subnet.SubnetId)
 		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.SubnetCreatedReason, "Subnet created %v %s", subnetSpec.Roles, subnetSpec.SubregionName)
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// add failureDomains
@@ -68,13 +101,30 @@ func (r *OscClusterReconciler) reconcileSubnets(ctx context.Context, clusterScop
 // reconcileDeleteSubnet reconcile the destruction of the Subnet of the cluster.
 func (r *OscClusterReconciler) reconcileDeleteSubnets(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
-	if clusterScope.GetNetwork().UseExisting.Net {
+	useExisting := clusterScope.GetNetwork().UseExisting.Net && !clusterScope.AllocatesSubnets()
+	if useExisting {
 		log.V(4).Info("Not deleting existing subnets")
-		return reconcile.Result{}, nil
+	}
+	if clusterScope.AllocatesSubnets() {
+		netId, err := r.Tracker.getNetId(ctx, clusterScope)
+		switch {
+		case IsNotFound(err):
+			log.V(4).Info("The net is already deleted, no subnet expected")
+			return reconcile.Result{}, nil
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("get net: %w", err)
+		}
+		if err := r.reconcileSubnetClaims(ctx, clusterScope, netId, false); err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot recover subnet claims: %w", err)
+		}
 	}
 	svc := r.Cloud.Net(clusterScope.Tenant)
 	subnetsSpec := clusterScope.GetSubnets()
 	for _, subnetSpec := range subnetsSpec {
+		if subnetSpec.IpSubnetRange == "" {
+			// unclaimed allocated subnet, never created
+			continue
+		}
 		subnet, err := r.Tracker.getSubnet(ctx, subnetSpec, clusterScope)
 		switch {
 		case IsNotFound(err):
@@ -83,6 +133,17 @@ func (r *OscClusterReconciler) reconcileDeleteSubnets(ctx context.Context, clust
 			return reconcile.Result{}, fmt.Errorf("find existing: %w", err)
 		}
 		subnetId := subnet.SubnetId
+		if useExisting {
+			untagged, err := untagShared(ctx, r.Cloud.Tag(clusterScope.Tenant), subnetId, subnet.Tags, clusterScope.GetUID(), r.Tracker.getSharedRoleTags(clusterScope, subnetId))
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot untag existing subnet: %w", err)
+			}
+			r.Tracker.unsetSharedRoleTags(clusterScope, subnetId)
+			if untagged {
+				log.V(2).Info("Removed shared tags from existing subnet", "subnetId", subnetId)
+			}
+			continue
+		}
 		log.V(2).Info("Deleting subnet", "subnetId", subnetId)
 		err = svc.DeleteSubnet(ctx, subnetId)
 		if err != nil {
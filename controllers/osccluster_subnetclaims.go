@@ -0,0 +1,148 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/utils"
+	"github.com/outscale/cluster-api-provider-outscale/util/cidr"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// subnetClaimsMu serializes subnet allocations, for the claims of clusters reconciled concurrently not to overlap.
+var subnetClaimsMu sync.Mutex
+
+// reconcileSubnetClaims ensures that an IP range is claimed for each allocated subnet.
+// Missing claims are first recovered from the subnets owned by the cluster, in case the status was reset.
+// If allocate is set, free ranges of the net are claimed for the remaining subnets, excluding the ranges of
+// existing subnets, the reserved range of the layout and the ranges claimed by other clusters sharing the net.
+func (r *OscClusterReconciler) reconcileSubnetClaims(ctx context.Context, clusterScope *scope.ClusterScope, netId string, allocate bool) error {
+	log := ctrl.LoggerFrom(ctx)
+	var missing []infrastructurev1beta2.OscSubnet
+	var used []netip.Prefix
+	for _, subnet := range clusterScope.GetSubnets() {
+		if subnet.IpSubnetRange == "" {
+			missing = append(missing, subnet)
+		} else if p, err := netip.ParsePrefix(subnet.IpSubnetRange); err == nil {
+			used = append(used, p)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	subnetClaimsMu.Lock()
+	defer subnetClaimsMu.Unlock()
+	existing, err := r.Cloud.Net(clusterScope.Tenant).GetSubnetsFromNet(ctx, netId)
+	if err != nil {
+		return fmt.Errorf("list subnets: %w", err)
+	}
+	claims := map[string]string{}
+	recovered := map[string]bool{}
+	var unclaimed []infrastructurev1beta2.OscSubnet
+	for _, subnet := range missing {
+		found := false
+		for _, sn := range existing {
+			if !recovered[sn.SubnetId] && sn.SubregionName == subnet.SubregionName && isOwnedSubnet(sn, clusterScope.GetUID(), clusterScope.GetSubnetName(subnet), subnet.Roles) {
+				log.V(3).Info("Recovered subnet claim", "roles", subnet.Roles, "subregion", subnet.SubregionName, "ipRange", sn.IpRange)
+				claims[scope.SubnetClaimKey(subnet)] = sn.IpRange
+				recovered[sn.SubnetId] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			unclaimed = append(unclaimed, subnet)
+		}
+	}
+
+	if allocate && len(unclaimed) > 0 {
+		net, err := r.Tracker.getNet(ctx, clusterScope)
+		if err != nil {
+			return fmt.Errorf("get net: %w", err)
+		}
+		parent, err := netip.ParsePrefix(net.IpRange)
+		if err != nil {
+			return fmt.Errorf("invalid net range: %w", err)
+		}
+		for _, sn := range existing {
+			if p, err := netip.ParsePrefix(sn.IpRange); err == nil {
+				used = append(used, p)
+			}
+		}
+		others, err := r.listSubnetClaims(ctx, clusterScope, netId)
+		if err != nil {
+			return fmt.Errorf("list claims of other clusters: %w", err)
+		}
+		used = append(used, others...)
+		if p, err := netip.ParsePrefix(clusterScope.GetNetwork().SubnetLayout.ReservedIpRange); err == nil {
+			used = append(used, p)
+		}
+		for _, subnet := range unclaimed {
+			p, err := cidr.Allocate(parent, used, clusterScope.GetSubnetPrefixLength(subnet))
+			if err != nil {
+				return err
+			}
+			log.V(2).Info("Claimed subnet range", "roles", subnet.Roles, "subregion", subnet.SubregionName, "ipRange", p.String())
+			claims[scope.SubnetClaimKey(subnet)] = p.String()
+			used = append(used, p)
+		}
+	}
+	if len(claims) == 0 {
+		return nil
+	}
+	return clusterScope.ClaimSubnets(ctx, claims)
+}
+
+// listSubnetClaims lists the ranges claimed by the other clusters sharing a net.
+// Clusters are read from the API server, as the cache might not include the latest claims.
+func (r *OscClusterReconciler) listSubnetClaims(ctx context.Context, clusterScope *scope.ClusterScope, netId string) ([]netip.Prefix, error) {
+	var list infrastructurev1beta2.OscClusterList
+	if err := r.APIReader.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var claims []netip.Prefix
+	for _, c := range list.Items {
+		if c.UID == clusterScope.OscCluster.UID || len(c.Status.Resources.SubnetClaims) == 0 {
+			continue
+		}
+		otherNetId := c.Spec.Network.Net.ResourceId
+		if otherNetId == "" {
+			otherNetId = getResource(defaultResource, c.Status.Resources.Net)
+		}
+		if otherNetId != netId {
+			continue
+		}
+		for _, ipRange := range c.Status.Resources.SubnetClaims {
+			if p, err := netip.ParsePrefix(ipRange); err == nil {
+				claims = append(claims, p)
+			}
+		}
+	}
+	return claims, nil
+}
+
+// isOwnedSubnet returns true if a subnet is owned by the cluster, is named name and has the tags of roles.
+func isOwnedSubnet(sn osc.Subnet, clusterID, name string, roles []infrastructurev1beta2.OscRole) bool {
+	if !tags.Has(sn.Tags, tags.ClusterIDKey(clusterID), tag.OwnedValue) || !tags.Has(sn.Tags, tag.NameKey, name) {
+		return false
+	}
+	for _, rt := range utils.RoleTags(roles) {
+		if !tags.Has(sn.Tags, rt.Key) {
+			return false
+		}
+	}
+	return true
+}
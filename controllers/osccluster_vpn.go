@@ -0,0 +1,366 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/goutils/sdk/ptr"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const defaultBgpAsn = 65000
+
+func getBgpAsn(conn infrastructurev1beta2.OscVpnConnection) int {
+	if conn.BgpAsn == 0 {
+		return defaultBgpAsn
+	}
+	return int(conn.BgpAsn)
+}
+
+func isVirtualGatewayAlive(vgw *osc.VirtualGateway) bool {
+	return vgw.State == osc.VirtualGatewayStatePending || vgw.State == osc.VirtualGatewayStateAvailable
+}
+
+func isVirtualGatewayLinked(vgw *osc.VirtualGateway, netId string) bool {
+	return slices.ContainsFunc(vgw.NetToVirtualGatewayLinks, func(l osc.NetToVirtualGatewayLink) bool {
+		state := ptr.From(l.State)
+		return ptr.From(l.NetId) == netId &&
+			(state == osc.NetToVirtualGatewayLinkStateAttached || state == osc.NetToVirtualGatewayLinkStateAttaching)
+	})
+}
+
+func isClientGatewayAlive(cgw *osc.ClientGateway) bool {
+	return cgw.State == osc.ClientGatewayStatePending || cgw.State == osc.ClientGatewayStateAvailable
+}
+
+func isVpnConnectionAlive(vpn *osc.VpnConnection) bool {
+	return vpn.State == osc.VpnConnectionStatePending || vpn.State == osc.VpnConnectionStateAvailable
+}
+
+func isVpnRouteAlive(r osc.RouteLight) bool {
+	return r.State == osc.RouteLightStatePending || r.State == osc.RouteLightStateAvailable
+}
+
+func hasVirtualGatewayRoute(rtbl osc.RouteTable, ipRange, virtualGatewayId string) bool {
+	return slices.ContainsFunc(rtbl.Routes, func(r osc.Route) bool {
+		return r.DestinationIpRange == ipRange && ptr.From(r.GatewayId) == virtualGatewayId
+	})
+}
+
+// reconcileVpn reconciles the virtual gateway of the cluster, its VPN connections and their routes.
+func (r *OscClusterReconciler) reconcileVpn(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if !clusterScope.NeedReconciliation(infrastructurev1beta2.ReconcilerVpn) {
+		log.V(4).Info("No need for vpn reconciliation")
+		return reconcile.Result{}, nil
+	}
+	log.V(4).Info("Reconciling vpn")
+
+	vpn := clusterScope.GetNetwork().Vpn
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	netId, err := r.Tracker.getNetId(ctx, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	vgw, err := r.Tracker.getVirtualGateway(ctx, clusterScope)
+	switch {
+	case IsNotFound(err) && vpn.VirtualGatewayID == "":
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("get existing: %w", err)
+	}
+	if vgw == nil || !isVirtualGatewayAlive(vgw) {
+		if vpn.VirtualGatewayID != "" {
+			return reconcile.Result{}, fmt.Errorf("virtual gateway %s is %s", vpn.VirtualGatewayID, vgw.State)
+		}
+		log.V(3).Info("Creating virtual gateway")
+		vgw, err = svc.CreateVirtualGateway(ctx, clusterScope.GetUID())
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot create virtual gateway: %w", err)
+		}
+		log.V(2).Info("Created virtual gateway", "virtualGatewayId", vgw.VirtualGatewayId)
+		r.Tracker.setVirtualGatewayId(clusterScope, vgw.VirtualGatewayId)
+		r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.VpnCreatedReason, "Virtual gateway created")
+	}
+	if !isVirtualGatewayLinked(vgw, netId) {
+		log.V(2).Info("Linking virtual gateway", "virtualGatewayId", vgw.VirtualGatewayId)
+		err = svc.LinkVirtualGateway(ctx, vgw.VirtualGatewayId, netId)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot link virtual gateway: %w", err)
+		}
+	}
+	// An existing virtual gateway is also tracked, to be unlinked if the VPN is disabled.
+	if r.Tracker.getVirtualGatewayId(clusterScope) != vgw.VirtualGatewayId {
+		r.Tracker.setVirtualGatewayId(clusterScope, vgw.VirtualGatewayId)
+	}
+
+	for _, conn := range vpn.Connections {
+		log := log.WithValues("vpnConnection", conn.Name)
+		cgw, err := r.Tracker.getClientGateway(ctx, conn, clusterScope)
+		switch {
+		case IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("get existing client gateway: %w", err)
+		}
+		if cgw == nil || !isClientGatewayAlive(cgw) {
+			log.V(3).Info("Creating client gateway", "publicIp", conn.ClientGatewayIP)
+			cgw, err = svc.CreateClientGateway(ctx, conn.ClientGatewayIP, getBgpAsn(conn), clusterScope.GetName()+"-"+conn.Name, clusterScope.GetUID())
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot create client gateway: %w", err)
+			}
+			log.V(2).Info("Created client gateway", "clientGatewayId", cgw.ClientGatewayId)
+			r.Tracker.setClientGatewayId(clusterScope, conn, cgw.ClientGatewayId)
+		}
+
+		vpnConn, err := r.Tracker.getVpnConnection(ctx, conn, cgw.ClientGatewayId, vgw.VirtualGatewayId, clusterScope)
+		switch {
+		case IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("get existing vpn connection: %w", err)
+		}
+		if vpnConn == nil || !isVpnConnectionAlive(vpnConn) {
+			log.V(3).Info("Creating vpn connection", "clientGatewayId", cgw.ClientGatewayId, "virtualGatewayId", vgw.VirtualGatewayId)
+			vpnConn, err = svc.CreateVpnConnection(ctx, cgw.ClientGatewayId, vgw.VirtualGatewayId, clusterScope.GetName()+"-"+conn.Name, clusterScope.GetUID())
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot create vpn connection: %w", err)
+			}
+			log.V(2).Info("Created vpn connection", "vpnConnectionId", vpnConn.VpnConnectionId)
+			r.Tracker.setVpnConnectionId(clusterScope, conn, vpnConn.VpnConnectionId)
+			r.Recorder.Eventf(clusterScope.OscCluster, corev1.EventTypeNormal, infrastructurev1beta2.VpnCreatedReason, "VPN connection %s created", conn.Name)
+		}
+
+		// Sync static routes of the VPN connection
+		for _, ipRange := range conn.StaticRoutes {
+			if slices.ContainsFunc(vpnConn.Routes, func(r osc.RouteLight) bool {
+				return r.DestinationIpRange == ipRange && isVpnRouteAlive(r)
+			}) {
+				continue
+			}
+			log.V(3).Info("Creating vpn connection route", "IPRange", ipRange)
+			err := svc.CreateVpnConnectionRoute(ctx, vpnConn.VpnConnectionId, ipRange)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("create vpn connection route: %w", err)
+			}
+		}
+		for _, route := range vpnConn.Routes {
+			if !isVpnRouteAlive(route) || slices.Contains(conn.StaticRoutes, route.DestinationIpRange) {
+				continue
+			}
+			log.V(3).Info("Deleting vpn connection route", "IPRange", route.DestinationIpRange)
+			err := svc.DeleteVpnConnectionRoute(ctx, vpnConn.VpnConnectionId, route.DestinationIpRange)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("delete vpn connection route: %w", err)
+			}
+		}
+	}
+
+	// Route on-premises networks to the virtual gateway
+	ipRanges := clusterScope.GetVpnIPRanges()
+	rtbls, err := svc.GetRouteTablesFromNet(ctx, netId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("list route tables: %w", err)
+	}
+	for _, rtbl := range rtbls {
+		for _, ipRange := range ipRanges {
+			if hasVirtualGatewayRoute(rtbl, ipRange, vgw.VirtualGatewayId) {
+				continue
+			}
+			log.V(3).Info("Creating route to virtual gateway", "routeTableId", rtbl.RouteTableId, "IPRange", ipRange)
+			_, err := svc.CreateRoute(ctx, ipRange, rtbl.RouteTableId, vgw.VirtualGatewayId, "virtualGateway")
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("create route: %w", err)
+			}
+		}
+		for _, route := range rtbl.Routes {
+			if ptr.From(route.GatewayId) != vgw.VirtualGatewayId || slices.Contains(ipRanges, route.DestinationIpRange) {
+				continue
+			}
+			log.V(3).Info("Deleting route to virtual gateway", "routeTableId", rtbl.RouteTableId, "IPRange", route.DestinationIpRange)
+			err := svc.DeleteRoute(ctx, route.DestinationIpRange, rtbl.RouteTableId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("delete route: %w", err)
+			}
+		}
+	}
+
+	clusterScope.SetReconciliationGeneration(infrastructurev1beta2.ReconcilerVpn)
+	return reconcile.Result{}, nil
+}
+
+// reconcileDeleteVpn reconciles the destruction of the VPN connections of the cluster, and unlinks its virtual gateway.
+// The virtual gateway is deleted if it has been created by the cluster.
+func (r *OscClusterReconciler) reconcileDeleteVpn(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	vpn := clusterScope.GetNetwork().Vpn
+	svc := r.Cloud.Net(clusterScope.Tenant)
+
+	vgw, err := r.Tracker.getVirtualGateway(ctx, clusterScope)
+	switch {
+	case IsNotFound(err):
+		log.V(4).Info("The virtual gateway is already deleted")
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+	var vgwId string
+	if vgw != nil {
+		vgwId = vgw.VirtualGatewayId
+	}
+
+	for _, conn := range vpn.Connections {
+		log := log.WithValues("vpnConnection", conn.Name)
+		cgw, err := r.Tracker.getClientGateway(ctx, conn, clusterScope)
+		switch {
+		case IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, err
+		}
+		var cgwId string
+		if cgw != nil {
+			cgwId = cgw.ClientGatewayId
+		}
+		vpnConn, err := r.Tracker.getVpnConnection(ctx, conn, cgwId, vgwId, clusterScope)
+		switch {
+		case IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, err
+		}
+		if vpnConn != nil && isVpnConnectionAlive(vpnConn) {
+			log.V(2).Info("Deleting vpn connection", "vpnConnectionId", vpnConn.VpnConnectionId)
+			err = svc.DeleteVpnConnection(ctx, vpnConn.VpnConnectionId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot delete vpn connection: %w", err)
+			}
+		}
+		if cgw != nil && isClientGatewayAlive(cgw) {
+			log.V(2).Info("Deleting client gateway", "clientGatewayId", cgw.ClientGatewayId)
+			err = svc.DeleteClientGateway(ctx, cgw.ClientGatewayId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot delete client gateway: %w", err)
+			}
+		}
+	}
+
+	if vgw == nil {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, r.deleteVirtualGateway(ctx, clusterScope, vgw, vpn.VirtualGatewayID == "")
+}
+
+// deleteVirtualGateway deletes the routes to a virtual gateway and unlinks it. It is deleted if owned is set.
+func (r *OscClusterReconciler) deleteVirtualGateway(ctx context.Context, clusterScope *scope.ClusterScope, vgw *osc.VirtualGateway, owned bool) error {
+	log := ctrl.LoggerFrom(ctx)
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	vgwId := vgw.VirtualGatewayId
+	netId, err := r.Tracker.getNetId(ctx, clusterScope)
+	switch {
+	case IsNotFound(err):
+		log.V(4).Info("The net is already deleted, no link or route expected")
+	case err != nil:
+		return fmt.Errorf("find net: %w", err)
+	default:
+		rtbls, err := svc.GetRouteTablesFromNet(ctx, netId)
+		if err != nil {
+			return fmt.Errorf("list route tables: %w", err)
+		}
+		for _, rtbl := range rtbls {
+			for _, route := range rtbl.Routes {
+				if ptr.From(route.GatewayId) != vgwId {
+					continue
+				}
+				log.V(3).Info("Deleting route to virtual gateway", "routeTableId", rtbl.RouteTableId, "IPRange", route.DestinationIpRange)
+				err := svc.DeleteRoute(ctx, route.DestinationIpRange, rtbl.RouteTableId)
+				if err != nil {
+					return fmt.Errorf("delete route: %w", err)
+				}
+			}
+		}
+		if isVirtualGatewayLinked(vgw, netId) {
+			log.V(2).Info("Unlinking virtual gateway", "virtualGatewayId", vgwId)
+			err = svc.UnlinkVirtualGateway(ctx, vgwId, netId)
+			if err != nil {
+				return fmt.Errorf("cannot unlink virtual gateway: %w", err)
+			}
+		}
+	}
+
+	if !owned || !isVirtualGatewayAlive(vgw) {
+		return nil
+	}
+	log.V(2).Info("Deleting virtual gateway", "virtualGatewayId", vgwId)
+	err = svc.DeleteVirtualGateway(ctx, vgwId)
+	if err != nil {
+		return fmt.Errorf("cannot delete virtual gateway: %w", err)
+	}
+	return nil
+}
+
+// reconcileRemovedVpn deletes the VPN resources no longer in the spec: the VPN connections removed from the spec and,
+// if the VPN is disabled, all connections and the virtual gateway along with its routes.
+func (r *OscClusterReconciler) reconcileRemovedVpn(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	svc := r.Cloud.Net(clusterScope.Tenant)
+	for _, name := range r.Tracker.getRemovedVpnConnections(clusterScope) {
+		log := log.WithValues("vpnConnection", name)
+		cgwId, vpnConnId := r.Tracker.getVpnConnectionIds(clusterScope, name)
+		if vpnConnId != "" {
+			vpnConn, err := svc.GetVpnConnection(ctx, vpnConnId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("get removed vpn connection %s: %w", name, err)
+			}
+			if vpnConn != nil && isVpnConnectionAlive(vpnConn) {
+				log.V(2).Info("Deleting removed vpn connection", "vpnConnectionId", vpnConnId)
+				err = svc.DeleteVpnConnection(ctx, vpnConnId)
+				if err != nil {
+					return reconcile.Result{}, fmt.Errorf("cannot delete vpn connection: %w", err)
+				}
+			}
+		}
+		if cgwId != "" {
+			cgw, err := svc.GetClientGateway(ctx, cgwId)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("get removed client gateway %s: %w", name, err)
+			}
+			if cgw != nil && isClientGatewayAlive(cgw) {
+				log.V(2).Info("Deleting removed client gateway", "clientGatewayId", cgwId)
+				err = svc.DeleteClientGateway(ctx, cgwId)
+				if err != nil {
+					return reconcile.Result{}, fmt.Errorf("cannot delete client gateway: %w", err)
+				}
+			}
+		}
+		r.Tracker.unsetVpnConnectionIds(clusterScope, name)
+	}
+
+	vgwId := r.Tracker.getVirtualGatewayId(clusterScope)
+	if clusterScope.GetNetwork().Vpn.Enable || vgwId == "" {
+		return reconcile.Result{}, nil
+	}
+	vgw, err := svc.GetVirtualGateway(ctx, vgwId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get virtual gateway: %w", err)
+	}
+	if vgw != nil {
+		log.V(3).Info("VPN is disabled, removing virtual gateway", "virtualGatewayId", vgwId)
+		owned := tags.Has(vgw.Tags, tags.ClusterIDKey(clusterScope.GetUID()), tag.OwnedValue)
+		if err := r.deleteVirtualGateway(ctx, clusterScope, vgw, owned); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	r.Tracker.unsetVirtualGatewayId(clusterScope)
+	clusterScope.ClearReconciliationGeneration(infrastructurev1beta2.ReconcilerVpn)
+	clusterScope.DeleteCondition(infrastructurev1beta2.VpnReadyCondition)
+	return reconcile.Result{}, nil
+}
@@ -17,6 +17,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -29,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const OscMachineFinalizer = "oscmachine.infrastructure.cluster.x-k8s.io"
@@ -43,6 +45,8 @@ type OscMachineReconciler struct {
 	Recorder         record.EventRecorder
 	ReconcileTimeout time.Duration
 	WatchFilterValue string
+	// VmPoller triggers reconciliations when vms change state, instead of periodic requeues.
+	VmPoller *VmStatePoller
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscmachines,verbs=get;list;watch;create;update;patch;delete
@@ -50,6 +54,8 @@ type OscMachineReconciler struct {
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oscmachines/finalizers,verbs=update
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines/status,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -193,10 +199,21 @@ func (r *OscMachineReconciler) reconcileDelete(ctx context.Context, machineScope
 	log := ctrl.LoggerFrom(ctx)
 	log.V(3).Info("Reconciling delete OscMachine")
 	oscmachine := machineScope.OscMachine
-	_, err := r.reconcileDeleteVm(ctx, clusterScope, machineScope)
+	res, err := r.reconcileDeleteVm(ctx, clusterScope, machineScope)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if machineScope.GetVm().PrivateIpPoolRef != nil {
+		// The IP address is released once the vm is terminated, for it not to be allocated to another vm meanwhile.
+		if !res.IsZero() {
+			log.V(3).Info("Waiting for the vm to be terminated before releasing its IP address")
+			return res, nil
+		}
+		err = r.reconcileDeleteIPAddressClaim(ctx, machineScope)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 	_, err = r.reconcileDeletePublicIp(ctx, clusterScope, machineScope)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -211,10 +228,11 @@ func (r *OscMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 	if err != nil {
 		return fmt.Errorf("failed to create mapper for Cluster to OscMachines: %w", err)
 	}
-	err = ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		For(&infrastructurev1beta2.OscMachine{}).
+		Owns(&ipamv1.IPAddressClaim{}).
 		Watches(
 			&clusterv1.Machine{},
 			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(infrastructurev1beta2.GroupVersion.WithKind("OscMachine"))),
@@ -227,8 +245,11 @@ func (r *OscMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 			&clusterv1.Cluster{},
 			handler.EnqueueRequestsFromMapFunc(clusterToObjectFunc),
 			builder.WithPredicates(predicates.ClusterPausedTransitionsOrInfrastructureReady(mgr.GetScheme(), ctrl.LoggerFrom(ctx))),
-		).
-		Complete(r)
+		)
+	if r.VmPoller != nil {
+		b = b.WatchesRawSource(source.Channel(r.VmPoller.Events(), &handler.EnqueueRequestForObject{}))
+	}
+	err = b.Complete(r)
 	if err != nil {
 		return fmt.Errorf("error creating controller: %w", err)
 	}
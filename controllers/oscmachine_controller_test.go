@@ -25,12 +25,17 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func runMachineTest(t *testing.T, tc testcase) {
 	c, oc := loadClusterSpecs(t, tc.clusterSpec, tc.clusterBaseSpec)
+	for _, fn := range tc.clusterPatches {
+		fn(oc)
+	}
 	m, om := loadMachineSpecs(t, tc.machineSpec, tc.machineBaseSpec, oc.Name)
 	for _, fn := range tc.machinePatches {
 		fn(om)
@@ -41,6 +46,7 @@ func runMachineTest(t *testing.T, tc testcase) {
 	_ = clusterv1.AddToScheme(fakeScheme)
 	_ = apiextensionsv1.AddToScheme(fakeScheme)
 	_ = infrastructurev1beta2.AddToScheme(fakeScheme)
+	_ = ipamv1.AddToScheme(fakeScheme)
 	client := fake.NewClientBuilder().WithScheme(fakeScheme).
 		WithStatusSubresource(om).WithObjects(c, oc, m, om).Build()
 	mockCtrl := gomock.NewController(t)
@@ -69,6 +75,11 @@ func runMachineTest(t *testing.T, tc testcase) {
 		for _, fn := range step.mockFuncs {
 			fn(cs)
 		}
+		cs.expectSecurityGroupRules()
+		for _, obj := range step.kubeObjects {
+			err := client.Create(context.TODO(), obj)
+			require.NoError(t, err)
+		}
 		res, err := rec.Reconcile(context.TODO(), controllerruntime.Request{NamespacedName: nsn})
 		if step.hasError {
 			require.Error(t, err)
@@ -88,6 +99,9 @@ func runMachineTest(t *testing.T, tc testcase) {
 				fn(t, &out)
 			}
 		}
+		for _, fn := range step.kubeAsserts {
+			fn(t, client)
+		}
 		step = step.next
 	}
 }
@@ -187,6 +201,32 @@ func TestReconcileOSCMachine_Create(t *testing.T) {
 			requeue: true,
 		},
 
+		{
+			name:        "A running worker is linked to the additional loadBalancers of its role",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			clusterPatches: []patchOSCClusterFunc{
+				patchAdditionalLoadBalancers(infrastructurev1beta2.OscAdditionalLoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s-ingress",
+					BackendRole:      infrastructurev1beta2.RoleWorker,
+					Listeners:        []infrastructurev1beta2.OscLoadBalancerListener{{LoadBalancerPort: 443, BackendPort: 30443}},
+				}, infrastructurev1beta2.OscAdditionalLoadBalancer{
+					LoadBalancerName: "test-cluster-api-k8s-public",
+					BackendRole:      infrastructurev1beta2.RoleControlPlane,
+					Listeners:        []infrastructurev1beta2.OscLoadBalancerListener{{LoadBalancerPort: 6443}},
+				}),
+			},
+			machinePatches: []patchOSCMachineFunc{patchVmExists("i-foo", osc.VmStatePending, false)},
+			mockFuncs: []mockFunc{
+				mockGetVm("i-foo", "running", false),
+				mockLoadBalancerFound("test-cluster-api-k8s-ingress", "test-cluster-api-k8s-ingress-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLinkLoadBalancer("i-foo", "test-cluster-api-k8s-ingress"),
+				mockVmSetCCMTag("i-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			machineAsserts: []assertOSCMachineFunc{
+				assertVmExists("i-foo", osc.VmStateRunning, true),
+			},
+		},
+
 		// Control plane node
 		{
 			name:        "Creating a controlplane with base parameters, vm is running & LB is ok",
@@ -238,6 +278,133 @@ func TestReconcileOSCMachine_Create(t *testing.T) {
 			},
 		},
 
+		// IPAM
+		{
+			name:        "Creating a controlplane with an IPAM pool claims an IP address",
+			clusterSpec: "ready-0.4", machineSpec: "base-controlplane",
+			machinePatches: []patchOSCMachineFunc{patchPrivateIpPool("InClusterIPPool", "controlplane")},
+			mockFuncs: []mockFunc{
+				mockGetVmFromClientToken("uster-api-test-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.VmResourceType, "cluster-api-test-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			kubeAsserts: []assertKubeFunc{
+				assertIPAddressClaim("cluster-api-test-controlplane", "InClusterIPPool", "controlplane"),
+			},
+		},
+		{
+			name:        "Creating a controlplane with a bound IP address claim uses the claimed IP",
+			clusterSpec: "ready-0.4", machineSpec: "base-controlplane",
+			machinePatches: []patchOSCMachineFunc{patchPrivateIpPool("InClusterIPPool", "controlplane")},
+			kubeObjects:    newBoundIPAddressClaim("cluster-api-test-controlplane", "10.0.4.10"),
+			mockFuncs: []mockFunc{
+				mockImageFoundByName("ubuntu-2204-kubernetes-v1.32.13-2026-03-06", "01234", "ami-foo"),
+				mockGetVmFromClientToken("uster-api-test-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.VmResourceType, "cluster-api-test-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockCreateVmNoVolumes("i-foo", "ami-foo", "subnet-c1a282b0", []string{"sg-750ae810", "sg-0cd1f87e"}, []string{"10.0.4.10"}, "cluster-api-test-controlplane", "uster-api-test-controlplane-9e1db9c4-bf0a-4583-8999-203ec002c520", map[string]string{}),
+			},
+			requeue: true,
+			machineAsserts: []assertOSCMachineFunc{
+				assertVmExists("i-foo", osc.VmStatePending, false),
+			},
+		},
+
+		// Adoption
+		{
+			name:        "Adopting an existing controlplane vm",
+			clusterSpec: "ready-0.4", machineSpec: "base-controlplane",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetUnmanagedVm("i-foo", "vpc-24ba90ce", "1.2.3.4"),
+				mockVmSetOwnedTag("i-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLoadBalancerFound("test-cluster-api-k8s", "test-cluster-api-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockLinkLoadBalancer("i-foo", "test-cluster-api-k8s"),
+				mockVmSetCCMTag("i-foo", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+			},
+			machineAsserts: []assertOSCMachineFunc{
+				assertHasMachineFinalizer(),
+				assertVmExists("i-foo", osc.VmStateRunning, true),
+				assertProviderID("aws:///eu-west-2a/i-foo"),
+				assertStatusMachineResources(infrastructurev1beta2.OscMachineResources{
+					Vm: map[string]string{
+						"default": "i-foo",
+					},
+					Volumes: map[string]string{
+						"/dev/sda1": "vol-foo",
+					},
+				}),
+			},
+		},
+		{
+			name:        "A vm owned by another cluster cannot be adopted",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetUnmanagedVm("i-foo", "vpc-24ba90ce", "", osc.ResourceTag{Key: "OscK8sClusterID/other", Value: "owned"}),
+			},
+			hasError: true,
+		},
+		{
+			name:        "A vm referenced by the providerID of another machine cannot be adopted",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			kubeObjects: []client.Object{otherMachine("other", "aws:///eu-west-2a/i-foo", "")},
+			mockFuncs: []mockFunc{
+				mockGetUnmanagedVm("i-foo", "vpc-24ba90ce", ""),
+			},
+			hasError: true,
+		},
+		{
+			name:        "A vm tracked by another machine cannot be adopted",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			kubeObjects: []client.Object{otherMachine("other", "", "i-foo")},
+			mockFuncs: []mockFunc{
+				mockGetUnmanagedVm("i-foo", "vpc-24ba90ce", ""),
+			},
+			hasError: true,
+		},
+		{
+			name:        "A vm outside of the cluster net cannot be adopted",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetUnmanagedVm("i-foo", "vpc-foo", ""),
+			},
+			hasError: true,
+		},
+		{
+			name:        "A missing vm cannot be adopted, and no vm is created",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVmNotFound("i-foo"),
+			},
+			hasError: true,
+		},
+		{
+			name:        "A terminated vm cannot be adopted, and no vm is created",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchAdoptVm("i-foo"),
+			},
+			mockFuncs: []mockFunc{
+				mockGetVm("i-foo", osc.VmStateTerminated, false),
+			},
+			hasError: true,
+		},
+
 		// Volumes
 		{
 			name:        "Creating a vm with additional volumes",
@@ -325,6 +492,107 @@ func TestReconcileOSCMachine_Create(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Creating a vm with a dynamic public IP adopts the IP of an interrupted creation",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchUsePublicIP(),
+				patchMachineCreating("public-ip/default", "ipalloc-other"),
+			},
+			mockFuncs: []mockFunc{
+				mockImageFoundByName("ubuntu-2204-kubernetes-v1.32.13-2026-03-06", "01234", "ami-foo"),
+				mockGetVmFromClientToken("cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.VmResourceType, "cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockListUnlinkedPublicIpIds("cluster-api-test-worker", "9e1db9c4-bf0a-4583-8999-203ec002c520", []string{"ipalloc-other", "ipalloc-worker"}),
+				mockTagCreated("ipalloc-worker", "cluster-api-test-worker", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockPublicIpFound("ipalloc-worker", &osc.PublicIp{PublicIpId: "ipalloc-worker", PublicIp: "1.2.3.4"}),
+				mockCreateVmNoVolumes("i-foo", "ami-foo", "subnet-1555ea91", []string{"sg-a093d014", "sg-0cd1f87e"}, []string{}, "cluster-api-test-worker", "cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", map[string]string{
+					compute.AutoAttachExternalIPTag: "1.2.3.4",
+					compute.RepulseServerTag:        "test-cluster-api-md-0",
+				}),
+			},
+			requeue: true,
+			machineAsserts: []assertOSCMachineFunc{
+				assertHasMachineFinalizer(),
+				assertVmExists("i-foo", osc.VmStatePending, false),
+				assertStatusMachineResources(infrastructurev1beta2.OscMachineResources{
+					Image: map[string]string{
+						"default": "ami-foo",
+					},
+					Vm: map[string]string{
+						"default": "i-foo",
+					},
+					PublicIPs: map[string]string{
+						"default": "ipalloc-worker",
+					},
+					Volumes: map[string]string{
+						"/dev/sda1": "vol-foo",
+					},
+				}),
+			},
+		},
+		{
+			name:        "Creating a vm with a dynamic public IP records the IP when tagging fails",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchUsePublicIP(),
+			},
+			mockFuncs: []mockFunc{
+				mockImageFoundByName("ubuntu-2204-kubernetes-v1.32.13-2026-03-06", "01234", "ami-foo"),
+				mockGetVmFromClientToken("cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.VmResourceType, "cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockCreatePublicIpTagFails("cluster-api-test-worker", "9e1db9c4-bf0a-4583-8999-203ec002c520", "ipalloc-worker", "1.2.3.4"),
+			},
+			hasError: true,
+			machineAsserts: []assertOSCMachineFunc{
+				assertStatusMachineResources(infrastructurev1beta2.OscMachineResources{
+					Image: map[string]string{
+						"default": "ami-foo",
+					},
+					Creating: map[string]string{
+						"public-ip/default": "created:ipalloc-worker",
+					},
+				}),
+			},
+		},
+		{
+			name:        "Creating a vm with a dynamic public IP adopts the recorded IP of a failed creation",
+			clusterSpec: "ready-0.4", machineSpec: "base-worker",
+			machinePatches: []patchOSCMachineFunc{
+				patchUsePublicIP(),
+				patchMachineCreating("public-ip/default", "created:ipalloc-worker"),
+			},
+			mockFuncs: []mockFunc{
+				mockImageFoundByName("ubuntu-2204-kubernetes-v1.32.13-2026-03-06", "01234", "ami-foo"),
+				mockGetVmFromClientToken("cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", nil),
+				mockReadTagByNameNoneFound(tag.VmResourceType, "cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockTagCreated("ipalloc-worker", "cluster-api-test-worker", "9e1db9c4-bf0a-4583-8999-203ec002c520"),
+				mockPublicIpFound("ipalloc-worker", &osc.PublicIp{PublicIpId: "ipalloc-worker", PublicIp: "1.2.3.4"}),
+				mockCreateVmNoVolumes("i-foo", "ami-foo", "subnet-1555ea91", []string{"sg-a093d014", "sg-0cd1f87e"}, []string{}, "cluster-api-test-worker", "cluster-api-test-worker-9e1db9c4-bf0a-4583-8999-203ec002c520", map[string]string{
+					compute.AutoAttachExternalIPTag: "1.2.3.4",
+					compute.RepulseServerTag:        "test-cluster-api-md-0",
+				}),
+			},
+			requeue: true,
+			machineAsserts: []assertOSCMachineFunc{
+				assertHasMachineFinalizer(),
+				assertVmExists("i-foo", osc.VmStatePending, false),
+				assertStatusMachineResources(infrastructurev1beta2.OscMachineResources{
+					Image: map[string]string{
+						"default": "ami-foo",
+					},
+					Vm: map[string]string{
+						"default": "i-foo",
+					},
+					PublicIPs: map[string]string{
+						"default": "ipalloc-worker",
+					},
+					Volumes: map[string]string{
+						"/dev/sda1": "vol-foo",
+					},
+				}),
+			},
+		},
 		{
 			name:        "Creating a vm with a public IP from a pool",
 			clusterSpec: "ready-0.4", machineSpec: "base-worker",
@@ -635,6 +903,41 @@ func TestReconcileOSCMachine_Delete(t *testing.T) {
 			},
 			assertDeleted: true,
 		},
+		{
+			name:        "deleting a 1.0 machine with an IPAM pool releases the IP address claim once the vm is terminated",
+			clusterSpec: "ready-0.4", machineSpec: "ready-worker-1.0",
+			machineBaseSpec: "ready-worker",
+			machinePatches:  []patchOSCMachineFunc{patchPrivateIpPool("InClusterIPPool", "workers"), patchDeleteMachine()},
+			kubeObjects:     newBoundIPAddressClaim("test-cluster-api-md-0-6p8qk-qgvhr", "10.0.3.10"),
+			mockFuncs: []mockFunc{
+				mockGetVm("i-046f4bd0", "running", true),
+				mockDeleteVm("i-046f4bd0"),
+			},
+			requeue: true,
+			kubeAsserts: []assertKubeFunc{
+				assertIPAddressClaimExists("test-cluster-api-md-0-6p8qk-qgvhr"),
+			},
+			next: &testcase{
+				name: "the vm is shutting down",
+				mockFuncs: []mockFunc{
+					mockGetVm("i-046f4bd0", "shutting-down", true),
+				},
+				requeue: true,
+				kubeAsserts: []assertKubeFunc{
+					assertIPAddressClaimExists("test-cluster-api-md-0-6p8qk-qgvhr"),
+				},
+				next: &testcase{
+					name: "the vm is terminated",
+					mockFuncs: []mockFunc{
+						mockGetVm("i-046f4bd0", "terminated", true),
+					},
+					kubeAsserts: []assertKubeFunc{
+						assertNoIPAddressClaim("test-cluster-api-md-0-6p8qk-qgvhr"),
+					},
+					assertDeleted: true,
+				},
+			},
+		},
 		{
 			name:        "deleting a 1.0 machine with a public ip",
 			clusterSpec: "ready-0.4", machineSpec: "ready-worker-1.0",
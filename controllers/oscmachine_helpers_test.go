@@ -6,16 +6,22 @@ SPDX-License-Identifier: BSD-3-Clause
 package controllers_test
 
 import (
+	"context"
 	"testing"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/cluster-api-provider-outscale/controllers"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -65,6 +71,32 @@ func patchPublicIPStatus(publicIpId string) patchOSCMachineFunc {
 	}
 }
 
+func patchMachineCreating(key, existing string) patchOSCMachineFunc {
+	return func(m *infrastructurev1beta2.OscMachine) {
+		m.Status.Resources.Creating = map[string]string{key: existing}
+	}
+}
+
+// otherMachine returns an OscMachine using a vm, through its providerID or its status.
+func otherMachine(name, providerID, vmId string) *infrastructurev1beta2.OscMachine {
+	m := &infrastructurev1beta2.OscMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+	}
+	if providerID != "" {
+		m.Spec.ProviderID = &providerID
+	}
+	if vmId != "" {
+		m.Status.Resources.Vm = map[string]string{"default": vmId}
+	}
+	return m
+}
+
+func patchAdoptVm(vmId string) patchOSCMachineFunc {
+	return func(m *infrastructurev1beta2.OscMachine) {
+		m.Spec.Node.Vm.AdoptVmId = vmId
+	}
+}
+
 func patchDeleteMachine() patchOSCMachineFunc {
 	return func(m *infrastructurev1beta2.OscMachine) {
 		m.DeletionTimestamp = new(metav1.Now())
@@ -74,6 +106,35 @@ func patchDeleteMachine() patchOSCMachineFunc {
 	}
 }
 
+func patchPrivateIpPool(kind, name string) patchOSCMachineFunc {
+	return func(m *infrastructurev1beta2.OscMachine) {
+		m.Spec.Node.Vm.PrivateIpPoolRef = &corev1.TypedLocalObjectReference{
+			APIGroup: new("ipam.cluster.x-k8s.io"),
+			Kind:     kind,
+			Name:     name,
+		}
+	}
+}
+
+func newBoundIPAddressClaim(name, address string) []client.Object {
+	return []client.Object{
+		&ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-api-test", Name: name},
+			Status: ipamv1.IPAddressClaimStatus{
+				AddressRef: corev1.LocalObjectReference{Name: name},
+			},
+		},
+		&ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-api-test", Name: name},
+			Spec: ipamv1.IPAddressSpec{
+				ClaimRef: corev1.LocalObjectReference{Name: name},
+				Address:  address,
+				Prefix:   24,
+			},
+		},
+	}
+}
+
 func patchUseOpenSourceOMI() patchOSCMachineFunc {
 	return func(m *infrastructurev1beta2.OscMachine) {
 		m.Spec.Node.Image.OutscaleOpenSource = true
@@ -132,6 +193,37 @@ func mockGetVm(vmId string, state osc.VmState, ccmtags bool) mockFunc {
 	}
 }
 
+func mockGetVmNotFound(vmId string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.ComputeMock.
+			EXPECT().
+			GetVm(gomock.Any(), gomock.Eq(vmId)).
+			Return(nil, nil)
+	}
+}
+
+func mockGetUnmanagedVm(vmId, netId, publicIp string, tags ...osc.ResourceTag) mockFunc {
+	vm := &osc.Vm{
+		VmId:                vmId,
+		NetId:               &netId,
+		PrivateDnsName:      new(defaultPrivateDnsName),
+		PrivateIp:           defaultPrivateIp,
+		State:               osc.VmStateRunning,
+		BlockDeviceMappings: defaultVolumes,
+		Placement:           osc.Placement{SubregionName: "eu-west-2a"},
+		Tags:                tags,
+	}
+	if publicIp != "" {
+		vm.PublicIp = &publicIp
+	}
+	return func(s *MockCloudServices) {
+		s.ComputeMock.
+			EXPECT().
+			GetVm(gomock.Any(), gomock.Eq(vmId)).
+			Return(vm, nil)
+	}
+}
+
 func mockGetVmFromClientToken(token string, vm *osc.Vm) mockFunc {
 	if vm != nil {
 		vm.PrivateDnsName = new(defaultPrivateDnsName)
@@ -210,6 +302,17 @@ func mockVmSetCCMTag(vmId, clusterID string) mockFunc {
 	}
 }
 
+func mockVmSetOwnedTag(vmId, clusterID string) mockFunc {
+	return func(s *MockCloudServices) {
+		s.TagMock.EXPECT().
+			AddTag(gomock.Any(), gomock.Eq(osc.CreateTagsRequest{
+				ResourceIds: []string{vmId},
+				Tags:        []osc.ResourceTag{{Key: tag.ClusterKeyPrefix + clusterID, Value: tag.OwnedValue}},
+			}), gomock.Eq([]string{vmId})).
+			Return(nil)
+	}
+}
+
 func assertVmExists(vmId string, state osc.VmState, ready bool) assertOSCMachineFunc {
 	return func(t *testing.T, m *infrastructurev1beta2.OscMachine) {
 		t.Helper()
@@ -221,6 +324,14 @@ func assertVmExists(vmId string, state osc.VmState, ready bool) assertOSCMachine
 	}
 }
 
+func assertProviderID(providerID string) assertOSCMachineFunc {
+	return func(t *testing.T, m *infrastructurev1beta2.OscMachine) {
+		t.Helper()
+		require.NotNil(t, m.Spec.ProviderID)
+		assert.Equal(t, providerID, *m.Spec.ProviderID)
+	}
+}
+
 func assertHasMachineFinalizer() assertOSCMachineFunc {
 	return func(t *testing.T, m *infrastructurev1beta2.OscMachine) {
 		t.Helper()
@@ -247,3 +358,35 @@ func assertVolumesAreConfigured(deviceAndVolume ...string) assertOSCMachineFunc
 		assert.Equal(t, expect, m.Status.Resources.Volumes)
 	}
 }
+
+func assertIPAddressClaim(name, kind, pool string) assertKubeFunc {
+	return func(t *testing.T, c client.Client) {
+		t.Helper()
+		var claim ipamv1.IPAddressClaim
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: "cluster-api-test", Name: name}, &claim)
+		require.NoError(t, err)
+		assert.Equal(t, kind, claim.Spec.PoolRef.Kind)
+		assert.Equal(t, pool, claim.Spec.PoolRef.Name)
+		assert.Equal(t, "test-cluster-api", claim.Spec.ClusterName)
+		require.Len(t, claim.OwnerReferences, 1)
+		assert.Equal(t, "OscMachine", claim.OwnerReferences[0].Kind)
+	}
+}
+
+func assertIPAddressClaimExists(name string) assertKubeFunc {
+	return func(t *testing.T, c client.Client) {
+		t.Helper()
+		var claim ipamv1.IPAddressClaim
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: "cluster-api-test", Name: name}, &claim)
+		require.NoError(t, err, "claim must not have been deleted")
+	}
+}
+
+func assertNoIPAddressClaim(name string) assertKubeFunc {
+	return func(t *testing.T, c client.Client) {
+		t.Helper()
+		var claim ipamv1.IPAddressClaim
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: "cluster-api-test", Name: name}, &claim)
+		assert.True(t, apierrors.IsNotFound(err), "claim must have been deleted")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileIPAddressClaim claims a private IP from the IPAM pool of the VM.
+// The claim has the name of the OscMachine, and is owned by it.
+// An empty address is returned until the IPAM provider has bound the claim to an address.
+func (r *OscMachineReconciler) reconcileIPAddressClaim(ctx context.Context, machineScope *scope.MachineScope) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
+	poolRef := machineScope.GetVm().PrivateIpPoolRef
+	var claim ipamv1.IPAddressClaim
+	key := client.ObjectKey{Namespace: machineScope.GetNamespace(), Name: machineScope.GetName()}
+	err := r.Client.Get(ctx, key, &claim)
+	switch {
+	case apierrors.IsNotFound(err):
+		claim = ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: machineScope.Machine.Spec.ClusterName,
+				},
+			},
+			Spec: ipamv1.IPAddressClaimSpec{
+				ClusterName: machineScope.Machine.Spec.ClusterName,
+				PoolRef:     *poolRef,
+			},
+		}
+		if err := controllerutil.SetControllerReference(machineScope.OscMachine, &claim, r.Client.Scheme()); err != nil {
+			return "", fmt.Errorf("set owner of ip address claim: %w", err)
+		}
+		if err := r.Client.Create(ctx, &claim); err != nil {
+			return "", fmt.Errorf("create ip address claim: %w", err)
+		}
+		log.V(2).Info("IP address claim created", "claim", claim.Name, "pool", poolRef.Name)
+		r.Recorder.Eventf(machineScope.OscMachine, corev1.EventTypeNormal, infrastructurev1beta2.IPAddressClaimedReason,
+			"IP address claimed from %s %s", poolRef.Kind, poolRef.Name)
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("get ip address claim: %w", err)
+	}
+	if claim.Status.AddressRef.Name == "" {
+		log.V(3).Info("IP address claim is not bound yet", "claim", claim.Name)
+		return "", nil
+	}
+	var addr ipamv1.IPAddress
+	err = r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}, &addr)
+	if err != nil {
+		return "", fmt.Errorf("get ip address: %w", err)
+	}
+	log.V(4).Info("Found claimed IP address", "claim", claim.Name, "address", addr.Spec.Address)
+	return addr.Spec.Address, nil
+}
+
+// reconcileDeleteIPAddressClaim releases the private IP claimed from the IPAM pool of the VM.
+func (r *OscMachineReconciler) reconcileDeleteIPAddressClaim(ctx context.Context, machineScope *scope.MachineScope) error {
+	log := ctrl.LoggerFrom(ctx)
+	claim := ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: machineScope.GetNamespace(),
+			Name:      machineScope.GetName(),
+		},
+	}
+	err := r.Client.Delete(ctx, &claim)
+	switch {
+	case apierrors.IsNotFound(err):
+		log.V(4).Info("IP address claim is already deleted")
+		return nil
+	case err != nil:
+		return fmt.Errorf("delete ip address claim: %w", err)
+	}
+	log.V(2).Info("IP address claim deleted", "claim", claim.Name)
+	return nil
+}
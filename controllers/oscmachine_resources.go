@@ -35,13 +35,9 @@ func (t *MachineResourceTracker) getVm(ctx context.Context, machineScope *scope.
 		return nil, err
 	case vm != nil:
 		t.trackVm(machineScope, vm)
-		if vm.PublicIp != nil {
-			err := t.IPAllocator(machineScope).RetrackIP(ctx, defaultResource, *vm.PublicIp, clusterScope)
-			return vm, err
-		}
-		return vm, nil
+		return vm, t.retrackIP(ctx, machineScope, clusterScope, vm)
 	}
-	vm, err = t.Cloud.Compute(clusterScope.Tenant).GetVm(ctx, id)
+	vm, err = t.Cloud.Cache().GetVm(ctx, clusterScope.Tenant, t.Cloud.Compute(clusterScope.Tenant), clusterScope.GetUID(), id)
 	switch {
 	case err != nil:
 		return nil, err
@@ -49,11 +45,7 @@ func (t *MachineResourceTracker) getVm(ctx context.Context, machineScope *scope.
 		return nil, fmt.Errorf("get vm %s: %w", id, ErrMissingResource)
 	default:
 		t.trackVm(machineScope, vm)
-		if vm.PublicIp != nil {
-			err := t.IPAllocator(machineScope).RetrackIP(ctx, defaultResource, *vm.PublicIp, clusterScope)
-			return vm, err
-		}
-		return vm, nil
+		return vm, t.retrackIP(ctx, machineScope, clusterScope, vm)
 	}
 }
 
@@ -69,8 +61,12 @@ func (t *MachineResourceTracker) _getVmOrId(ctx context.Context, machineScope *s
 	if id != "" {
 		return nil, id, nil
 	}
+	id = machineScope.GetVm().AdoptVmId
+	if id != "" {
+		return nil, id, nil
+	}
 	clientToken := machineScope.GetClientToken(clusterScope)
-	vm, err := t.Cloud.Compute(clusterScope.Tenant).GetVmFromClientToken(ctx, clientToken)
+	vm, err := t.Cloud.Cache().GetVmFromClientToken(ctx, clusterScope.Tenant, t.Cloud.Compute(clusterScope.Tenant), clusterScope.GetUID(), clientToken)
 	switch {
 	case err != nil:
 		return nil, "", fmt.Errorf("get vm from client token: %w", err)
@@ -94,6 +90,14 @@ func (t *MachineResourceTracker) trackVm(machineScope *scope.MachineScope, vm *o
 	t.setVolumeIds(machineScope, vm.BlockDeviceMappings)
 }
 
+// retrackIP retracks the public IP of a vm. The public IP of an adopted vm is not owned by the machine, and is not tracked.
+func (t *MachineResourceTracker) retrackIP(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, vm *osc.Vm) error {
+	if vm.PublicIp == nil || machineScope.GetVm().AdoptVmId == vm.VmId {
+		return nil
+	}
+	return t.IPAllocator(machineScope).RetrackIP(ctx, defaultResource, *vm.PublicIp, clusterScope)
+}
+
 func (t *MachineResourceTracker) setVmId(machineScope *scope.MachineScope, id string) {
 	rsrc := machineScope.GetResources()
 	if rsrc.Vm == nil {
@@ -171,6 +175,7 @@ func (t *MachineResourceTracker) IPAllocator(machineScope *scope.MachineScope) I
 			}
 			rsrc.PublicIPs[key] = id
 		},
+		creations: machineScope,
 	}
 }
 
@@ -11,11 +11,13 @@ import (
 	"fmt"
 	"maps"
 	"slices"
-	"time"
+	"strings"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/compute"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/k8s/tags"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,12 +43,39 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 		}
 	}
 	vm, err := r.Tracker.getVm(ctx, machineScope, clusterScope)
+	// An adopted vm is never replaced by a new one.
+	if vmSpec.AdoptVmId != "" {
+		switch {
+		case IsNotFound(err):
+			return reconcile.Result{}, fmt.Errorf("cannot adopt vm %s: %w", vmSpec.AdoptVmId, err)
+		case err == nil && (vm.State == osc.VmStateTerminated || vm.State == osc.VmStateShuttingDown):
+			return reconcile.Result{}, fmt.Errorf("cannot adopt vm %s: vm is %s", vm.VmId, vm.State)
+		}
+	}
 	switch {
 	case err == nil:
 		machineScope.SetVmState(vm.State)
+		if vmSpec.AdoptVmId == vm.VmId && !tags.Has(vm.Tags, tags.ClusterIDKey(clusterScope.GetUID()), tag.OwnedValue) {
+			err := r.adoptVm(ctx, clusterScope, machineScope, vm)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("cannot adopt vm: %w", err)
+			}
+		}
 	case !IsNotFound(err):
 		return reconcile.Result{}, fmt.Errorf("cannot get VM: %w", err)
 	default:
+		var claimedIp string
+		if vmSpec.PrivateIpPoolRef != nil {
+			claimedIp, err = r.reconcileIPAddressClaim(ctx, machineScope)
+			switch {
+			case err != nil:
+				return reconcile.Result{}, err
+			case claimedIp == "":
+				// The machine is reconciled again when the claim is updated.
+				log.V(3).Info("Waiting for the private IP address to be allocated")
+				return reconcile.Result{}, nil
+			}
+		}
 		// Check if a machine needs to be placed in a subregion.
 		subnetName := vmSpec.SubnetName
 		var subregionName string
@@ -88,7 +117,12 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 				infrastructurev1beta2.FGPUAllocatedReason, "%s (%s) allocated", fgpu.FlexibleGpuId, vmSpec.FGPU.Model)
 		}
 
-		subnetSpec, err := clusterScope.GetSubnet(subnetName, vmSpec.GetRole(), subregionName)
+		var subnetSpec infrastructurev1beta2.OscSubnet
+		if vmSpec.SubnetPool != "" {
+			subnetSpec, err = clusterScope.GetPoolSubnet(vmSpec.SubnetPool, vmSpec.GetRole(), subregionName)
+		} else {
+			subnetSpec, err = clusterScope.GetSubnet(subnetName, vmSpec.GetRole(), subregionName)
+		}
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("reconcile vm: %w", err)
 		}
@@ -115,6 +149,9 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 			privateIp := vmPrivateIp.PrivateIp
 			privateIps = append(privateIps, privateIp)
 		}
+		if claimedIp != "" {
+			privateIps = append(privateIps, claimedIp)
+		}
 		imageId, err := r.Tracker.getImageId(ctx, machineScope, clusterScope)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -195,12 +232,12 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 		default:
 			log.V(4).Info("Waiting for VM state", "vmId", vm.VmId, "state", vm.State)
 		}
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, fgpu)}, nil
 	case fgpu.State == osc.FlexibleGpuStateAttached:
 		log.V(4).Info("fGPU is attached", "vmId", vm.VmId, "fGPUId", fgpu.FlexibleGpuId)
 	default:
 		log.V(4).Info("Waiting for fGPU state", "vmId", vm.VmId, "fGPUId", fgpu.FlexibleGpuId, "state", fgpu.State)
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, fgpu)}, nil
 	}
 
 	switch vm.State {
@@ -210,25 +247,25 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("start vm: %w", err)
 		}
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, fgpu)}, nil
 	case osc.VmStateRunning:
 		log.V(4).Info("VM is running", "vmId", vm.VmId)
 	default:
 		log.V(4).Info("VM is not yet running", "vmId", vm.VmId, "state", vm.State)
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, fgpu)}, nil
 	}
 
 	machineScope.SetReady()
 
-	if vmSpec.GetRole() == infrastructurev1beta2.RoleControlPlane && !clusterScope.IsLBDisabled() {
+	// Control planes are backends of the API loadBalancer, and VMs of any role may be backends of additional loadBalancers.
+	for _, loadBalancerName := range clusterScope.GetBackendLoadBalancerNames(vmSpec.GetRole()) {
 		svc := r.Cloud.Net(clusterScope.Tenant)
-		loadBalancerName := clusterScope.GetLoadBalancer().LoadBalancerName
 		loadbalancer, err := svc.GetLoadBalancer(ctx, loadBalancerName)
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("cannot get loadbalancer: %w", err)
 		}
 		if loadbalancer == nil {
-			return reconcile.Result{}, errors.New("no loadbalancer found")
+			return reconcile.Result{}, fmt.Errorf("no loadbalancer %s found", loadBalancerName)
 		}
 		if !slices.Contains(loadbalancer.BackendVmIds, vm.VmId) {
 			log.V(2).Info("Linking loadbalancer", "loadBalancerName", loadBalancerName)
@@ -271,6 +308,52 @@ func (r *OscMachineReconciler) reconcileVm(ctx context.Context, clusterScope *sc
 	return reconcile.Result{}, nil
 }
 
+// adoptVm checks that an existing vm is in the cluster net and is not used by another cluster or machine, and tags it as owned by the cluster.
+// CCM tags are added once the vm is running.
+func (r *OscMachineReconciler) adoptVm(ctx context.Context, clusterScope *scope.ClusterScope, machineScope *scope.MachineScope, vm *osc.Vm) error {
+	log := ctrl.LoggerFrom(ctx)
+	netId, err := r.ClusterTracker.getNetId(ctx, clusterScope)
+	if err != nil {
+		return err
+	}
+	if vm.NetId == nil || *vm.NetId != netId {
+		return fmt.Errorf("vm %s is not in net %s", vm.VmId, netId)
+	}
+	for _, t := range vm.Tags {
+		if strings.HasPrefix(t.Key, tags.ClusterIDKey("")) && t.Value == tag.OwnedValue {
+			return fmt.Errorf("vm %s is owned by another cluster (%s)", vm.VmId, t.Key)
+		}
+	}
+	var machines infrastructurev1beta2.OscMachineList
+	if err := r.Client.List(ctx, &machines); err != nil {
+		return fmt.Errorf("cannot list machines: %w", err)
+	}
+	for _, m := range machines.Items {
+		if m.Namespace == machineScope.OscMachine.Namespace && m.Name == machineScope.OscMachine.Name {
+			continue
+		}
+		if getResource(defaultResource, m.Status.Resources.Vm) == vm.VmId ||
+			(m.Spec.ProviderID != nil && strings.HasSuffix(*m.Spec.ProviderID, "/"+vm.VmId)) {
+			return fmt.Errorf("vm %s is already used by OscMachine %s/%s", vm.VmId, m.Namespace, m.Name)
+		}
+	}
+	log.V(2).Info("Adopting VM", "vmId", vm.VmId)
+	ids := []string{vm.VmId}
+	err = r.Cloud.Tag(clusterScope.Tenant).AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: ids,
+		Tags:        []osc.ResourceTag{{Key: tags.ClusterIDKey(clusterScope.GetUID()), Value: tag.OwnedValue}},
+	}, ids)
+	if err != nil {
+		return fmt.Errorf("cannot add owned tag: %w", err)
+	}
+	if machineScope.GetProviderID() == "" {
+		machineScope.SetProviderID(vm.Placement.SubregionName, vm.VmId)
+	}
+	r.Recorder.Eventf(machineScope.OscMachine, corev1.EventTypeNormal,
+		infrastructurev1beta2.VmAdoptedReason, "VM %s adopted", vm.VmId)
+	return nil
+}
+
 // reconcileDeleteVm reconcile the destruction of the vm of the machine
 func (r *OscMachineReconciler) reconcileDeleteVm(ctx context.Context, clusterScope *scope.ClusterScope, machineScope *scope.MachineScope) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -285,12 +368,14 @@ func (r *OscMachineReconciler) reconcileDeleteVm(ctx context.Context, clusterSco
 	case vm.State == "terminated":
 		log.V(4).Info("VM is already deleted")
 		return reconcile.Result{}, nil
+	case vm.State == osc.VmStateShuttingDown:
+		log.V(4).Info("VM is being deleted")
+		return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, nil)}, nil
 	}
 
 	vmSpec := machineScope.GetVm()
-	if vmSpec.GetRole() == infrastructurev1beta2.RoleControlPlane && !clusterScope.IsLBDisabled() {
+	for _, loadBalancerName := range clusterScope.GetBackendLoadBalancerNames(vmSpec.GetRole()) {
 		svc := r.Cloud.Net(clusterScope.Tenant)
-		loadBalancerName := clusterScope.GetLoadBalancer().LoadBalancerName
 		loadbalancer, err := svc.GetLoadBalancer(ctx, loadBalancerName)
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("cannot get loadbalancer: %w", err)
@@ -309,7 +394,8 @@ func (r *OscMachineReconciler) reconcileDeleteVm(ctx context.Context, clusterSco
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot delete vm: %w", err)
 	}
-	return reconcile.Result{}, nil
+	vm.State = osc.VmStateShuttingDown
+	return reconcile.Result{RequeueAfter: r.VmPoller.Watch(clusterScope.Tenant, machineScope.OscMachine, vm, nil)}, nil
 }
 
 /*
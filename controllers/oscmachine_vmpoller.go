@@ -0,0 +1,201 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
+	"github.com/outscale/cluster-api-provider-outscale/util/reconciler"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// defaultVmRequeue is the requeue delay of a vm in a transitional state, when no poller is configured.
+const defaultVmRequeue = 30 * time.Second
+
+// VmStatePoller polls the state of vms in a transitional state, and triggers the reconciliation of their OscMachine when their state changes.
+// All vms and fGPUs of a tenant are read in a single call, on an interval starting at MinInterval and doubling up to MaxInterval.
+type VmStatePoller struct {
+	Cloud       services.Servicer
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	events chan event.GenericEvent
+
+	mu      sync.Mutex
+	tenants map[string]*polledTenant
+}
+
+type polledTenant struct {
+	tenant   tenant.Tenant
+	vms      map[string]*polledVm
+	interval time.Duration
+	next     time.Time
+}
+
+type polledVm struct {
+	machine   *infrastructurev1beta2.OscMachine
+	state     osc.VmState
+	fgpuId    string
+	fgpuState osc.FlexibleGpuState
+}
+
+// NewVmStatePoller creates a VmStatePoller.
+func NewVmStatePoller(cloud services.Servicer, minInterval, maxInterval time.Duration) *VmStatePoller {
+	if minInterval <= 0 {
+		minInterval = reconciler.DefaultVmPollMinInterval
+	}
+	return &VmStatePoller{
+		Cloud:       cloud,
+		MinInterval: minInterval,
+		MaxInterval: max(minInterval, maxInterval),
+		events:      make(chan event.GenericEvent, 100),
+		tenants:     map[string]*polledTenant{},
+	}
+}
+
+// Events returns the channel receiving the OscMachines to reconcile.
+func (p *VmStatePoller) Events() <-chan event.GenericEvent {
+	return p.events
+}
+
+// Watch polls the state of a vm and of its fGPU, until one of them changes.
+// It returns the delay after which the OscMachine should be requeued, if no change was detected.
+func (p *VmStatePoller) Watch(t tenant.Tenant, machine *infrastructurev1beta2.OscMachine, vm *osc.Vm, fgpu *osc.FlexibleGpu) time.Duration {
+	if p == nil {
+		return defaultVmRequeue
+	}
+	pvm := &polledVm{
+		machine: &infrastructurev1beta2.OscMachine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: machine.Namespace, Name: machine.Name},
+		},
+		state: vm.State,
+	}
+	if fgpu != nil {
+		pvm.fgpuId, pvm.fgpuState = fgpu.FlexibleGpuId, fgpu.State
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := tenant.Key(t)
+	pt, found := p.tenants[key]
+	if !found {
+		pt = &polledTenant{vms: map[string]*polledVm{}}
+		p.tenants[key] = pt
+	}
+	// The latest tenant is kept, in case credentials were changed.
+	pt.tenant = t
+	pt.vms[vm.VmId] = pvm
+	// A new transition starts: poll fast.
+	pt.interval = p.MinInterval
+	pt.next = time.Now().Add(p.MinInterval)
+	return 2 * p.MaxInterval
+}
+
+// Start polls vms until ctx is done.
+func (p *VmStatePoller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.MinInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		p.mu.Lock()
+		var keys []string
+		now := time.Now()
+		for key, pt := range p.tenants {
+			if !now.Before(pt.next) {
+				keys = append(keys, key)
+			}
+		}
+		p.mu.Unlock()
+		for _, key := range keys {
+			p.poll(ctx, key)
+		}
+	}
+}
+
+func (p *VmStatePoller) poll(ctx context.Context, key string) {
+	log := ctrl.LoggerFrom(ctx).WithValues("tenant", key)
+
+	p.mu.Lock()
+	pt := p.tenants[key]
+	t := pt.tenant
+	vmIds := make([]string, 0, len(pt.vms))
+	var fgpuIds []string
+	for vmId, pvm := range pt.vms {
+		vmIds = append(vmIds, vmId)
+		if pvm.fgpuId != "" {
+			fgpuIds = append(fgpuIds, pvm.fgpuId)
+		}
+	}
+	p.mu.Unlock()
+
+	svc := p.Cloud.Compute(t)
+	vms, err := svc.GetVms(ctx, vmIds)
+	var fgpus []osc.FlexibleGpu
+	if err == nil && len(fgpuIds) > 0 {
+		fgpus, err = svc.GetFGPUs(ctx, fgpuIds)
+	}
+	if err != nil {
+		log.V(3).Error(err, "Unable to poll vm states")
+	}
+	vmStates := make(map[string]osc.VmState, len(vms))
+	for _, vm := range vms {
+		vmStates[vm.VmId] = vm.State
+	}
+	fgpuStates := make(map[string]osc.FlexibleGpuState, len(fgpus))
+	for _, fgpu := range fgpus {
+		fgpuStates[fgpu.FlexibleGpuId] = fgpu.State
+	}
+
+	var changed []*polledVm
+	p.mu.Lock()
+	for _, vmId := range vmIds {
+		pvm, found := pt.vms[vmId]
+		switch {
+		case err != nil || !found:
+			continue
+		case vmStates[vmId] == pvm.state && (pvm.fgpuId == "" || fgpuStates[pvm.fgpuId] == pvm.fgpuState):
+			continue
+		}
+		log.V(4).Info("VM state changed", "vmId", vmId, "state", vmStates[vmId])
+		changed = append(changed, pvm)
+		delete(pt.vms, vmId)
+	}
+	switch {
+	case len(pt.vms) == 0:
+		delete(p.tenants, key)
+	case len(changed) == 0:
+		// No change: back off.
+		pt.interval = min(2*pt.interval, p.MaxInterval)
+		pt.next = time.Now().Add(pt.interval)
+	default:
+		pt.next = time.Now().Add(pt.interval)
+	}
+	p.mu.Unlock()
+
+	// The reconciliation must not read the previous state from the cache.
+	if len(changed) > 0 {
+		p.Cloud.Cache().Invalidate(t)
+	}
+	for _, pvm := range changed {
+		select {
+		case p.events <- event.GenericEvent{Object: pvm.machine}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/cache"
+	"github.com/outscale/cluster-api-provider-outscale/controllers"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVmStatePoller(t *testing.T) {
+	machine := &infrastructurev1beta2.OscMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+	tn := MockTenant{region: "eu-west-2"}
+	t.Run("A reconcile is triggered when the vm state changes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		cs := newMockCloudServices(mockCtrl, "eu-west-2")
+		gomock.InOrder(
+			cs.ComputeMock.EXPECT().GetVms(gomock.Any(), []string{"i-foo"}).Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStatePending}}, nil),
+			cs.ComputeMock.EXPECT().GetVms(gomock.Any(), []string{"i-foo"}).Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStateRunning}}, nil),
+		)
+		p := controllers.NewVmStatePoller(cs, time.Millisecond, time.Millisecond)
+		requeue := p.Watch(tn, machine, &osc.Vm{VmId: "i-foo", State: osc.VmStatePending}, nil)
+		assert.Positive(t, requeue)
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		go func() { _ = p.Start(ctx) }()
+		select {
+		case ev := <-p.Events():
+			assert.Equal(t, "foo", ev.Object.GetName())
+			assert.Equal(t, "default", ev.Object.GetNamespace())
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event received")
+		}
+	})
+	t.Run("The reconcile triggered by a state change reads the new state", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		cs := newMockCloudServices(mockCtrl, "eu-west-2")
+		cs.ReadCache = cache.New(time.Minute)
+		gomock.InOrder(
+			cs.ComputeMock.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStatePending}}, nil),
+			cs.ComputeMock.EXPECT().GetVmsFromCluster(gomock.Any(), "uid").Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStateRunning}}, nil),
+		)
+		cs.ComputeMock.EXPECT().GetVms(gomock.Any(), []string{"i-foo"}).Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStateRunning}}, nil)
+		vm, err := cs.Cache().GetVm(context.TODO(), tn, cs.ComputeMock, "uid", "i-foo")
+		require.NoError(t, err)
+		p := controllers.NewVmStatePoller(cs, time.Millisecond, time.Millisecond)
+		p.Watch(tn, machine, vm, nil)
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		go func() { _ = p.Start(ctx) }()
+		select {
+		case <-p.Events():
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event received")
+		}
+		cancel()
+		vm, err = cs.Cache().GetVm(context.TODO(), tn, cs.ComputeMock, "uid", "i-foo")
+		require.NoError(t, err)
+		assert.Equal(t, osc.VmStateRunning, vm.State)
+	})
+	t.Run("A reconcile is triggered when the fGPU state changes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		cs := newMockCloudServices(mockCtrl, "eu-west-2")
+		cs.ComputeMock.EXPECT().GetVms(gomock.Any(), []string{"i-foo"}).Return([]osc.Vm{{VmId: "i-foo", State: osc.VmStateStopped}}, nil)
+		cs.ComputeMock.EXPECT().GetFGPUs(gomock.Any(), []string{"fgpu-foo"}).Return([]osc.FlexibleGpu{{FlexibleGpuId: "fgpu-foo", State: osc.FlexibleGpuStateAttached}}, nil)
+		p := controllers.NewVmStatePoller(cs, time.Millisecond, time.Millisecond)
+		p.Watch(tn, machine, &osc.Vm{VmId: "i-foo", State: osc.VmStateStopped}, &osc.FlexibleGpu{FlexibleGpuId: "fgpu-foo", State: osc.FlexibleGpuStateAttaching})
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		go func() { _ = p.Start(ctx) }()
+		select {
+		case ev := <-p.Events():
+			assert.Equal(t, "foo", ev.Object.GetName())
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event received")
+		}
+	})
+	t.Run("Without a poller, the default requeue is used", func(t *testing.T) {
+		var p *controllers.VmStatePoller
+		assert.Equal(t, 30*time.Second, p.Watch(tn, machine, &osc.Vm{VmId: "i-foo"}, nil))
+	})
+}
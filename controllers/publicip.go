@@ -14,6 +14,7 @@ import (
 	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services/net"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
 	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -33,6 +34,8 @@ type IPAllocator struct {
 	getPublicIP func(key string) (id string, found bool)
 	// setter to set a public ip to the clusterScope/machineScope state
 	setPublicIP func(key, id string)
+	// records public ip creations in the clusterScope/machineScope state
+	creations creationTracker
 }
 
 // AllocateIP alloctes an IP from a pool or creates a new IP.
@@ -49,6 +52,9 @@ func (a *IPAllocator) AllocateIP(ctx context.Context, key, name, pool string, cl
 			// we need to reallocate
 			log.V(3).Info("Known IP is already linked, reallocating", "publicIpId", id)
 		default:
+			if err := a.creations.EndCreation(ctx, creationKey(tag.PublicIPResourceType, key)); err != nil {
+				return "", "", fmt.Errorf("allocate ip: %w", err)
+			}
 			return id, pip.PublicIp, nil
 		}
 	}
@@ -56,22 +62,51 @@ func (a *IPAllocator) AllocateIP(ctx context.Context, key, name, pool string, cl
 	if pool != "" {
 		pip, err = a.allocateFromPool(ctx, pool, clusterScope)
 	} else {
-		pip, err = a.allocate(ctx, name, clusterScope)
+		pip, err = a.allocate(ctx, key, name, clusterScope)
 	}
 	if err != nil {
 		return "", "", fmt.Errorf("allocate ip: %w", err)
 	}
 	a.setPublicIP(key, pip.PublicIpId)
+	if err := a.creations.EndCreation(ctx, creationKey(tag.PublicIPResourceType, key)); err != nil {
+		return "", "", fmt.Errorf("allocate ip: %w", err)
+	}
 	return pip.PublicIpId, pip.PublicIp, nil
 }
 
-func (a *IPAllocator) allocate(ctx context.Context, name string, clusterScope *scope.ClusterScope) (*osc.PublicIp, error) {
+func (a *IPAllocator) allocate(ctx context.Context, key, name string, clusterScope *scope.ClusterScope) (*osc.PublicIp, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.V(3).Info("Allocating publicIp")
-	pip, err := a.Cloud.Net(clusterScope.Tenant).CreatePublicIp(ctx, name, clusterScope.GetUID())
+	svc := a.Cloud.Net(clusterScope.Tenant)
+	var pip *osc.PublicIp
+	id, err := createOnce(ctx, a.creations, creationKey(tag.PublicIPResourceType, key), creation{
+		candidates: func() ([]string, error) {
+			return svc.ListUnlinkedPublicIpIds(ctx, name, clusterScope.GetUID())
+		},
+		create: func() (string, error) {
+			created, err := svc.CreatePublicIp(ctx, name, clusterScope.GetUID())
+			if created == nil {
+				return "", err
+			}
+			pip = created
+			return pip.PublicIpId, err
+		},
+		adopt: func(id string) error {
+			return tagCreated(ctx, a.Cloud.Tag(clusterScope.Tenant), id, name, clusterScope.GetUID())
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
+	if pip == nil {
+		pip, err = svc.GetPublicIp(ctx, id)
+		switch {
+		case err != nil:
+			return nil, err
+		case pip == nil:
+			return nil, fmt.Errorf("get public ip %s: %w", id, ErrMissingResource)
+		}
+	}
 	log.V(2).Info("Allocated publicIp", "publicIpId", pip.PublicIpId, "publicIp", pip.PublicIp)
 	return pip, nil
 }
@@ -0,0 +1,87 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package controllers
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/outscale/cluster-api-provider-outscale/cloud/services/tag"
+	"github.com/outscale/goutils/k8s/tags"
+	"github.com/outscale/osc-sdk-go/v3/pkg/osc"
+)
+
+// Reused resources are not owned by the cluster: they are tagged with OscK8sClusterID/<uid>=shared, along with their role tags,
+// for the CCM to find them. Those tags are removed when the cluster is deleted, the resources are kept.
+// Only the role tags added by the cluster are removed, role tags that were already set are left untouched.
+
+// tagShared tags a reused resource as shared, if not already tagged.
+// It returns the keys of the role tags that were added, and true if tags were added.
+func tagShared(ctx context.Context, svc tag.Servicer, id string, rsrcTags []osc.ResourceTag, clusterID string, roleTags []osc.ResourceTag) ([]string, bool, error) {
+	var (
+		add   []osc.ResourceTag
+		added []string
+	)
+	key := tags.ClusterIDKey(clusterID)
+	if !tags.Has(rsrcTags, key, tag.SharedValue) {
+		add = append(add, osc.ResourceTag{Key: key, Value: tag.SharedValue})
+	}
+	for _, t := range roleTags {
+		if !hasTagKey(rsrcTags, t.Key) {
+			add = append(add, t)
+			added = append(added, t.Key)
+		}
+	}
+	if len(add) == 0 {
+		return nil, false, nil
+	}
+	ids := []string{id}
+	err := svc.AddTag(ctx, osc.CreateTagsRequest{
+		ResourceIds: ids,
+		Tags:        add,
+	}, ids)
+	if err != nil {
+		return nil, false, err
+	}
+	return added, true, nil
+}
+
+// untagShared removes the shared tags of a reused resource, and the role tags listed in addedKeys.
+// Role tags are kept if the resource is still shared with another cluster.
+// It returns true if tags were removed.
+func untagShared(ctx context.Context, svc tag.Servicer, id string, rsrcTags []osc.ResourceTag, clusterID string, addedKeys []string) (bool, error) {
+	key := tags.ClusterIDKey(clusterID)
+	if !tags.Has(rsrcTags, key, tag.SharedValue) {
+		return false, nil
+	}
+	rm := []osc.ResourceTag{{Key: key, Value: tag.SharedValue}}
+	if !sharedWithOtherClusters(rsrcTags, clusterID) {
+		for _, t := range rsrcTags {
+			if slices.Contains(addedKeys, t.Key) {
+				rm = append(rm, t)
+			}
+		}
+	}
+	return true, svc.DeleteTags(ctx, osc.DeleteTagsRequest{
+		ResourceIds: []string{id},
+		Tags:        rm,
+	})
+}
+
+func hasTagKey(rsrcTags []osc.ResourceTag, key string) bool {
+	return slices.ContainsFunc(rsrcTags, func(t osc.ResourceTag) bool { return t.Key == key })
+}
+
+func sharedWithOtherClusters(rsrcTags []osc.ResourceTag, clusterID string) bool {
+	key := tags.ClusterIDKey(clusterID)
+	for _, t := range rsrcTags {
+		if strings.HasPrefix(t.Key, tag.ClusterKeyPrefix) && t.Key != key {
+			return true
+		}
+	}
+	return false
+}
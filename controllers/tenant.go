@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	infrastructurev1beta2 "github.com/outscale/cluster-api-provider-outscale/api/v1beta2"
+	"github.com/outscale/cluster-api-provider-outscale/cloud/scope"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/services"
 	"github.com/outscale/cluster-api-provider-outscale/cloud/tenant"
 	"github.com/outscale/osc-sdk-go/v3/pkg/profile"
@@ -49,6 +50,22 @@ func getMgmtTenant(ctx context.Context, cl client.Client, c services.Servicer, c
 	}
 }
 
+func getPeeringTenant(ctx context.Context, cl client.Client, clusterScope *scope.ClusterScope, peering infrastructurev1beta2.OscPeering) (tenant.Tenant, error) {
+	logger := log.FromContext(ctx).V(4)
+	creds := peering.Credentials
+	switch {
+	case creds.FromFile != "":
+		logger.Info("Using tenant from file for peering", "peering", peering.Name, "file", creds.FromFile, "profile", creds.Profile)
+		return tenant.FromFile(creds.FromFile, creds.Profile)
+	case creds.FromSecret != "":
+		logger.Info("Using tenant from secret for peering", "peering", peering.Name, "secret", creds.FromSecret)
+		return getTenantFromSecret(ctx, cl, creds.FromSecret, clusterScope.OscCluster.Namespace)
+	default:
+		logger.Info("Using cluster tenant for peering", "peering", peering.Name)
+		return clusterScope.Tenant, nil
+	}
+}
+
 func getTenantFromSecret(ctx context.Context, cl client.Client, name, ns string) (tenant.Tenant, error) {
 	var secret corev1.Secret
 	err := cl.Get(ctx, client.ObjectKey{
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -45,6 +46,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme))
 	utilruntime.Must(bootstrapv1.AddToScheme(scheme))
+	utilruntime.Must(ipamv1.AddToScheme(scheme))
 	utilruntime.Must(infrastructurev1beta1.AddToScheme(scheme))
 	utilruntime.Must(infrastructurev1beta2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -64,6 +66,8 @@ func main() {
 		retryPeriod          time.Duration
 		clusterConcurrency   int
 		machineConcurrency   int
+		clusterParallelism   int
+		cacheTTL             time.Duration
 		reconcileTimeout     time.Duration
 	)
 	fs := pflag.CommandLine
@@ -91,6 +95,10 @@ func main() {
 		"Number of OscCluster reconciles to process simultaneously")
 	fs.IntVar(&machineConcurrency, "oscmachine-concurrency", 2,
 		"Number of OscMachine reconciles to process simultaneously")
+	fs.DurationVar(&cacheTTL, "cloud-cache-ttl", reconciler.DefaultCacheTTL,
+		"The duration cloud reads are cached, 0 to disable the cache")
+	fs.IntVar(&clusterParallelism, "osccluster-parallelism", reconciler.DefaultParallelism,
+		"Number of independent resources reconciled simultaneously within an OscCluster reconcile")
 
 	logOptions := logs.NewOptions()
 	v1.AddFlags(logOptions, fs)
@@ -135,7 +143,7 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
-	cs, err := services.NewServices()
+	cs, err := services.NewServices(cacheTTL)
 	if err != nil {
 		logger.Error(err, "unable to initialize cloud services")
 		os.Exit(1)
@@ -153,12 +161,14 @@ func main() {
 	}
 	if err = (&controllers.OscClusterReconciler{
 		Client:           mgr.GetClient(),
+		APIReader:        mgr.GetAPIReader(),
 		Tracker:          tracker,
 		Cloud:            cs,
 		Metadata:         meta,
 		Recorder:         mgr.GetEventRecorderFor("osccluster-controller"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
+		Parallelism:      clusterParallelism,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: clusterConcurrency}); err != nil {
 		logger.Error(err, "unable to create controller", "controller", "OscCluster")
 		os.Exit(1)
@@ -169,6 +179,12 @@ func main() {
 	}
 	allocator := controllers.NewMultiAZAllocator(mgr.GetClient())
 
+	poller := controllers.NewVmStatePoller(cs, reconciler.DefaultVmPollMinInterval, reconciler.DefaultVmPollMaxInterval)
+	if err = mgr.Add(poller); err != nil {
+		logger.Error(err, "unable to add vm state poller")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.OscMachineReconciler{
 		Client:           mgr.GetClient(),
 		ClusterTracker:   tracker,
@@ -178,6 +194,7 @@ func main() {
 		Recorder:         mgr.GetEventRecorderFor("oscmachine-controller"),
 		ReconcileTimeout: reconcileTimeout,
 		WatchFilterValue: watchFilterValue,
+		VmPoller:         poller,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: machineConcurrency}); err != nil {
 		logger.Error(err, "unable to create controller", "controller", "OscMachine")
 		os.Exit(1)
@@ -0,0 +1,72 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package cidr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"slices"
+)
+
+// ErrNoFreeRange is returned when no free range is available.
+var ErrNoFreeRange = errors.New("no free range")
+
+// Allocate returns the first range of prefix length bits within parent, not overlapping any used range.
+func Allocate(parent netip.Prefix, used []netip.Prefix, bits int) (netip.Prefix, error) {
+	parent = parent.Masked()
+	if bits < parent.Bits() || bits > parent.Addr().BitLen() {
+		return netip.Prefix{}, fmt.Errorf("cannot allocate a /%d range in %s", bits, parent)
+	}
+	size := new(big.Int).Lsh(big.NewInt(1), uint(parent.Addr().BitLen()-bits))
+	end := new(big.Int).Lsh(big.NewInt(1), uint(parent.Addr().BitLen()-parent.Bits()))
+	end.Add(end, toInt(parent.Addr()))
+	for cur := toInt(parent.Addr()); cur.Cmp(end) < 0; cur.Add(cur, size) {
+		candidate := netip.PrefixFrom(fromInt(cur, parent.Addr().Is4()), bits)
+		free := true
+		for _, u := range used {
+			if u.Overlaps(candidate) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate, nil
+		}
+	}
+	return netip.Prefix{}, fmt.Errorf("allocate /%d in %s: %w", bits, parent, ErrNoFreeRange)
+}
+
+func toInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+func fromInt(i *big.Int, is4 bool) netip.Addr {
+	if is4 {
+		var b [4]byte
+		i.FillBytes(b[:])
+		return netip.AddrFrom4(b)
+	}
+	var b [16]byte
+	i.FillBytes(b[:])
+	return netip.AddrFrom16(b)
+}
+
+// AllocateAll allocates ranges of prefix lengths bits within parent, in order, not overlapping any used range or each other.
+func AllocateAll(parent netip.Prefix, used []netip.Prefix, bits []int) ([]netip.Prefix, error) {
+	used = slices.Clone(used)
+	res := make([]netip.Prefix, 0, len(bits))
+	for _, b := range bits {
+		p, err := Allocate(parent, used, b)
+		if err != nil {
+			return nil, err
+		}
+		used = append(used, p)
+		res = append(res, p)
+	}
+	return res, nil
+}
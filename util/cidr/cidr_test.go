@@ -0,0 +1,68 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package cidr_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/outscale/cluster-api-provider-outscale/util/cidr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prefixes(ps ...string) []netip.Prefix {
+	res := make([]netip.Prefix, 0, len(ps))
+	for _, p := range ps {
+		res = append(res, netip.MustParsePrefix(p))
+	}
+	return res
+}
+
+func TestAllocate(t *testing.T) {
+	parent := netip.MustParsePrefix("10.0.0.0/16")
+	t.Run("The first range is allocated in an empty parent", func(t *testing.T) {
+		p, err := cidr.Allocate(parent, nil, 24)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/24", p.String())
+	})
+	t.Run("Used ranges are skipped", func(t *testing.T) {
+		p, err := cidr.Allocate(parent, prefixes("10.0.0.0/24", "10.0.1.0/26", "10.0.3.0/24"), 24)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.2.0/24", p.String())
+	})
+	t.Run("A range overlapping a larger used range is skipped", func(t *testing.T) {
+		p, err := cidr.Allocate(parent, prefixes("10.0.0.0/20"), 26)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.16.0/26", p.String())
+	})
+	t.Run("Used ranges outside of the parent are ignored", func(t *testing.T) {
+		p, err := cidr.Allocate(parent, prefixes("10.1.0.0/16"), 24)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/24", p.String())
+	})
+	t.Run("An error is returned when the parent is full", func(t *testing.T) {
+		_, err := cidr.Allocate(netip.MustParsePrefix("10.0.0.0/23"), prefixes("10.0.0.0/24", "10.0.1.0/24"), 24)
+		require.ErrorIs(t, err, cidr.ErrNoFreeRange)
+	})
+	t.Run("An error is returned when the range is larger than the parent", func(t *testing.T) {
+		_, err := cidr.Allocate(parent, nil, 8)
+		require.Error(t, err)
+	})
+}
+
+func TestAllocateAll(t *testing.T) {
+	parent := netip.MustParsePrefix("10.0.0.0/16")
+	t.Run("Ranges are allocated in order", func(t *testing.T) {
+		ps, err := cidr.AllocateAll(parent, prefixes("10.0.0.0/24"), []int{26, 24, 26})
+		require.NoError(t, err)
+		assert.Equal(t, prefixes("10.0.1.0/26", "10.0.2.0/24", "10.0.1.64/26"), ps)
+	})
+	t.Run("An error is returned when ranges do not fit", func(t *testing.T) {
+		_, err := cidr.AllocateAll(netip.MustParsePrefix("10.0.0.0/23"), nil, []int{24, 24, 24})
+		require.ErrorIs(t, err, cidr.ErrNoFreeRange)
+	})
+}
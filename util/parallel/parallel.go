@@ -0,0 +1,145 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ErrDependencyFailed is returned for steps that were not run because one of their dependencies failed.
+var ErrDependencyFailed = errors.New("dependency failed")
+
+type step struct {
+	name      string
+	dependsOn []string
+	run       func(ctx context.Context) error
+}
+
+// Executor runs steps concurrently, while respecting dependencies between steps.
+type Executor struct {
+	limit int
+	steps []step
+	errs  map[string]error
+}
+
+// NewExecutor creates an executor running at most limit steps simultaneously.
+func NewExecutor(limit int) *Executor {
+	return &Executor{
+		limit: max(limit, 1),
+	}
+}
+
+// Add adds a step, which will only run once all the steps it depends on have succeeded.
+// Dependencies on steps that have not been added are ignored.
+func (e *Executor) Add(name string, run func(ctx context.Context) error, dependsOn ...string) {
+	e.steps = append(e.steps, step{name: name, run: run, dependsOn: dependsOn})
+}
+
+const (
+	statePending = iota
+	stateRunning
+	stateDone
+)
+
+type result struct {
+	idx int
+	err error
+}
+
+// Run runs all steps, and returns the aggregated errors of failed steps.
+// Steps skipped because of a failed dependency are not part of the returned error.
+func (e *Executor) Run(ctx context.Context) error {
+	index := make(map[string]int, len(e.steps))
+	for i, st := range e.steps {
+		index[st.name] = i
+	}
+	e.errs = make(map[string]error, len(e.steps))
+	states := make([]int, len(e.steps))
+	results := make(chan result)
+	var running, done int
+	for done < len(e.steps) {
+		changed := true
+		for changed {
+			changed = false
+			for i, st := range e.steps {
+				if states[i] != statePending {
+					continue
+				}
+				ready, failed := true, false
+				for _, dep := range st.dependsOn {
+					j, ok := index[dep]
+					switch {
+					case !ok:
+					case states[j] != stateDone:
+						ready = false
+					case e.errs[dep] != nil:
+						failed = true
+					}
+				}
+				switch {
+				case failed:
+					states[i] = stateDone
+					e.errs[st.name] = fmt.Errorf("%s: %w", st.name, ErrDependencyFailed)
+					done++
+					changed = true
+				case ready && running < e.limit:
+					states[i] = stateRunning
+					running++
+					go func() {
+						results <- result{idx: i, err: st.run(ctx)}
+					}()
+				}
+			}
+		}
+		if running == 0 {
+			if done < len(e.steps) {
+				return errors.New("dependency cycle between steps")
+			}
+			break
+		}
+		res := <-results
+		running--
+		done++
+		states[res.idx] = stateDone
+		e.errs[e.steps[res.idx].name] = res.err
+	}
+	var errs []error
+	for _, st := range e.steps {
+		if err := e.errs[st.name]; err != nil && !errors.Is(err, ErrDependencyFailed) {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// Err returns the error of a step, after Run has been called.
+func (e *Executor) Err(name string) error {
+	return e.errs[name]
+}
+
+// ForEach calls fn on each item, with at most limit concurrent calls, and returns the aggregated errors.
+func ForEach[T any](ctx context.Context, limit int, items []T, fn func(ctx context.Context, item T) error) error {
+	if len(items) == 0 {
+		return nil
+	}
+	sem := make(chan struct{}, max(limit, 1))
+	errs := make([]error, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			errs[i] = fn(ctx, item)
+		})
+	}
+	wg.Wait()
+	return kerrors.NewAggregate(errs)
+}
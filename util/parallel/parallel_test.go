@@ -0,0 +1,99 @@
+/*
+SPDX-FileCopyrightText: 2025 Outscale SAS <opensource@outscale.com>
+
+SPDX-License-Identifier: BSD-3-Clause
+*/
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/outscale/cluster-api-provider-outscale/util/parallel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor(t *testing.T) {
+	t.Run("Steps are run after their dependencies", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []string
+		record := func(name string) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+		e := parallel.NewExecutor(4)
+		e.Add("c", record("c"), "a", "b")
+		e.Add("a", record("a"))
+		e.Add("b", record("b"), "a", "missing")
+		require.NoError(t, e.Run(context.TODO()))
+		assert.Equal(t, []string{"a", "b", "c"}, order)
+	})
+	t.Run("Steps depending on a failed step are skipped", func(t *testing.T) {
+		errA := errors.New("a failed")
+		var ranB, ranC bool
+		e := parallel.NewExecutor(4)
+		e.Add("a", func(ctx context.Context) error { return errA })
+		e.Add("b", func(ctx context.Context) error { ranB = true; return nil }, "a")
+		e.Add("c", func(ctx context.Context) error { ranC = true; return nil })
+		err := e.Run(context.TODO())
+		require.ErrorIs(t, err, errA)
+		assert.NotErrorIs(t, err, parallel.ErrDependencyFailed)
+		assert.False(t, ranB)
+		assert.True(t, ranC)
+		assert.ErrorIs(t, e.Err("b"), parallel.ErrDependencyFailed)
+		assert.NoError(t, e.Err("c"))
+	})
+	t.Run("Errors of independent steps are aggregated", func(t *testing.T) {
+		errA, errB := errors.New("a failed"), errors.New("b failed")
+		e := parallel.NewExecutor(4)
+		e.Add("a", func(ctx context.Context) error { return errA })
+		e.Add("b", func(ctx context.Context) error { return errB })
+		err := e.Run(context.TODO())
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+	})
+	t.Run("Cycles are detected", func(t *testing.T) {
+		e := parallel.NewExecutor(4)
+		e.Add("a", func(ctx context.Context) error { return nil }, "b")
+		e.Add("b", func(ctx context.Context) error { return nil }, "a")
+		require.Error(t, e.Run(context.TODO()))
+	})
+}
+
+func TestForEach(t *testing.T) {
+	t.Run("Concurrency is limited", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		items := make([]int, 20)
+		err := parallel.ForEach(context.TODO(), 3, items, func(ctx context.Context, _ int) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	})
+	t.Run("Errors are aggregated", func(t *testing.T) {
+		errOdd := errors.New("odd")
+		err := parallel.ForEach(context.TODO(), 2, []int{1, 2, 3}, func(ctx context.Context, i int) error {
+			if i%2 == 1 {
+				return errOdd
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errOdd)
+	})
+}
@@ -13,6 +13,13 @@ import (
 const (
 	DefaultLoopTimeout    = 90 * time.Minute
 	DefaultMappingTimeout = 60 * time.Second
+	DefaultParallelism    = 4
+	DefaultCacheTTL       = 10 * time.Second
+
+	DefaultVmPollMinInterval = 5 * time.Second
+	DefaultVmPollMaxInterval = time.Minute
+
+	DefaultEgressResolveInterval = 5 * time.Minute
 )
 
 func DefaultedLoopTimeout(timeout time.Duration) time.Duration {
@@ -22,3 +29,11 @@ func DefaultedLoopTimeout(timeout time.Duration) time.Duration {
 
 	return timeout
 }
+
+func DefaultedParallelism(parallelism int) int {
+	if parallelism <= 0 {
+		return DefaultParallelism
+	}
+
+	return parallelism
+}